@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yourusername/jobapply/internal/encryption"
+	"github.com/yourusername/jobapply/internal/store"
+)
+
+const encryptionUsage = `usage: jobapply encryption <command>
+
+  rotate   re-encrypt profile phone numbers and addresses with the first key in
+           ENCRYPTION_KEYS, including any stored before encryption was enabled
+  decrypt  store them in plaintext again, e.g. before removing ENCRYPTION_KEYS or
+           rolling back migration 021`
+
+// runEncryption implements the encryption subcommand. keys is nil when ENCRYPTION_KEYS is unset.
+func runEncryption(ctx context.Context, db *pgxpool.Pool, keys *encryption.StaticKeys, args []string) error {
+	if len(args) != 1 {
+		return errors.New(encryptionUsage)
+	}
+	if keys == nil {
+		return errors.New("ENCRYPTION_KEYS is not set")
+	}
+
+	switch args[0] {
+	case "rotate":
+	case "decrypt":
+		keys = keys.ReadOnly()
+	default:
+		return errors.New(encryptionUsage)
+	}
+
+	changed, err := store.NewPostgres(db, encryption.New(keys)).Users.ReencryptProfiles(ctx)
+	fmt.Printf("%d profiles updated\n", changed)
+	return err
+}
@@ -2,11 +2,15 @@ package main
 
 import (
 	"context"
-	"log"
+	"crypto/rand"
+	"log/slog"
+	"math"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -14,35 +18,343 @@ import (
 	"github.com/go-chi/cors"
 	"github.com/joho/godotenv"
 	"github.com/yourusername/jobapply/internal/database"
+	"github.com/yourusername/jobapply/internal/encryption"
+	"github.com/yourusername/jobapply/internal/events"
+	"github.com/yourusername/jobapply/internal/geo"
+	"github.com/yourusername/jobapply/internal/graph"
+	"github.com/yourusername/jobapply/internal/grpcapi"
 	"github.com/yourusername/jobapply/internal/handlers"
+	"github.com/yourusername/jobapply/internal/logging"
 	"github.com/yourusername/jobapply/internal/middleware"
+	"github.com/yourusername/jobapply/internal/redis"
+	"github.com/yourusername/jobapply/internal/resume"
+	"github.com/yourusername/jobapply/internal/scanner"
+	"github.com/yourusername/jobapply/internal/services"
+	"github.com/yourusername/jobapply/internal/storage"
+	"github.com/yourusername/jobapply/internal/store"
+	"github.com/yourusername/jobapply/internal/tracing"
+	"google.golang.org/grpc"
 )
 
 func main() {
 	// Load .env file
 	_ = godotenv.Load()
 
+	// Structured logging; every request gets a child logger tagged with its request ID
+	logger, err := logging.New(os.Stderr, getEnv("LOG_LEVEL", "info"), getEnv("LOG_FORMAT", "text"))
+	if err != nil {
+		fatal("Invalid logging configuration", "error", err)
+	}
+	slog.SetDefault(logger)
+
 	// Get config from env
 	databaseURL := os.Getenv("DATABASE_URL")
 	if databaseURL == "" {
-		log.Fatal("DATABASE_URL is required")
+		fatal("DATABASE_URL is required")
 	}
 
 	port := getEnv("PORT", "8080")
 	uploadDir := getEnv("UPLOAD_DIR", "./uploads")
 	maxUploadSize, _ := strconv.ParseInt(getEnv("MAX_UPLOAD_SIZE", "5242880"), 10, 64)
 
-	// Connect to database and run migrations
+	// Optional tracing, exported over OTLP/HTTP to an OpenTelemetry collector
+	var traceExporter *tracing.Exporter
+	if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); endpoint != "" {
+		traceExporter = tracing.Init(endpoint, getEnv("OTEL_SERVICE_NAME", "jobapply"), parseHeaders(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS")))
+		slog.Info("Tracing enabled", "endpoint", endpoint)
+	}
+
+	// Connect to database
 	ctx := context.Background()
-	db, err := database.Connect(ctx, databaseURL)
+	db, err := database.Connect(ctx, databaseURL, database.PoolConfig{
+		MaxConns:           int32(parseCount("DB_MAX_CONNS")),
+		MinConns:           int32(parseCount("DB_MIN_CONNS")),
+		MaxConnLifetime:    parseInterval("DB_MAX_CONN_LIFETIME", "0"),
+		MaxConnIdleTime:    parseInterval("DB_MAX_CONN_IDLE_TIME", "0"),
+		HealthCheckPeriod:  parseInterval("DB_HEALTH_CHECK_PERIOD", "0"),
+		SlowQueryThreshold: parseInterval("DB_SLOW_QUERY_THRESHOLD", "500ms"),
+	})
 	if err != nil {
-		log.Fatalf("Database connection failed: %v", err)
+		fatal("Database connection failed", "error", err)
 	}
 	defer db.Close()
-	log.Println("Connected to database successfully")
+	slog.Info("Connected to database successfully")
+
+	// Optional encryption of profile phone numbers and addresses at rest
+	var encryptionKeys *encryption.StaticKeys
+	var profileCipher *encryption.Cipher
+	if spec := os.Getenv("ENCRYPTION_KEYS"); spec != "" {
+		if encryptionKeys, err = encryption.ParseKeys(spec); err != nil {
+			fatal("Invalid ENCRYPTION_KEYS", "error", err)
+		}
+		profileCipher = encryption.New(encryptionKeys)
+	}
+
+	// "jobapply migrate ..." manages the schema and exits instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(ctx, db, os.Args[2:]); err != nil {
+			fatal("Migration failed", "error", err)
+		}
+		return
+	}
+	if getEnv("AUTO_MIGRATE", "true") == "true" {
+		if err := database.Migrate(ctx, db); err != nil {
+			fatal("Migration failed", "error", err)
+		}
+	}
+
+	// "jobapply encryption ..." re-encrypts stored profiles after migrating, and exits instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "encryption" {
+		if err := runEncryption(ctx, db, encryptionKeys, os.Args[2:]); err != nil {
+			fatal("Re-encryption failed", "error", err)
+		}
+		return
+	}
+
+	// Resume parsing (pdftotext, with optional tesseract OCR for image-only PDFs)
+	minTextChars, _ := strconv.Atoi(getEnv("RESUME_MIN_TEXT_CHARS", "50"))
+	var ocr resume.OCR
+	if getEnv("OCR_ENABLED", "false") == "true" {
+		tesseract := resume.NewTesseractOCR(os.Getenv("TESSERACT_PATH"), os.Getenv("PDFTOPPM_PATH"))
+		if tesseract.Available() {
+			ocr = tesseract
+		} else {
+			slog.Warn("OCR_ENABLED is set but tesseract/pdftoppm not found - OCR fallback disabled")
+		}
+	}
+	resumeParser := resume.NewParser(resume.NewPdfToText(os.Getenv("PDFTOTEXT_PATH")), ocr, minTextChars)
+	if llmURL := os.Getenv("LLM_API_URL"); llmURL != "" {
+		resumeParser.RegisterBackend(resume.NewLLMBackend(llmURL, os.Getenv("LLM_API_KEY"), getEnv("LLM_MODEL", "gpt-4o-mini")))
+	}
+	if err := resumeParser.SetDefaultBackend(getEnv("RESUME_PARSER_BACKEND", resume.BackendHeuristic)); err != nil {
+		fatal("Invalid RESUME_PARSER_BACKEND", "error", err)
+	}
+
+	// Optional geocoding of profile addresses and job locations for distance filters
+	var geocoder geo.Geocoder
+	if getEnv("GEOCODING_ENABLED", "false") == "true" {
+		switch provider := getEnv("GEOCODER", "nominatim"); provider {
+		case "nominatim":
+			geocoder = geo.NewNominatim(os.Getenv("NOMINATIM_URL"), getEnv("GEOCODER_USER_AGENT", "jobapply/1.0"))
+		default:
+			fatal("Unknown GEOCODER", "geocoder", provider)
+		}
+	}
+
+	// Optional commute estimates from profile addresses to on-site jobs; needs geocoding
+	var router geo.Router
+	switch provider := getEnv("COMMUTE_ROUTER", "none"); provider {
+	case "none":
+	case "osrm":
+		router = geo.NewOSRM(os.Getenv("OSRM_URL"))
+	case "google":
+		apiKey := os.Getenv("GOOGLE_MAPS_API_KEY")
+		if apiKey == "" {
+			fatal("GOOGLE_MAPS_API_KEY is required for COMMUTE_ROUTER=google")
+		}
+		router = geo.NewGoogleRouter(apiKey)
+	default:
+		fatal("Unknown COMMUTE_ROUTER", "router", provider)
+	}
+
+	// Malware scanning of uploads before they are stored and served from /uploads
+	var fileScanner scanner.Scanner = scanner.Noop{}
+	switch provider := getEnv("UPLOAD_SCANNER", "none"); provider {
+	case "none":
+	case "clamav":
+		fileScanner = scanner.NewClamAV(getEnv("CLAMD_NETWORK", "unix"), getEnv("CLAMD_ADDRESS", "/var/run/clamav/clamd.ctl"))
+	default:
+		fatal("Unknown UPLOAD_SCANNER", "scanner", provider)
+	}
+
+	// Upload storage: local disk by default, S3/MinIO or GCS for multi-instance deployments
+	var files storage.Storage
+	switch backend := getEnv("STORAGE_BACKEND", "local"); backend {
+	case "local":
+		files = storage.NewLocal(uploadDir)
+	case "s3":
+		files = storage.NewS3(os.Getenv("S3_ENDPOINT"), getEnv("S3_REGION", "us-east-1"), os.Getenv("S3_BUCKET"),
+			os.Getenv("S3_ACCESS_KEY_ID"), os.Getenv("S3_SECRET_ACCESS_KEY"), getEnv("S3_FORCE_PATH_STYLE", "false") == "true")
+	case "gcs":
+		files = storage.NewGCS(os.Getenv("GCS_BUCKET"), os.Getenv("GCS_HMAC_ACCESS_KEY"), os.Getenv("GCS_HMAC_SECRET"))
+	default:
+		fatal("Unknown STORAGE_BACKEND", "backend", backend)
+	}
+
+	// Uploads are only served through signed, short-lived links. Without a configured key a random
+	// one is used, which invalidates links on restart and doesn't work across multiple instances.
+	uploadSigningKey := []byte(os.Getenv("UPLOAD_SIGNING_KEY"))
+	if len(uploadSigningKey) == 0 {
+		uploadSigningKey = make([]byte, 32)
+		if _, err := rand.Read(uploadSigningKey); err != nil {
+			fatal("Failed to generate upload signing key", "error", err)
+		}
+		slog.Warn("UPLOAD_SIGNING_KEY not set - using a random key for this process")
+	}
+
+	// Optional Redis for state shared between instances behind a load balancer
+	var redisClient *redis.Client
+	if redisURL := os.Getenv("REDIS_URL"); redisURL != "" {
+		if redisClient, err = redis.NewFromURL(redisURL); err != nil {
+			fatal("Invalid REDIS_URL", "error", err)
+		}
+		defer redisClient.Close()
+		if err := redisClient.Ping(ctx); err != nil {
+			slog.Warn("Redis is unreachable", "error", err)
+		}
+	}
 
 	// Create handlers
-	h := handlers.New(db, uploadDir, maxUploadSize)
+	stores := store.NewPostgres(db, profileCipher)
+	h := handlers.New(db, stores, files, maxUploadSize, resumeParser, geocoder, fileScanner, uploadSigningKey)
+	h.SetMaxResumePages(parseCount("MAX_RESUME_PAGES"))
+	impersonationTTL := parseInterval("IMPERSONATION_TTL", "1h")
+	if impersonationTTL == 0 {
+		fatal("Invalid IMPERSONATION_TTL", "value", os.Getenv("IMPERSONATION_TTL"))
+	}
+	h.SetImpersonationTTL(impersonationTTL)
+	if previewer := resume.NewPreviewer(os.Getenv("PDFTOPPM_PATH")); previewer.Available() {
+		h.SetPreviewer(previewer)
+	} else {
+		slog.Warn("pdftoppm not found - resume previews disabled")
+	}
+	if router != nil {
+		if err := h.SetRouter(router, splitList(getEnv("COMMUTE_MODES", geo.ModeDriving))); err != nil {
+			fatal("Invalid COMMUTE_MODES", "error", err)
+		}
+	}
+	cacheTTL, sourceCacheTTLs := parseCacheTTLs()
+	h.SetScrapeCache(services.NewScrapeCache(db, cacheTTL, sourceCacheTTLs))
+
+	// Events from the store and handlers reach subscribers such as the notification inbox in
+	// process by default, or through a Redis stream shared by every instance
+	var bus events.Bus = events.NewLocal()
+	var redisBus *events.Redis
+	switch backend := getEnv("EVENT_BUS", "local"); backend {
+	case "local":
+	case "redis":
+		if redisClient == nil {
+			fatal("EVENT_BUS=redis requires REDIS_URL")
+		}
+		redisBus = events.NewRedis(redisClient, os.Getenv("EVENT_STREAM"))
+		bus = redisBus
+	default:
+		fatal("Unknown EVENT_BUS", "backend", backend)
+	}
+	h.SetEventBus(bus)
+	if redisBus != nil {
+		// After every subscriber has registered
+		redisBus.Start()
+	}
+
+	// The outbox delivers queued events to the bus and an optional operator webhook, retrying
+	// failures until they are dead-lettered
+	outbox := services.NewOutbox(db, parseInterval("OUTBOX_POLL_INTERVAL", "1s"), parseCount("OUTBOX_MAX_ATTEMPTS"))
+	outbox.AddSink("bus", bus.Publish)
+	if webhookURL := os.Getenv("EVENT_WEBHOOK_URL"); webhookURL != "" {
+		outbox.AddSink("webhook", events.NewWebhook(webhookURL, os.Getenv("EVENT_WEBHOOK_SECRET")).Deliver)
+	}
+	h.SetOutbox(outbox)
+	outbox.Start()
+
+	// Bearer tokens by default; browsers can use httpOnly session cookies with CSRF tokens instead
+	switch authMode := getEnv("AUTH_MODE", handlers.AuthModeBearer); authMode {
+	case handlers.AuthModeBearer:
+	case handlers.AuthModeCookie, handlers.AuthModeBoth:
+		cookies := handlers.CookieAuth{
+			Secure: getEnv("AUTH_COOKIE_SECURE", "true") == "true",
+			Domain: os.Getenv("AUTH_COOKIE_DOMAIN"),
+		}
+		switch sameSite := getEnv("AUTH_COOKIE_SAMESITE", "lax"); sameSite {
+		case "lax":
+			cookies.SameSite = http.SameSiteLaxMode
+		case "strict":
+			cookies.SameSite = http.SameSiteStrictMode
+		case "none":
+			if !cookies.Secure {
+				fatal("AUTH_COOKIE_SAMESITE=none requires AUTH_COOKIE_SECURE=true")
+			}
+			cookies.SameSite = http.SameSiteNoneMode
+		default:
+			fatal("Unknown AUTH_COOKIE_SAMESITE", "samesite", sameSite)
+		}
+		h.SetAuthMode(authMode, cookies)
+	default:
+		fatal("Unknown AUTH_MODE", "mode", authMode)
+	}
+
+	if redisClient != nil {
+		// Optional: the rate limiter lets requests through while Redis is down
+		h.AddHealthCheck("redis", false, redisClient.Ping)
+	}
+
+	// Recurring background work. A zero interval or a listing in SCHEDULER_DISABLED_TASKS
+	// keeps a task off the schedule; admins can still run it by hand.
+	scheduler := services.NewScheduler(db, splitList(os.Getenv("SCHEDULER_DISABLED_TASKS")))
+	expiryInterval := parseInterval("JOB_EXPIRY_CHECK_INTERVAL", "6h")
+	scheduler.Register(services.Task{
+		Name:     "job_expiry",
+		Interval: expiryInterval,
+		Run: func(ctx context.Context) error {
+			// Saved jobs are rechecked once per interval; when only run by hand, once a day
+			staleAfter := expiryInterval
+			if staleAfter == 0 {
+				staleAfter = 24 * time.Hour
+			}
+			return h.CheckSavedJobs(ctx, staleAfter)
+		},
+	})
+	scheduler.Register(services.Task{
+		Name:     "scrape_cache_cleanup",
+		Interval: parseInterval("SCRAPE_CACHE_CLEANUP_INTERVAL", "1h"),
+		Run:      h.CleanScrapeCache,
+	})
+	scheduler.Register(services.Task{
+		Name:     "idempotency_cleanup",
+		Interval: parseInterval("IDEMPOTENCY_CLEANUP_INTERVAL", "1h"),
+		Run:      h.CleanIdempotencyKeys,
+	})
+	scheduler.Register(services.Task{
+		Name:     "commute_estimates",
+		Interval: parseInterval("COMMUTE_ESTIMATE_INTERVAL", "1h"),
+		Run:      h.EstimateCommutes,
+	})
+	scheduler.Register(services.Task{
+		Name:     "ghost_scoring",
+		Interval: parseInterval("GHOST_SCORING_INTERVAL", "1h"),
+		Run:      h.ScoreGhostJobs,
+	})
+	deletedRetention := parseInterval("SOFT_DELETE_RETENTION", "720h")
+	scheduler.Register(services.Task{
+		Name:     "notification_reminders",
+		Interval: parseInterval("NOTIFICATION_REMINDER_INTERVAL", "1h"),
+		Run:      h.SendReminders,
+	})
+	scheduler.Register(services.Task{
+		Name:     "outbox_cleanup",
+		Interval: parseInterval("OUTBOX_CLEANUP_INTERVAL", "1h"),
+		Run:      outbox.Clean,
+	})
+	scheduler.Register(services.Task{
+		Name:     "purge_deleted",
+		Interval: parseInterval("PURGE_DELETED_INTERVAL", "24h"),
+		Run: func(ctx context.Context) error {
+			return h.PurgeDeleted(ctx, deletedRetention)
+		},
+	})
+	h.SetRetention(handlers.RetentionPolicy{
+		ClosedJobs:           parseInterval("RETENTION_CLOSED_JOBS", "0"),
+		RejectedApplications: parseInterval("RETENTION_REJECTED_APPLICATIONS", "0"),
+		OrphanUploads:        parseInterval("RETENTION_ORPHAN_UPLOADS", "0"),
+	})
+	scheduler.Register(services.Task{
+		Name:     "retention",
+		Interval: parseInterval("RETENTION_INTERVAL", "24h"),
+		Run:      h.ApplyRetention,
+	})
+	h.SetScheduler(scheduler)
+	h.SetFlags(services.NewFlags(db))
+	scheduler.Start()
 
 	// Setup router
 	r := chi.NewRouter()
@@ -51,51 +363,217 @@ func main() {
 	// 1. Security headers first to protect all responses
 	r.Use(middleware.SecurityHeaders)
 
-	// 2. Rate limiting to prevent DDoS (60 requests per minute per IP)
-	rateLimiter := middleware.NewRateLimiter(60)
-	r.Use(rateLimiter.Middleware)
+	// 2. Client IPs for rate limiting; forwarding headers are only honored from trusted proxies
+	trustedProxies, err := middleware.ParseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		fatal("Invalid TRUSTED_PROXIES", "error", err)
+	}
+	r.Use(trustedProxies.Middleware)
+
+	// 3. Rate limiting to prevent DDoS (60 requests per minute per IP). Counts are per instance
+	// unless kept in Redis.
+	switch backend := getEnv("RATE_LIMIT_BACKEND", "memory"); backend {
+	case "memory":
+		r.Use(middleware.NewRateLimiter(60).Middleware)
+	case "redis":
+		if redisClient == nil {
+			fatal("RATE_LIMIT_BACKEND=redis requires REDIS_URL")
+		}
+		r.Use(middleware.NewRedisRateLimiter(redisClient, 60).Middleware)
+	default:
+		fatal("Unknown RATE_LIMIT_BACKEND", "backend", backend)
+	}
 
-	// 3. Request size limiting to prevent memory exhaustion (10MB max)
+	// 4. Request size limiting to prevent memory exhaustion (10MB max)
 	r.Use(middleware.MaxBytesMiddleware(10 * 1024 * 1024))
 
-	// 4. Logging for audit trail
-	r.Use(loggerMiddleware)
+	// 5. Request IDs, tracing and structured request logging for audit trail, then response
+	// compression, so logged sizes are what was actually sent
+	r.Use(middleware.RequestID)
+	r.Use(tracing.Middleware)
+	r.Use(middleware.RequestLogger)
+	r.Use(middleware.Compress)
+	r.Use(middleware.Recover)
 
-	// 5. CORS - allow frontend to communicate
+	// 6. CORS - allow frontend to communicate. Credentials are allowed, so origins must be
+	// listed explicitly.
+	allowedOrigins := splitList(getEnv("ALLOWED_ORIGINS", "http://localhost:3000,http://localhost:5173"))
+	for _, origin := range allowedOrigins {
+		if strings.Contains(origin, "*") {
+			fatal("ALLOWED_ORIGINS must list origins explicitly, without wildcards", "origin", origin)
+		}
+	}
 	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"http://localhost:3000", "http://localhost:5173"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type"},
+		AllowedOrigins:   allowedOrigins,
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "Idempotency-Key", "X-CSRF-Token", "X-On-Behalf-Of"},
+		ExposedHeaders:   []string{"Idempotent-Replayed"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
 
 	// Routes
-	r.Get("/health", h.Health)
-	r.Handle("/uploads/*", http.StripPrefix("/uploads/", http.FileServer(http.Dir(uploadDir))))
+	r.Get("/healthz", h.Healthz)
+	r.Get("/readyz", h.Readyz)
+	r.Get("/health", h.Readyz) // Kept for existing monitors
+	r.Get("/uploads/*", h.ServeUpload)
 
 	r.Route("/api/v1", func(r chi.Router) {
 		// Public routes (no auth required)
 		r.Post("/auth/signup", h.Signup)
 		r.Post("/auth/login", h.Login)
+		r.Get("/openapi.json", h.OpenAPISpec)
+		r.Get("/shared/stats/{token}", h.GetSharedStats)
+		if getEnv("API_DOCS_ENABLED", "false") == "true" {
+			r.Get("/docs", h.APIDocs)
+		}
+
+		// Browser extension routes, which also accept extension keys with the route's scope
+		r.With(h.ExtensionAuth(handlers.ScopeJobsCapture), h.Audit, h.BlockImpersonatedWrites).Post("/jobs/capture", h.CaptureJob)
+		r.With(h.ExtensionAuth(handlers.ScopeApplicationsWrite), h.Audit, h.BlockImpersonatedWrites).Post("/applications/manual", h.RecordManualApplication)
+
+		// GraphQL, like the profile, job and application routes below, but outside
+		// BlockImpersonatedWrites: its queries are POSTs too, so it refuses mutations to
+		// read-only impersonation itself
+		r.Group(func(r chi.Router) {
+			r.Use(h.AuthMiddleware, h.Audit, h.ActOnBehalf)
+
+			gql := graph.NewHandler(stores)
+			r.Method(http.MethodGet, "/graphql", gql)
+			r.Method(http.MethodPost, "/graphql", gql)
+		})
 
-		// Protected routes (auth required)
+		// Protected routes (auth required). Writes, and everything done while impersonating,
+		// are recorded in the audit log, including writes refused to read-only impersonation.
 		r.Group(func(r chi.Router) {
-			r.Use(handlers.AuthMiddleware)
+			r.Use(h.AuthMiddleware)
+			r.Use(h.Audit)
+			r.Use(h.BlockImpersonatedWrites)
 
 			r.Get("/auth/me", h.GetMe)
-			r.Put("/auth/password", h.ChangePassword)
-			r.Put("/auth/email", h.UpdateEmail)
-			r.Post("/profile", h.CreateProfile)
-			r.Get("/profile", h.GetProfile)
-			r.Delete("/profile", h.DeleteProfile)
-			r.Get("/profile/validate", h.ValidateProfile)
-			r.Post("/profile/resume", h.UploadResume)
-			r.Post("/scrape", h.ScrapeJobs)
-			r.Get("/jobs", h.GetJobs)
+			r.Get("/auth/csrf", h.GetCSRFToken)
+			r.Post("/auth/logout", h.Logout)
+			r.With(h.NoImpersonation).Put("/auth/password", h.ChangePassword)
+			r.With(h.NoImpersonation).Put("/auth/email", h.UpdateEmail)
+			r.With(h.NoImpersonation).Delete("/profile", h.DeleteProfile)
+			r.Get("/flags", h.GetMyFlags)
+			r.Get("/organizations", h.ListOrganizations)
+			r.Post("/organizations", h.CreateOrganization)
+			r.Get("/organizations/{id}/members", h.ListOrganizationMembers)
+			r.Post("/organizations/{id}/members", h.AddOrganizationMember)
+			r.Delete("/organizations/{id}/members/{userId}", h.RemoveOrganizationMember)
+			r.With(h.NoImpersonation).Put("/organizations/{id}/consent", h.GrantOrganizationConsent)
+			r.With(h.NoImpersonation).Delete("/organizations/{id}/consent", h.RevokeOrganizationConsent)
+			r.With(h.NoImpersonation).Get("/extension-keys", h.ListExtensionKeys)
+			r.With(h.NoImpersonation).Post("/extension-keys", h.CreateExtensionKey)
+			r.With(h.NoImpersonation).Delete("/extension-keys/{id}", h.DeleteExtensionKey)
+			r.Get("/stats-shares", h.ListStatsShares)
+			r.With(h.NoImpersonation).Post("/stats-shares", h.CreateStatsShare)
+			r.With(h.NoImpersonation).Delete("/stats-shares/{id}", h.DeleteStatsShare)
+			r.Get("/notifications", h.GetNotifications)
+			r.Get("/notifications/count", h.GetNotificationCounts)
+			r.Post("/notifications/read-all", h.MarkAllNotificationsRead)
+			r.Post("/notifications/{id}/read", h.MarkNotificationRead)
+
+			// Profile, job and application data, which coaches can also reach for consenting
+			// clients with X-On-Behalf-Of; account settings above stay the user's own
+			r.Group(func(r chi.Router) {
+				r.Use(h.ActOnBehalf)
+
+				r.Post("/profile", h.CreateProfile)
+				r.Get("/profile", h.GetProfile)
+				r.Get("/profile/validate", h.ValidateProfile)
+				r.Get("/profile/privacy", h.GetAutofillPrivacy)
+				r.Put("/profile/privacy", h.UpdateAutofillPrivacy)
+				r.Get("/profile/blocklist", h.GetJobBlocklist)
+				r.Put("/profile/blocklist", h.UpdateJobBlocklist)
+				r.Post("/profile/work-history", h.AddWorkHistory)
+				r.Put("/profile/work-history/{idx}", h.UpdateWorkHistory)
+				r.Delete("/profile/work-history/{idx}", h.DeleteWorkHistory)
+				r.Post("/profile/education", h.AddEducation)
+				r.Put("/profile/education/{idx}", h.UpdateEducation)
+				r.Delete("/profile/education/{idx}", h.DeleteEducation)
+				r.Post("/profile/resume", h.UploadResume)
+				r.Post("/profile/resume/parse", h.ParseResume)
+				r.Get("/profile/resume/diff", h.ReviewResume)
+				r.Post("/profile/resume/confirm", h.ConfirmProfileMerge)
+				r.Get("/profile/resume/generate", h.GenerateResume)
+				r.Post("/profile/import/linkedin", h.ImportLinkedIn)
+				r.Post("/profile/import/confirm", h.ConfirmProfileMerge)
+				r.Get("/profiles", h.ListPersonas)
+				r.Post("/profiles", h.CreatePersona)
+				r.Get("/profiles/{id}", h.GetPersona)
+				r.Put("/profiles/{id}", h.UpdatePersona)
+				r.Delete("/profiles/{id}", h.DeletePersona)
+				r.Post("/profiles/{id}/restore", h.RestorePersona)
+				r.Put("/profiles/{id}/default", h.SetDefaultPersona)
+				r.Post("/profiles/{id}/resume", h.UploadPersonaResume)
+				r.Get("/uploads/{key}", h.GetUpload)
+				r.Get("/uploads/{key}/signed-url", h.SignUploadURL)
+				r.Get("/skills/suggest", h.SuggestSkills)
+				r.Get("/tags", h.ListTags)
+				r.Post("/tags", h.CreateTag)
+				r.Put("/tags/{id}", h.UpdateTag)
+				r.Delete("/tags/{id}", h.DeleteTag)
+				r.Get("/applications", h.GetApplications)
+				r.With(h.Idempotent).Post("/applications", h.CreateApplication)
+				r.Patch("/applications/{id}/status", h.UpdateApplicationStatus)
+				r.Post("/applications/import", h.ImportApplications)
+				r.Delete("/applications/{id}", h.DeleteApplication)
+				r.Post("/applications/{id}/restore", h.RestoreApplication)
+				r.Get("/applications/{id}/events", h.GetApplicationEvents)
+				r.Get("/applications/{id}/questions", h.GetApplicationQuestions)
+				r.Get("/applications/{id}/snapshot", h.GetApplicationSnapshot)
+				r.Get("/application-templates", h.ListApplicationTemplates)
+				r.Post("/application-templates", h.CreateApplicationTemplate)
+				r.Put("/application-templates/{id}", h.UpdateApplicationTemplate)
+				r.Delete("/application-templates/{id}", h.DeleteApplicationTemplate)
+				r.Put("/applications/{id}/tags/{tagId}", h.TagApplication)
+				r.Delete("/applications/{id}/tags/{tagId}", h.UntagApplication)
+				r.With(h.Idempotent).Post("/scrape", h.ScrapeJobs)
+				r.Get("/jobs", h.GetJobs)
+				r.Get("/jobs/saved", h.GetSavedJobs)
+				r.Get("/jobs/recommended", h.GetRecommendedJobs)
+				r.Get("/jobs/{id}", h.GetJob)
+				r.Post("/jobs/{id}/match", h.MatchJob)
+				r.Post("/jobs/{id}/dismiss", h.DismissJob)
+				r.Delete("/jobs/{id}/dismiss", h.UndismissJob)
+				r.Get("/companies/{id}", h.GetCompany)
+				r.Put("/companies/{id}/notes", h.UpdateCompanyNotes)
+				r.Put("/jobs/{id}/tags/{tagId}", h.TagJob)
+				r.Delete("/jobs/{id}/tags/{tagId}", h.UntagJob)
+				r.Post("/jobs/{id}/save", h.SaveJob)
+				r.Delete("/jobs/{id}/save", h.UnsaveJob)
+			})
+
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(h.RequireAdmin)
+
+				r.Get("/tasks", h.ListTasks)
+				r.Get("/tasks/{name}/runs", h.ListTaskRuns)
+				r.Post("/tasks/{name}/run", h.RunTask)
+				r.Get("/flags", h.ListFlags)
+				r.Get("/flags/{name}", h.GetFlag)
+				r.Put("/flags/{name}", h.PutFlag)
+				r.Delete("/flags/{name}", h.DeleteFlag)
+				r.Delete("/scrape/cache", h.InvalidateScrapeCache)
+				r.Get("/scrape-rejects", h.ListScrapeRejects)
+				r.Post("/companies/{id}/aliases", h.AddCompanyAlias)
+				r.Delete("/companies/{id}/aliases", h.DeleteCompanyAlias)
+				r.Get("/apply-health", h.GetApplyHealth)
+				r.Get("/outbox/dead", h.ListDeadLetters)
+				r.Post("/outbox/{id}/retry", h.RetryDeadLetter)
+				r.Get("/retention/report", h.GetRetentionReport)
+				r.Get("/audit", h.ListAuditLog)
+				r.Post("/impersonate/{user_id}", h.Impersonate)
+			})
 		})
 	})
 
+	if missing := handlers.UndocumentedRoutes(r); len(missing) > 0 {
+		slog.Warn("Routes missing from the OpenAPI spec", "routes", missing)
+	}
+
 	// Start server
 	srv := &http.Server{
 		Addr:         ":" + port,
@@ -105,25 +583,72 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
-	// Graceful shutdown
+	// Optional gRPC API on its own port, for non-browser clients
+	var grpcServer *grpc.Server
+	if grpcPort := os.Getenv("GRPC_PORT"); grpcPort != "" {
+		lis, err := net.Listen("tcp", ":"+grpcPort)
+		if err != nil {
+			fatal("Failed to listen for gRPC", "error", err)
+		}
+		grpcServer = grpcapi.NewServer(stores, h)
+		go func() {
+			slog.Info("gRPC server starting", "port", grpcPort)
+			if err := grpcServer.Serve(lis); err != nil {
+				slog.Error("gRPC server failed", "error", err)
+			}
+		}()
+	}
+
+	shutdownTimeout, err := time.ParseDuration(getEnv("SHUTDOWN_TIMEOUT", "30s"))
+	if err != nil {
+		fatal("Invalid SHUTDOWN_TIMEOUT", "error", err)
+	}
+
+	// Graceful shutdown, in order: cancel scheduled tasks, stop new scrapes and wait for
+	// in-flight ones and their background work, drain HTTP and gRPC requests, flush traces. The database pool closes last, when
+	// main returns.
+	shutdownDone := make(chan struct{})
 	go func() {
+		defer close(shutdownDone)
 		sigint := make(chan os.Signal, 1)
 		signal.Notify(sigint, os.Interrupt, syscall.SIGTERM)
 		<-sigint
+		slog.Info("Shutting down", "timeout", shutdownTimeout)
 
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 		defer cancel()
 
+		if err := scheduler.Stop(ctx); err != nil {
+			slog.Warn("Scheduled tasks did not stop in time", "error", err)
+		}
+		if err := h.Drain(ctx); err != nil {
+			slog.Warn("Cancelled in-flight work at shutdown", "error", err)
+		}
 		if err := srv.Shutdown(ctx); err != nil {
-			log.Printf("Server shutdown error: %v", err)
+			slog.Error("Server shutdown error", "error", err)
+		}
+		if err := outbox.Stop(ctx); err != nil {
+			slog.Warn("Outbox deliveries did not finish in time", "error", err)
+		}
+		if redisBus != nil {
+			if err := redisBus.Stop(ctx); err != nil {
+				slog.Warn("Event deliveries did not finish in time", "error", err)
+			}
+		}
+		if grpcServer != nil {
+			stopGRPC(ctx, grpcServer)
+		}
+		if traceExporter != nil {
+			traceExporter.Shutdown(ctx)
 		}
 	}()
 
-	log.Printf("Server starting on port %s", port)
+	slog.Info("Server starting", "port", port)
 	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatalf("Server failed: %v", err)
+		fatal("Server failed", "error", err)
 	}
-	log.Println("Server stopped")
+	<-shutdownDone
+	slog.Info("Server stopped")
 }
 
 func getEnv(key, defaultValue string) string {
@@ -133,11 +658,108 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
-// Simple logging middleware
-func loggerMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
-		next.ServeHTTP(w, r)
-		log.Printf("%s %s %s", r.Method, r.URL.Path, time.Since(start))
-	})
+// stopGRPC lets in-flight gRPC calls finish, closing connections outright once ctx ends
+func stopGRPC(ctx context.Context, srv *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		srv.Stop()
+	}
+}
+
+// parseInterval reads a duration setting, exiting if it is invalid
+func parseInterval(key, defaultValue string) time.Duration {
+	interval, err := time.ParseDuration(getEnv(key, defaultValue))
+	if err != nil || interval < 0 {
+		fatal("Invalid "+key, "value", os.Getenv(key))
+	}
+	return interval
+}
+
+// parseCount reads an optional non-negative count, 0 if unset
+func parseCount(key string) int {
+	n, err := strconv.Atoi(getEnv(key, "0"))
+	if err != nil || n < 0 || n > math.MaxInt32 {
+		fatal("Invalid "+key, "value", os.Getenv(key))
+	}
+	return n
+}
+
+// parseCacheTTLs reads SCRAPE_CACHE_TTL and SCRAPE_CACHE_EXPIRY, each a default duration
+// optionally followed by per-source overrides, e.g. "12h,muse=6h". A source overriding one
+// setting keeps the default for the other.
+func parseCacheTTLs() (services.CacheTTL, map[string]services.CacheTTL) {
+	fresh, freshBySource := parseSourceDurations("SCRAPE_CACHE_TTL", services.DefaultCacheTTL.Fresh)
+	expire, expireBySource := parseSourceDurations("SCRAPE_CACHE_EXPIRY", services.DefaultCacheTTL.Expire)
+	defaults := services.CacheTTL{Fresh: fresh, Expire: expire}
+	sources := make(map[string]services.CacheTTL)
+	for source, d := range freshBySource {
+		ttl := defaults
+		ttl.Fresh = d
+		sources[source] = ttl
+	}
+	for source, d := range expireBySource {
+		ttl, ok := sources[source]
+		if !ok {
+			ttl = defaults
+		}
+		ttl.Expire = d
+		sources[source] = ttl
+	}
+	return defaults, sources
+}
+
+// parseSourceDurations reads "default,source=duration,..." where every part is optional,
+// exiting if a duration is invalid
+func parseSourceDurations(key string, defaultValue time.Duration) (time.Duration, map[string]time.Duration) {
+	bySource := make(map[string]time.Duration)
+	for _, item := range splitList(os.Getenv(key)) {
+		source, value, ok := strings.Cut(item, "=")
+		if !ok {
+			source, value = "", item
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || d <= 0 {
+			fatal("Invalid "+key, "value", os.Getenv(key))
+		}
+		if source = strings.TrimSpace(source); source == "" {
+			defaultValue = d
+		} else {
+			bySource[source] = d
+		}
+	}
+	return defaultValue, bySource
+}
+
+// splitList reads a comma-separated list, dropping blank entries
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseHeaders reads "key1=value1,key2=value2" as used by OTEL_EXPORTER_OTLP_HEADERS
+func parseHeaders(s string) map[string]string {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		if key, value, ok := strings.Cut(pair, "="); ok && strings.TrimSpace(key) != "" {
+			headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return headers
+}
+
+// fatal logs a startup error and exits
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
 }
@@ -0,0 +1,334 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/jobapply/internal/models"
+)
+
+func newLoginCommand(a *app) *cobra.Command {
+	var email string
+	var passwordStdin bool
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Log in and remember the token",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLogin(cmd.Context(), a, email, passwordStdin)
+		},
+	}
+	cmd.Flags().StringVar(&email, "email", "", "account email (prompted for if omitted)")
+	cmd.Flags().BoolVar(&passwordStdin, "password-stdin", false, "read the password from stdin without prompting")
+	return cmd
+}
+
+func runLogin(ctx context.Context, a *app, email string, passwordStdin bool) error {
+	in := bufio.NewReader(os.Stdin)
+	if email == "" {
+		fmt.Fprint(os.Stderr, "Email: ")
+		line, err := readLine(in)
+		if err != nil {
+			return err
+		}
+		email = line
+	}
+	var password string
+	var err error
+	if passwordStdin {
+		password, err = readLine(in)
+	} else {
+		password, err = readPassword(in)
+	}
+	if err != nil {
+		return err
+	}
+
+	var resp struct {
+		Token string `json:"token"`
+		Name  string `json:"name"`
+	}
+	body := map[string]string{"email": email, "password": password}
+	if err := newClient(a.server, "").do(ctx, http.MethodPost, "/auth/login", body, &resp); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(a.tokenPath), 0o700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(a.tokenPath, []byte(resp.Token+"\n"), 0o600); err != nil {
+		return fmt.Errorf("failed to save token: %w", err)
+	}
+	fmt.Printf("Logged in as %s\n", resp.Name)
+	return nil
+}
+
+func newLogoutCommand(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "logout",
+		Short: "Forget the saved token",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := os.Remove(a.tokenPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+			fmt.Println("Logged out")
+			return nil
+		},
+	}
+}
+
+func newWhoamiCommand(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "whoami",
+		Short: "Show the logged-in user",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var profile models.UserProfile
+			if err := a.client().get(cmd.Context(), "/auth/me", nil, &profile); err != nil {
+				return err
+			}
+			fmt.Printf("%s <%s>\n", profile.FullName, profile.Email)
+			return nil
+		},
+	}
+}
+
+// scrapeOptions are the scrape command's flags
+type scrapeOptions struct {
+	keywords, location, profileID string
+}
+
+func newScrapeCommand(a *app) *cobra.Command {
+	var opts scrapeOptions
+	cmd := &cobra.Command{
+		Use:   "scrape",
+		Short: "Search job boards for new jobs",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runScrape(cmd.Context(), a, opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.keywords, "keywords", "", "search keywords (default: the profile's target keywords)")
+	cmd.Flags().StringVar(&opts.location, "location", "", "job location, or Remote")
+	cmd.Flags().StringVar(&opts.profileID, "profile", "", "ID of the persona to search for")
+	return cmd
+}
+
+func runScrape(ctx context.Context, a *app, opts scrapeOptions) error {
+	var resp struct {
+		JobsScraped  int  `json:"jobs_scraped"`
+		JobsInserted int  `json:"jobs_inserted"`
+		FromCache    bool `json:"from_cache"`
+	}
+	body := map[string]string{"keywords": opts.keywords, "location": opts.location, "profile_id": opts.profileID}
+	if err := a.client().do(ctx, http.MethodPost, "/scrape", body, &resp); err != nil {
+		return err
+	}
+	if resp.FromCache {
+		fmt.Printf("%d jobs found (cached search)\n", resp.JobsScraped)
+	} else {
+		fmt.Printf("%d jobs found, %d new\n", resp.JobsScraped, resp.JobsInserted)
+	}
+	return nil
+}
+
+// jobsOptions are the jobs command's flags
+type jobsOptions struct {
+	search, location, status, tag, sort string
+	limit                               int
+	json                                bool
+}
+
+func newJobsCommand(a *app) *cobra.Command {
+	var opts jobsOptions
+	cmd := &cobra.Command{
+		Use:   "jobs",
+		Short: "List jobs found by your searches",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runJobs(cmd.Context(), a, opts)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.search, "query", "q", "", "full-text search")
+	cmd.Flags().StringVar(&opts.location, "location", "", "location filter")
+	cmd.Flags().StringVar(&opts.status, "status", "", "open or closed")
+	cmd.Flags().StringVar(&opts.tag, "tag", "", "only jobs with this tag")
+	cmd.Flags().StringVar(&opts.sort, "sort", "", "recent, match or salary")
+	cmd.Flags().IntVar(&opts.limit, "limit", 20, "number of jobs to list")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "print JSON instead of a table")
+	return cmd
+}
+
+func runJobs(ctx context.Context, a *app, opts jobsOptions) error {
+	query := url.Values{}
+	for key, value := range map[string]string{"q": opts.search, "location": opts.location, "status": opts.status, "tag": opts.tag, "sort": opts.sort} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	query.Set("limit", strconv.Itoa(opts.limit))
+
+	var resp struct {
+		Jobs  []models.JobListing `json:"jobs"`
+		Total int                 `json:"total"`
+	}
+	if err := a.client().get(ctx, "/jobs", query, &resp); err != nil {
+		return err
+	}
+	if opts.json {
+		return printJSON(resp.Jobs)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTITLE\tCOMPANY\tLOCATION\tSTATUS")
+	for _, job := range resp.Jobs {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", job.ID, truncate(job.Title, 50), truncate(job.Company, 30), truncate(job.Location, 30), job.Status)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("\nShowing %d of %d jobs\n", len(resp.Jobs), resp.Total)
+	return nil
+}
+
+// applicationsOptions are the applications command's flags
+type applicationsOptions struct {
+	format, output, tag string
+}
+
+func newApplicationsCommand(a *app) *cobra.Command {
+	var opts applicationsOptions
+	cmd := &cobra.Command{
+		Use:   "applications",
+		Short: "List or export your applications",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runApplications(cmd.Context(), a, opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.format, "format", "table", "table, csv or json")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "write to this file instead of stdout")
+	cmd.Flags().StringVar(&opts.tag, "tag", "", "only applications with this tag")
+	return cmd
+}
+
+func runApplications(ctx context.Context, a *app, opts applicationsOptions) error {
+	switch opts.format {
+	case "table", "csv", "json":
+	default:
+		return fmt.Errorf("unknown format %q", opts.format)
+	}
+
+	query := url.Values{}
+	if opts.tag != "" {
+		query.Set("tag", opts.tag)
+	}
+	var applications []models.Application
+	if err := a.client().get(ctx, "/applications", query, &applications); err != nil {
+		return err
+	}
+
+	out := io.Writer(os.Stdout)
+	if opts.output != "" {
+		f, err := os.Create(opts.output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	switch opts.format {
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(applications)
+	case "csv":
+		return writeApplicationsCSV(out, applications)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "APPLIED\tSTATUS\tTITLE\tCOMPANY\tTAGS")
+	for _, app := range applications {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", app.AppliedAt.Format("2006-01-02"), app.Status,
+			truncate(app.JobTitle, 50), truncate(app.Company, 30), strings.Join(app.Tags, ", "))
+	}
+	return w.Flush()
+}
+
+func writeApplicationsCSV(out io.Writer, applications []models.Application) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{"id", "applied_at", "status", "job_title", "company", "job_url", "tags", "fields_filled"})
+	for _, app := range applications {
+		w.Write([]string{
+			app.ID,
+			app.AppliedAt.Format("2006-01-02T15:04:05Z07:00"),
+			app.Status,
+			app.JobTitle,
+			app.Company,
+			app.JobURL,
+			strings.Join(app.Tags, ";"),
+			strings.Join(app.FieldsFilled, ";"),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// truncate shortens s to at most n runes for table output
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+func readLine(in *bufio.Reader) (string, error) {
+	line, err := in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// readPassword prompts for a password, hiding it as it is typed where stty is available
+func readPassword(in *bufio.Reader) (string, error) {
+	fmt.Fprint(os.Stderr, "Password: ")
+	if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		if stty("-echo") == nil {
+			defer func() {
+				stty("echo")
+				fmt.Fprintln(os.Stderr)
+			}()
+		}
+	}
+	return readLine(in)
+}
+
+func stty(arg string) error {
+	cmd := exec.Command("stty", arg)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
@@ -0,0 +1,80 @@
+// Command jobctl is a command-line client for the Job Apply API, for scripts and people who
+// would rather not use the web frontend.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// app is the configuration shared by commands
+type app struct {
+	server    string
+	tokenPath string
+}
+
+// client returns an API client, authenticated if the user has logged in
+func (a *app) client() *client {
+	token := os.Getenv("JOBCTL_TOKEN")
+	if token == "" {
+		if data, err := os.ReadFile(a.tokenPath); err == nil {
+			token = strings.TrimSpace(string(data))
+		}
+	}
+	return newClient(a.server, token)
+}
+
+// newRootCommand builds the jobctl command tree around a
+func newRootCommand(a *app) *cobra.Command {
+	root := &cobra.Command{
+		Use:   "jobctl",
+		Short: "Command-line client for the Job Apply API",
+		Long: `jobctl talks to the Job Apply API.
+
+The server defaults to $JOBCTL_SERVER or http://localhost:8080. The token is read from
+$JOBCTL_TOKEN, or from the file written by login.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	server := os.Getenv("JOBCTL_SERVER")
+	if server == "" {
+		server = "http://localhost:8080"
+	}
+	root.PersistentFlags().StringVar(&a.server, "server", server, "API server URL")
+
+	root.AddCommand(
+		newLoginCommand(a),
+		newLogoutCommand(a),
+		newWhoamiCommand(a),
+		newScrapeCommand(a),
+		newJobsCommand(a),
+		newApplicationsCommand(a),
+	)
+	return root
+}
+
+func main() {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = "."
+	}
+	a := &app{tokenPath: filepath.Join(configDir, "jobctl", "token")}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := newRootCommand(a).ExecuteContext(ctx); err != nil {
+		var apiErr *apiError
+		if errors.As(err, &apiErr) && apiErr.status == 401 {
+			err = fmt.Errorf("%w (run \"jobctl login\")", err)
+		}
+		fmt.Fprintln(os.Stderr, "jobctl:", err)
+		os.Exit(1)
+	}
+}
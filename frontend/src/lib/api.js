@@ -17,7 +17,7 @@ export async function signup(fullName, email, password) {
   });
   if (!response.ok) {
     const error = await response.json();
-    throw new Error(error.error || 'Failed to create account');
+    throw new Error(error.error?.message || 'Failed to create account');
   }
   return response.json();
 }
@@ -30,7 +30,7 @@ export async function login(email, password) {
   });
   if (!response.ok) {
     const error = await response.json();
-    throw new Error(error.error || 'Failed to login');
+    throw new Error(error.error?.message || 'Failed to login');
   }
   return response.json();
 }
@@ -41,7 +41,7 @@ export async function getMe() {
   });
   if (!response.ok) {
     const error = await response.json();
-    throw new Error(error.error || 'Failed to get user info');
+    throw new Error(error.error?.message || 'Failed to get user info');
   }
   return response.json();
 }
@@ -54,7 +54,7 @@ export async function createProfile(profileData) {
   });
   if (!response.ok) {
     const error = await response.json();
-    throw new Error(error.error || 'Failed to update profile');
+    throw new Error(error.error?.message || 'Failed to update profile');
   }
   return response.json();
 }
@@ -73,18 +73,32 @@ export async function uploadResume(file, shouldParse = false) {
   });
   if (!response.ok) {
     const error = await response.json();
-    throw new Error(error.error || 'Failed to upload resume');
+    throw new Error(error.error?.message || 'Failed to upload resume');
   }
   return response.json();
 }
 
+// Uploads are private; this returns a short-lived link that can be opened in a new tab
+export async function getResumeLink(resumeUrl) {
+  const key = resumeUrl.split('/').pop();
+  const response = await fetch(`${API_BASE}/uploads/${encodeURIComponent(key)}/signed-url`, {
+    headers: getAuthHeaders()
+  });
+  if (!response.ok) {
+    const error = await response.json();
+    throw new Error(error.error?.message || 'Failed to get resume link');
+  }
+  const data = await response.json();
+  return data.url;
+}
+
 export async function validateProfile() {
   const response = await fetch(`${API_BASE}/profile/validate`, {
     headers: getAuthHeaders()
   });
   if (!response.ok) {
     const error = await response.json();
-    throw new Error(error.error || 'Failed to validate profile');
+    throw new Error(error.error?.message || 'Failed to validate profile');
   }
   return response.json();
 }
@@ -95,7 +109,7 @@ export async function getProfile() {
   });
   if (!response.ok) {
     const error = await response.json();
-    throw new Error(error.error || 'Failed to get profile');
+    throw new Error(error.error?.message || 'Failed to get profile');
   }
   return response.json();
 }
@@ -107,7 +121,7 @@ export async function deleteProfile() {
   });
   if (!response.ok) {
     const error = await response.json();
-    throw new Error(error.error || 'Failed to delete profile');
+    throw new Error(error.error?.message || 'Failed to delete profile');
   }
   return response.json();
 }
@@ -120,7 +134,7 @@ export async function changePassword(currentPassword, newPassword) {
   });
   if (!response.ok) {
     const error = await response.json();
-    throw new Error(error.error || 'Failed to change password');
+    throw new Error(error.error?.message || 'Failed to change password');
   }
   return response.json();
 }
@@ -133,7 +147,7 @@ export async function updateEmail(newEmail) {
   });
   if (!response.ok) {
     const error = await response.json();
-    throw new Error(error.error || 'Failed to update email');
+    throw new Error(error.error?.message || 'Failed to update email');
   }
   return response.json();
 }
@@ -146,18 +160,34 @@ export async function scrapeJobs(keywords, location) {
   });
   if (!response.ok) {
     const error = await response.json();
-    throw new Error(error.error || 'Failed to scrape jobs');
+    throw new Error(error.error?.message || 'Failed to scrape jobs');
+  }
+  return response.json();
+}
+
+// params: q, location, site, posted_since, remote, salary_min, salary_max, within_miles, limit, cursor
+export async function getJobs(params = {}) {
+  const query = new URLSearchParams(
+    Object.entries(params).filter(([, v]) => v !== undefined && v !== null && v !== '')
+  ).toString();
+  const response = await fetch(`${API_BASE}/jobs${query ? `?${query}` : ''}`, {
+    headers: getAuthHeaders()
+  });
+  if (!response.ok) {
+    const error = await response.json();
+    throw new Error(error.error?.message || 'Failed to get jobs');
   }
   return response.json();
 }
 
-export async function getJobs() {
-  const response = await fetch(`${API_BASE}/jobs`, {
+export async function dismissJob(jobId) {
+  const response = await fetch(`${API_BASE}/jobs/${jobId}/dismiss`, {
+    method: 'POST',
     headers: getAuthHeaders()
   });
   if (!response.ok) {
     const error = await response.json();
-    throw new Error(error.error || 'Failed to get jobs');
+    throw new Error(error.error?.message || 'Failed to dismiss job');
   }
   return response.json();
 }
@@ -170,7 +200,7 @@ export async function applyToJob(jobId) {
   });
   if (!response.ok) {
     const error = await response.json();
-    throw new Error(error.error || 'Failed to apply to job');
+    throw new Error(error.error?.message || 'Failed to apply to job');
   }
   return response.json();
 }
@@ -181,7 +211,7 @@ export async function getApplications() {
   });
   if (!response.ok) {
     const error = await response.json();
-    throw new Error(error.error || 'Failed to get applications');
+    throw new Error(error.error?.message || 'Failed to get applications');
   }
   return response.json();
 }
@@ -0,0 +1,172 @@
+// Package apierror defines the envelope every API error is sent in. It also maps internal
+// errors onto that envelope, so causes such as SQL errors are logged rather than leaked to
+// clients:
+//
+//	{"error": {"code": "validation_failed", "message": "...", "fields": [...], "request_id": "..."}}
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/yourusername/jobapply/internal/store"
+	"github.com/yourusername/jobapply/internal/validation"
+)
+
+// Code identifies the kind of failure for clients to branch on; messages are for people
+type Code string
+
+const (
+	BadRequest           Code = "bad_request"
+	ValidationFailed     Code = "validation_failed"
+	Unauthorized         Code = "unauthorized"
+	Forbidden            Code = "forbidden"
+	NotFound             Code = "not_found"
+	Conflict             Code = "conflict"
+	PayloadTooLarge      Code = "payload_too_large"
+	UnsupportedMediaType Code = "unsupported_media_type"
+	RateLimited          Code = "rate_limited"
+	Internal             Code = "internal_error"
+	Upstream             Code = "upstream_error" // A job board or other third party failed
+	Unavailable          Code = "unavailable"
+)
+
+// CodeFor returns the default code for an HTTP status
+func CodeFor(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return BadRequest
+	case http.StatusUnauthorized:
+		return Unauthorized
+	case http.StatusForbidden:
+		return Forbidden
+	case http.StatusNotFound:
+		return NotFound
+	case http.StatusConflict:
+		return Conflict
+	case http.StatusRequestEntityTooLarge:
+		return PayloadTooLarge
+	case http.StatusUnsupportedMediaType:
+		return UnsupportedMediaType
+	case http.StatusUnprocessableEntity:
+		return ValidationFailed
+	case http.StatusTooManyRequests:
+		return RateLimited
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return Upstream
+	case http.StatusServiceUnavailable:
+		return Unavailable
+	}
+	if status >= 500 {
+		return Internal
+	}
+	return BadRequest
+}
+
+// FieldError points a validation failure at one request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is a failure with a message that is safe to show clients. Cause is only logged.
+type Error struct {
+	Status  int
+	Code    Code
+	Message string
+	Fields  []FieldError
+	Cause   error
+}
+
+func (e *Error) Error() string {
+	if e.Cause != nil {
+		return e.Message + ": " + e.Cause.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// New returns an error with the default code for status
+func New(status int, message string) *Error {
+	return &Error{Status: status, Code: CodeFor(status), Message: message}
+}
+
+// Wrap returns an error that shows message to the client and keeps cause for the logs
+func Wrap(cause error, status int, message string) *Error {
+	return &Error{Status: status, Code: CodeFor(status), Message: message, Cause: cause}
+}
+
+// Invalid reports a rejected request using err's message. Validation errors that name
+// fields are also listed in Fields.
+func Invalid(err error) *Error {
+	e := &Error{Status: http.StatusBadRequest, Code: BadRequest, Message: err.Error()}
+	var fieldErrs validation.Errors
+	var fieldErr *validation.FieldError
+	switch {
+	case errors.As(err, &fieldErrs):
+		e.Code = ValidationFailed
+		for _, f := range fieldErrs {
+			e.Fields = append(e.Fields, FieldError{Field: f.Field, Message: f.Message})
+		}
+	case errors.As(err, &fieldErr):
+		e.Code = ValidationFailed
+		e.Fields = []FieldError{{Field: fieldErr.Field, Message: fieldErr.Message}}
+	}
+	return e
+}
+
+// From maps any error onto the taxonomy. Errors it doesn't recognize become a generic
+// internal error, keeping the original as the cause.
+func From(err error) *Error {
+	var apiErr *Error
+	var fieldErrs validation.Errors
+	var fieldErr *validation.FieldError
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &apiErr):
+		return apiErr
+	case errors.As(err, &fieldErrs), errors.As(err, &fieldErr):
+		return Invalid(err)
+	case errors.As(err, &tooLarge):
+		return Wrap(err, http.StatusRequestEntityTooLarge, "Request body too large")
+	case errors.Is(err, store.ErrNotFound):
+		return Wrap(err, http.StatusNotFound, "Not found")
+	case errors.Is(err, store.ErrConflict):
+		return Wrap(err, http.StatusConflict, "Conflicts with an existing record")
+	case errors.Is(err, store.ErrInvalidTransition):
+		return Wrap(err, http.StatusConflict, err.Error())
+	}
+	return Wrap(err, http.StatusInternalServerError, "Internal server error")
+}
+
+// Body is the content of the envelope
+type Body struct {
+	Code      Code         `json:"code"`
+	Message   string       `json:"message"`
+	Fields    []FieldError `json:"fields,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+}
+
+// Response is the envelope every API error is sent in
+type Response struct {
+	Error Body `json:"error"`
+}
+
+// requestIDHeader is set on the response by middleware.RequestID before handlers run
+const requestIDHeader = "X-Request-ID"
+
+// Write sends e in the envelope
+func Write(w http.ResponseWriter, e *Error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.Status)
+	json.NewEncoder(w).Encode(Response{Error: Body{
+		Code:      e.Code,
+		Message:   e.Message,
+		Fields:    e.Fields,
+		RequestID: w.Header().Get(requestIDHeader),
+	}})
+}
@@ -0,0 +1,126 @@
+// Package audit collects what a request changed, for the audit log. The API's Audit
+// middleware starts an entry for each write request; stores record their changes into it.
+// Outside an audited request, recording does nothing.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync"
+)
+
+// Redacted replaces the values of sensitive fields, such as those encrypted at rest
+const Redacted = "[redacted]"
+
+// Change is one record's changed fields, before and after
+type Change struct {
+	Entity string         `json:"entity"`
+	ID     string         `json:"id"`
+	Before map[string]any `json:"before,omitempty"`
+	After  map[string]any `json:"after,omitempty"`
+}
+
+// Entry gathers a request's changes
+type Entry struct {
+	mu      sync.Mutex
+	subject string
+	changes []Change
+}
+
+type contextKey struct{}
+
+// Start returns a context whose changes are gathered in the returned entry
+func Start(ctx context.Context) (context.Context, *Entry) {
+	e := &Entry{}
+	return context.WithValue(ctx, contextKey{}, e), e
+}
+
+func from(ctx context.Context) *Entry {
+	e, _ := ctx.Value(contextKey{}).(*Entry)
+	return e
+}
+
+// Enabled reports whether ctx is audited, so callers can skip reading state only the audit
+// log needs
+func Enabled(ctx context.Context) bool {
+	return from(ctx) != nil
+}
+
+// SetSubject records whose data the request acts on, when that isn't the authenticated user
+func SetSubject(ctx context.Context, userID string) {
+	if e := from(ctx); e != nil {
+		e.mu.Lock()
+		e.subject = userID
+		e.mu.Unlock()
+	}
+}
+
+// Record adds the fields that differ between before and after, which must marshal to JSON
+// objects. A nil before records a creation, a nil after a deletion. Fields named in redact
+// are listed with their values hidden; updated_at is left out.
+func Record(ctx context.Context, entity, id string, before, after any, redact ...string) {
+	e := from(ctx)
+	if e == nil {
+		return
+	}
+	b, a := fields(before), fields(after)
+	change := Change{Entity: entity, ID: id, Before: map[string]any{}, After: map[string]any{}}
+	for _, m := range []map[string]any{b, a} {
+		for k := range m {
+			if k == "updated_at" || reflect.DeepEqual(b[k], a[k]) {
+				continue
+			}
+			change.Before[k], change.After[k] = b[k], a[k]
+			for _, r := range redact {
+				if k == r {
+					change.Before[k], change.After[k] = Redacted, Redacted
+				}
+			}
+		}
+	}
+	if len(change.Before) == 0 {
+		return
+	}
+	if isNil(before) {
+		change.Before = nil
+	}
+	if isNil(after) {
+		change.After = nil
+	}
+
+	e.mu.Lock()
+	e.changes = append(e.changes, change)
+	e.mu.Unlock()
+}
+
+func isNil(v any) bool {
+	return v == nil || reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil()
+}
+
+// fields decodes v's JSON form into a map; nil and non-objects give an empty map
+func fields(v any) map[string]any {
+	m := map[string]any{}
+	if isNil(v) {
+		return m
+	}
+	data, err := json.Marshal(v)
+	if err == nil {
+		json.Unmarshal(data, &m)
+	}
+	return m
+}
+
+// Subject returns the user set with SetSubject, or ""
+func (e *Entry) Subject() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.subject
+}
+
+// Changes returns the changes recorded so far
+func (e *Entry) Changes() []Change {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]Change(nil), e.changes...)
+}
@@ -0,0 +1,94 @@
+package autofill
+
+import (
+	"fmt"
+
+	"github.com/yourusername/jobapply/internal/models"
+)
+
+// Profile fields that can be marked "never auto-fill"
+const (
+	FieldFullName    = "full_name"
+	FieldEmail       = "email"
+	FieldPhone       = "phone"
+	FieldAddress     = "address"
+	FieldWorkHistory = "work_history"
+	FieldEducation   = "education"
+	FieldResume      = "resume"
+	FieldSkills      = "skills"
+)
+
+var Fields = []string{
+	FieldFullName, FieldEmail, FieldPhone, FieldAddress,
+	FieldWorkHistory, FieldEducation, FieldResume, FieldSkills,
+}
+
+// ValidateFields rejects unknown field names and returns the list deduplicated in canonical order
+func ValidateFields(fields []string) ([]string, error) {
+	requested := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		if !isField(f) {
+			return nil, fmt.Errorf("unknown field %q", f)
+		}
+		requested[f] = true
+	}
+
+	result := []string{}
+	for _, f := range Fields {
+		if requested[f] {
+			result = append(result, f)
+		}
+	}
+	return result, nil
+}
+
+// Redact returns a copy of the profile with the never-autofill fields cleared, plus the
+// fields that were actually omitted so a paused application can list them for the user
+func Redact(profile *models.UserProfile, never []string) (*models.UserProfile, []string) {
+	redacted := *profile
+	omitted := []string{}
+
+	for _, f := range never {
+		var cleared bool
+		switch f {
+		case FieldFullName:
+			cleared = redacted.FullName != ""
+			redacted.FullName = ""
+		case FieldEmail:
+			cleared = redacted.Email != ""
+			redacted.Email = ""
+		case FieldPhone:
+			cleared = redacted.Phone != ""
+			redacted.Phone = ""
+		case FieldAddress:
+			cleared = redacted.Address != nil
+			redacted.Address = nil
+		case FieldWorkHistory:
+			cleared = len(redacted.WorkHistory) > 0
+			redacted.WorkHistory = nil
+		case FieldEducation:
+			cleared = len(redacted.Education) > 0
+			redacted.Education = nil
+		case FieldResume:
+			cleared = redacted.ResumeURL != nil
+			redacted.ResumeURL = nil
+		case FieldSkills:
+			cleared = len(redacted.Skills) > 0
+			redacted.Skills = nil
+		}
+		if cleared {
+			omitted = append(omitted, f)
+		}
+	}
+
+	return &redacted, omitted
+}
+
+func isField(f string) bool {
+	for _, known := range Fields {
+		if f == known {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package autofill
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/jobapply/internal/models"
+)
+
+// QuestionClass is the kind of answer a custom question on an application form asks for
+type QuestionClass string
+
+const (
+	QuestionExperience        QuestionClass = "years_of_experience"
+	QuestionSalary            QuestionClass = "salary_expectation"
+	QuestionWorkAuthorization QuestionClass = "work_authorization"
+	QuestionAvailability      QuestionClass = "availability"
+	QuestionEssay             QuestionClass = "essay"
+	QuestionOther             QuestionClass = "other"
+)
+
+// questionRules are tried in order, so the more specific classes come first. "How many years of
+// experience do you expect..." is about experience, not salary, hence experience before salary.
+var questionRules = []struct {
+	class   QuestionClass
+	pattern *regexp.Regexp
+}{
+	{QuestionWorkAuthorization, regexp.MustCompile(`\b(authori[sz]ed to work|work authori[sz]ation|right to work|legally (eligible|able|allowed)|eligible to work|visa|sponsorship|sponsor)\b`)},
+	{QuestionExperience, regexp.MustCompile(`\b(years? of (professional |relevant |work )?experience|how many years|years? (have you|of) (worked|using|working))\b`)},
+	{QuestionSalary, regexp.MustCompile(`\b(salary|compensation|pay (expectation|requirement|range)|expected pay|desired pay|rate expectation)s?\b`)},
+	{QuestionAvailability, regexp.MustCompile(`\b(start date|when (can|could|would) you (start|begin|join)|available to start|availability|notice period|earliest start)\b`)},
+	{QuestionEssay, regexp.MustCompile(`\b(why (do )?you want|why are you interested|tell us (about|why)|describe|explain|cover letter|what (interests|excites|motivates) you|anything else)\b`)},
+}
+
+// ClassifyQuestion places a question by its label, falling back to QuestionOther
+func ClassifyQuestion(label string) QuestionClass {
+	label = strings.ToLower(strings.Join(strings.Fields(label), " "))
+	for _, rule := range questionRules {
+		if rule.pattern.MatchString(label) {
+			return rule.class
+		}
+	}
+	return QuestionOther
+}
+
+// AutoAnswer answers a question of the given class from the profile, so the application
+// needn't pause for it. It reports false when the profile doesn't say; the profile should
+// already be redacted, so never-autofill fields aren't used.
+func AutoAnswer(class QuestionClass, profile *models.UserProfile, now time.Time) (string, bool) {
+	switch class {
+	case QuestionExperience:
+		years, ok := YearsOfExperience(profile.WorkHistory, now)
+		if !ok {
+			return "", false
+		}
+		return strconv.Itoa(years), true
+	case QuestionSalary:
+		if profile.DesiredSalary == nil || *profile.DesiredSalary <= 0 {
+			return "", false
+		}
+		return strconv.Itoa(*profile.DesiredSalary), true
+	}
+	// Work authorization and availability aren't on the profile, and essays need the user
+	return "", false
+}
+
+// YearsOfExperience totals the work history in whole years, counting overlapping jobs once.
+// Entries without a parseable start date are skipped; an empty end date means current. It
+// reports false if no entry could be dated.
+func YearsOfExperience(history []models.WorkHistory, now time.Time) (int, bool) {
+	type span struct{ start, end time.Time }
+	var spans []span
+	for _, w := range history {
+		start, err := time.Parse(time.DateOnly, w.StartDate)
+		if err != nil {
+			continue
+		}
+		end := now
+		if w.EndDate != "" {
+			if end, err = time.Parse(time.DateOnly, w.EndDate); err != nil {
+				continue
+			}
+		}
+		if end.After(start) {
+			spans = append(spans, span{start, end})
+		}
+	}
+	if len(spans) == 0 {
+		return 0, false
+	}
+
+	slices.SortFunc(spans, func(a, b span) int { return a.start.Compare(b.start) })
+	var total time.Duration
+	current := spans[0]
+	for _, s := range spans[1:] {
+		if s.start.After(current.end) {
+			total += current.end.Sub(current.start)
+			current = s
+		} else if s.end.After(current.end) {
+			current.end = s.end
+		}
+	}
+	total += current.end.Sub(current.start)
+	return int(total.Hours() / (24 * 365.25)), true
+}
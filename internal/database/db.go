@@ -4,16 +4,56 @@ import (
 	"context"
 	"embed"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yourusername/jobapply/internal/tracing"
 )
 
 //go:embed migrations/*.sql
 var migrationFS embed.FS
 
-// Connect creates a new database connection pool and runs migrations
-func Connect(ctx context.Context, databaseURL string) (*pgxpool.Pool, error) {
-	pool, err := pgxpool.New(ctx, databaseURL)
+// PoolConfig tunes the connection pool. Zero values keep pgx's defaults, or whatever the
+// database URL sets with pool_max_conns and friends.
+type PoolConfig struct {
+	MaxConns          int32
+	MinConns          int32
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+	// SlowQueryThreshold logs queries taking at least this long; zero disables the log
+	SlowQueryThreshold time.Duration
+}
+
+// Connect creates a new database connection pool. Migrations are applied separately, see Migrate.
+func Connect(ctx context.Context, databaseURL string, poolConfig PoolConfig) (*pgxpool.Pool, error) {
+	config, err := pgxpool.ParseConfig(databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database URL: %w", err)
+	}
+	if poolConfig.MaxConns > 0 {
+		config.MaxConns = poolConfig.MaxConns
+	}
+	if poolConfig.MinConns > 0 {
+		config.MinConns = poolConfig.MinConns
+	}
+	if config.MinConns > config.MaxConns {
+		return nil, fmt.Errorf("minimum connections (%d) exceed the maximum (%d)", config.MinConns, config.MaxConns)
+	}
+	if poolConfig.MaxConnLifetime > 0 {
+		config.MaxConnLifetime = poolConfig.MaxConnLifetime
+	}
+	if poolConfig.MaxConnIdleTime > 0 {
+		config.MaxConnIdleTime = poolConfig.MaxConnIdleTime
+	}
+	if poolConfig.HealthCheckPeriod > 0 {
+		config.HealthCheckPeriod = poolConfig.HealthCheckPeriod
+	}
+
+	// Records query spans when tracing is enabled; a no-op otherwise
+	config.ConnConfig.Tracer = slowQueryLogger{next: tracing.QueryTracer{}, threshold: poolConfig.SlowQueryThreshold}
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create connection pool: %w", err)
 	}
@@ -24,33 +64,14 @@ func Connect(ctx context.Context, databaseURL string) (*pgxpool.Pool, error) {
 		return nil, fmt.Errorf("unable to ping database: %w", err)
 	}
 
-	// Run migrations
-	migrations := []string{
-		"migrations/001_initial_schema.up.sql",
-		"migrations/002_add_location_to_jobs.up.sql",
-		"migrations/003_add_authentication.up.sql",
-		"migrations/004_application_state.up.sql",
-	}
-
-	for _, migration := range migrations {
-		upSQL, err := migrationFS.ReadFile(migration)
-		if err != nil {
-			pool.Close()
-			return nil, fmt.Errorf("failed to read migration %s: %w", migration, err)
-		}
+	return pool, nil
+}
 
-		if _, err = pool.Exec(ctx, string(upSQL)); err != nil {
-			// Ignore if already exists errors
-			errMsg := err.Error()
-			if errMsg != "ERROR: relation \"user_profiles\" already exists (SQLSTATE 42P07)" &&
-				errMsg != "ERROR: column \"location\" of relation \"jobs\" already exists (SQLSTATE 42701)" &&
-				errMsg != "ERROR: column \"password_hash\" of relation \"user_profiles\" already exists (SQLSTATE 42701)" &&
-				errMsg != "ERROR: column \"paused_at\" of relation \"applications\" already exists (SQLSTATE 42701)" {
-				pool.Close()
-				return nil, fmt.Errorf("failed to run migration %s: %w", migration, err)
-			}
-		}
+// Migrate applies any pending migrations
+func Migrate(ctx context.Context, pool *pgxpool.Pool) error {
+	migrator, err := NewMigrator(pool)
+	if err != nil {
+		return err
 	}
-
-	return pool, nil
+	return migrator.Up(ctx)
 }
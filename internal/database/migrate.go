@@ -0,0 +1,345 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// legacyVersion is the last migration that wasn't idempotent. Databases set up before
+// schema_migrations existed are assumed to be at least this far along; the later migrations
+// all use IF NOT EXISTS and are safe to re-run against them.
+const legacyVersion = 4
+
+// migrationLockID is the advisory lock key that keeps concurrently starting instances from
+// migrating at the same time
+const migrationLockID = 7236001
+
+var migrationFileRegex = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// ErrDirty means a migration failed part way and the schema needs checking by hand before
+// "migrate force" marks it resolved
+var ErrDirty = errors.New("database is in a dirty migration state")
+
+// Migration is one numbered schema change with its rollback
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus reports whether a migration has been applied
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+	Dirty     bool
+}
+
+// Migrator applies the embedded migrations and records them in schema_migrations
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+}
+
+// NewMigrator loads the embedded migrations, checking their numbering has no gaps
+func NewMigrator(pool *pgxpool.Pool) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationFS)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{pool: pool, migrations: migrations}, nil
+}
+
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		m := migrationFileRegex.FindStringSubmatch(entry.Name())
+		if m == nil {
+			continue
+		}
+		version, _ := strconv.Atoi(m[1])
+		sql, err := fs.ReadFile(fsys, "migrations/"+entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration := byVersion[version]
+		if migration == nil {
+			migration = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = migration
+		} else if migration.Name != m[2] {
+			return nil, fmt.Errorf("migration %03d has two names: %s and %s", version, migration.Name, m[2])
+		}
+		if m[3] == "up" {
+			migration.Up = string(sql)
+		} else {
+			migration.Down = string(sql)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %03d_%s has no up file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			return nil, fmt.Errorf("migration %03d is missing", i+1)
+		}
+	}
+	return migrations, nil
+}
+
+// Up applies all pending migrations in order
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			start := time.Now()
+			if err := m.run(ctx, conn, migration, true); err != nil {
+				return err
+			}
+			slog.Info("Applied migration", "version", migration.Version, "name", migration.Name, "duration_ms", time.Since(start).Milliseconds())
+		}
+		return nil
+	})
+}
+
+// Down rolls back the last steps applied migrations, newest first
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && steps > 0; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("migration %03d_%s has no down file", migration.Version, migration.Name)
+			}
+			if err := m.run(ctx, conn, migration, false); err != nil {
+				return err
+			}
+			slog.Info("Rolled back migration", "version", migration.Version, "name", migration.Name)
+			steps--
+		}
+		return nil
+	})
+}
+
+// Force records the schema as exactly at version, clearing any dirty state, without running
+// anything. Use it after repairing a failed migration by hand.
+func (m *Migrator) Force(ctx context.Context, version int) error {
+	if version < 0 || version > len(m.migrations) {
+		return fmt.Errorf("no migration %03d", version)
+	}
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		tx, err := conn.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(ctx)
+
+		if _, err := tx.Exec(ctx, "DELETE FROM schema_migrations WHERE version > $1", version); err != nil {
+			return err
+		}
+		for _, migration := range m.migrations[:version] {
+			if _, err := tx.Exec(ctx, `
+				INSERT INTO schema_migrations (version, name, dirty) VALUES ($1, $2, FALSE)
+				ON CONFLICT (version) DO UPDATE SET dirty = FALSE
+			`, migration.Version, migration.Name); err != nil {
+				return err
+			}
+		}
+		return tx.Commit(ctx)
+	})
+}
+
+// Status lists every known migration with when it was applied, if it has been
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		rows, err := conn.Query(ctx, "SELECT version, applied_at, dirty FROM schema_migrations")
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		type record struct {
+			appliedAt time.Time
+			dirty     bool
+		}
+		records := make(map[int]record)
+		for rows.Next() {
+			var version int
+			var r record
+			if err := rows.Scan(&version, &r.appliedAt, &r.dirty); err != nil {
+				return err
+			}
+			records[version] = r
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			status := MigrationStatus{Migration: migration}
+			if r, ok := records[migration.Version]; ok {
+				status.AppliedAt = &r.appliedAt
+				status.Dirty = r.dirty
+			}
+			statuses = append(statuses, status)
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// withLock runs fn on one connection holding the migration lock, after making sure
+// schema_migrations exists
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return fmt.Errorf("failed to take migration lock: %w", err)
+	}
+	defer conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockID)
+
+	var exists bool
+	if err := conn.QueryRow(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		if err := m.createTable(ctx, conn); err != nil {
+			return err
+		}
+	}
+	return fn(conn)
+}
+
+// createTable creates schema_migrations, baselining databases that predate it
+func (m *Migrator) createTable(ctx context.Context, conn *pgxpool.Conn) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		CREATE TABLE schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			dirty BOOLEAN NOT NULL DEFAULT FALSE,
+			applied_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	var legacy bool
+	if err := tx.QueryRow(ctx, "SELECT to_regclass('user_profiles') IS NOT NULL").Scan(&legacy); err != nil {
+		return err
+	}
+	if legacy {
+		for _, migration := range m.migrations[:legacyVersion] {
+			if _, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)",
+				migration.Version, migration.Name); err != nil {
+				return err
+			}
+		}
+		slog.Info("Baselined existing database", "version", legacyVersion)
+	}
+	return tx.Commit(ctx)
+}
+
+// applied returns the recorded migrations, failing if any is dirty
+func (m *Migrator) applied(ctx context.Context, conn *pgxpool.Conn) (map[int]bool, error) {
+	rows, err := conn.Query(ctx, "SELECT version, dirty FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		var dirty bool
+		if err := rows.Scan(&version, &dirty); err != nil {
+			return nil, err
+		}
+		if dirty {
+			return nil, fmt.Errorf("%w: migration %03d", ErrDirty, version)
+		}
+		applied[version] = true
+	}
+	return applied, rows.Err()
+}
+
+// run applies or rolls back one migration. The version is marked dirty before the SQL runs
+// and the mark is settled in the same transaction as the SQL, so it only survives if the
+// process dies mid-migration or the SQL commits part of itself.
+func (m *Migrator) run(ctx context.Context, conn *pgxpool.Conn, migration Migration, up bool) error {
+	direction, sql := "up", migration.Up
+	if !up {
+		direction, sql = "down", migration.Down
+	}
+
+	if _, err := conn.Exec(ctx, `
+		INSERT INTO schema_migrations (version, name, dirty) VALUES ($1, $2, TRUE)
+		ON CONFLICT (version) DO UPDATE SET dirty = TRUE
+	`, migration.Version, migration.Name); err != nil {
+		return err
+	}
+
+	err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, sql); err != nil {
+			return err
+		}
+		if up {
+			_, err := tx.Exec(ctx, "UPDATE schema_migrations SET dirty = FALSE, applied_at = NOW() WHERE version = $1", migration.Version)
+			return err
+		}
+		_, err := tx.Exec(ctx, "DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+		return err
+	})
+	if err != nil {
+		// The transaction rolled back cleanly, so the mark can go back to how it was
+		if up {
+			conn.Exec(ctx, "DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+		} else {
+			conn.Exec(ctx, "UPDATE schema_migrations SET dirty = FALSE WHERE version = $1", migration.Version)
+		}
+		return fmt.Errorf("migration %03d_%s %s failed: %w", migration.Version, migration.Name, direction, err)
+	}
+	return nil
+}
@@ -0,0 +1,2 @@
+ALTER TABLE applications DROP COLUMN IF EXISTS persona_id;
+DROP TABLE IF EXISTS profile_personas;
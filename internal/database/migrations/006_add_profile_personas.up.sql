@@ -0,0 +1,21 @@
+-- Named profiles (personas) so one account can apply as e.g. "Data Engineer" and "Backend Engineer"
+CREATE TABLE IF NOT EXISTS profile_personas (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES user_profiles(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    headline TEXT,
+    summary TEXT,
+    target_keywords TEXT,
+    resume_url TEXT,
+    skills TEXT[],
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    UNIQUE (user_id, name)
+);
+
+-- At most one default persona per user
+CREATE UNIQUE INDEX IF NOT EXISTS idx_profile_personas_default ON profile_personas(user_id) WHERE is_default;
+
+-- Remember which persona an application was made with
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS persona_id UUID REFERENCES profile_personas(id) ON DELETE SET NULL;
@@ -0,0 +1,90 @@
+-- Canonical skill names with lowercase aliases, used to normalize profile skills and for autocomplete
+CREATE TABLE IF NOT EXISTS skills (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    aliases TEXT[] NOT NULL DEFAULT '{}',
+    category TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_skills_name_lower ON skills(LOWER(name));
+CREATE INDEX IF NOT EXISTS idx_skills_aliases ON skills USING GIN(aliases);
+
+INSERT INTO skills (name, aliases, category) VALUES
+    ('Go', '{golang}', 'language'),
+    ('Python', '{py}', 'language'),
+    ('Java', '{}', 'language'),
+    ('JavaScript', '{js,ecmascript,es6}', 'language'),
+    ('TypeScript', '{ts}', 'language'),
+    ('Ruby', '{}', 'language'),
+    ('PHP', '{}', 'language'),
+    ('Rust', '{}', 'language'),
+    ('C++', '{cpp}', 'language'),
+    ('C#', '{csharp}', 'language'),
+    ('Kotlin', '{}', 'language'),
+    ('Swift', '{}', 'language'),
+    ('Scala', '{}', 'language'),
+    ('R', '{}', 'language'),
+    ('SQL', '{}', 'language'),
+    ('Bash', '{shell scripting,shell}', 'language'),
+    ('React', '{react.js,reactjs}', 'framework'),
+    ('Vue', '{vue.js,vuejs}', 'framework'),
+    ('Angular', '{angularjs}', 'framework'),
+    ('Svelte', '{}', 'framework'),
+    ('Node.js', '{nodejs,node}', 'framework'),
+    ('Django', '{}', 'framework'),
+    ('Flask', '{}', 'framework'),
+    ('FastAPI', '{}', 'framework'),
+    ('Spring', '{spring boot,spring framework}', 'framework'),
+    ('Rails', '{ruby on rails,ror}', 'framework'),
+    ('.NET', '{dotnet,asp.net}', 'framework'),
+    ('GraphQL', '{}', 'framework'),
+    ('REST', '{restful,rest api,rest apis}', 'framework'),
+    ('gRPC', '{}', 'framework'),
+    ('HTML', '{html5}', 'framework'),
+    ('CSS', '{css3}', 'framework'),
+    ('PostgreSQL', '{postgres,psql}', 'data'),
+    ('MySQL', '{}', 'data'),
+    ('MongoDB', '{mongo}', 'data'),
+    ('Redis', '{}', 'data'),
+    ('Elasticsearch', '{elastic search}', 'data'),
+    ('Kafka', '{apache kafka}', 'data'),
+    ('Spark', '{pyspark,apache spark}', 'data'),
+    ('Airflow', '{apache airflow}', 'data'),
+    ('Snowflake', '{}', 'data'),
+    ('dbt', '{}', 'data'),
+    ('Hadoop', '{}', 'data'),
+    ('Tableau', '{}', 'data'),
+    ('Power BI', '{powerbi}', 'data'),
+    ('Pandas', '{}', 'data'),
+    ('NumPy', '{}', 'data'),
+    ('TensorFlow', '{}', 'data'),
+    ('PyTorch', '{torch}', 'data'),
+    ('scikit-learn', '{sklearn}', 'data'),
+    ('Machine Learning', '{ml}', 'data'),
+    ('Data Analysis', '{data analytics}', 'data'),
+    ('ETL', '{}', 'data'),
+    ('Excel', '{microsoft excel,ms excel}', 'data'),
+    ('AWS', '{amazon web services}', 'infrastructure'),
+    ('GCP', '{google cloud,google cloud platform}', 'infrastructure'),
+    ('Azure', '{microsoft azure}', 'infrastructure'),
+    ('Docker', '{}', 'infrastructure'),
+    ('Kubernetes', '{k8s}', 'infrastructure'),
+    ('Terraform', '{}', 'infrastructure'),
+    ('Ansible', '{}', 'infrastructure'),
+    ('Linux', '{}', 'infrastructure'),
+    ('CI/CD', '{ci,cicd,continuous integration}', 'infrastructure'),
+    ('Jenkins', '{}', 'infrastructure'),
+    ('GitHub Actions', '{}', 'infrastructure'),
+    ('Git', '{}', 'infrastructure'),
+    ('Microservices', '{microservice}', 'infrastructure'),
+    ('Agile', '{}', 'practice'),
+    ('Scrum', '{}', 'practice'),
+    ('Jira', '{}', 'practice'),
+    ('Figma', '{}', 'practice'),
+    ('Salesforce', '{}', 'practice'),
+    ('Unit Testing', '{unit tests,tdd}', 'practice'),
+    ('Project Management', '{}', 'practice'),
+    ('Product Management', '{}', 'practice'),
+    ('SEO', '{}', 'practice'),
+    ('Cybersecurity', '{information security,infosec}', 'practice')
+ON CONFLICT (name) DO NOTHING;
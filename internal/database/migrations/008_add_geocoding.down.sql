@@ -0,0 +1,5 @@
+ALTER TABLE jobs DROP COLUMN IF EXISTS geocoded_at;
+ALTER TABLE jobs DROP COLUMN IF EXISTS longitude;
+ALTER TABLE jobs DROP COLUMN IF EXISTS latitude;
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS longitude;
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS latitude;
@@ -0,0 +1,2 @@
+ALTER TABLE applications DROP COLUMN IF EXISTS omitted_fields;
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS never_autofill;
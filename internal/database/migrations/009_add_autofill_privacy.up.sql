@@ -0,0 +1,5 @@
+-- Profile fields the user never wants auto-filled into application forms
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS never_autofill TEXT[] NOT NULL DEFAULT '{}';
+
+-- Fields intentionally left blank on an application because of never_autofill
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS omitted_fields TEXT[];
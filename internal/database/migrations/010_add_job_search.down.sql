@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_jobs_scraped_at_id;
+ALTER TABLE jobs DROP COLUMN IF EXISTS salary_max;
+ALTER TABLE jobs DROP COLUMN IF EXISTS salary_min;
+DROP INDEX IF EXISTS idx_jobs_search_vector;
+ALTER TABLE jobs DROP COLUMN IF EXISTS search_vector;
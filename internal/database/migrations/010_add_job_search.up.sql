@@ -0,0 +1,14 @@
+-- Full-text search over title, company and description for GET /jobs?q=
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(company, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'C')
+) STORED;
+CREATE INDEX IF NOT EXISTS idx_jobs_search_vector ON jobs USING GIN(search_vector);
+
+-- Salary range in whole currency units per year, when the source provides it
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS salary_min INTEGER;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS salary_max INTEGER;
+
+-- Keyset pagination orders by (scraped_at, id)
+CREATE INDEX IF NOT EXISTS idx_jobs_scraped_at_id ON jobs(scraped_at DESC, id DESC);
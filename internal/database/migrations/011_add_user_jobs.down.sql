@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS job_dismissals;
+DROP TABLE IF EXISTS user_jobs;
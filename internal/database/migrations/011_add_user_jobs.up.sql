@@ -0,0 +1,16 @@
+-- Jobs stay in a shared pool (one row per URL); user_jobs is each user's view of it
+CREATE TABLE IF NOT EXISTS user_jobs (
+    user_id UUID NOT NULL REFERENCES user_profiles(id) ON DELETE CASCADE,
+    job_id UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    found_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (user_id, job_id)
+);
+CREATE INDEX IF NOT EXISTS idx_user_jobs_job_id ON user_jobs(job_id);
+
+-- Dismissals are keyed by URL so they survive cached jobs being deleted and re-scraped
+CREATE TABLE IF NOT EXISTS job_dismissals (
+    user_id UUID NOT NULL REFERENCES user_profiles(id) ON DELETE CASCADE,
+    job_url TEXT NOT NULL,
+    dismissed_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (user_id, job_url)
+);
@@ -0,0 +1,2 @@
+-- Minimum yearly salary the user is looking for, used when scoring job recommendations
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS desired_salary INTEGER;
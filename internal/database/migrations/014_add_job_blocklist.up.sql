@@ -0,0 +1,3 @@
+-- Companies and keywords whose jobs are never shown to the user
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS blocked_companies TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS excluded_keywords TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_jobs_salary;
+ALTER TABLE jobs DROP COLUMN IF EXISTS salary_period;
+ALTER TABLE jobs DROP COLUMN IF EXISTS salary_currency;
+ALTER TABLE jobs DROP COLUMN IF EXISTS salary_text;
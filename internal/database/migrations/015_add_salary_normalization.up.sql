@@ -0,0 +1,6 @@
+-- Original salary text and how it was quoted; salary_min/salary_max hold the normalized
+-- annual amounts in the base currency (USD)
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS salary_text TEXT;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS salary_currency VARCHAR(3);
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS salary_period VARCHAR(10);
+CREATE INDEX IF NOT EXISTS idx_jobs_salary ON jobs((COALESCE(salary_max, salary_min)) DESC NULLS LAST);
@@ -0,0 +1,4 @@
+ALTER TABLE jobs DROP COLUMN IF EXISTS status_checked_at;
+ALTER TABLE jobs DROP COLUMN IF EXISTS closed_reason;
+ALTER TABLE jobs DROP COLUMN IF EXISTS closed_at;
+ALTER TABLE jobs DROP COLUMN IF EXISTS status;
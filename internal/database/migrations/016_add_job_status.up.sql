@@ -0,0 +1,5 @@
+-- Postings found to be removed or closed by the background expiry checker
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS status VARCHAR(10) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed'));
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS closed_at TIMESTAMPTZ;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS closed_reason TEXT;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS status_checked_at TIMESTAMPTZ;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS company_notes;
+DROP INDEX IF EXISTS idx_jobs_company_id;
+ALTER TABLE jobs DROP COLUMN IF EXISTS company_id;
+DROP TABLE IF EXISTS companies;
+DROP FUNCTION IF EXISTS normalize_company_name(TEXT);
@@ -0,0 +1,45 @@
+-- Companies normalized from scraped jobs, so "Acme, Inc." and "ACME Inc" are one entity
+CREATE OR REPLACE FUNCTION normalize_company_name(name TEXT) RETURNS TEXT AS $$
+    SELECT TRIM(regexp_replace(
+        TRIM(regexp_replace(LOWER(name), '[^[:alnum:]]+', ' ', 'g')),
+        '( (inc|llc|ltd|limited|corp|corporation|co|company|gmbh|plc|ag|sa))+$', ''))
+$$ LANGUAGE SQL IMMUTABLE;
+
+CREATE TABLE IF NOT EXISTS companies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    normalized_name TEXT NOT NULL UNIQUE,
+    -- Enrichment, filled in from the source's company data when available
+    muse_company_id INTEGER,
+    profile_url TEXT,
+    industry TEXT,
+    size TEXT,
+    rating NUMERIC(2, 1),
+    description TEXT,
+    enriched_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS company_id UUID REFERENCES companies(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_jobs_company_id ON jobs(company_id);
+
+-- Backfill from jobs scraped before companies existed
+INSERT INTO companies (name, normalized_name)
+SELECT DISTINCT ON (normalize_company_name(company)) TRIM(company), normalize_company_name(company)
+FROM jobs
+WHERE normalize_company_name(company) <> ''
+ON CONFLICT (normalized_name) DO NOTHING;
+
+UPDATE jobs SET company_id = c.id
+FROM companies c
+WHERE jobs.company_id IS NULL AND c.normalized_name = normalize_company_name(jobs.company);
+
+-- Private notes a user keeps about a company
+CREATE TABLE IF NOT EXISTS company_notes (
+    user_id UUID NOT NULL REFERENCES user_profiles(id) ON DELETE CASCADE,
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    notes TEXT NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (user_id, company_id)
+);
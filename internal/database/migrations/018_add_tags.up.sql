@@ -0,0 +1,25 @@
+-- User-defined labels ("dream job", "referral available") for jobs and applications
+CREATE TABLE IF NOT EXISTS tags (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES user_profiles(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    color VARCHAR(7),
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_user_name ON tags(user_id, LOWER(name));
+
+CREATE TABLE IF NOT EXISTS job_tags (
+    tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    job_id UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    tagged_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (tag_id, job_id)
+);
+CREATE INDEX IF NOT EXISTS idx_job_tags_job_id ON job_tags(job_id);
+
+CREATE TABLE IF NOT EXISTS application_tags (
+    tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    tagged_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (tag_id, application_id)
+);
+CREATE INDEX IF NOT EXISTS idx_application_tags_application_id ON application_tags(application_id);
@@ -0,0 +1,15 @@
+-- History of background task runs, written by the scheduler
+CREATE TABLE IF NOT EXISTS task_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task TEXT NOT NULL,
+    trigger VARCHAR(10) NOT NULL CHECK (trigger IN ('schedule', 'manual')),
+    status VARCHAR(10) NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'succeeded', 'failed')),
+    error TEXT,
+    triggered_by UUID REFERENCES user_profiles(id) ON DELETE SET NULL,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_task_runs_task_started_at ON task_runs(task, started_at DESC);
+
+-- Operators with access to /api/v1/admin; granted directly in the database
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS is_admin BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,14 @@
+-- Without the column, soft-deleted rows would come back, so they are deleted for real
+DELETE FROM profile_personas WHERE deleted_at IS NOT NULL;
+DELETE FROM applications WHERE deleted_at IS NOT NULL;
+DELETE FROM jobs WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_jobs_deleted_at;
+DROP INDEX IF EXISTS idx_applications_deleted_at;
+DROP INDEX IF EXISTS idx_profile_personas_deleted_at;
+DROP INDEX IF EXISTS idx_profile_personas_name;
+ALTER TABLE profile_personas ADD CONSTRAINT profile_personas_user_id_name_key UNIQUE (user_id, name);
+
+ALTER TABLE jobs DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE applications DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE profile_personas DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,14 @@
+-- Soft deletes: deleted rows are hidden from normal queries and can be restored until the
+-- purge_deleted task removes them for good
+ALTER TABLE profile_personas ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- A deleted profile shouldn't keep its name from being reused
+ALTER TABLE profile_personas DROP CONSTRAINT IF EXISTS profile_personas_user_id_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_profile_personas_name ON profile_personas(user_id, name) WHERE deleted_at IS NULL;
+
+-- For the purge task
+CREATE INDEX IF NOT EXISTS idx_profile_personas_deleted_at ON profile_personas(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_applications_deleted_at ON applications(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_jobs_deleted_at ON jobs(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,2 @@
+-- Encrypted addresses can't be cast back; run "jobapply encryption decrypt" first
+ALTER TABLE user_profiles ALTER COLUMN address TYPE JSONB USING address::jsonb;
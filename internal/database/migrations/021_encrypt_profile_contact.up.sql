@@ -0,0 +1,3 @@
+-- Addresses may now be stored encrypted, which isn't valid JSON. Existing values are kept as
+-- their JSON text and read as legacy plaintext until "jobapply encryption rotate" encrypts them.
+ALTER TABLE user_profiles ALTER COLUMN address TYPE TEXT USING address::text;
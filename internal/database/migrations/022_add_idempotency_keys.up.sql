@@ -0,0 +1,13 @@
+-- Responses to requests sent with an Idempotency-Key header, replayed when a client retries.
+-- A NULL status means the original request is still running.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    user_id UUID NOT NULL REFERENCES user_profiles(id) ON DELETE CASCADE,
+    key TEXT NOT NULL,
+    fingerprint BYTEA NOT NULL,
+    status INT,
+    content_type TEXT,
+    body BYTEA,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, key)
+);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys(created_at);
@@ -0,0 +1,14 @@
+-- Scraped jobs that failed validation, kept for inspection instead of being stored as jobs.
+-- Fields are truncated; a broken scraper can pick up whole pages.
+CREATE TABLE IF NOT EXISTS scrape_rejects (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    site TEXT NOT NULL,
+    search_params_hash TEXT,
+    title TEXT NOT NULL,
+    company TEXT NOT NULL,
+    location TEXT NOT NULL,
+    url TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    rejected_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_scrape_rejects_rejected_at ON scrape_rejects(rejected_at DESC);
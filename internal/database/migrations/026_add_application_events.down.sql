@@ -0,0 +1,2 @@
+ALTER TABLE applications DROP CONSTRAINT IF EXISTS applications_status_check;
+DROP TABLE IF EXISTS application_events;
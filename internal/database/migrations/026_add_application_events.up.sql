@@ -0,0 +1,14 @@
+-- Every change of an application's status, in order. from_status is NULL for the event
+-- recording the application's creation.
+CREATE TABLE IF NOT EXISTS application_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    from_status TEXT,
+    to_status TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_application_events_application ON application_events(application_id, created_at);
+
+-- NOT VALID leaves existing rows alone but checks every insert and update
+ALTER TABLE applications ADD CONSTRAINT applications_status_check
+    CHECK (status IN ('pending', 'in_progress', 'paused', 'submitted', 'failed', 'cancelled', 'timeout')) NOT VALID;
@@ -0,0 +1,3 @@
+COMMENT ON COLUMN applications.custom_questions IS NULL;
+COMMENT ON COLUMN applications.user_answers IS NULL;
+DROP TABLE IF EXISTS application_questions;
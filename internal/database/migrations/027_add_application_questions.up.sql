@@ -0,0 +1,23 @@
+-- Custom questions found on each page of an application form, and the user's answers. An
+-- application pauses once per page with questions, so there can be several pages.
+CREATE TABLE IF NOT EXISTS application_questions (
+    application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    page_index INT NOT NULL,
+    question_key TEXT NOT NULL,
+    question TEXT NOT NULL,
+    answer TEXT,
+    asked_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    answered_at TIMESTAMPTZ,
+    PRIMARY KEY (application_id, page_index, question_key)
+);
+
+-- The single set of questions applications held until now becomes their first page
+INSERT INTO application_questions (application_id, page_index, question_key, question, answer, asked_at, answered_at)
+SELECT a.id, 0, q.key, q.value, a.user_answers->>q.key, COALESCE(a.paused_at, a.created_at, NOW()),
+    CASE WHEN a.user_answers ? q.key THEN COALESCE(a.applied_at, NOW()) END
+FROM applications a, jsonb_each_text(a.custom_questions) AS q
+WHERE jsonb_typeof(a.custom_questions) = 'object'
+ON CONFLICT DO NOTHING;
+
+COMMENT ON COLUMN applications.custom_questions IS 'Superseded by application_questions';
+COMMENT ON COLUMN applications.user_answers IS 'Superseded by application_questions';
@@ -0,0 +1,12 @@
+-- Saved answers for an employer's standard questions and the profile to apply with, reused on
+-- every application to that employer. employer is a lowercased company name or domain.
+CREATE TABLE IF NOT EXISTS application_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES user_profiles(id) ON DELETE CASCADE,
+    employer TEXT NOT NULL,
+    persona_id UUID REFERENCES profile_personas(id) ON DELETE SET NULL,
+    answers JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, employer)
+);
@@ -0,0 +1,12 @@
+-- Estimated travel time from a user's address to on-site jobs, per travel mode. The origin is
+-- kept so estimates from an old address are recomputed; minutes is NULL when there is no route.
+CREATE TABLE IF NOT EXISTS job_commutes (
+    user_id UUID NOT NULL REFERENCES user_profiles(id) ON DELETE CASCADE,
+    job_id UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    mode TEXT NOT NULL,
+    minutes INTEGER,
+    origin_latitude DOUBLE PRECISION NOT NULL,
+    origin_longitude DOUBLE PRECISION NOT NULL,
+    computed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, job_id, mode)
+);
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS company_aliases;
+ALTER TABLE companies DROP COLUMN IF EXISTS staffing_agency;
+ALTER TABLE jobs DROP COLUMN IF EXISTS agency;
+
+-- Merged companies and split agency names aren't restored
+CREATE OR REPLACE FUNCTION normalize_company_name(name TEXT) RETURNS TEXT AS $$
+    SELECT TRIM(regexp_replace(
+        TRIM(regexp_replace(LOWER(name), '[^[:alnum:]]+', ' ', 'g')),
+        '( (inc|llc|ltd|limited|corp|corporation|co|company|gmbh|plc|ag|sa))+$', ''))
+$$ LANGUAGE SQL IMMUTABLE;
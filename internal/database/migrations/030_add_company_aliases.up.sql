@@ -0,0 +1,56 @@
+-- Wider legal-suffix list, and "Google (via TekSystems)" is Google. Keep in step with
+-- scrapers.NormalizeCompany.
+CREATE OR REPLACE FUNCTION normalize_company_name(name TEXT) RETURNS TEXT AS $$
+    SELECT TRIM(regexp_replace(
+        TRIM(regexp_replace(
+            regexp_replace(LOWER(name), '(\S)\s*[(\[]?\s*\mvia\s.*$', '\1'),
+            '[^[:alnum:]]+', ' ', 'g')),
+        '( (inc|incorporated|llc|llp|lp|ltd|limited|corp|corporation|co|company|gmbh|plc|ag|sa|sas|bv|nv|pte|pty|srl|spa|ab|oy))+$', ''))
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- The staffing agency a posting came through, split off the company name
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS agency TEXT;
+UPDATE jobs SET
+    agency = TRIM(BOTH ' )]' FROM substring(company from '\S\s*[(\[]?\s*\m[Vv][Ii][Aa]\s+(.*)$')),
+    company = regexp_replace(company, '(\S)\s*[(\[]?\s*\m[Vv][Ii][Aa]\s.*$', '\1')
+WHERE company ~* '\S\s*[(\[]?\s*\mvia\s';
+
+-- Companies that are themselves staffing agencies, posting for unnamed clients
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS staffing_agency BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Other names a company goes by ("facebook" for Meta), by normalized name; scraped jobs under
+-- an alias join the company it points to
+CREATE TABLE IF NOT EXISTS company_aliases (
+    normalized_name TEXT PRIMARY KEY,
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_company_aliases_company_id ON company_aliases(company_id);
+
+-- Companies the new rules give the same name are merged into the enriched or oldest one
+CREATE TEMP TABLE company_merges ON COMMIT DROP AS
+SELECT id AS old_id,
+    FIRST_VALUE(id) OVER (PARTITION BY normalize_company_name(name) ORDER BY enriched_at IS NULL, created_at, id) AS new_id
+FROM companies
+WHERE normalize_company_name(name) <> '';
+DELETE FROM company_merges WHERE old_id = new_id;
+
+UPDATE jobs SET company_id = m.new_id FROM company_merges m WHERE jobs.company_id = m.old_id;
+INSERT INTO company_notes (user_id, company_id, notes, updated_at)
+SELECT n.user_id, m.new_id, n.notes, n.updated_at
+FROM company_notes n JOIN company_merges m ON m.old_id = n.company_id
+ON CONFLICT (user_id, company_id) DO NOTHING;
+DELETE FROM companies WHERE id IN (SELECT old_id FROM company_merges);
+
+UPDATE companies SET
+    name = regexp_replace(name, '(\S)\s*[(\[]?\s*\m[Vv][Ii][Aa]\s.*$', '\1'),
+    normalized_name = normalize_company_name(name)
+WHERE normalized_name <> normalize_company_name(name) AND normalize_company_name(name) <> '';
+
+UPDATE companies SET staffing_agency = TRUE
+WHERE normalized_name ~ '\m(staffing|recruiting|recruitment|recruiters|talent solutions|employment agency)\M'
+OR normalized_name = ANY (ARRAY['teksystems', 'tek systems', 'robert half', 'randstad', 'adecco', 'kforce',
+    'insight global', 'aerotek', 'apex systems', 'manpower', 'manpowergroup', 'kelly services', 'hays', 'modis',
+    'akkodis', 'collabera', 'cybercoders', 'jobot', 'allegis group', 'experis', 'beacon hill staffing group',
+    'motion recruitment', 'addison group', 'vaco', 'creative circle', 'aston carter', 'actalent',
+    'express employment professionals']);
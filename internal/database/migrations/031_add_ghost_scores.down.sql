@@ -0,0 +1,6 @@
+ALTER TABLE jobs DROP COLUMN IF EXISTS ghost_scored_at;
+ALTER TABLE jobs DROP COLUMN IF EXISTS ghost_reasons;
+ALTER TABLE jobs DROP COLUMN IF EXISTS ghost_score;
+DROP INDEX IF EXISTS idx_jobs_company_title;
+DROP INDEX IF EXISTS idx_jobs_description_hash;
+ALTER TABLE jobs DROP COLUMN IF EXISTS description_hash;
@@ -0,0 +1,9 @@
+-- Likely ghost jobs and agency reposts, scored in the background. description_hash finds the
+-- same description posted by several companies.
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS description_hash TEXT GENERATED ALWAYS AS (md5(description)) STORED;
+CREATE INDEX IF NOT EXISTS idx_jobs_description_hash ON jobs(description_hash);
+CREATE INDEX IF NOT EXISTS idx_jobs_company_title ON jobs(normalize_company_name(company), LOWER(title));
+
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS ghost_score SMALLINT;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS ghost_reasons TEXT[];
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS ghost_scored_at TIMESTAMPTZ;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_applications_user_job;
+ALTER TABLE applications DROP COLUMN IF EXISTS external_status;
+ALTER TABLE applications DROP COLUMN IF EXISTS source;
@@ -0,0 +1,8 @@
+-- Where an application was made: NULL for jobapply's own, 'import' for ones imported from a
+-- spreadsheet. external_status keeps the status as the user tracked it ("interviewing",
+-- "rejected"), which the status machine has no place for.
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS source TEXT;
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS external_status TEXT;
+
+-- Imports look for an application the user already has for a job
+CREATE INDEX IF NOT EXISTS idx_applications_user_job ON applications(user_id, job_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,13 @@
+-- API keys for the browser extension, each limited to a few scopes. Only a hash of the key is
+-- kept; prefix is its start, shown so users can tell their keys apart.
+CREATE TABLE IF NOT EXISTS extension_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES user_profiles(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    key_hash TEXT NOT NULL UNIQUE,
+    prefix TEXT NOT NULL,
+    scopes TEXT[] NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_extension_keys_user_id ON extension_keys(user_id);
@@ -0,0 +1,13 @@
+-- Public links to a few of a user's stats, for accountability partners and coaches. Only a
+-- hash of the token is kept; stats lists what the link shows.
+CREATE TABLE IF NOT EXISTS stats_shares (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES user_profiles(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    stats TEXT[] NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ,
+    last_viewed_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_stats_shares_user_id ON stats_shares(user_id);
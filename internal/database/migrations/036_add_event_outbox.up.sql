@@ -0,0 +1,20 @@
+-- Events waiting to be delivered, written in the same transaction as the change they report
+-- so a crash can't lose them. id is the event's ID; event is the whole event as JSON.
+-- delivered_to lists the sinks that already have it, so retries only go to the rest. user_id
+-- has no foreign key: user.deleted outlives its user.
+CREATE TABLE IF NOT EXISTS event_outbox (
+    id UUID PRIMARY KEY,
+    type TEXT NOT NULL,
+    user_id UUID,
+    event JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_to TEXT[] NOT NULL DEFAULT '{}',
+    last_error TEXT,
+    delivered_at TIMESTAMPTZ,
+    dead_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_event_outbox_pending ON event_outbox(next_attempt_at)
+    WHERE delivered_at IS NULL AND dead_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_event_outbox_dead ON event_outbox(dead_at) WHERE dead_at IS NOT NULL;
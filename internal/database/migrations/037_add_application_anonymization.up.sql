@@ -0,0 +1,2 @@
+-- Set when the retention task strips a rejected application down to what the stats need
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS anonymized_at TIMESTAMPTZ;
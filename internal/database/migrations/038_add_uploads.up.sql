@@ -0,0 +1,10 @@
+-- Stored upload files by content, so uploading an identical file again reuses the stored copy.
+-- Profiles and personas refer to a file by its key; it is deleted once none do. last_used_at
+-- protects a file that was just reused from being deleted before the reference is saved.
+CREATE TABLE IF NOT EXISTS uploads (
+    key TEXT PRIMARY KEY,
+    sha256 TEXT NOT NULL UNIQUE,
+    size BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
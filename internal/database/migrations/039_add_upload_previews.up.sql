@@ -0,0 +1,2 @@
+-- A PNG of the upload's first page, stored alongside it
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS preview_key TEXT UNIQUE;
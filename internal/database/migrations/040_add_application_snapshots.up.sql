@@ -0,0 +1,3 @@
+-- A PDF of the job posting as it read when the user applied, since postings are often taken
+-- down. The file is in upload storage.
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS snapshot_key TEXT;
@@ -0,0 +1,18 @@
+-- Every write request: who made it, for whom, and what it changed. Users aren't foreign keys
+-- so entries outlive deleted accounts.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor_id UUID,
+    subject_id UUID,
+    method TEXT NOT NULL,
+    route TEXT NOT NULL,
+    path TEXT NOT NULL,
+    status INT NOT NULL,
+    changes JSONB NOT NULL DEFAULT '[]',
+    request_id TEXT,
+    ip TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log(actor_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_subject ON audit_log(subject_id, created_at DESC);
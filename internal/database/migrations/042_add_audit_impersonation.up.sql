@@ -0,0 +1,2 @@
+-- Requests an admin made while impersonating the subject
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS impersonation BOOLEAN NOT NULL DEFAULT FALSE;
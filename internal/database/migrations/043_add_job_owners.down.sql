@@ -0,0 +1,10 @@
+-- URLs are unique again, so owned jobs repeating a shared or another owned job's URL are
+-- deleted for real
+DELETE FROM jobs j WHERE j.owner_id IS NOT NULL AND EXISTS (
+    SELECT 1 FROM jobs o WHERE o.url = j.url AND o.id <> j.id AND (o.owner_id IS NULL OR o.id < j.id)
+);
+
+DROP INDEX IF EXISTS idx_jobs_owner_url;
+DROP INDEX IF EXISTS idx_jobs_url;
+ALTER TABLE jobs ADD CONSTRAINT jobs_url_key UNIQUE (url);
+ALTER TABLE jobs DROP COLUMN IF EXISTS owner_id;
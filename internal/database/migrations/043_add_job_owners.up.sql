@@ -0,0 +1,16 @@
+-- Jobs a user captured or imported hold what that user sent, so they belong to them alone.
+-- Shared jobs, written only by scrapes, have no owner; an owned job may repeat a shared URL.
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS owner_id UUID REFERENCES user_profiles(id) ON DELETE CASCADE;
+
+-- Captured and imported jobs so far go to their one user; any with several users stay shared
+UPDATE jobs SET owner_id = u.user_id
+FROM (
+    SELECT job_id, MIN(user_id::text)::uuid AS user_id
+    FROM (SELECT job_id, user_id FROM user_jobs UNION SELECT job_id, user_id FROM applications WHERE user_id IS NOT NULL) j
+    GROUP BY job_id HAVING COUNT(DISTINCT user_id) = 1
+) u
+WHERE jobs.id = u.job_id AND jobs.site IN ('extension', 'import') AND jobs.owner_id IS NULL;
+
+ALTER TABLE jobs DROP CONSTRAINT IF EXISTS jobs_url_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_jobs_url ON jobs(url) WHERE owner_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_jobs_owner_url ON jobs(owner_id, url) WHERE owner_id IS NOT NULL;
@@ -0,0 +1,9 @@
+-- Hashes are unique again; for each, the earliest row stays recorded. Files whose rows are
+-- dropped are still referenced by key and released like files stored before uploads existed.
+DELETE FROM uploads u WHERE EXISTS (
+    SELECT 1 FROM uploads o WHERE o.sha256 = u.sha256 AND (o.created_at, o.key) < (u.created_at, u.key)
+);
+
+DROP INDEX IF EXISTS idx_uploads_user_sha256;
+ALTER TABLE uploads ADD CONSTRAINT uploads_sha256_key UNIQUE (sha256);
+ALTER TABLE uploads DROP COLUMN IF EXISTS user_id;
@@ -0,0 +1,16 @@
+-- Uploads are deduplicated per user, so reusing a stored file can't reveal that another user
+-- uploaded the same document. A file shared before now goes to one of the users referring to
+-- it; the others keep their reference, but their next identical upload is stored anew.
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS user_id UUID REFERENCES user_profiles(id) ON DELETE SET NULL;
+
+UPDATE uploads SET user_id = r.user_id
+FROM (
+    SELECT resume_url, MIN(user_id::text)::uuid AS user_id
+    FROM (SELECT id AS user_id, resume_url FROM user_profiles UNION SELECT user_id, resume_url FROM profile_personas) p
+    WHERE resume_url IS NOT NULL
+    GROUP BY resume_url
+) r
+WHERE r.resume_url = '/uploads/' || uploads.key AND uploads.user_id IS NULL;
+
+ALTER TABLE uploads DROP CONSTRAINT IF EXISTS uploads_sha256_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_uploads_user_sha256 ON uploads(user_id, sha256);
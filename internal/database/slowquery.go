@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yourusername/jobapply/internal/logging"
+)
+
+// maxLoggedStatement caps how much of a slow statement is logged
+const maxLoggedStatement = 500
+
+// slowQueryLogger is a pgx.QueryTracer that logs queries slower than threshold, naming the
+// function that made them, then hands every query on to next
+type slowQueryLogger struct {
+	next      pgx.QueryTracer
+	threshold time.Duration
+}
+
+type queryStartKey struct{}
+
+type queryStart struct {
+	at  time.Time
+	sql string
+}
+
+func (t slowQueryLogger) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	ctx = t.next.TraceQueryStart(ctx, conn, data)
+	if t.threshold <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, queryStartKey{}, queryStart{at: time.Now(), sql: data.SQL})
+}
+
+func (t slowQueryLogger) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	t.next.TraceQueryEnd(ctx, conn, data)
+	start, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+	elapsed := time.Since(start.at)
+	if elapsed < t.threshold {
+		return
+	}
+
+	statement := strings.Join(strings.Fields(start.sql), " ")
+	if len(statement) > maxLoggedStatement {
+		statement = statement[:maxLoggedStatement] + "..."
+	}
+	args := []any{
+		"duration_ms", elapsed.Milliseconds(),
+		"caller", queryCaller(),
+		"statement", statement,
+		"rows", data.CommandTag.RowsAffected(),
+	}
+	if data.Err != nil {
+		args = append(args, "error", data.Err)
+	}
+	logging.FromContext(ctx).Warn("Slow query", args...)
+}
+
+// queryCaller names the first function on the stack outside pgx and the data access layer,
+// such as the handler the query was made for. Queries end on the goroutine that made them,
+// when their rows are closed, so the stack still leads back to it.
+func queryCaller() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		fn := frame.Function
+		if !strings.Contains(fn, "/pgx/") && !strings.Contains(fn, "/internal/store.") &&
+			!strings.Contains(fn, "/internal/database.") && !strings.HasPrefix(fn, "runtime.") {
+			return fn[strings.LastIndex(fn, "/")+1:]
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
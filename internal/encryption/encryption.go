@@ -0,0 +1,199 @@
+// Package encryption encrypts sensitive values, such as phone numbers and addresses, before they
+// are stored. Values are sealed with AES-256-GCM and tagged with the ID of the key used, so keys
+// can be rotated: new values use the current key, and older ones stay readable as long as their
+// key is still provided.
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// prefix marks an encrypted value; anything without it is treated as legacy plaintext
+const prefix = "enc:v1:"
+
+// ErrUnknownKey is returned when a value was encrypted with a key the provider doesn't have
+var ErrUnknownKey = errors.New("unknown encryption key")
+
+// KeyProvider supplies the keys values are encrypted with. The environment is the only provider
+// for now; a KMS-backed one would fetch and unwrap data keys here.
+type KeyProvider interface {
+	// CurrentKeyID names the key new values are encrypted with, or "" to store them in plaintext
+	CurrentKeyID() string
+	// Key returns the 32-byte key with the given ID, or an error wrapping ErrUnknownKey
+	Key(ctx context.Context, id string) ([]byte, error)
+}
+
+// StaticKeys is a fixed set of keys, the first of which is current
+type StaticKeys struct {
+	current string
+	keys    map[string][]byte
+}
+
+// ParseKeys reads keys from a comma-separated list of id:base64key pairs, as in ENCRYPTION_KEYS.
+// The first key is current; the rest are only used to read values written before a rotation.
+func ParseKeys(spec string) (*StaticKeys, error) {
+	k := &StaticKeys{keys: map[string][]byte{}}
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(pair, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("key %q is not in id:base64key form", pair)
+		}
+		if strings.ContainsAny(id, ": ") {
+			return nil, fmt.Errorf("key ID %q contains a colon or space", id)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key %q is not valid base64: %w", id, err)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("key %q is %d bytes; AES-256 needs 32", id, len(key))
+		}
+		if _, dup := k.keys[id]; dup {
+			return nil, fmt.Errorf("key %q is listed twice", id)
+		}
+		if k.current == "" {
+			k.current = id
+		}
+		k.keys[id] = key
+	}
+	if len(k.keys) == 0 {
+		return nil, errors.New("no keys given")
+	}
+	return k, nil
+}
+
+func (k *StaticKeys) CurrentKeyID() string {
+	return k.current
+}
+
+func (k *StaticKeys) Key(ctx context.Context, id string) ([]byte, error) {
+	key, ok := k.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, id)
+	}
+	return key, nil
+}
+
+// ReadOnly returns the same keys with none current, so values are decrypted but new ones are
+// stored in plaintext. Used to take encryption back out.
+func (k *StaticKeys) ReadOnly() *StaticKeys {
+	return &StaticKeys{keys: k.keys}
+}
+
+// Cipher encrypts and decrypts stored values. A nil *Cipher stores everything in plaintext and
+// passes plaintext through, so callers don't need to check whether encryption is configured;
+// it still refuses to return encrypted values it can't read.
+type Cipher struct {
+	keys KeyProvider
+
+	mu    sync.Mutex
+	aeads map[string]cipher.AEAD
+}
+
+// New returns a Cipher using keys from provider
+func New(provider KeyProvider) *Cipher {
+	return &Cipher{keys: provider, aeads: map[string]cipher.AEAD{}}
+}
+
+func (c *Cipher) aead(ctx context.Context, id string) (cipher.AEAD, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if aead, ok := c.aeads[id]; ok {
+		return aead, nil
+	}
+	key, err := c.keys.Key(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	c.aeads[id] = aead
+	return aead, nil
+}
+
+// Encrypt seals plaintext with the current key, returning "enc:v1:<key ID>:<base64 nonce and
+// ciphertext>". Empty values, and all values when there is no current key, are returned as is.
+func (c *Cipher) Encrypt(ctx context.Context, plaintext string) (string, error) {
+	if c == nil || plaintext == "" || c.keys.CurrentKeyID() == "" {
+		return plaintext, nil
+	}
+	id := c.keys.CurrentKeyID()
+	aead, err := c.aead(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(id))
+	return prefix + id + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value written by Encrypt. Values without the encrypted prefix are legacy
+// plaintext and are returned unchanged.
+func (c *Cipher) Decrypt(ctx context.Context, value string) (string, error) {
+	rest, ok := strings.CutPrefix(value, prefix)
+	if !ok {
+		return value, nil
+	}
+	id, encoded, ok := strings.Cut(rest, ":")
+	if !ok {
+		return "", errors.New("malformed encrypted value")
+	}
+	if c == nil {
+		return "", fmt.Errorf("%w %q: encryption is not configured", ErrUnknownKey, id)
+	}
+	aead, err := c.aead(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errors.New("malformed encrypted value")
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(id))
+	if err != nil {
+		return "", fmt.Errorf("decrypting with key %q: %w", id, err)
+	}
+	return string(plaintext), nil
+}
+
+// NeedsRotation reports whether value isn't stored the way Encrypt would store it now: plaintext
+// while there is a current key, encrypted with an older key, or encrypted when there is none.
+func (c *Cipher) NeedsRotation(value string) bool {
+	if value == "" {
+		return false
+	}
+	current := ""
+	if c != nil {
+		current = c.keys.CurrentKeyID()
+	}
+	rest, encrypted := strings.CutPrefix(value, prefix)
+	switch {
+	case current == "":
+		return encrypted
+	case !encrypted:
+		return true
+	default:
+		return !strings.HasPrefix(rest, current+":")
+	}
+}
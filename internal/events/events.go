@@ -0,0 +1,186 @@
+// Package events is an internal publish/subscribe bus, so subsystems that react to the same
+// things - the notification inbox, and later webhooks, timelines and stats - don't each need a
+// hook in the code that does them. The store and handlers queue events in the outbox, which
+// services.Outbox delivers to the bus and to webhooks; subscribers register by name. Delivery
+// is at least once, so subscribers should tolerate seeing an event ID again.
+//
+// Event types and their payloads (Event.Data):
+//
+//	application.created         ApplicationCreated
+//	application.status_changed  ApplicationStatusChanged
+//	scrape.completed            ScrapeCompleted
+//	scrape.failed               ScrapeFailed
+//	user.signed_up              none
+//	user.logged_in              none
+//	user.password_changed       none
+//	user.email_changed          none
+//	user.deleted                none
+//
+// Payloads only grow: fields may be added, but are never renamed or removed.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/jobapply/internal/logging"
+)
+
+// Type names an event as "<subject>.<what happened>"
+type Type string
+
+const (
+	ApplicationCreated       Type = "application.created"
+	ApplicationStatusChanged Type = "application.status_changed"
+	ScrapeCompleted          Type = "scrape.completed"
+	ScrapeFailed             Type = "scrape.failed"
+	UserSignedUp             Type = "user.signed_up"
+	UserLoggedIn             Type = "user.logged_in"
+	UserPasswordChanged      Type = "user.password_changed"
+	UserEmailChanged         Type = "user.email_changed"
+	UserDeleted              Type = "user.deleted"
+)
+
+// Event is something that happened, for the user it happened to
+type Event struct {
+	ID     string          `json:"id"`
+	Type   Type            `json:"type"`
+	UserID string          `json:"user_id,omitempty"`
+	Time   time.Time       `json:"time"`
+	Data   json.RawMessage `json:"data,omitempty"`
+}
+
+// New returns an event with a fresh ID, stamped now. data is the type's payload, or nil for
+// types without one; payloads are plain structs, so marshaling them doesn't fail.
+func New(typ Type, userID string, data any) Event {
+	e := Event{ID: uuid.NewString(), Type: typ, UserID: userID, Time: time.Now().UTC()}
+	if data != nil {
+		e.Data, _ = json.Marshal(data)
+	}
+	return e
+}
+
+// Decode unmarshals the payload into v, which should be the type's payload struct
+func (e Event) Decode(v any) error {
+	return json.Unmarshal(e.Data, v)
+}
+
+// ApplicationPayload identifies an application and the job it is for
+type ApplicationPayload struct {
+	ApplicationID string `json:"application_id"`
+	JobID         string `json:"job_id"`
+	Title         string `json:"title"`
+	Company       string `json:"company"`
+}
+
+// ApplicationCreatedData is the payload of application.created
+type ApplicationCreatedData struct {
+	ApplicationPayload
+	Status string `json:"status"`
+	Source string `json:"source,omitempty"` // "" for jobapply's own applications; see models.Application
+}
+
+// ApplicationStatusChangedData is the payload of application.status_changed
+type ApplicationStatusChangedData struct {
+	ApplicationPayload
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ScrapeCompletedData is the payload of scrape.completed. Scrapes served from the cache
+// aren't published.
+type ScrapeCompletedData struct {
+	Site     string `json:"site"`
+	Keywords string `json:"keywords"`
+	Location string `json:"location"`
+	Inserted int    `json:"inserted"` // Jobs not seen before
+	Updated  int    `json:"updated"`
+	Rejected int    `json:"rejected"` // Jobs quarantined as invalid
+}
+
+// ScrapeFailedData is the payload of scrape.failed
+type ScrapeFailedData struct {
+	Site     string `json:"site"`
+	Keywords string `json:"keywords"`
+	Location string `json:"location"`
+	Error    string `json:"error"`
+}
+
+// Handler reacts to an event. An error is logged; it doesn't reach the publisher.
+type Handler func(ctx context.Context, e Event) error
+
+// Publisher is the publishing half of a Bus
+type Publisher interface {
+	// Publish hands the event to the bus. Delivery to subscribers may happen before Publish
+	// returns, or later on another instance, depending on the bus.
+	Publish(ctx context.Context, e Event) error
+}
+
+// Bus delivers published events to subscribers
+type Bus interface {
+	Publisher
+	// Subscribe registers fn for events of the given types, or all events if none are given.
+	// name identifies the subscriber: on a shared bus each event is delivered to one instance
+	// per name. Subscribe before the bus is started.
+	Subscribe(name string, fn Handler, types ...Type)
+}
+
+type subscription struct {
+	name  string
+	types []Type
+	fn    Handler
+}
+
+func (s subscription) wants(t Type) bool {
+	return len(s.types) == 0 || slices.Contains(s.types, t)
+}
+
+// deliver runs the subscriber, logging its error or panic so one subscriber can't break
+// publishing or the others
+func (s subscription) deliver(ctx context.Context, e Event) {
+	logger := logging.FromContext(ctx).With("subscriber", s.name, "event_type", e.Type, "event_id", e.ID)
+	defer func() {
+		if p := recover(); p != nil {
+			logger.Error("Event subscriber panicked", "panic", p)
+		}
+	}()
+	if err := s.fn(ctx, e); err != nil {
+		logger.Error("Event subscriber failed", "error", err)
+	}
+}
+
+// Local delivers events in process, synchronously, to each subscriber in turn. Subscribers
+// should hand slow work off rather than hold up the publisher.
+type Local struct {
+	mu   sync.RWMutex
+	subs []subscription
+}
+
+func NewLocal() *Local {
+	return &Local{}
+}
+
+func (b *Local) Subscribe(name string, fn Handler, types ...Type) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs = append(b.subs, subscription{name: name, types: types, fn: fn})
+}
+
+// Publish delivers the event before returning. It never fails.
+func (b *Local) Publish(ctx context.Context, e Event) error {
+	b.mu.RLock()
+	subs := b.subs
+	b.mu.RUnlock()
+	// Subscribers finish their work even if the request that published goes away
+	ctx = context.WithoutCancel(ctx)
+	for _, s := range subs {
+		if s.wants(e.Type) {
+			s.deliver(ctx, e)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/jobapply/internal/redis"
+)
+
+const (
+	// DefaultStream is the Redis stream events are kept in
+	DefaultStream = "jobapply:events"
+	// streamMaxLen caps the stream, approximately; delivered events are only kept for debugging
+	streamMaxLen = 100000
+	// readBlock is how long each read waits for new events; it must stay under the client's
+	// command timeout
+	readBlock = time.Second
+	readCount = 50
+)
+
+// Redis shares events between instances through a Redis stream. Each subscriber name is a
+// consumer group, so every event reaches one instance per subscriber rather than all of them.
+// Events are acknowledged once the subscriber returns, whatever its result; one whose
+// instance dies mid-delivery is not redelivered.
+type Redis struct {
+	client   *redis.Client
+	stream   string
+	consumer string
+
+	mu      sync.Mutex
+	subs    []subscription
+	cancel  context.CancelFunc
+	stopped chan struct{}
+}
+
+// NewRedis returns a bus on stream, or DefaultStream if it is ""
+func NewRedis(client *redis.Client, stream string) *Redis {
+	if stream == "" {
+		stream = DefaultStream
+	}
+	host, _ := os.Hostname()
+	return &Redis{client: client, stream: stream, consumer: fmt.Sprintf("%s-%d", host, os.Getpid())}
+}
+
+func (b *Redis) Subscribe(name string, fn Handler, types ...Type) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs = append(b.subs, subscription{name: name, types: types, fn: fn})
+}
+
+// Publish appends the event to the stream; subscribers receive it once Start has been called
+// on some instance
+func (b *Redis) Publish(ctx context.Context, e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = b.client.Do(ctx, "XADD", b.stream, "MAXLEN", "~", streamMaxLen, "*", "event", data)
+	if err != nil {
+		return fmt.Errorf("failed to publish %s: %w", e.Type, err)
+	}
+	return nil
+}
+
+// Start reads the stream for each subscriber until Stop. Events published before a
+// subscriber's first start are not delivered to it.
+func (b *Redis) Start() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ctx, cancel := context.WithCancel(context.Background())
+	b.cancel = cancel
+	b.stopped = make(chan struct{})
+
+	var wg sync.WaitGroup
+	for _, s := range b.subs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.consume(ctx, s)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(b.stopped)
+	}()
+}
+
+// Stop ends reading and waits for deliveries under way until ctx ends
+func (b *Redis) Stop(ctx context.Context) error {
+	b.mu.Lock()
+	cancel, stopped := b.cancel, b.stopped
+	b.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *Redis) consume(ctx context.Context, s subscription) {
+	logger := slog.With("subscriber", s.name, "stream", b.stream)
+	for ctx.Err() == nil {
+		if err := b.createGroup(ctx, s.name); err != nil {
+			logger.Warn("Failed to create event consumer group, retrying", "error", err)
+			sleep(ctx, 5*time.Second)
+			continue
+		}
+		break
+	}
+
+	for ctx.Err() == nil {
+		reply, err := b.client.Do(ctx, "XREADGROUP", "GROUP", s.name, b.consumer,
+			"COUNT", readCount, "BLOCK", readBlock.Milliseconds(), "STREAMS", b.stream, ">")
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Warn("Failed to read events, retrying", "error", err)
+				sleep(ctx, time.Second)
+			}
+			continue
+		}
+		for _, entry := range streamEntries(reply) {
+			var e Event
+			if err := json.Unmarshal([]byte(entry.event), &e); err != nil {
+				logger.Error("Dropping malformed event", "entry", entry.id, "error", err)
+			} else if s.wants(e.Type) {
+				s.deliver(context.WithoutCancel(ctx), e)
+			}
+			if _, err := b.client.Do(context.WithoutCancel(ctx), "XACK", b.stream, s.name, entry.id); err != nil {
+				logger.Warn("Failed to acknowledge event", "entry", entry.id, "error", err)
+			}
+		}
+	}
+}
+
+// createGroup makes the subscriber's consumer group, starting at new events, unless it exists
+func (b *Redis) createGroup(ctx context.Context, name string) error {
+	_, err := b.client.Do(ctx, "XGROUP", "CREATE", b.stream, name, "$", "MKSTREAM")
+	var replyErr redis.Error
+	if errors.As(err, &replyErr) && strings.HasPrefix(string(replyErr), "BUSYGROUP") {
+		return nil
+	}
+	return err
+}
+
+type streamEntry struct {
+	id, event string
+}
+
+// streamEntries picks the entries out of an XREADGROUP reply: [[stream, [[id, [field, value, ...]], ...]]],
+// or nil when the read timed out
+func streamEntries(reply any) []streamEntry {
+	streams, _ := reply.([]any)
+	var entries []streamEntry
+	for _, stream := range streams {
+		pair, _ := stream.([]any)
+		if len(pair) != 2 {
+			continue
+		}
+		items, _ := pair[1].([]any)
+		for _, item := range items {
+			fields, _ := item.([]any)
+			if len(fields) != 2 {
+				continue
+			}
+			id, _ := fields[0].(string)
+			values, _ := fields[1].([]any)
+			entry := streamEntry{id: id}
+			for i := 0; i+1 < len(values); i += 2 {
+				if key, _ := values[i].(string); key == "event" {
+					entry.event, _ = values[i+1].(string)
+				}
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+func sleep(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
@@ -0,0 +1,52 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+)
+
+var ErrNotFound = errors.New("location not found")
+
+// Point is a WGS84 coordinate
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// Geocoder resolves a free-text address or place name to coordinates
+type Geocoder interface {
+	Geocode(ctx context.Context, query string) (*Point, error)
+}
+
+const EarthRadiusMiles = 3958.8
+
+// KmPerMile converts kilometre distances to the miles used throughout
+const KmPerMile = 1.609344
+
+// BoundingBox returns the lat/lng ranges that contain every point within miles of center.
+// Used as an index-friendly prefilter before the exact great-circle distance check.
+func BoundingBox(center Point, miles float64) (minLat, maxLat, minLng, maxLng float64) {
+	const milesPerDegree = EarthRadiusMiles * math.Pi / 180
+	latDelta := miles / milesPerDegree
+	// Longitude degrees shrink toward the poles; clamp to avoid dividing by ~0
+	lngDelta := miles / (milesPerDegree * math.Max(math.Cos(center.Lat*math.Pi/180), 0.01))
+	return center.Lat - latDelta, center.Lat + latDelta, center.Lng - lngDelta, center.Lng + lngDelta
+}
+
+// DistanceMiles returns the great-circle distance between two points
+func DistanceMiles(a, b Point) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(b.Lat - a.Lat)
+	dLng := toRad(b.Lng - a.Lng)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(a.Lat))*math.Cos(toRad(b.Lat))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadiusMiles * math.Asin(math.Sqrt(h))
+}
+
+// IsRemote reports whether a job location names no physical place ("Remote", "Flexible / Remote")
+func IsRemote(location string) bool {
+	lower := strings.ToLower(location)
+	return strings.Contains(lower, "remote") || strings.Contains(lower, "anywhere")
+}
@@ -0,0 +1,108 @@
+package geo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const DefaultNominatimURL = "https://nominatim.openstreetmap.org/search"
+
+// Nominatim geocodes with OpenStreetMap's Nominatim API. The public instance allows at most
+// one request per second and requires an identifying User-Agent, so requests are serialized.
+type Nominatim struct {
+	BaseURL   string
+	UserAgent string
+	client    *http.Client
+
+	mu       sync.Mutex
+	interval time.Duration
+	last     time.Time
+}
+
+func NewNominatim(baseURL, userAgent string) *Nominatim {
+	if baseURL == "" {
+		baseURL = DefaultNominatimURL
+	}
+	if userAgent == "" {
+		userAgent = "jobapply/1.0"
+	}
+	return &Nominatim{
+		BaseURL:   baseURL,
+		UserAgent: userAgent,
+		client:    &http.Client{Timeout: 10 * time.Second},
+		interval:  time.Second,
+	}
+}
+
+type nominatimResult struct {
+	Lat string `json:"lat"`
+	Lon string `json:"lon"`
+}
+
+func (n *Nominatim) Geocode(ctx context.Context, query string) (*Point, error) {
+	if err := n.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("format", "json")
+	params.Set("limit", "1")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, n.BaseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", n.UserAgent)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("geocoding request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geocoding returned status %d", resp.StatusCode)
+	}
+
+	var results []nominatimResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("failed to decode geocoding response: %w", err)
+	}
+	if len(results) == 0 {
+		return nil, ErrNotFound
+	}
+
+	lat, err := strconv.ParseFloat(results[0].Lat, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid latitude %q", results[0].Lat)
+	}
+	lng, err := strconv.ParseFloat(results[0].Lon, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid longitude %q", results[0].Lon)
+	}
+
+	return &Point{Lat: lat, Lng: lng}, nil
+}
+
+// wait blocks until the rate limit allows another request
+func (n *Nominatim) wait(ctx context.Context) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if delay := n.interval - time.Since(n.last); delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	n.last = time.Now()
+	return nil
+}
@@ -0,0 +1,163 @@
+package geo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// ErrNoRoute means the router found no way between two points by that mode
+var ErrNoRoute = errors.New("no route found")
+
+// Travel modes a Router may support
+const (
+	ModeDriving = "driving"
+	ModeTransit = "transit"
+)
+
+// Router estimates travel time between two points
+type Router interface {
+	// Modes lists the travel modes the router supports
+	Modes() []string
+	TravelTime(ctx context.Context, from, to Point, mode string) (time.Duration, error)
+}
+
+const DefaultOSRMURL = "https://router.project-osrm.org"
+
+// OSRM routes with an Open Source Routing Machine server. It only knows driving. The public
+// demo server allows about one request per second, so requests are serialized like Nominatim's.
+type OSRM struct {
+	BaseURL string
+	client  *http.Client
+
+	mu       sync.Mutex
+	interval time.Duration
+	last     time.Time
+}
+
+func NewOSRM(baseURL string) *OSRM {
+	if baseURL == "" {
+		baseURL = DefaultOSRMURL
+	}
+	return &OSRM{BaseURL: baseURL, client: &http.Client{Timeout: 10 * time.Second}, interval: time.Second}
+}
+
+func (o *OSRM) Modes() []string { return []string{ModeDriving} }
+
+func (o *OSRM) TravelTime(ctx context.Context, from, to Point, mode string) (time.Duration, error) {
+	if mode != ModeDriving {
+		return 0, fmt.Errorf("osrm does not support %s", mode)
+	}
+	if err := o.wait(ctx); err != nil {
+		return 0, err
+	}
+	endpoint := fmt.Sprintf("%s/route/v1/driving/%f,%f;%f,%f?overview=false", o.BaseURL, from.Lng, from.Lat, to.Lng, to.Lat)
+	var result struct {
+		Code   string `json:"code"`
+		Routes []struct {
+			Duration float64 `json:"duration"` // Seconds
+		} `json:"routes"`
+	}
+	if err := getJSON(ctx, o.client, endpoint, &result); err != nil {
+		return 0, err
+	}
+	if result.Code == "NoRoute" || (result.Code == "Ok" && len(result.Routes) == 0) {
+		return 0, ErrNoRoute
+	}
+	if result.Code != "Ok" {
+		return 0, fmt.Errorf("routing returned %s", result.Code)
+	}
+	return time.Duration(result.Routes[0].Duration * float64(time.Second)), nil
+}
+
+// wait blocks until the rate limit allows another request
+func (o *OSRM) wait(ctx context.Context) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if delay := o.interval - time.Since(o.last); delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	o.last = time.Now()
+	return nil
+}
+
+const DefaultGoogleDistanceMatrixURL = "https://maps.googleapis.com/maps/api/distancematrix/json"
+
+// GoogleRouter routes with the Google Distance Matrix API, which also knows public transit
+type GoogleRouter struct {
+	BaseURL string
+	APIKey  string
+	client  *http.Client
+}
+
+func NewGoogleRouter(apiKey string) *GoogleRouter {
+	return &GoogleRouter{BaseURL: DefaultGoogleDistanceMatrixURL, APIKey: apiKey, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (g *GoogleRouter) Modes() []string { return []string{ModeDriving, ModeTransit} }
+
+func (g *GoogleRouter) TravelTime(ctx context.Context, from, to Point, mode string) (time.Duration, error) {
+	params := url.Values{}
+	params.Set("origins", fmt.Sprintf("%f,%f", from.Lat, from.Lng))
+	params.Set("destinations", fmt.Sprintf("%f,%f", to.Lat, to.Lng))
+	params.Set("mode", mode)
+	params.Set("key", g.APIKey)
+	var result struct {
+		Status string `json:"status"`
+		Rows   []struct {
+			Elements []struct {
+				Status   string `json:"status"`
+				Duration struct {
+					Value float64 `json:"value"` // Seconds
+				} `json:"duration"`
+			} `json:"elements"`
+		} `json:"rows"`
+	}
+	if err := getJSON(ctx, g.client, g.BaseURL+"?"+params.Encode(), &result); err != nil {
+		return 0, err
+	}
+	if result.Status != "OK" {
+		return 0, fmt.Errorf("routing returned %s", result.Status)
+	}
+	if len(result.Rows) == 0 || len(result.Rows[0].Elements) == 0 {
+		return 0, ErrNoRoute
+	}
+	element := result.Rows[0].Elements[0]
+	switch element.Status {
+	case "OK":
+		return time.Duration(element.Duration.Value * float64(time.Second)), nil
+	case "ZERO_RESULTS", "NOT_FOUND":
+		return 0, ErrNoRoute
+	}
+	return 0, fmt.Errorf("routing returned %s", element.Status)
+}
+
+func getJSON(ctx context.Context, client *http.Client, endpoint string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("routing request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	// OSRM answers NoRoute with a 400 and a JSON body, so the body is decoded either way
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("routing returned status %d", resp.StatusCode)
+		}
+		return fmt.Errorf("failed to decode routing response: %w", err)
+	}
+	return nil
+}
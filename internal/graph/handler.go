@@ -0,0 +1,84 @@
+// Package graph serves the GraphQL API defined in schema.graphqls. It reads through the same
+// stores as the REST handlers and reports errors with the same codes and messages.
+//
+// generated.go, model/ and the resolver stubs in schema.resolvers.go are generated by gqlgen
+// from the schema; run "make graphql" after changing it.
+package graph
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/yourusername/jobapply/internal/apierror"
+	"github.com/yourusername/jobapply/internal/handlers"
+	"github.com/yourusername/jobapply/internal/logging"
+	"github.com/yourusername/jobapply/internal/store"
+)
+
+// maxComplexity bounds the work one query can ask for, counting one per field, so deeply
+// nested or very wide queries are rejected before they run
+const maxComplexity = 500
+
+// NewHandler returns the GraphQL endpoint. It must run behind the auth middleware.
+func NewHandler(stores *store.Store) http.Handler {
+	srv := handler.New(NewExecutableSchema(Config{Resolvers: &Resolver{
+		users:        stores.Users,
+		jobs:         stores.Jobs,
+		applications: stores.Applications,
+	}}))
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.FixedComplexityLimit(maxComplexity))
+	srv.SetErrorPresenter(presentError)
+	srv.AroundOperations(refuseReadOnlyMutations)
+	srv.SetRecoverFunc(func(ctx context.Context, rec any) error {
+		logging.FromContext(ctx).Error("GraphQL resolver panicked", "panic", rec, "stack", string(debug.Stack()))
+		return apierror.New(http.StatusInternalServerError, "Internal server error")
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		srv.ServeHTTP(w, r.WithContext(withLoaders(r.Context(), stores.Jobs)))
+	})
+}
+
+// refuseReadOnlyMutations stops mutations made with a read-only impersonation token. The
+// endpoint isn't behind handlers.BlockImpersonatedWrites, since queries are POSTs too.
+func refuseReadOnlyMutations(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	op := graphql.GetOperationContext(ctx).Operation
+	if op != nil && op.Operation == ast.Mutation && handlers.ReadOnlyImpersonation(ctx) {
+		err := presentError(ctx, apierror.New(http.StatusForbidden, "This impersonation session is read-only"))
+		return graphql.OneShot(&graphql.Response{Errors: gqlerror.List{err}})
+	}
+	return next(ctx)
+}
+
+// presentError maps resolver errors through the API error taxonomy, putting the code and any
+// field errors in the error's extensions. Server-side causes are logged, not sent.
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	var gqlErr *gqlerror.Error
+	if errors.As(err, &gqlErr) && gqlErr.Err == nil {
+		// Parse and validation errors from gqlgen itself
+		return gqlErr
+	}
+
+	e := apierror.From(err)
+	if e.Status >= 500 {
+		logging.FromContext(ctx).Error(e.Message, "error", e.Cause)
+	}
+	presented := graphql.DefaultErrorPresenter(ctx, err)
+	presented.Message = e.Message
+	presented.Extensions = map[string]any{"code": e.Code}
+	if len(e.Fields) > 0 {
+		presented.Extensions["fields"] = e.Fields
+	}
+	return presented
+}
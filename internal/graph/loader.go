@@ -0,0 +1,97 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/yourusername/jobapply/internal/handlers"
+	"github.com/yourusername/jobapply/internal/models"
+	"github.com/yourusername/jobapply/internal/store"
+)
+
+// batchWait is how long a loader collects keys before fetching them. gqlgen resolves the
+// fields of list items concurrently, so a list's keys all arrive well within it.
+const batchWait = 2 * time.Millisecond
+
+// loader batches the keys requested within batchWait into one fetch and remembers the results
+// for the rest of the request, so resolving a field on every item of a list costs one query
+type loader[K comparable, V any] struct {
+	ctx   context.Context
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mu    sync.Mutex
+	seen  map[K]*batch[K, V]
+	batch *batch[K, V]
+}
+
+type batch[K comparable, V any] struct {
+	keys   []K
+	done   chan struct{}
+	values map[K]V
+	err    error
+}
+
+func newLoader[K comparable, V any](ctx context.Context, fetch func(ctx context.Context, keys []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{ctx: ctx, fetch: fetch, seen: make(map[K]*batch[K, V])}
+}
+
+// load returns the value for key; ok is false if the fetch didn't return one
+func (l *loader[K, V]) load(key K) (value V, ok bool, err error) {
+	l.mu.Lock()
+	b, seen := l.seen[key]
+	if !seen {
+		if l.batch == nil {
+			l.batch = &batch[K, V]{done: make(chan struct{})}
+			next := l.batch
+			time.AfterFunc(batchWait, func() { l.run(next) })
+		}
+		b = l.batch
+		b.keys = append(b.keys, key)
+		l.seen[key] = b
+	}
+	l.mu.Unlock()
+
+	<-b.done
+	value, ok = b.values[key]
+	return value, ok, b.err
+}
+
+func (l *loader[K, V]) run(b *batch[K, V]) {
+	l.mu.Lock()
+	l.batch = nil
+	l.mu.Unlock()
+
+	b.values, b.err = l.fetch(l.ctx, b.keys)
+	close(b.done)
+}
+
+// loaders are the request's loaders, created fresh for each request so nothing is cached
+// across users
+type loaders struct {
+	jobs *loader[string, models.JobListing]
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, jobs store.JobStore) context.Context {
+	userID := handlers.UserIDFromContext(ctx)
+	l := &loaders{
+		jobs: newLoader(ctx, func(ctx context.Context, ids []string) (map[string]models.JobListing, error) {
+			found, err := jobs.JobsByID(ctx, userID, ids)
+			if err != nil {
+				return nil, err
+			}
+			byID := make(map[string]models.JobListing, len(found))
+			for _, job := range found {
+				byID[job.ID] = job
+			}
+			return byID, nil
+		}),
+	}
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
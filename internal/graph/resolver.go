@@ -0,0 +1,10 @@
+package graph
+
+import "github.com/yourusername/jobapply/internal/store"
+
+// Resolver holds the dependencies shared by all resolvers
+type Resolver struct {
+	users        store.UserStore
+	jobs         store.JobStore
+	applications store.ApplicationStore
+}
@@ -0,0 +1,105 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.70
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/yourusername/jobapply/internal/apierror"
+	"github.com/yourusername/jobapply/internal/graph/model"
+	"github.com/yourusername/jobapply/internal/handlers"
+	"github.com/yourusername/jobapply/internal/models"
+	"github.com/yourusername/jobapply/internal/scrapers"
+	"github.com/yourusername/jobapply/internal/store"
+	"github.com/yourusername/jobapply/internal/validation"
+)
+
+// Job is the resolver for the job field.
+func (r *applicationResolver) Job(ctx context.Context, obj *models.Application) (*models.JobListing, error) {
+	job, ok, err := loadersFrom(ctx).jobs.load(obj.JobID)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Description is the resolver for the description field.
+func (r *jobResolver) Description(ctx context.Context, obj *models.JobListing) (string, error) {
+	_, description, err := r.jobs.JobText(ctx, obj.ID)
+	if errors.Is(err, store.ErrNotFound) {
+		return "", nil
+	}
+	return scrapers.SanitizeHTML(description), err
+}
+
+// Me is the resolver for the me field.
+func (r *queryResolver) Me(ctx context.Context) (*models.UserProfile, error) {
+	return r.users.Profile(ctx, handlers.UserIDFromContext(ctx))
+}
+
+// Job is the resolver for the job field.
+func (r *queryResolver) Job(ctx context.Context, id string) (*models.JobListing, error) {
+	if !validation.ValidateUUID(id) {
+		return nil, apierror.Invalid(&validation.FieldError{Field: "id", Message: "Invalid job ID format"})
+	}
+	job, ok, err := loadersFrom(ctx).jobs.load(id)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// SavedJobs is the resolver for the savedJobs field.
+func (r *queryResolver) SavedJobs(ctx context.Context, tags []string) ([]models.SavedJob, error) {
+	return r.jobs.SavedJobs(ctx, handlers.UserIDFromContext(ctx), tags)
+}
+
+// Applications is the resolver for the applications field.
+func (r *queryResolver) Applications(ctx context.Context, tags []string) ([]models.Application, error) {
+	return r.applications.Applications(ctx, handlers.UserIDFromContext(ctx), tags)
+}
+
+// Stats is the resolver for the stats field.
+func (r *queryResolver) Stats(ctx context.Context) (*models.UserStats, error) {
+	return r.users.Stats(ctx, handlers.UserIDFromContext(ctx))
+}
+
+// Job is the resolver for the job field.
+func (r *savedJobResolver) Job(ctx context.Context, obj *models.SavedJob) (*models.JobListing, error) {
+	return &obj.JobListing, nil
+}
+
+// ApplicationsByStatus is the resolver for the applicationsByStatus field.
+func (r *statsResolver) ApplicationsByStatus(ctx context.Context, obj *models.UserStats) ([]model.StatusCount, error) {
+	counts := make([]model.StatusCount, 0, len(obj.ApplicationsByStatus))
+	for status, count := range obj.ApplicationsByStatus {
+		counts = append(counts, model.StatusCount{Status: status, Count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool { return counts[i].Status < counts[j].Status })
+	return counts, nil
+}
+
+// Application returns ApplicationResolver implementation.
+func (r *Resolver) Application() ApplicationResolver { return &applicationResolver{r} }
+
+// Job returns JobResolver implementation.
+func (r *Resolver) Job() JobResolver { return &jobResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+// SavedJob returns SavedJobResolver implementation.
+func (r *Resolver) SavedJob() SavedJobResolver { return &savedJobResolver{r} }
+
+// Stats returns StatsResolver implementation.
+func (r *Resolver) Stats() StatsResolver { return &statsResolver{r} }
+
+type applicationResolver struct{ *Resolver }
+type jobResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
+type savedJobResolver struct{ *Resolver }
+type statsResolver struct{ *Resolver }
@@ -0,0 +1,261 @@
+// Package grpcapi serves the gRPC API defined in api/jobapply/v1. It shares the store layer,
+// tokens and error taxonomy with the REST handlers, so both APIs behave the same.
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	jobapplyv1 "github.com/yourusername/jobapply/api/jobapply/v1"
+	"github.com/yourusername/jobapply/internal/apierror"
+	"github.com/yourusername/jobapply/internal/audit"
+	"github.com/yourusername/jobapply/internal/handlers"
+	"github.com/yourusername/jobapply/internal/logging"
+	"github.com/yourusername/jobapply/internal/store"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+type contextKey int
+
+const userIDKey contextKey = iota
+
+// writeMethods are the calls that change data; each is logged in audit_log, like REST writes
+var writeMethods = map[string]bool{
+	jobapplyv1.JobService_SaveJob_FullMethodName:    true,
+	jobapplyv1.JobService_UnsaveJob_FullMethodName:  true,
+	jobapplyv1.JobService_DismissJob_FullMethodName: true,
+}
+
+// NewServer returns a gRPC server with every service registered. Calls must carry a bearer
+// token in the authorization metadata, so with AUTH_MODE=cookie every call is refused. h
+// supplies the auth mode and writes the audit log.
+func NewServer(stores *store.Store, h *handlers.Handler) *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(logCalls, recoverPanics, authenticate(h), auditWrites(h)),
+		// Streams only read, so they skip the audit log
+		grpc.ChainStreamInterceptor(logStreams, recoverStreamPanics, authenticateStream(h)),
+	)
+	jobapplyv1.RegisterProfileServiceServer(srv, &profileService{users: stores.Users})
+	jobapplyv1.RegisterJobServiceServer(srv, &jobService{jobs: stores.Jobs})
+	jobapplyv1.RegisterApplicationServiceServer(srv, &applicationService{applications: stores.Applications})
+	// Lets tools like grpcurl discover the services without the .proto file
+	reflection.Register(srv)
+	return srv
+}
+
+// userID returns the authenticated user; authenticate guarantees it is set
+func userID(ctx context.Context) string {
+	id, _ := ctx.Value(userIDKey).(string)
+	return id
+}
+
+func logCalls(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	logging.FromContext(ctx).Info("grpc call",
+		"method", info.FullMethod,
+		"code", status.Code(err).String(),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return resp, err
+}
+
+func logStreams(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	logging.FromContext(ss.Context()).Info("grpc stream",
+		"method", info.FullMethod,
+		"code", status.Code(err).String(),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return err
+}
+
+func recoverPanics(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			logging.FromContext(ctx).Error("gRPC handler panicked", "method", info.FullMethod, "panic", rec, "stack", string(debug.Stack()))
+			err = status.Error(codes.Internal, "Internal server error")
+		}
+	}()
+	return handler(ctx, req)
+}
+
+func recoverStreamPanics(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			logging.FromContext(ss.Context()).Error("gRPC handler panicked", "method", info.FullMethod, "panic", rec, "stack", string(debug.Stack()))
+			err = status.Error(codes.Internal, "Internal server error")
+		}
+	}()
+	return handler(srv, ss)
+}
+
+func authenticate(h *handlers.Handler) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authorize(ctx, h, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func authenticateStream(h *handlers.Handler) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(ss.Context(), h, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authorize checks the call's bearer token and returns ctx carrying the user
+func authorize(ctx context.Context, h *handlers.Handler, method string) (context.Context, error) {
+	// Reflection is public, like the REST API's OpenAPI document
+	if strings.HasPrefix(method, "/grpc.reflection.") {
+		return ctx, nil
+	}
+	if !h.BearerEnabled() {
+		return nil, status.Error(codes.Unauthenticated, "Bearer tokens are disabled; set AUTH_MODE=both to use the gRPC API")
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "Missing authorization metadata")
+	}
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "Invalid authorization metadata format")
+	}
+	id, err := handlers.UserIDFromToken(token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	ctx = context.WithValue(ctx, userIDKey, id)
+	return logging.With(ctx, "user_id", id), nil
+}
+
+// authedStream is a stream whose context carries the authenticated user
+type authedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authedStream) Context() context.Context {
+	return s.ctx
+}
+
+// auditWrites logs each write call once it has been handled, as the REST Audit middleware does
+// for write requests. gRPC has no impersonation or acting for clients, so the caller is both
+// the actor and, unless a store says otherwise, the subject. It must run after authenticate.
+func auditWrites(h *handlers.Handler) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !writeMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		auditCtx, entry := audit.Start(ctx)
+		resp, err := handler(auditCtx, req)
+
+		actorID := userID(ctx)
+		subjectID := entry.Subject()
+		if subjectID == "" {
+			subjectID = actorID
+		}
+		var ip string
+		if p, ok := peer.FromContext(ctx); ok {
+			ip = p.Addr.String()
+			if host, _, splitErr := net.SplitHostPort(ip); splitErr == nil {
+				ip = host
+			}
+		}
+		h.LogAudit(ctx, handlers.AuditRecord{
+			ActorID:   &actorID,
+			SubjectID: &subjectID,
+			Method:    "GRPC",
+			Route:     info.FullMethod,
+			Path:      info.FullMethod,
+			Status:    httpStatus(status.Code(err)),
+			Changes:   entry.Changes(),
+			IP:        ip,
+		})
+		return resp, err
+	}
+}
+
+// toStatus maps err through the API error taxonomy onto a gRPC status. Field errors are sent
+// as BadRequest details; server-side causes are logged, not sent.
+func toStatus(ctx context.Context, err error) error {
+	e := apierror.From(err)
+	if e.Status >= 500 {
+		logging.FromContext(ctx).Error(e.Message, "error", e.Cause)
+	}
+	st := status.New(grpcCode(e.Status), e.Message)
+	if len(e.Fields) > 0 {
+		details := &errdetails.BadRequest{}
+		for _, f := range e.Fields {
+			details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{Field: f.Field, Description: f.Message})
+		}
+		if withDetails, err := st.WithDetails(details); err == nil {
+			st = withDetails
+		}
+	}
+	return st.Err()
+}
+
+func grpcCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusRequestEntityTooLarge, http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return codes.Unavailable
+	}
+	return codes.Internal
+}
+
+// httpStatus is grpcCode's inverse, so the audit log records one kind of status for both APIs
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
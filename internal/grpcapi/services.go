@@ -0,0 +1,247 @@
+package grpcapi
+
+import (
+	"context"
+	"time"
+
+	jobapplyv1 "github.com/yourusername/jobapply/api/jobapply/v1"
+	"github.com/yourusername/jobapply/internal/apierror"
+	"github.com/yourusername/jobapply/internal/handlers"
+	"github.com/yourusername/jobapply/internal/models"
+	"github.com/yourusername/jobapply/internal/store"
+	"github.com/yourusername/jobapply/internal/validation"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type profileService struct {
+	jobapplyv1.UnimplementedProfileServiceServer
+	users store.UserStore
+}
+
+func (s *profileService) GetProfile(ctx context.Context, req *jobapplyv1.GetProfileRequest) (*jobapplyv1.Profile, error) {
+	profile, err := s.users.Profile(ctx, userID(ctx))
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return profileProto(profile), nil
+}
+
+type jobService struct {
+	jobapplyv1.UnimplementedJobServiceServer
+	jobs store.JobStore
+}
+
+func (s *jobService) GetJob(ctx context.Context, req *jobapplyv1.GetJobRequest) (*jobapplyv1.Job, error) {
+	if err := checkJobID(req.Id); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	job, err := s.jobs.Job(ctx, userID(ctx), req.Id)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	pb := jobProto(&job.JobListing)
+	pb.Description = job.Description
+	return pb, nil
+}
+
+func (s *jobService) ListSavedJobs(ctx context.Context, req *jobapplyv1.ListSavedJobsRequest) (*jobapplyv1.ListSavedJobsResponse, error) {
+	saved, err := s.jobs.SavedJobs(ctx, userID(ctx), req.Tags)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	resp := &jobapplyv1.ListSavedJobsResponse{Jobs: make([]*jobapplyv1.SavedJob, 0, len(saved))}
+	for i := range saved {
+		resp.Jobs = append(resp.Jobs, &jobapplyv1.SavedJob{
+			Job:      jobProto(&saved[i].JobListing),
+			Notes:    saved[i].Notes,
+			Priority: int32(saved[i].Priority),
+			SavedAt:  timestamppb.New(saved[i].SavedAt),
+		})
+	}
+	return resp, nil
+}
+
+func (s *jobService) SaveJob(ctx context.Context, req *jobapplyv1.SaveJobRequest) (*jobapplyv1.SaveJobResponse, error) {
+	if err := checkJobID(req.Id); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	save := handlers.SaveJobRequest{Notes: req.Notes, Priority: int(req.Priority)}
+	if err := save.Validate(); err != nil {
+		return nil, toStatus(ctx, apierror.Invalid(err))
+	}
+	if err := s.jobs.SaveJob(ctx, userID(ctx), req.Id, save.Notes, save.Priority); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &jobapplyv1.SaveJobResponse{}, nil
+}
+
+func (s *jobService) UnsaveJob(ctx context.Context, req *jobapplyv1.UnsaveJobRequest) (*jobapplyv1.UnsaveJobResponse, error) {
+	if err := checkJobID(req.Id); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	if err := s.jobs.UnsaveJob(ctx, userID(ctx), req.Id); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &jobapplyv1.UnsaveJobResponse{}, nil
+}
+
+func (s *jobService) DismissJob(ctx context.Context, req *jobapplyv1.DismissJobRequest) (*jobapplyv1.DismissJobResponse, error) {
+	if err := checkJobID(req.Id); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	if err := s.jobs.Dismiss(ctx, userID(ctx), req.Id); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &jobapplyv1.DismissJobResponse{}, nil
+}
+
+type applicationService struct {
+	jobapplyv1.UnimplementedApplicationServiceServer
+	applications store.ApplicationStore
+}
+
+func (s *applicationService) ListApplications(ctx context.Context, req *jobapplyv1.ListApplicationsRequest) (*jobapplyv1.ListApplicationsResponse, error) {
+	applications, err := s.applications.Applications(ctx, userID(ctx), req.Tags)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	resp := &jobapplyv1.ListApplicationsResponse{Applications: make([]*jobapplyv1.Application, 0, len(applications))}
+	for _, app := range applications {
+		resp.Applications = append(resp.Applications, &jobapplyv1.Application{
+			Id:            app.ID,
+			Status:        app.Status,
+			AppliedAt:     timestamppb.New(app.AppliedAt),
+			FieldsFilled:  app.FieldsFilled,
+			FieldsOmitted: app.FieldsOmitted,
+			ProfileId:     deref(app.ProfileID),
+			JobTitle:      app.JobTitle,
+			Company:       app.Company,
+			JobUrl:        app.JobURL,
+			Tags:          app.Tags,
+		})
+	}
+	return resp, nil
+}
+
+// watchInterval is how often WatchApplication checks for new status changes
+const watchInterval = 2 * time.Second
+
+func (s *applicationService) WatchApplication(req *jobapplyv1.WatchApplicationRequest, stream jobapplyv1.ApplicationService_WatchApplicationServer) error {
+	ctx := stream.Context()
+	if !validation.ValidateUUID(req.Id) {
+		return toStatus(ctx, apierror.Invalid(&validation.FieldError{Field: "id", Message: "Invalid application ID format"}))
+	}
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+	// By ID rather than count: a change committed late can sort before one already sent
+	sent := map[string]bool{}
+	for {
+		events, err := s.applications.ApplicationEvents(ctx, userID(ctx), req.Id)
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		if err != nil {
+			return toStatus(ctx, err)
+		}
+		for _, event := range events {
+			if sent[event.ID] {
+				continue
+			}
+			if err := stream.Send(&jobapplyv1.ApplicationEvent{
+				Id:            event.ID,
+				ApplicationId: event.ApplicationID,
+				From:          string(event.From),
+				To:            string(event.To),
+				CreatedAt:     timestamppb.New(event.CreatedAt),
+			}); err != nil {
+				return err
+			}
+			sent[event.ID] = true
+		}
+		if len(events) > 0 && events[len(events)-1].To.Final() {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func checkJobID(id string) error {
+	if !validation.ValidateUUID(id) {
+		return apierror.Invalid(&validation.FieldError{Field: "id", Message: "Invalid job ID format"})
+	}
+	return nil
+}
+
+func profileProto(p *models.UserProfile) *jobapplyv1.Profile {
+	pb := &jobapplyv1.Profile{
+		Id:            p.ID,
+		FullName:      p.FullName,
+		Email:         p.Email,
+		Phone:         p.Phone,
+		Skills:        p.Skills,
+		DesiredSalary: int32Ptr(p.DesiredSalary),
+		CreatedAt:     timestamppb.New(p.CreatedAt),
+		UpdatedAt:     timestamppb.New(p.UpdatedAt),
+	}
+	if p.Address != nil {
+		pb.Address = &jobapplyv1.Address{Street: p.Address.Street, City: p.Address.City, State: p.Address.State, ZipCode: p.Address.ZipCode}
+	}
+	for _, w := range p.WorkHistory {
+		pb.WorkHistory = append(pb.WorkHistory, &jobapplyv1.WorkHistory{
+			Company: w.Company, Title: w.Title, StartDate: w.StartDate, EndDate: w.EndDate, Description: w.Description,
+		})
+	}
+	for _, e := range p.Education {
+		pb.Education = append(pb.Education, &jobapplyv1.Education{
+			School: e.School, Degree: e.Degree, Major: e.Major, GradYear: int32(e.GradYear),
+		})
+	}
+	return pb
+}
+
+func jobProto(j *models.JobListing) *jobapplyv1.Job {
+	return &jobapplyv1.Job{
+		Id:         j.ID,
+		Site:       j.Site,
+		Title:      j.Title,
+		Company:    j.Company,
+		Location:   j.Location,
+		Url:        j.URL,
+		PostedDate: timestampPtr(j.PostedDate),
+		SalaryMin:  int32Ptr(j.SalaryMin),
+		SalaryMax:  int32Ptr(j.SalaryMax),
+		SalaryText: j.SalaryText,
+		ScrapedAt:  timestamppb.New(j.ScrapedAt),
+		Status:     j.Status,
+		Tags:       j.Tags,
+	}
+}
+
+func int32Ptr(n *int) *int32 {
+	if n == nil {
+		return nil
+	}
+	v := int32(*n)
+	return &v
+}
+
+func timestampPtr(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/yourusername/jobapply/internal/services"
+)
+
+const (
+	defaultTaskRunsLimit = 20
+	maxTaskRunsLimit     = 100
+)
+
+// SetScheduler makes background tasks visible to the admin endpoints
+func (h *Handler) SetScheduler(s *services.Scheduler) {
+	h.scheduler = s
+}
+
+// RequireAdmin only lets admins through; it must run after AuthMiddleware. Admins are marked
+// with user_profiles.is_admin.
+func (h *Handler) RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID := getUserIDFromContext(r.Context())
+		if userID == "" {
+			h.error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if getImpersonationFromContext(r.Context()) != nil {
+			h.error(w, "Not available while impersonating", http.StatusForbidden)
+			return
+		}
+
+		user, err := h.users.UserByID(r.Context(), userID)
+		if err != nil {
+			h.fail(w, r, err)
+			return
+		}
+		if !user.IsAdmin {
+			h.error(w, "Admin access required", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ListTasks lists background tasks with their schedules and latest runs
+func (h *Handler) ListTasks(w http.ResponseWriter, r *http.Request) {
+	if h.scheduler == nil {
+		h.json(w, []services.TaskStatus{}, http.StatusOK)
+		return
+	}
+
+	tasks, err := h.scheduler.Tasks(r.Context())
+	if err != nil {
+		h.internalError(w, r, "Failed to list tasks", err)
+		return
+	}
+	h.json(w, tasks, http.StatusOK)
+}
+
+// ListTaskRuns returns a task's recent runs, newest first
+func (h *Handler) ListTaskRuns(w http.ResponseWriter, r *http.Request) {
+	limit := defaultTaskRunsLimit
+	if l := r.URL.Query().Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 || n > maxTaskRunsLimit {
+			h.error(w, fmt.Sprintf("limit must be between 1 and %d", maxTaskRunsLimit), http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	if h.scheduler == nil {
+		h.error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+	runs, err := h.scheduler.Runs(r.Context(), chi.URLParam(r, "name"), limit)
+	if errors.Is(err, services.ErrUnknownTask) {
+		h.error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.internalError(w, r, "Failed to list task runs", err)
+		return
+	}
+	h.json(w, runs, http.StatusOK)
+}
+
+// RunTask starts a background task now, even one that is disabled on a schedule
+func (h *Handler) RunTask(w http.ResponseWriter, r *http.Request) {
+	if h.scheduler == nil {
+		h.error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+
+	err := h.scheduler.Trigger(chi.URLParam(r, "name"), getUserIDFromContext(r.Context()))
+	switch {
+	case errors.Is(err, services.ErrUnknownTask):
+		h.error(w, "Task not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrTaskRunning):
+		h.error(w, "Task is already running", http.StatusConflict)
+	case errors.Is(err, services.ErrStopped):
+		h.error(w, "Server is shutting down", http.StatusServiceUnavailable)
+	case err != nil:
+		h.internalError(w, r, "Failed to start task", err)
+	default:
+		h.json(w, message{"message": "Task started"}, http.StatusAccepted)
+	}
+}
+
+// ListDeadLetters lists events the outbox gave up delivering, most recent first
+func (h *Handler) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	limit := defaultTaskRunsLimit
+	if l := r.URL.Query().Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 || n > maxTaskRunsLimit {
+			h.error(w, fmt.Sprintf("limit must be between 1 and %d", maxTaskRunsLimit), http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	if h.deliveries == nil {
+		h.json(w, []services.DeadLetter{}, http.StatusOK)
+		return
+	}
+	letters, err := h.deliveries.DeadLetters(r.Context(), limit)
+	if err != nil {
+		h.internalError(w, r, "Failed to list dead letters", err)
+		return
+	}
+	if letters == nil {
+		letters = []services.DeadLetter{}
+	}
+	h.json(w, letters, http.StatusOK)
+}
+
+// RetryDeadLetter queues a dead-lettered event for delivery again, e.g. once a webhook
+// receiver is fixed
+func (h *Handler) RetryDeadLetter(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, eventID, "event ID") {
+		return
+	}
+	if h.deliveries == nil {
+		h.error(w, "Dead letter not found", http.StatusNotFound)
+		return
+	}
+	err := h.deliveries.Retry(r.Context(), eventID)
+	if errors.Is(err, services.ErrUnknownEvent) {
+		h.error(w, "Dead letter not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.internalError(w, r, "Failed to retry event", err)
+		return
+	}
+	h.json(w, message{"message": "Event queued for delivery"}, http.StatusAccepted)
+}
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/yourusername/jobapply/internal/models"
+	"github.com/yourusername/jobapply/internal/scrapers"
+	"github.com/yourusername/jobapply/internal/store"
+	"github.com/yourusername/jobapply/internal/validation"
+)
+
+const (
+	maxImportRows     = 2000
+	maxImportCSVBytes = 2 << 20
+)
+
+// importColumns maps the header names spreadsheets commonly use onto the import's fields
+var importColumns = map[string]string{
+	"date": "date", "applied": "date", "applied at": "date", "applied on": "date", "date applied": "date", "application date": "date",
+	"company": "company", "employer": "company", "organization": "company",
+	"title": "title", "job title": "title", "position": "title", "role": "title",
+	"url": "url", "link": "url", "job url": "url", "posting": "url", "job link": "url",
+	"status": "status", "stage": "status",
+}
+
+// importDateLayouts are tried in order; US month-first dates win over day-first
+var importDateLayouts = []string{
+	time.DateOnly, time.RFC3339, "2006/01/02", "01/02/2006", "1/2/2006", "01/02/06", "1/2/06",
+	"Jan 2, 2006", "January 2, 2006", "2 Jan 2006", "02-Jan-2006",
+}
+
+// importStatuses maps spreadsheet statuses onto the status machine. Anything else, like
+// "interviewing" or "rejected", was still sent, so it is submitted; the original is kept
+// as the external status either way.
+var importStatuses = map[string]models.ApplicationStatus{
+	"saved": models.ApplicationPending, "wishlist": models.ApplicationPending, "draft": models.ApplicationPending,
+	"planned": models.ApplicationPending, "to apply": models.ApplicationPending, "pending": models.ApplicationPending,
+	"withdrawn": models.ApplicationCancelled, "withdrew": models.ApplicationCancelled,
+	"cancelled": models.ApplicationCancelled, "canceled": models.ApplicationCancelled,
+}
+
+// ImportRowError is a CSV row that couldn't be imported; rows count from 1 for the header
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
+
+type ImportApplicationsResponse struct {
+	Imported       int              `json:"imported"`
+	Duplicates     int              `json:"duplicates"` // Rows for jobs already in the pipeline
+	ApplicationIDs []string         `json:"application_ids"`
+	Errors         []ImportRowError `json:"errors"`
+}
+
+// ImportApplications handles POST /api/v1/applications/import: a CSV of applications made
+// elsewhere, with a header row naming date, company, title and optionally url and status
+// columns. It is sent as the "file" field of a form, or as a text/csv body. Rows with
+// errors are reported and the rest imported; importing the same file again adds nothing.
+func (h *Handler) ImportApplications(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var body io.Reader = http.MaxBytesReader(w, r.Body, maxImportCSVBytes)
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		if err := r.ParseMultipartForm(maxImportCSVBytes); err != nil {
+			h.error(w, "File too large or invalid request", http.StatusBadRequest)
+			return
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			h.error(w, "Failed to read file", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		if !validation.ValidateFileExtension(validation.SanitizeFilename(header.Filename), []string{".csv"}) {
+			h.error(w, "Only CSV files allowed", http.StatusBadRequest)
+			return
+		}
+		body = file
+	}
+
+	apps, rowErrors, err := parseApplicationsCSV(body, userID, time.Now())
+	if err != nil {
+		h.error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := ImportApplicationsResponse{ApplicationIDs: []string{}, Errors: rowErrors}
+	if len(apps) > 0 {
+		result, err := h.applications.ImportApplications(r.Context(), userID, apps)
+		if err != nil {
+			h.fail(w, r, err)
+			return
+		}
+		resp.ApplicationIDs = result.IDs
+		resp.Imported = len(result.IDs)
+		resp.Duplicates = len(result.Duplicates)
+	}
+	h.json(w, resp, http.StatusOK)
+}
+
+// parseApplicationsCSV reads an import, returning the valid rows and what was wrong with the
+// others. It fails only when the file as a whole is unusable.
+func parseApplicationsCSV(body io.Reader, userID string, now time.Time) ([]store.ImportedApplication, []ImportRowError, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, errors.New("CSV is empty or unreadable")
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if field, ok := importColumns[name]; ok {
+			if _, dup := columns[field]; !dup {
+				columns[field] = i
+			}
+		}
+	}
+	for _, required := range []string{"date", "company", "title"} {
+		if _, ok := columns[required]; !ok {
+			return nil, nil, fmt.Errorf("CSV header has no %s column", required)
+		}
+	}
+
+	apps := []store.ImportedApplication{}
+	rowErrors := []ImportRowError{}
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var maxBytes *http.MaxBytesError
+		if errors.As(err, &maxBytes) {
+			return nil, nil, fmt.Errorf("CSV must be at most %d MB", maxImportCSVBytes>>20)
+		}
+		if err != nil {
+			rowErrors = append(rowErrors, ImportRowError{Row: row, Message: "Malformed CSV row"})
+			continue
+		}
+		if len(apps)+len(rowErrors) >= maxImportRows {
+			return nil, nil, fmt.Errorf("CSV can have at most %d rows", maxImportRows)
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if strings.Join(record, "") == "" {
+			continue
+		}
+
+		app, problem := importedApplication(field, userID, now)
+		if problem != "" {
+			rowErrors = append(rowErrors, ImportRowError{Row: row, Message: problem})
+			continue
+		}
+		apps = append(apps, app)
+	}
+	return apps, rowErrors, nil
+}
+
+// importedApplication checks one row, returning what is wrong with it if anything
+func importedApplication(field func(string) string, userID string, now time.Time) (store.ImportedApplication, string) {
+	app := store.ImportedApplication{
+		Company:        validation.SanitizeString(field("company"), 200),
+		Title:          validation.SanitizeString(field("title"), 300),
+		ExternalStatus: validation.SanitizeString(field("status"), 100),
+	}
+	if app.Company == "" || app.Title == "" {
+		return app, "company and title are required"
+	}
+
+	date := field("date")
+	for _, layout := range importDateLayouts {
+		if t, err := time.Parse(layout, date); err == nil {
+			app.AppliedAt = t
+			break
+		}
+	}
+	if app.AppliedAt.IsZero() {
+		return app, fmt.Sprintf("Unrecognized date %q; use YYYY-MM-DD", date)
+	}
+	if app.AppliedAt.After(now) {
+		return app, "date is in the future"
+	}
+
+	app.Status = models.ApplicationSubmitted
+	if status, ok := importStatuses[strings.ToLower(app.ExternalStatus)]; ok {
+		app.Status = status
+	}
+
+	if raw := field("url"); raw != "" {
+		// Checked once canonical, since that unwraps redirect links to their target
+		app.URL = scrapers.CanonicalURL(raw)
+		u, err := url.Parse(app.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return app, "url must be an http(s) URL"
+		}
+		if !scrapers.PublicHost(u.Host) {
+			return app, "url host is not public"
+		}
+	} else {
+		// Without a URL the job is the user's own, so the same row imports to the same job
+		key := sha256.Sum256([]byte(userID + "|" + strings.ToLower(app.Company) + "|" + strings.ToLower(app.Title) + "|" + app.AppliedAt.Format(time.DateOnly)))
+		app.URL = fmt.Sprintf("urn:jobapply:import:%x", key[:16])
+	}
+	return app, ""
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/yourusername/jobapply/internal/models"
+	"github.com/yourusername/jobapply/internal/store"
+	"github.com/yourusername/jobapply/internal/validation"
+)
+
+// maxApplyErrorLen bounds the error an apply attempt reports, e.g. a browser stack trace
+const maxApplyErrorLen = 10_000
+
+// CreateApplicationRequest starts an application to one of the user's jobs
+type CreateApplicationRequest struct {
+	JobID     string `json:"job_id"`
+	ProfileID string `json:"profile_id"` // Persona to apply as; "" for the base profile
+	// Status defaults to pending. A later status records an attempt the apply engine already
+	// made, with its fields and error; pending and in_progress take neither.
+	Status        models.ApplicationStatus `json:"status"`
+	FieldsFilled  []string                 `json:"fields_filled"`
+	FieldsOmitted []string                 `json:"fields_omitted"`
+	Error         string                   `json:"error"`
+}
+
+func (req *CreateApplicationRequest) Validate() error {
+	var v validation.Collector
+	if v.Required("job_id", req.JobID) {
+		v.Check(validation.ValidateUUID(req.JobID), "job_id", "job_id must be a UUID")
+	}
+	if req.ProfileID != "" {
+		v.Check(validation.ValidateUUID(req.ProfileID), "profile_id", "profile_id must be a UUID")
+	}
+	if req.Status == "" {
+		req.Status = models.ApplicationPending
+	}
+	v.Check(req.Status.Valid(), "status", "status is not a known application status")
+	req.Error = strings.TrimSpace(validation.SanitizeString(req.Error, maxApplyErrorLen))
+	if req.Status.Initial() {
+		v.Check(len(req.FieldsFilled) == 0, "fields_filled", "fields_filled needs a status past in_progress")
+		v.Check(len(req.FieldsOmitted) == 0, "fields_omitted", "fields_omitted needs a status past in_progress")
+		v.Check(req.Error == "", "error", "error needs a status past in_progress")
+	}
+	return v.Err()
+}
+
+// UpdateApplicationStatusRequest is the outcome of an apply attempt, or the user cancelling.
+// Fields left out keep their stored values; an empty error clears it.
+type UpdateApplicationStatusRequest struct {
+	Status        models.ApplicationStatus `json:"status"`
+	FieldsFilled  []string                 `json:"fields_filled"`
+	FieldsOmitted []string                 `json:"fields_omitted"` // Left blank as never-autofill
+	Error         *string                  `json:"error"`          // Why a failed attempt failed
+}
+
+func (req *UpdateApplicationStatusRequest) Validate() error {
+	var v validation.Collector
+	if v.Required("status", string(req.Status)) {
+		v.Check(req.Status.Valid(), "status", "status is not a known application status")
+	}
+	if req.Error != nil {
+		*req.Error = strings.TrimSpace(validation.SanitizeString(*req.Error, maxApplyErrorLen))
+	}
+	return v.Err()
+}
+
+// ApplicationStatusResponse is an application's ID and status after a write
+type ApplicationStatusResponse struct {
+	ID     string                   `json:"id"`
+	Status models.ApplicationStatus `json:"status"`
+}
+
+// CreateApplication handles POST /api/v1/applications. An application created with a status
+// past in_progress is created in progress and moved on in the same transaction, so a failed
+// write leaves no half-recorded attempt.
+func (h *Handler) CreateApplication(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req CreateApplicationRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	var personaID *string
+	if req.ProfileID != "" {
+		personaID = &req.ProfileID
+	}
+
+	var id string
+	err := h.stores.InTx(r.Context(), func(tx *store.Store) error {
+		start := req.Status
+		if !start.Initial() {
+			start = models.ApplicationInProgress
+		}
+		var err error
+		id, err = tx.Applications.CreateApplication(r.Context(), userID, req.JobID, personaID, start)
+		if err != nil || start == req.Status {
+			return err
+		}
+		return tx.Applications.UpdateStatus(r.Context(), userID, id, store.ApplicationUpdate{
+			Status:        req.Status,
+			FieldsFilled:  req.FieldsFilled,
+			FieldsOmitted: req.FieldsOmitted,
+			ErrorLog:      &req.Error,
+		})
+	})
+	if errors.Is(err, store.ErrNotFound) {
+		h.error(w, "Job or profile not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+	h.json(w, ApplicationStatusResponse{ID: id, Status: req.Status}, http.StatusCreated)
+}
+
+// UpdateApplicationStatus handles PATCH /api/v1/applications/{id}/status. Moves the status
+// machine doesn't allow, and any update to a submitted or cancelled application, are refused
+// with a 409.
+func (h *Handler) UpdateApplicationStatus(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	applicationID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, applicationID, "application ID") {
+		return
+	}
+	var req UpdateApplicationStatusRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+
+	err := h.applications.UpdateStatus(r.Context(), userID, applicationID, store.ApplicationUpdate{
+		Status:        req.Status,
+		FieldsFilled:  req.FieldsFilled,
+		FieldsOmitted: req.FieldsOmitted,
+		ErrorLog:      req.Error,
+	})
+	if errors.Is(err, store.ErrNotFound) {
+		h.error(w, "Application not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+	h.json(w, ApplicationStatusResponse{ID: applicationID, Status: req.Status}, http.StatusOK)
+}
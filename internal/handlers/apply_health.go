@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultApplyHealthDays = 30
+	maxApplyHealthDays     = 365
+	applyFailureReasons    = 10
+	applyRecentFailures    = 20
+)
+
+// applyDomainSQL is the lowercased host of a row of jobs' URL, without "www."
+const applyDomainSQL = `regexp_replace(lower(substring(j.url from '^[a-zA-Z][a-zA-Z0-9+.-]*://(?:[^@/]*@)?([^/:?#]+)')), '^www\.', '')`
+
+// ApplyHealth summarizes how applications have fared per site, so maintenance goes to the
+// sites that break most
+type ApplyHealth struct {
+	Since          time.Time            `json:"since"`
+	Sites          []SiteApplyStats     `json:"sites"`
+	FailureReasons []ApplyFailureReason `json:"failure_reasons"`
+	RecentFailures []ApplyFailure       `json:"recent_failures"`
+}
+
+// SiteApplyStats counts a site's applications by outcome
+type SiteApplyStats struct {
+	Domain     string `json:"domain"`
+	Total      int    `json:"total"`
+	Submitted  int    `json:"submitted"`
+	Failed     int    `json:"failed"`
+	TimedOut   int    `json:"timed_out"`
+	Cancelled  int    `json:"cancelled"`
+	InProgress int    `json:"in_progress"` // Pending, in progress or paused
+	// SuccessRate is submitted over submitted, failed and timed out; nil until one finishes
+	SuccessRate *float64 `json:"success_rate,omitempty"`
+}
+
+// ApplyFailureReason is a failure message and how often it was seen
+type ApplyFailureReason struct {
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+// ApplyFailure is one failed or timed out application
+type ApplyFailure struct {
+	ApplicationID string    `json:"application_id"`
+	Domain        string    `json:"domain"`
+	Status        string    `json:"status"`
+	Reason        string    `json:"reason,omitempty"`
+	FailedAt      time.Time `json:"failed_at"`
+}
+
+// GetApplyHealth reports application outcomes by site over the last days (default 30): success
+// rates, the most common failure reasons, and the latest failures
+func (h *Handler) GetApplyHealth(w http.ResponseWriter, r *http.Request) {
+	days := defaultApplyHealthDays
+	if d := r.URL.Query().Get("days"); d != "" {
+		n, err := strconv.Atoi(d)
+		if err != nil || n < 1 || n > maxApplyHealthDays {
+			h.error(w, fmt.Sprintf("days must be between 1 and %d", maxApplyHealthDays), http.StatusBadRequest)
+			return
+		}
+		days = n
+	}
+	health := ApplyHealth{
+		Since:          time.Now().AddDate(0, 0, -days),
+		Sites:          []SiteApplyStats{},
+		FailureReasons: []ApplyFailureReason{},
+		RecentFailures: []ApplyFailure{},
+	}
+
+	rows, err := h.db.Query(r.Context(), `
+		SELECT COALESCE(`+applyDomainSQL+`, ''), COUNT(*),
+			COUNT(*) FILTER (WHERE a.status = 'submitted'),
+			COUNT(*) FILTER (WHERE a.status = 'failed'),
+			COUNT(*) FILTER (WHERE a.status = 'timeout'),
+			COUNT(*) FILTER (WHERE a.status = 'cancelled'),
+			COUNT(*) FILTER (WHERE a.status IN ('pending', 'in_progress', 'paused'))
+		FROM applications a JOIN jobs j ON j.id = a.job_id
+		WHERE a.created_at >= $1
+		GROUP BY 1
+		ORDER BY COUNT(*) FILTER (WHERE a.status IN ('failed', 'timeout')) DESC, COUNT(*) DESC, 1
+	`, health.Since)
+	if err != nil {
+		h.internalError(w, r, "Failed to get apply health", err)
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var s SiteApplyStats
+		if err := rows.Scan(&s.Domain, &s.Total, &s.Submitted, &s.Failed, &s.TimedOut, &s.Cancelled, &s.InProgress); err != nil {
+			h.internalError(w, r, "Failed to get apply health", err)
+			return
+		}
+		if finished := s.Submitted + s.Failed + s.TimedOut; finished > 0 {
+			rate := float64(s.Submitted) / float64(finished)
+			s.SuccessRate = &rate
+		}
+		health.Sites = append(health.Sites, s)
+	}
+	if err := rows.Err(); err != nil {
+		h.internalError(w, r, "Failed to get apply health", err)
+		return
+	}
+
+	// Only the first line of an error log is compared, so stack traces don't split reasons
+	rows, err = h.db.Query(r.Context(), `
+		SELECT LEFT(split_part(a.error_log, E'\n', 1), 200) AS reason, COUNT(*)
+		FROM applications a
+		WHERE a.created_at >= $1 AND a.status IN ('failed', 'timeout') AND COALESCE(a.error_log, '') <> ''
+		GROUP BY reason ORDER BY COUNT(*) DESC, reason LIMIT $2
+	`, health.Since, applyFailureReasons)
+	if err != nil {
+		h.internalError(w, r, "Failed to get apply health", err)
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var reason ApplyFailureReason
+		if err := rows.Scan(&reason.Reason, &reason.Count); err != nil {
+			h.internalError(w, r, "Failed to get apply health", err)
+			return
+		}
+		health.FailureReasons = append(health.FailureReasons, reason)
+	}
+	if err := rows.Err(); err != nil {
+		h.internalError(w, r, "Failed to get apply health", err)
+		return
+	}
+
+	rows, err = h.db.Query(r.Context(), `
+		SELECT a.id, COALESCE(`+applyDomainSQL+`, ''), a.status, COALESCE(LEFT(a.error_log, 500), ''), f.failed_at
+		FROM applications a JOIN jobs j ON j.id = a.job_id
+		CROSS JOIN LATERAL (
+			SELECT COALESCE(MAX(e.created_at), a.created_at) AS failed_at
+			FROM application_events e WHERE e.application_id = a.id AND e.to_status = a.status
+		) f
+		WHERE a.created_at >= $1 AND a.status IN ('failed', 'timeout')
+		ORDER BY f.failed_at DESC LIMIT $2
+	`, health.Since, applyRecentFailures)
+	if err != nil {
+		h.internalError(w, r, "Failed to get apply health", err)
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var f ApplyFailure
+		if err := rows.Scan(&f.ApplicationID, &f.Domain, &f.Status, &f.Reason, &f.FailedAt); err != nil {
+			h.internalError(w, r, "Failed to get apply health", err)
+			return
+		}
+		health.RecentFailures = append(health.RecentFailures, f)
+	}
+	if err := rows.Err(); err != nil {
+		h.internalError(w, r, "Failed to get apply health", err)
+		return
+	}
+
+	h.json(w, health, http.StatusOK)
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/yourusername/jobapply/internal/audit"
+	"github.com/yourusername/jobapply/internal/logging"
+	"github.com/yourusername/jobapply/internal/middleware"
+)
+
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 500
+)
+
+// AuditRecord is one write request in the audit log
+type AuditRecord struct {
+	ID string `json:"id"`
+	// ActorID made the request; SubjectID owns the data it acted on, when that is someone else
+	ActorID   *string        `json:"actor_id,omitempty"`
+	SubjectID *string        `json:"subject_id,omitempty"`
+	Method    string         `json:"method"`
+	Route     string         `json:"route"` // e.g. /api/v1/applications/{id}
+	Path      string         `json:"path"`
+	Status    int            `json:"status"`
+	Changes   []audit.Change `json:"changes"`
+	RequestID string         `json:"request_id,omitempty"`
+	IP        string         `json:"ip,omitempty"`
+	// Impersonation is set for requests an admin made as SubjectID
+	Impersonation bool      `json:"impersonation"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// statusWriter remembers the response status for the audit log
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Audit records each write request in audit_log once it has been handled: who made it, for
+// whom, the route and status, and the changes the stores recorded. Reads aren't logged,
+// except under impersonation, where every request is logged with the admin as the actor. It
+// must run after AuthMiddleware or ExtensionAuth.
+func (h *Handler) Audit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		imp := getImpersonationFromContext(r.Context())
+		if safeMethod(r.Method) && imp == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, entry := audit.Start(r.Context())
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r.WithContext(ctx))
+
+		actorID := getUserIDFromContext(r.Context())
+		subjectID := entry.Subject()
+		if subjectID == "" {
+			subjectID = actorID
+		}
+		if imp != nil {
+			actorID = imp.AdminID
+		}
+		route := r.URL.Path
+		if rc := chi.RouteContext(r.Context()); rc != nil && rc.RoutePattern() != "" {
+			route = rc.RoutePattern()
+		}
+
+		h.LogAudit(r.Context(), AuditRecord{
+			ActorID:       &actorID,
+			SubjectID:     &subjectID,
+			Method:        r.Method,
+			Route:         route,
+			Path:          r.URL.Path,
+			Status:        sw.status,
+			Changes:       entry.Changes(),
+			RequestID:     w.Header().Get(middleware.RequestIDHeader),
+			IP:            middleware.ClientIP(r),
+			Impersonation: imp != nil,
+		})
+	})
+}
+
+// LogAudit writes rec to audit_log; the database assigns its ID and time. It is how callers
+// outside the Audit middleware, such as the gRPC API, log their writes. A failed write is
+// logged, not returned, so it doesn't fail the request it records.
+func (h *Handler) LogAudit(ctx context.Context, rec AuditRecord) {
+	if rec.Changes == nil {
+		rec.Changes = []audit.Change{}
+	}
+	// The client may be gone by now; the entry is written regardless
+	_, err := h.db.Exec(context.WithoutCancel(ctx), `
+		INSERT INTO audit_log (actor_id, subject_id, method, route, path, status, changes, request_id, ip, impersonation)
+		VALUES (NULLIF($1, '')::uuid, NULLIF($2, '')::uuid, $3, $4, $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''), $10)
+	`, deref(rec.ActorID), deref(rec.SubjectID), rec.Method, rec.Route, rec.Path, rec.Status, rec.Changes,
+		rec.RequestID, rec.IP, rec.Impersonation)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to write audit log", "error", err)
+	}
+}
+
+// ListAuditLog returns the most recent write requests, optionally only those made by or on
+// behalf of one user, or since a time
+func (h *Handler) ListAuditLog(w http.ResponseWriter, r *http.Request) {
+	limit := defaultAuditLimit
+	if l := r.URL.Query().Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 || n > maxAuditLimit {
+			h.error(w, fmt.Sprintf("limit must be between 1 and %d", maxAuditLimit), http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	args := []any{limit}
+	where := "TRUE"
+	if userID := r.URL.Query().Get("user_id"); userID != "" {
+		if !h.validateUUID(w, userID, "user_id") {
+			return
+		}
+		args = append(args, userID)
+		where += fmt.Sprintf(" AND (actor_id = $%d OR subject_id = $%[1]d)", len(args))
+	}
+	if since := r.URL.Query().Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			h.error(w, "since must be an RFC 3339 time", http.StatusBadRequest)
+			return
+		}
+		args = append(args, t)
+		where += fmt.Sprintf(" AND created_at >= $%d", len(args))
+	}
+
+	rows, err := h.db.Query(r.Context(), `
+		SELECT id, actor_id::text, subject_id::text, method, route, path, status, changes,
+			COALESCE(request_id, ''), COALESCE(ip, ''), impersonation, created_at
+		FROM audit_log WHERE `+where+`
+		ORDER BY created_at DESC LIMIT $1
+	`, args...)
+	if err != nil {
+		h.internalError(w, r, "Failed to list audit log", err)
+		return
+	}
+	defer rows.Close()
+
+	records := []AuditRecord{}
+	for rows.Next() {
+		var rec AuditRecord
+		var changes []byte
+		if err := rows.Scan(&rec.ID, &rec.ActorID, &rec.SubjectID, &rec.Method, &rec.Route, &rec.Path,
+			&rec.Status, &changes, &rec.RequestID, &rec.IP, &rec.Impersonation, &rec.CreatedAt); err != nil {
+			h.internalError(w, r, "Failed to list audit log", err)
+			return
+		}
+		if err := json.Unmarshal(changes, &rec.Changes); err != nil {
+			h.internalError(w, r, "Failed to list audit log", err)
+			return
+		}
+		records = append(records, rec)
+	}
+	if err := rows.Err(); err != nil {
+		h.internalError(w, r, "Failed to list audit log", err)
+		return
+	}
+	h.json(w, records, http.StatusOK)
+}
@@ -2,13 +2,14 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/yourusername/jobapply/internal/events"
+	"github.com/yourusername/jobapply/internal/store"
 	"github.com/yourusername/jobapply/internal/validation"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -27,10 +28,12 @@ type LoginRequest struct {
 }
 
 type AuthResponse struct {
-	Token  string `json:"token"`
+	Token  string `json:"token,omitempty"` // Left out when only cookie auth is enabled
 	UserID string `json:"user_id"`
 	Email  string `json:"email"`
 	Name   string `json:"name"`
+	// CSRFToken is sent in the X-CSRF-Token header with cookie-authenticated writes
+	CSRFToken string `json:"csrf_token,omitempty"`
 }
 
 type ChangePasswordRequest struct {
@@ -42,93 +45,92 @@ type UpdateEmailRequest struct {
 	NewEmail string `json:"new_email"`
 }
 
-// Signup creates a new user account
-func (h *Handler) Signup(w http.ResponseWriter, r *http.Request) {
-	var req SignupRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.error(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
+const passwordRules = "must be 6-128 characters with at least one letter and one number"
 
-	// Validate and sanitize input to prevent XSS and injection attacks
-	if req.FullName == "" || req.Email == "" || req.Password == "" {
-		h.error(w, "full_name, email, and password are required", http.StatusBadRequest)
-		return
+// Validate sanitizes the name and checks every field
+func (req *SignupRequest) Validate() error {
+	var v validation.Collector
+	// Sanitize full name (remove HTML, limit length) to prevent XSS
+	req.FullName = validation.SanitizeString(req.FullName, 100)
+	v.Required("full_name", req.FullName)
+	if v.Required("email", req.Email) {
+		v.Check(validation.ValidateEmail(req.Email), "email", "Invalid email format")
+	}
+	if v.Required("password", req.Password) {
+		v.Check(validation.ValidatePassword(req.Password), "password", "Password "+passwordRules)
 	}
+	return v.Err()
+}
 
-	// Sanitize full name (remove HTML, limit length)
-	req.FullName = validation.SanitizeString(req.FullName, 100)
-	if req.FullName == "" {
-		h.error(w, "Invalid full name", http.StatusBadRequest)
-		return
+func (req *LoginRequest) Validate() error {
+	var v validation.Collector
+	v.Required("email", req.Email)
+	v.Required("password", req.Password)
+	return v.Err()
+}
+
+func (req *ChangePasswordRequest) Validate() error {
+	var v validation.Collector
+	v.Required("current_password", req.CurrentPassword)
+	if v.Required("new_password", req.NewPassword) {
+		v.Check(validation.ValidatePassword(req.NewPassword), "new_password", "New password "+passwordRules)
 	}
+	return v.Err()
+}
 
-	// Validate email format using regex to prevent injection
-	if !validation.ValidateEmail(req.Email) {
-		h.error(w, "Invalid email format", http.StatusBadRequest)
-		return
+func (req *UpdateEmailRequest) Validate() error {
+	var v validation.Collector
+	if v.Required("new_email", req.NewEmail) {
+		v.Check(validation.ValidateEmail(req.NewEmail), "new_email", "Invalid email format")
 	}
+	return v.Err()
+}
 
-	// Validate password strength (6+ chars, must have letter and number)
-	if !validation.ValidatePassword(req.Password) {
-		h.error(w, "Password must be 6-128 characters with at least one letter and one number", http.StatusBadRequest)
+// Signup creates a new user account
+func (h *Handler) Signup(w http.ResponseWriter, r *http.Request) {
+	var req SignupRequest
+	if !h.decodeJSON(w, r, &req) {
 		return
 	}
 
 	// Hash password
 	passwordHash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
-		h.error(w, "Failed to process password", http.StatusInternalServerError)
+		h.internalError(w, r, "Failed to process password", err)
 		return
 	}
 
 	// Create user
-	query := `
-		INSERT INTO user_profiles (full_name, email, password_hash)
-		VALUES ($1, $2, $3)
-		RETURNING id, full_name, email, created_at
-	`
-
-	var userID, fullName, email string
-	var createdAt time.Time
-	err = h.db.QueryRow(r.Context(), query, req.FullName, req.Email, string(passwordHash)).
-		Scan(&userID, &fullName, &email, &createdAt)
-
+	user, err := h.users.CreateUser(r.Context(), req.FullName, req.Email, string(passwordHash))
 	if err != nil {
-		if strings.Contains(err.Error(), "duplicate key") {
+		if errors.Is(err, store.ErrConflict) {
 			h.error(w, "Email already registered", http.StatusConflict)
 			return
 		}
-		h.error(w, fmt.Sprintf("Failed to create user: %v", err), http.StatusInternalServerError)
+		h.internalError(w, r, "Failed to create user", err)
 		return
 	}
 
-	// Generate JWT token
-	token, err := generateJWT(userID, email)
+	// Generate JWT token, also set as the session cookie in the cookie auth modes
+	token, csrf, err := h.issueToken(w, user.ID, user.Email)
 	if err != nil {
-		h.error(w, "Failed to generate token", http.StatusInternalServerError)
+		h.internalError(w, r, "Failed to generate token", err)
 		return
 	}
 
 	h.json(w, AuthResponse{
-		Token:  token,
-		UserID: userID,
-		Email:  email,
-		Name:   fullName,
+		Token:     token,
+		UserID:    user.ID,
+		Email:     user.Email,
+		Name:      user.FullName,
+		CSRFToken: csrf,
 	}, http.StatusCreated)
 }
 
 // Login authenticates a user
 func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	var req LoginRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.error(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	// Validate input to prevent injection attacks
-	if req.Email == "" || req.Password == "" {
-		h.error(w, "email and password are required", http.StatusBadRequest)
+	if !h.decodeJSON(w, r, &req) {
 		return
 	}
 
@@ -145,40 +147,33 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get user from database
-	query := `
-		SELECT id, full_name, email, password_hash
-		FROM user_profiles
-		WHERE email = $1
-	`
-
-	var userID, fullName, email, passwordHash string
-	err := h.db.QueryRow(r.Context(), query, req.Email).
-		Scan(&userID, &fullName, &email, &passwordHash)
-
+	user, err := h.users.UserByEmail(r.Context(), req.Email)
 	if err != nil {
 		h.error(w, "Invalid email or password", http.StatusUnauthorized)
 		return
 	}
 
 	// Verify password
-	err = bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(req.Password))
+	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password))
 	if err != nil {
 		h.error(w, "Invalid email or password", http.StatusUnauthorized)
 		return
 	}
 
-	// Generate JWT token
-	token, err := generateJWT(userID, email)
+	// Generate JWT token, also set as the session cookie in the cookie auth modes
+	token, csrf, err := h.issueToken(w, user.ID, user.Email)
 	if err != nil {
-		h.error(w, "Failed to generate token", http.StatusInternalServerError)
+		h.internalError(w, r, "Failed to generate token", err)
 		return
 	}
+	h.publish(r.Context(), events.UserLoggedIn, user.ID, nil)
 
 	h.json(w, AuthResponse{
-		Token:  token,
-		UserID: userID,
-		Email:  email,
-		Name:   fullName,
+		Token:     token,
+		UserID:    user.ID,
+		Email:     user.Email,
+		Name:      user.FullName,
+		CSRFToken: csrf,
 	}, http.StatusOK)
 }
 
@@ -190,7 +185,7 @@ func (h *Handler) GetMe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	profile, err := h.getUserProfile(r.Context(), userID)
+	profile, err := h.users.Profile(r.Context(), userID)
 	if err != nil {
 		h.error(w, "User not found", http.StatusNotFound)
 		return
@@ -199,6 +194,53 @@ func (h *Handler) GetMe(w http.ResponseWriter, r *http.Request) {
 	h.json(w, *profile, http.StatusOK)
 }
 
+// UserIDFromToken validates a token issued at signup or login and returns the user it was
+// issued to. Impersonation tokens are refused, since only the REST API audits their use. The
+// error is suitable for showing to the client.
+func UserIDFromToken(tokenString string) (string, error) {
+	userID, impersonation, err := parseToken(tokenString)
+	if err != nil {
+		return "", err
+	}
+	if impersonation != nil {
+		return "", errors.New("Impersonation tokens only work with the REST API")
+	}
+	return userID, nil
+}
+
+// parseToken validates a token and returns the user it acts as, and for impersonation tokens,
+// who issued them and what they allow
+func parseToken(tokenString string) (string, *impersonation, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method")
+		}
+		return []byte(jwtSecret), nil
+	})
+	if err != nil || !token.Valid {
+		return "", nil, errors.New("Invalid or expired token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", nil, errors.New("Invalid token claims")
+	}
+	userID, ok := claims["user_id"].(string)
+	if !ok {
+		return "", nil, errors.New("Invalid user ID in token")
+	}
+	if _, ok := claims["impersonator_id"]; !ok {
+		return userID, nil, nil
+	}
+	imp := &impersonation{}
+	imp.AdminID, _ = claims["impersonator_id"].(string)
+	imp.AllowWrites, _ = claims["allow_writes"].(bool)
+	if imp.AdminID == "" {
+		return "", nil, errors.New("Invalid impersonator in token")
+	}
+	return userID, imp, nil
+}
+
 // generateJWT creates a new JWT token for a user
 func generateJWT(userID, email string) (string, error) {
 	claims := jwt.MapClaims{
@@ -212,56 +254,6 @@ func generateJWT(userID, email string) (string, error) {
 	return token.SignedString([]byte(jwtSecret))
 }
 
-// AuthMiddleware validates JWT tokens
-func AuthMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			http.Error(w, `{"error":"Missing authorization header"}`, http.StatusUnauthorized)
-			return
-		}
-
-		// Extract token from "Bearer <token>"
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			http.Error(w, `{"error":"Invalid authorization header format"}`, http.StatusUnauthorized)
-			return
-		}
-
-		tokenString := parts[1]
-
-		// Parse and validate token
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method")
-			}
-			return []byte(jwtSecret), nil
-		})
-
-		if err != nil || !token.Valid {
-			http.Error(w, `{"error":"Invalid or expired token"}`, http.StatusUnauthorized)
-			return
-		}
-
-		// Extract user ID from claims
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			http.Error(w, `{"error":"Invalid token claims"}`, http.StatusUnauthorized)
-			return
-		}
-
-		userID, ok := claims["user_id"].(string)
-		if !ok {
-			http.Error(w, `{"error":"Invalid user ID in token"}`, http.StatusUnauthorized)
-			return
-		}
-
-		// Add user ID to request context
-		ctx := context.WithValue(r.Context(), "user_id", userID)
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
-}
-
 // ChangePassword allows authenticated users to change their password
 func (h *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 	userID := getUserIDFromContext(r.Context())
@@ -271,34 +263,19 @@ func (h *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req ChangePasswordRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.error(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	// Validate inputs
-	if req.CurrentPassword == "" || req.NewPassword == "" {
-		h.error(w, "current_password and new_password are required", http.StatusBadRequest)
-		return
-	}
-
-	// Validate new password strength
-	if !validation.ValidatePassword(req.NewPassword) {
-		h.error(w, "New password must be 6-128 characters with at least one letter and one number", http.StatusBadRequest)
+	if !h.decodeJSON(w, r, &req) {
 		return
 	}
 
 	// Get current password hash
-	var currentHash string
-	err := h.db.QueryRow(r.Context(), "SELECT password_hash FROM user_profiles WHERE id = $1", userID).
-		Scan(&currentHash)
+	user, err := h.users.UserByID(r.Context(), userID)
 	if err != nil {
 		h.error(w, "User not found", http.StatusNotFound)
 		return
 	}
 
 	// Verify current password
-	if err := bcrypt.CompareHashAndPassword([]byte(currentHash), []byte(req.CurrentPassword)); err != nil {
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.CurrentPassword)); err != nil {
 		h.error(w, "Current password is incorrect", http.StatusUnauthorized)
 		return
 	}
@@ -306,14 +283,13 @@ func (h *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 	// Hash new password
 	newHash, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
 	if err != nil {
-		h.error(w, "Failed to process new password", http.StatusInternalServerError)
+		h.internalError(w, r, "Failed to process new password", err)
 		return
 	}
 
 	// Update password
-	_, err = h.db.Exec(r.Context(), "UPDATE user_profiles SET password_hash = $1, updated_at = NOW() WHERE id = $2", string(newHash), userID)
-	if err != nil {
-		h.error(w, "Failed to update password", http.StatusInternalServerError)
+	if err := h.users.UpdatePassword(r.Context(), userID, string(newHash)); err != nil {
+		h.internalError(w, r, "Failed to update password", err)
 		return
 	}
 
@@ -329,44 +305,43 @@ func (h *Handler) UpdateEmail(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req UpdateEmailRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.error(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	// Validate email format
-	if !validation.ValidateEmail(req.NewEmail) {
-		h.error(w, "Invalid email format", http.StatusBadRequest)
+	if !h.decodeJSON(w, r, &req) {
 		return
 	}
 
 	// Update email (will fail if email already exists due to unique constraint)
-	result, err := h.db.Exec(r.Context(), "UPDATE user_profiles SET email = $1, updated_at = NOW() WHERE id = $2", req.NewEmail, userID)
-	if err != nil {
-		if strings.Contains(err.Error(), "duplicate key") {
+	if err := h.users.UpdateEmail(r.Context(), userID, req.NewEmail); err != nil {
+		switch {
+		case errors.Is(err, store.ErrConflict):
 			h.error(w, "Email already in use", http.StatusConflict)
-			return
+		case errors.Is(err, store.ErrNotFound):
+			h.error(w, "User not found", http.StatusNotFound)
+		default:
+			h.internalError(w, r, "Failed to update email", err)
 		}
-		h.error(w, fmt.Sprintf("Failed to update email: %v", err), http.StatusInternalServerError)
-		return
-	}
-
-	if result.RowsAffected() == 0 {
-		h.error(w, "User not found", http.StatusNotFound)
 		return
 	}
 
 	// Generate new JWT with updated email
-	token, err := generateJWT(userID, req.NewEmail)
+	token, csrf, err := h.issueToken(w, userID, req.NewEmail)
 	if err != nil {
-		h.error(w, "Failed to generate new token", http.StatusInternalServerError)
+		h.internalError(w, r, "Failed to generate new token", err)
 		return
 	}
 
-	h.json(w, map[string]string{
-		"message": "Email updated successfully",
-		"token":   token,
-	}, http.StatusOK)
+	response := map[string]string{"message": "Email updated successfully"}
+	if token != "" {
+		response["token"] = token
+	}
+	if csrf != "" {
+		response["csrf_token"] = csrf
+	}
+	h.json(w, response, http.StatusOK)
+}
+
+// UserIDFromContext returns the user authenticated by AuthMiddleware, or "" if there is none
+func UserIDFromContext(ctx context.Context) string {
+	return getUserIDFromContext(ctx)
 }
 
 // getUserIDFromContext extracts the user ID from the request context
@@ -374,3 +349,16 @@ func getUserIDFromContext(ctx context.Context) string {
 	userID, _ := ctx.Value("user_id").(string)
 	return userID
 }
+
+// ReadOnlyImpersonation reports whether the request was made with an impersonation token that
+// doesn't allow writes
+func ReadOnlyImpersonation(ctx context.Context) bool {
+	imp := getImpersonationFromContext(ctx)
+	return imp != nil && !imp.AllowWrites
+}
+
+// getImpersonationFromContext returns the impersonation the request is made under, or nil
+func getImpersonationFromContext(ctx context.Context) *impersonation {
+	imp, _ := ctx.Value("impersonation").(*impersonation)
+	return imp
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const maxBlocklistEntries = 200
+
+type JobBlocklist struct {
+	Companies []string `json:"companies"` // Matched by normalized name, and against the agency a job came through
+	Keywords  []string `json:"keywords"`  // Matched case-insensitively against title and description
+}
+
+// blockedJobSQL is a predicate that is true when a row of jobs matches the blocklist of the
+// user whose ID is bound to the given placeholder
+func blockedJobSQL(user string) string {
+	return `EXISTS (
+		SELECT 1 FROM user_profiles bp
+		WHERE bp.id = ` + user + ` AND (
+			EXISTS (SELECT 1 FROM unnest(bp.blocked_companies) c WHERE normalize_company_name(c) IN (normalize_company_name(jobs.company), normalize_company_name(jobs.agency)))
+			OR EXISTS (SELECT 1 FROM unnest(bp.excluded_keywords) k
+				WHERE STRPOS(LOWER(jobs.title || ' ' || COALESCE(jobs.description, '')), LOWER(k)) > 0)
+		)
+	)`
+}
+
+// GetJobBlocklist returns the companies and keywords whose jobs are hidden from the user
+func (h *Handler) GetJobBlocklist(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var blocklist JobBlocklist
+	err := h.db.QueryRow(r.Context(),
+		"SELECT blocked_companies, excluded_keywords FROM user_profiles WHERE id = $1", userID,
+	).Scan(&blocklist.Companies, &blocklist.Keywords)
+	if err != nil {
+		h.error(w, "Profile not found", http.StatusNotFound)
+		return
+	}
+
+	h.json(w, blocklist, http.StatusOK)
+}
+
+// UpdateJobBlocklist replaces the blocklist. It applies to jobs already found as well as
+// future scrapes, so removing an entry brings its jobs back.
+func (h *Handler) UpdateJobBlocklist(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req JobBlocklist
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+
+	req.Companies = cleanBlocklist(req.Companies)
+	req.Keywords = cleanBlocklist(req.Keywords)
+	if len(req.Companies) > maxBlocklistEntries || len(req.Keywords) > maxBlocklistEntries {
+		h.error(w, fmt.Sprintf("At most %d companies and %d keywords can be blocked", maxBlocklistEntries, maxBlocklistEntries), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.db.Exec(r.Context(),
+		"UPDATE user_profiles SET blocked_companies = $1, excluded_keywords = $2, updated_at = NOW() WHERE id = $3",
+		req.Companies, req.Keywords, userID)
+	if err != nil {
+		h.internalError(w, r, "Failed to update blocklist", err)
+		return
+	}
+	if result.RowsAffected() == 0 {
+		h.error(w, "Profile not found", http.StatusNotFound)
+		return
+	}
+
+	h.json(w, req, http.StatusOK)
+}
+
+// cleanBlocklist trims entries and drops blanks and case-insensitive duplicates. Entries are
+// not HTML-escaped like other text since they are compared against raw company names.
+func cleanBlocklist(entries []string) []string {
+	seen := make(map[string]bool, len(entries))
+	cleaned := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(strings.ReplaceAll(entry, "\x00", ""))
+		if runes := []rune(entry); len(runes) > 200 {
+			entry = string(runes[:200])
+		}
+		key := strings.ToLower(entry)
+		if entry == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, entry)
+	}
+	return cleaned
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yourusername/jobapply/internal/geo"
+	"github.com/yourusername/jobapply/internal/logging"
+)
+
+const (
+	// commuteBatch bounds the routing requests one EstimateCommutes run makes per mode
+	commuteBatch = 200
+	// commuteMaxMiles skips jobs too far away for a daily commute to be worth routing
+	commuteMaxMiles = 100
+)
+
+// SetRouter enables commute estimates by the given travel modes, which the router must support
+func (h *Handler) SetRouter(router geo.Router, modes []string) error {
+	for _, mode := range modes {
+		if !slices.Contains(router.Modes(), mode) {
+			return fmt.Errorf("router does not support %s", mode)
+		}
+	}
+	h.router = router
+	h.commuteModes = modes
+	return nil
+}
+
+// EstimateCommutes routes from users' geocoded addresses to the on-site jobs their searches
+// found, storing the travel time per mode for the commute filter and sort on GET /jobs.
+// Estimates made from an earlier address are redone. It is run by the scheduler; without a
+// router it does nothing.
+func (h *Handler) EstimateCommutes(ctx context.Context) error {
+	if h.router == nil {
+		return nil
+	}
+	logger := logging.FromContext(ctx)
+	for _, mode := range h.commuteModes {
+		rows, err := h.db.Query(ctx, `
+			SELECT p.id, p.latitude, p.longitude, j.id, j.latitude, j.longitude
+			FROM user_jobs uj
+			JOIN user_profiles p ON p.id = uj.user_id
+			JOIN jobs j ON j.id = uj.job_id
+			LEFT JOIN job_commutes c ON c.user_id = uj.user_id AND c.job_id = uj.job_id AND c.mode = $1
+			WHERE p.latitude IS NOT NULL AND p.longitude IS NOT NULL
+			AND j.latitude IS NOT NULL AND j.longitude IS NOT NULL
+			AND j.deleted_at IS NULL AND j.status = 'open'
+			AND NOT (j.location ILIKE '%remote%' OR j.location ILIKE '%anywhere%')
+			AND `+haversineSQL("p.latitude", "p.longitude", "j.latitude", "j.longitude")+` <= $2
+			AND (c.job_id IS NULL OR c.origin_latitude <> p.latitude OR c.origin_longitude <> p.longitude)
+			ORDER BY uj.found_at DESC
+			LIMIT $3
+		`, mode, commuteMaxMiles, commuteBatch)
+		if err != nil {
+			return fmt.Errorf("failed to find jobs to route: %w", err)
+		}
+		type pending struct {
+			userID, jobID string
+			from, to      geo.Point
+		}
+		trips, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (pending, error) {
+			var t pending
+			err := row.Scan(&t.userID, &t.from.Lat, &t.from.Lng, &t.jobID, &t.to.Lat, &t.to.Lng)
+			return t, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to find jobs to route: %w", err)
+		}
+
+		routed := 0
+		for _, t := range trips {
+			var minutes *int
+			duration, err := h.router.TravelTime(ctx, t.from, t.to, mode)
+			switch {
+			case err == nil:
+				m := int(math.Round(duration.Minutes()))
+				minutes = &m
+			case errors.Is(err, geo.ErrNoRoute):
+			default:
+				// The provider is down or over quota; the rest wait for the next run
+				return fmt.Errorf("failed to route %s commute: %w", mode, err)
+			}
+			if _, err := h.db.Exec(ctx, `
+				INSERT INTO job_commutes (user_id, job_id, mode, minutes, origin_latitude, origin_longitude)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				ON CONFLICT (user_id, job_id, mode) DO UPDATE SET minutes = EXCLUDED.minutes,
+					origin_latitude = EXCLUDED.origin_latitude, origin_longitude = EXCLUDED.origin_longitude, computed_at = NOW()
+			`, t.userID, t.jobID, mode, minutes, t.from.Lat, t.from.Lng); err != nil {
+				return fmt.Errorf("failed to store commute: %w", err)
+			}
+			routed++
+		}
+		logger.Info("Commutes estimated", "mode", mode, "jobs", routed)
+	}
+	return nil
+}
+
+// commuteSQL is the user's stored commute in minutes to a row of jobs by mode, or NULL
+func commuteSQL(user, mode string) string {
+	return `(SELECT c.minutes FROM job_commutes c WHERE c.user_id = ` + user + ` AND c.job_id = jobs.id AND c.mode = ` + mode + `)`
+}
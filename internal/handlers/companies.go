@@ -0,0 +1,378 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/yourusername/jobapply/internal/logging"
+	"github.com/yourusername/jobapply/internal/models"
+	"github.com/yourusername/jobapply/internal/scrapers"
+	"github.com/yourusername/jobapply/internal/validation"
+)
+
+// Companies enriched per scrape; the rest are picked up by later scrapes
+const maxCompanyEnrichments = 20
+
+type Company struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Industry    string     `json:"industry,omitempty"`
+	Size        string     `json:"size,omitempty"`
+	Rating      *float64   `json:"rating,omitempty"`
+	Description string     `json:"description,omitempty"`
+	ProfileURL  string     `json:"profile_url,omitempty"`
+	EnrichedAt  *time.Time `json:"enriched_at,omitempty"`
+	// StaffingAgency marks agencies that post jobs for unnamed clients
+	StaffingAgency bool `json:"staffing_agency"`
+	// Aliases are other normalized names whose jobs are listed under this company
+	Aliases []string `json:"aliases"`
+}
+
+type CompanyApplication struct {
+	ID        string     `json:"id"`
+	JobID     string     `json:"job_id"`
+	JobTitle  string     `json:"job_title"`
+	Status    string     `json:"status"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+type CompanyView struct {
+	Company
+	OpenJobs     []models.JobListing  `json:"open_jobs"`
+	Applications []CompanyApplication `json:"applications"`
+	Notes        string               `json:"notes"`
+}
+
+// GetCompany returns a company with its open jobs, the user's applications to it and
+// the user's notes
+func (h *Handler) GetCompany(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	companyID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, companyID, "company ID") {
+		return
+	}
+
+	var view CompanyView
+	var industry, size, description, profileURL *string
+	err := h.db.QueryRow(r.Context(), `
+		SELECT id, name, industry, size, rating::float8, description, profile_url, enriched_at, staffing_agency,
+			ARRAY(SELECT normalized_name FROM company_aliases WHERE company_id = companies.id ORDER BY normalized_name)
+		FROM companies WHERE id = $1
+	`, companyID).Scan(&view.ID, &view.Name, &industry, &size, &view.Rating, &description, &profileURL, &view.EnrichedAt,
+		&view.StaffingAgency, &view.Aliases)
+	if errors.Is(err, pgx.ErrNoRows) {
+		h.error(w, "Company not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.internalError(w, r, "Failed to get company", err)
+		return
+	}
+	view.Industry = deref(industry)
+	view.Size = deref(size)
+	view.Description = deref(description)
+	view.ProfileURL = deref(profileURL)
+
+	view.OpenJobs = []models.JobListing{}
+	rows, err := h.db.Query(r.Context(), `
+		SELECT id, site, title, company, company_id, location, url, posted_date, salary_min, salary_max, salary_text, scraped_at, status
+		FROM jobs
+		WHERE company_id = $1 AND status = 'open' AND deleted_at IS NULL AND (owner_id IS NULL OR owner_id = $2)
+		AND NOT EXISTS (SELECT 1 FROM job_dismissals d WHERE d.job_url = jobs.url AND d.user_id = $2)
+		ORDER BY scraped_at DESC
+		LIMIT 100
+	`, companyID, userID)
+	if err != nil {
+		h.internalError(w, r, "Failed to get company jobs", err)
+		return
+	}
+	for rows.Next() {
+		var job models.JobListing
+		var location, salaryText *string
+		if err := rows.Scan(&job.ID, &job.Site, &job.Title, &job.Company, &job.CompanyID, &location, &job.URL,
+			&job.PostedDate, &job.SalaryMin, &job.SalaryMax, &salaryText, &job.ScrapedAt, &job.Status); err != nil {
+			rows.Close()
+			h.internalError(w, r, "Failed to get company jobs", err)
+			return
+		}
+		job.Location = deref(location)
+		job.SalaryText = deref(salaryText)
+		view.OpenJobs = append(view.OpenJobs, job)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		h.internalError(w, r, "Failed to get company jobs", err)
+		return
+	}
+
+	view.Applications = []CompanyApplication{}
+	rows, err = h.db.Query(r.Context(), `
+		SELECT a.id, j.id, j.title, a.status, a.applied_at
+		FROM applications a
+		JOIN jobs j ON j.id = a.job_id
+		WHERE a.user_id = $1 AND j.company_id = $2 AND a.deleted_at IS NULL
+		ORDER BY a.created_at DESC
+	`, userID, companyID)
+	if err != nil {
+		h.internalError(w, r, "Failed to get company applications", err)
+		return
+	}
+	for rows.Next() {
+		var app CompanyApplication
+		if err := rows.Scan(&app.ID, &app.JobID, &app.JobTitle, &app.Status, &app.AppliedAt); err != nil {
+			rows.Close()
+			h.internalError(w, r, "Failed to get company applications", err)
+			return
+		}
+		view.Applications = append(view.Applications, app)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		h.internalError(w, r, "Failed to get company applications", err)
+		return
+	}
+
+	// No row means the user has no notes on this company
+	err = h.db.QueryRow(r.Context(),
+		"SELECT notes FROM company_notes WHERE user_id = $1 AND company_id = $2", userID, companyID,
+	).Scan(&view.Notes)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		h.internalError(w, r, "Failed to get company notes", err)
+		return
+	}
+
+	h.json(w, view, http.StatusOK)
+}
+
+// UpdateCompanyNotes replaces the user's notes on a company; empty notes delete them
+func (h *Handler) UpdateCompanyNotes(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	companyID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, companyID, "company ID") {
+		return
+	}
+
+	var req struct {
+		Notes string `json:"notes"`
+	}
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	req.Notes = validation.SanitizeString(req.Notes, 5000)
+
+	if req.Notes == "" {
+		if _, err := h.db.Exec(r.Context(), "DELETE FROM company_notes WHERE user_id = $1 AND company_id = $2", userID, companyID); err != nil {
+			h.internalError(w, r, "Failed to save notes", err)
+			return
+		}
+		h.json(w, map[string]string{"notes": ""}, http.StatusOK)
+		return
+	}
+
+	result, err := h.db.Exec(r.Context(), `
+		INSERT INTO company_notes (user_id, company_id, notes)
+		SELECT $1, id, $3 FROM companies WHERE id = $2
+		ON CONFLICT (user_id, company_id) DO UPDATE SET notes = EXCLUDED.notes, updated_at = NOW()
+	`, userID, companyID, req.Notes)
+	if err != nil {
+		h.internalError(w, r, "Failed to save notes", err)
+		return
+	}
+	if result.RowsAffected() == 0 {
+		h.error(w, "Company not found", http.StatusNotFound)
+		return
+	}
+
+	h.json(w, map[string]string{"notes": req.Notes}, http.StatusOK)
+}
+
+// upsertCompany returns the ID of the company a scraped job belongs to, creating it if needed.
+// A name that is an alias resolves to the company it points to. It returns nil for names that
+// normalize to nothing.
+func (h *Handler) upsertCompany(ctx context.Context, name string, museID int) *string {
+	var id string
+	err := h.db.QueryRow(ctx,
+		"SELECT company_id FROM company_aliases WHERE normalized_name = normalize_company_name($1)", name).Scan(&id)
+	if err == nil {
+		return &id
+	}
+	err = h.db.QueryRow(ctx, `
+		INSERT INTO companies (name, normalized_name, muse_company_id, staffing_agency)
+		SELECT TRIM($1), normalize_company_name($1), NULLIF($2, 0), $3
+		WHERE normalize_company_name($1) <> ''
+		ON CONFLICT (normalized_name) DO UPDATE SET
+			muse_company_id = COALESCE(companies.muse_company_id, EXCLUDED.muse_company_id),
+			staffing_agency = companies.staffing_agency OR EXCLUDED.staffing_agency
+		RETURNING id
+	`, name, museID, scrapers.IsStaffingAgency(name)).Scan(&id)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
+
+type CompanyAliasRequest struct {
+	Name string `json:"name"` // Any spelling; it is normalized like scraped names
+}
+
+// AddCompanyAlias makes another name resolve to a company. A company already stored under
+// that name is merged in: its jobs and users' notes move over and it is deleted.
+func (h *Handler) AddCompanyAlias(w http.ResponseWriter, r *http.Request) {
+	companyID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, companyID, "company ID") {
+		return
+	}
+	var req CompanyAliasRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	alias := scrapers.NormalizeCompany(validation.SanitizeString(req.Name, 200))
+	if alias == "" {
+		h.error(w, "name is required", http.StatusBadRequest)
+		return
+	}
+
+	var merged int64
+	err := pgx.BeginFunc(r.Context(), h.db, func(tx pgx.Tx) error {
+		var own string
+		if err := tx.QueryRow(r.Context(), "SELECT normalized_name FROM companies WHERE id = $1 FOR UPDATE", companyID).Scan(&own); err != nil {
+			return err
+		}
+		if own == alias {
+			return errAliasIsName
+		}
+		if _, err := tx.Exec(r.Context(), `
+			INSERT INTO company_aliases (normalized_name, company_id) VALUES ($1, $2)
+			ON CONFLICT (normalized_name) DO UPDATE SET company_id = EXCLUDED.company_id
+		`, alias, companyID); err != nil {
+			return err
+		}
+
+		var otherID string
+		err := tx.QueryRow(r.Context(), "SELECT id FROM companies WHERE normalized_name = $1", alias).Scan(&otherID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		result, err := tx.Exec(r.Context(), "UPDATE jobs SET company_id = $1 WHERE company_id = $2", companyID, otherID)
+		if err != nil {
+			return err
+		}
+		merged = result.RowsAffected()
+		if _, err := tx.Exec(r.Context(), `
+			INSERT INTO company_notes (user_id, company_id, notes, updated_at)
+			SELECT user_id, $1, notes, updated_at FROM company_notes WHERE company_id = $2
+			ON CONFLICT (user_id, company_id) DO NOTHING
+		`, companyID, otherID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(r.Context(), "UPDATE company_aliases SET company_id = $1 WHERE company_id = $2", companyID, otherID); err != nil {
+			return err
+		}
+		_, err = tx.Exec(r.Context(), "DELETE FROM companies WHERE id = $1", otherID)
+		return err
+	})
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		h.error(w, "Company not found", http.StatusNotFound)
+	case errors.Is(err, errAliasIsName):
+		h.error(w, "That is already the company's own name", http.StatusConflict)
+	case err != nil:
+		h.internalError(w, r, "Failed to add company alias", err)
+	default:
+		logging.FromContext(r.Context()).Info("Company alias added", "company_id", companyID, "alias", alias, "jobs_merged", merged)
+		h.json(w, map[string]any{"alias": alias, "jobs_merged": merged}, http.StatusOK)
+	}
+}
+
+var errAliasIsName = errors.New("alias is the company's own name")
+
+// DeleteCompanyAlias stops a name resolving to a company; jobs already merged stay with it
+func (h *Handler) DeleteCompanyAlias(w http.ResponseWriter, r *http.Request) {
+	companyID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, companyID, "company ID") {
+		return
+	}
+	alias := scrapers.NormalizeCompany(r.URL.Query().Get("name"))
+	result, err := h.db.Exec(r.Context(), "DELETE FROM company_aliases WHERE company_id = $1 AND normalized_name = $2", companyID, alias)
+	if err != nil {
+		h.internalError(w, r, "Failed to delete company alias", err)
+		return
+	}
+	if result.RowsAffected() == 0 {
+		h.error(w, "Alias not found", http.StatusNotFound)
+		return
+	}
+	h.json(w, map[string]string{"message": "Alias deleted successfully"}, http.StatusOK)
+}
+
+// enrichCompanies fills in size, industry and description for companies the source knows
+// about. Like geocoding it runs in the background after a scrape and failures are only logged.
+func (h *Handler) enrichCompanies(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	rows, err := h.db.Query(ctx, `
+		SELECT id, muse_company_id FROM companies
+		WHERE enriched_at IS NULL AND muse_company_id IS NOT NULL
+		LIMIT $1
+	`, maxCompanyEnrichments)
+	if err != nil {
+		logging.FromContext(ctx).Error("Company enrichment failed to list companies", "error", err)
+		return
+	}
+
+	pending := map[string]int{}
+	for rows.Next() {
+		var id string
+		var museID int
+		if err := rows.Scan(&id, &museID); err == nil {
+			pending[id] = museID
+		}
+	}
+	rows.Close()
+
+	scraper := scrapers.NewMuseScraper()
+	for id, museID := range pending {
+		info, err := scraper.Company(ctx, museID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return // Shutting down; the rest are enriched after a later scrape
+			}
+			logging.FromContext(ctx).Warn("Company enrichment failed", "company_id", id, "error", err)
+			continue
+		}
+
+		_, err = h.db.Exec(ctx, `
+			UPDATE companies SET industry = NULLIF($2, ''), size = NULLIF($3, ''), description = NULLIF($4, ''),
+				profile_url = NULLIF($5, ''), enriched_at = NOW(), updated_at = NOW()
+			WHERE id = $1
+		`, id, info.Industry, info.Size, info.Description, info.ProfileURL)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to store company enrichment", "company_id", id, "error", err)
+		}
+	}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
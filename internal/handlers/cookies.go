@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/yourusername/jobapply/internal/apierror"
+	"github.com/yourusername/jobapply/internal/logging"
+)
+
+// Auth modes: how clients present the token issued at signup and login
+const (
+	AuthModeBearer = "bearer" // Authorization: Bearer header only
+	AuthModeCookie = "cookie" // httpOnly session cookie only; the token is not returned in bodies
+	AuthModeBoth   = "both"   // Either, e.g. cookies for the browser and bearer for jobctl and gRPC
+)
+
+const (
+	sessionCookie = "jobapply_session"
+	// csrfCookie holds the double-submit token. It is readable by scripts, unlike the session,
+	// so the frontend can echo it in csrfHeader; a cross-site page can't read it.
+	csrfCookie = "jobapply_csrf"
+	csrfHeader = "X-CSRF-Token"
+
+	sessionLifetime = 7 * 24 * time.Hour // Matches the token expiry
+)
+
+// CookieAuth configures the session cookies used in the cookie and both auth modes
+type CookieAuth struct {
+	SameSite http.SameSite
+	Secure   bool   // Only send over HTTPS; required with SameSite=None
+	Domain   string // Empty for the API's host only
+}
+
+// SetAuthMode selects how clients authenticate; see the AuthMode constants. Handlers default to
+// bearer tokens only.
+func (h *Handler) SetAuthMode(mode string, cookies CookieAuth) {
+	h.authMode = mode
+	h.cookies = cookies
+}
+
+// BearerEnabled reports whether clients may authenticate with bearer tokens, which AUTH_MODE
+// cookie turns off
+func (h *Handler) BearerEnabled() bool {
+	return h.authMode != AuthModeCookie
+}
+
+func (h *Handler) cookiesEnabled() bool {
+	return h.authMode == AuthModeCookie || h.authMode == AuthModeBoth
+}
+
+// issueToken generates a token for the user and, in the cookie modes, sets it as the session
+// cookie along with a fresh CSRF token. It returns what goes in the response body: the token,
+// or "" if bearer tokens are disabled, and the CSRF token, or "" if cookies are.
+func (h *Handler) issueToken(w http.ResponseWriter, userID, email string) (token, csrf string, err error) {
+	token, err = generateJWT(userID, email)
+	if err != nil {
+		return "", "", err
+	}
+	if h.cookiesEnabled() {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return "", "", err
+		}
+		csrf = base64.RawURLEncoding.EncodeToString(b)
+		maxAge := int(sessionLifetime.Seconds())
+		http.SetCookie(w, h.cookie(sessionCookie, token, true, maxAge))
+		http.SetCookie(w, h.cookie(csrfCookie, csrf, false, maxAge))
+	}
+	if !h.BearerEnabled() {
+		token = ""
+	}
+	return token, csrf, nil
+}
+
+// cookie builds an auth cookie lasting maxAge seconds; a negative maxAge deletes it
+func (h *Handler) cookie(name, value string, httpOnly bool, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Domain:   h.cookies.Domain,
+		MaxAge:   maxAge,
+		Secure:   h.cookies.Secure,
+		HttpOnly: httpOnly,
+		SameSite: h.cookies.SameSite,
+	}
+}
+
+// AuthMiddleware validates the token from the Authorization header or, in the cookie modes, the
+// session cookie. Cookie-authenticated requests that can change state must also send the CSRF
+// token from the login response in the X-CSRF-Token header; bearer requests can't be forged
+// cross-site, so they don't need it.
+func (h *Handler) AuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var token string
+		if authHeader := r.Header.Get("Authorization"); authHeader != "" {
+			if !h.BearerEnabled() {
+				apierror.Write(w, apierror.New(http.StatusUnauthorized, "Bearer tokens are disabled; log in to get a session cookie"))
+				return
+			}
+			// Extract token from "Bearer <token>"
+			parts := strings.Split(authHeader, " ")
+			if len(parts) != 2 || parts[0] != "Bearer" {
+				apierror.Write(w, apierror.New(http.StatusUnauthorized, "Invalid authorization header format"))
+				return
+			}
+			token = parts[1]
+		} else if c, err := r.Cookie(sessionCookie); err == nil && h.cookiesEnabled() {
+			if !safeMethod(r.Method) && !validCSRF(r) {
+				apierror.Write(w, apierror.New(http.StatusForbidden, "Missing or invalid CSRF token"))
+				return
+			}
+			token = c.Value
+		} else {
+			apierror.Write(w, apierror.New(http.StatusUnauthorized, "Missing authorization header"))
+			return
+		}
+
+		userID, imp, err := parseToken(token)
+		if err != nil {
+			apierror.Write(w, apierror.New(http.StatusUnauthorized, err.Error()))
+			return
+		}
+
+		// Add user ID to request context
+		ctx := context.WithValue(r.Context(), "user_id", userID)
+		ctx = logging.With(ctx, "user_id", userID)
+		if imp != nil {
+			ctx = context.WithValue(ctx, "impersonation", imp)
+			ctx = logging.With(ctx, "impersonator_id", imp.AdminID)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func safeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// validCSRF checks the double-submitted token: the header must match the CSRF cookie
+func validCSRF(r *http.Request) bool {
+	c, err := r.Cookie(csrfCookie)
+	header := r.Header.Get(csrfHeader)
+	if err != nil || c.Value == "" || header == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(c.Value), []byte(header)) == 1
+}
+
+// GetCSRFToken returns the CSRF token for the current session, so a frontend on another origin,
+// which can't read the cookie, can recover it after a reload
+func (h *Handler) GetCSRFToken(w http.ResponseWriter, r *http.Request) {
+	c, err := r.Cookie(csrfCookie)
+	if err != nil {
+		h.error(w, "No session cookie; log in again", http.StatusNotFound)
+		return
+	}
+	h.json(w, map[string]string{"csrf_token": c.Value}, http.StatusOK)
+}
+
+// Logout clears the session cookies. Bearer tokens can't be revoked; clients just drop them.
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	if h.cookiesEnabled() {
+		http.SetCookie(w, h.cookie(sessionCookie, "", true, -1))
+		http.SetCookie(w, h.cookie(csrfCookie, "", false, -1))
+	}
+	h.json(w, message{"message": "Logged out"}, http.StatusOK)
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/yourusername/jobapply/internal/apierror"
+	"github.com/yourusername/jobapply/internal/validation"
+)
+
+// validator is implemented by request bodies that check, and may normalize, their own fields.
+// Validate should report every problem at once through a validation.Collector.
+type validator interface {
+	Validate() error
+}
+
+// decodeJSON decodes the request body into dst, rejecting unknown fields and trailing data,
+// then runs dst's Validate if it has one. On failure it writes the error response and
+// returns false.
+func (h *Handler) decodeJSON(w http.ResponseWriter, r *http.Request, dst any) bool {
+	return h.decode(w, r, dst, false)
+}
+
+// decodeOptionalJSON is decodeJSON for endpoints where the body may be omitted entirely
+func (h *Handler) decodeOptionalJSON(w http.ResponseWriter, r *http.Request, dst any) bool {
+	return h.decode(w, r, dst, true)
+}
+
+func (h *Handler) decode(w http.ResponseWriter, r *http.Request, dst any, optional bool) bool {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(dst)
+	if err == nil && dec.More() {
+		err = errors.New("request body must contain a single JSON object")
+	}
+	if errors.Is(err, io.EOF) && optional {
+		err = nil
+	}
+	if err != nil {
+		h.fail(w, r, decodeError(err))
+		return false
+	}
+
+	if v, ok := dst.(validator); ok {
+		if err := v.Validate(); err != nil {
+			h.fail(w, r, apierror.Invalid(err))
+			return false
+		}
+	}
+	return true
+}
+
+// decodeError turns a json decoding failure into a client error, naming the field if it can
+func decodeError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		return err
+	case errors.Is(err, io.EOF):
+		return apierror.New(http.StatusBadRequest, "Request body is required")
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return validation.Errors{{Field: typeErr.Field, Message: fmt.Sprintf("%s must be %s", typeErr.Field, jsonTypeName(typeErr.Type.Kind()))}}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return validation.Errors{{Field: field, Message: fmt.Sprintf("unknown field %q", field)}}
+	}
+	return apierror.New(http.StatusBadRequest, "Invalid request body")
+}
+
+// jsonTypeName describes a Go kind in JSON terms for error messages
+func jsonTypeName(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	}
+	return "a valid value"
+}
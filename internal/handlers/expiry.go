@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/jobapply/internal/logging"
+	"github.com/yourusername/jobapply/internal/scrapers"
+	"github.com/yourusername/jobapply/internal/tracing"
+)
+
+const (
+	// Postings checked per round; the rest wait for the next one
+	expiryBatchSize = 100
+	// Pause between requests so a round doesn't hammer any one job board
+	expiryRequestDelay = time.Second
+)
+
+// CheckSavedJobs revisits open saved jobs not checked within staleAfter and marks postings that
+// were removed or stopped accepting applications as closed. It is run by the scheduler.
+func (h *Handler) CheckSavedJobs(ctx context.Context, staleAfter time.Duration) error {
+	rows, err := h.db.Query(ctx, `
+		SELECT id, url FROM jobs
+		WHERE status = 'open'
+		AND EXISTS (SELECT 1 FROM saved_jobs s WHERE s.job_id = jobs.id)
+		AND (status_checked_at IS NULL OR status_checked_at < $1)
+		ORDER BY status_checked_at NULLS FIRST
+		LIMIT $2
+	`, time.Now().Add(-staleAfter), expiryBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	type pending struct{ id, url string }
+	var jobs []pending
+	for rows.Next() {
+		var job pending
+		if err := rows.Scan(&job.id, &job.url); err == nil {
+			jobs = append(jobs, job)
+		}
+	}
+	rows.Close()
+
+	checker := scrapers.NewPostingChecker()
+	closed := 0
+	for i, job := range jobs {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(expiryRequestDelay):
+			}
+		}
+
+		checkCtx, span := tracing.Start(ctx, "expiry.check", tracing.KindInternal, "job.id", job.id)
+		status, err := checker.Check(checkCtx, job.url)
+		span.SetAttributes("job.closed", status.Closed)
+		span.RecordError(err)
+		span.End()
+		if err != nil {
+			// Unknown rather than closed; it is retried next round
+			logging.FromContext(ctx).Warn("Expiry check of job failed", "job_id", job.id, "error", err)
+			continue
+		}
+
+		if status.Closed {
+			closed++
+			_, err = h.db.Exec(ctx, `
+				UPDATE jobs SET status = 'closed', closed_at = NOW(), closed_reason = $2, status_checked_at = NOW()
+				WHERE id = $1`, job.id, status.Reason)
+		} else {
+			_, err = h.db.Exec(ctx, "UPDATE jobs SET status_checked_at = NOW() WHERE id = $1", job.id)
+		}
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to store job status", "job_id", job.id, "error", err)
+		}
+	}
+
+	if len(jobs) > 0 {
+		logging.FromContext(ctx).Info("Expiry check finished", "checked", len(jobs), "closed", closed)
+	}
+	return nil
+}
@@ -0,0 +1,408 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/yourusername/jobapply/internal/apierror"
+	"github.com/yourusername/jobapply/internal/logging"
+	"github.com/yourusername/jobapply/internal/matching"
+	"github.com/yourusername/jobapply/internal/models"
+	"github.com/yourusername/jobapply/internal/salary"
+	"github.com/yourusername/jobapply/internal/scrapers"
+	"github.com/yourusername/jobapply/internal/store"
+	"github.com/yourusername/jobapply/internal/validation"
+)
+
+// Extension key scopes. A key can only reach the endpoints of its scopes.
+const (
+	ScopeJobsCapture       = "jobs:capture"       // POST /jobs/capture
+	ScopeApplicationsWrite = "applications:write" // POST /applications/manual
+)
+
+var extensionScopes = []string{ScopeJobsCapture, ScopeApplicationsWrite}
+
+const (
+	// extensionKeyPrefix marks extension keys apart from JWTs in the Authorization header
+	extensionKeyPrefix = "jak_"
+	maxExtensionKeys   = 10
+)
+
+// ExtensionKey is an API key for the browser extension; the key itself is only shown once
+type ExtensionKey struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"` // The key's first characters, to tell keys apart
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// CreatedExtensionKey is a new key with its secret
+type CreatedExtensionKey struct {
+	ExtensionKey
+	Key string `json:"key"` // Send as "Authorization: Bearer <key>"; it can't be shown again
+}
+
+type CreateExtensionKeyRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"` // Defaults to every scope
+}
+
+// Validate checks the name and scopes, defaulting the scopes to all of them
+func (req *CreateExtensionKeyRequest) Validate() error {
+	var v validation.Collector
+	req.Name = validation.SanitizeString(req.Name, 100)
+	v.Required("name", req.Name)
+	if len(req.Scopes) == 0 {
+		req.Scopes = slices.Clone(extensionScopes)
+	}
+	for _, scope := range req.Scopes {
+		if !slices.Contains(extensionScopes, scope) {
+			v.Check(false, "scopes", "scopes must be "+strings.Join(extensionScopes, " or "))
+			break
+		}
+	}
+	slices.Sort(req.Scopes)
+	req.Scopes = slices.Compact(req.Scopes)
+	return v.Err()
+}
+
+const extensionKeyColumns = `id, name, prefix, scopes, created_at, last_used_at`
+
+func scanExtensionKey(row pgx.CollectableRow) (ExtensionKey, error) {
+	var k ExtensionKey
+	err := row.Scan(&k.ID, &k.Name, &k.Prefix, &k.Scopes, &k.CreatedAt, &k.LastUsedAt)
+	return k, err
+}
+
+// ListExtensionKeys returns the user's extension keys, without their secrets
+func (h *Handler) ListExtensionKeys(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	rows, err := h.db.Query(r.Context(), `SELECT `+extensionKeyColumns+` FROM extension_keys WHERE user_id = $1 ORDER BY created_at`, userID)
+	if err != nil {
+		h.internalError(w, r, "Failed to get extension keys", err)
+		return
+	}
+	keys, err := pgx.CollectRows(rows, scanExtensionKey)
+	if err != nil {
+		h.internalError(w, r, "Failed to get extension keys", err)
+		return
+	}
+	h.json(w, keys, http.StatusOK)
+}
+
+// CreateExtensionKey issues a key for the browser extension, limited to the given scopes
+func (h *Handler) CreateExtensionKey(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req CreateExtensionKeyRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+
+	secret, err := newToken(extensionKeyPrefix)
+	if err != nil {
+		h.internalError(w, r, "Failed to create extension key", err)
+		return
+	}
+
+	rows, err := h.db.Query(r.Context(), `
+		INSERT INTO extension_keys (user_id, name, key_hash, prefix, scopes)
+		SELECT $1, $2, $3, $4, $5
+		WHERE (SELECT COUNT(*) FROM extension_keys WHERE user_id = $1) < $6
+		RETURNING `+extensionKeyColumns,
+		userID, req.Name, hashToken(secret), secret[:len(extensionKeyPrefix)+6], req.Scopes, maxExtensionKeys)
+	if err != nil {
+		h.internalError(w, r, "Failed to create extension key", err)
+		return
+	}
+	key, err := pgx.CollectExactlyOneRow(rows, scanExtensionKey)
+	if errors.Is(err, pgx.ErrNoRows) {
+		h.error(w, fmt.Sprintf("You can have at most %d extension keys; delete one first", maxExtensionKeys), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		h.internalError(w, r, "Failed to create extension key", err)
+		return
+	}
+	h.json(w, CreatedExtensionKey{ExtensionKey: key, Key: secret}, http.StatusCreated)
+}
+
+// DeleteExtensionKey revokes a key immediately
+func (h *Handler) DeleteExtensionKey(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	keyID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, keyID, "key ID") {
+		return
+	}
+	result, err := h.db.Exec(r.Context(), "DELETE FROM extension_keys WHERE id = $1 AND user_id = $2", keyID, userID)
+	if err != nil {
+		h.internalError(w, r, "Failed to delete extension key", err)
+		return
+	}
+	if result.RowsAffected() == 0 {
+		h.error(w, "Extension key not found", http.StatusNotFound)
+		return
+	}
+	h.json(w, map[string]string{"message": "Extension key deleted successfully"}, http.StatusOK)
+}
+
+// newToken returns a random bearer token starting with prefix
+func newToken(prefix string) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return prefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken is how tokens are stored, so a database leak doesn't leak them
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ExtensionAuth authenticates the extension endpoints: an extension key with the scope, or
+// anything AuthMiddleware accepts. Extension keys are only accepted on routes behind this,
+// so a leaked key can't reach the rest of the account.
+func (h *Handler) ExtensionAuth(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		userAuth := h.AuthMiddleware(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || !strings.HasPrefix(key, extensionKeyPrefix) {
+				userAuth.ServeHTTP(w, r)
+				return
+			}
+
+			var keyID, userID string
+			var scopes []string
+			err := h.db.QueryRow(r.Context(), `
+				UPDATE extension_keys SET last_used_at = NOW() WHERE key_hash = $1
+				RETURNING id, user_id, scopes
+			`, hashToken(key)).Scan(&keyID, &userID, &scopes)
+			if errors.Is(err, pgx.ErrNoRows) {
+				apierror.Write(w, apierror.New(http.StatusUnauthorized, "Invalid or revoked extension key"))
+				return
+			}
+			if err != nil {
+				h.internalError(w, r, "Failed to check extension key", err)
+				return
+			}
+			if !slices.Contains(scopes, scope) {
+				apierror.Write(w, apierror.New(http.StatusForbidden, "Extension key lacks the "+scope+" scope"))
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), "user_id", userID)
+			ctx = logging.With(ctx, "user_id", userID, "extension_key_id", keyID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// CaptureJobRequest is a job page as the extension read it
+type CaptureJobRequest struct {
+	URL         string     `json:"url"`
+	Title       string     `json:"title"`
+	Company     string     `json:"company"`
+	Location    string     `json:"location"`
+	Description string     `json:"description"` // HTML or plain text
+	Salary      string     `json:"salary"`      // As written on the page
+	PostedAt    *time.Time `json:"posted_at"`
+}
+
+// CaptureJob handles POST /api/v1/jobs/capture: the job page the user is viewing, added to
+// their jobs. A job scrapes already found under the URL is added as it is; otherwise what the
+// page showed is stored as the user's own job, and capturing it again updates it.
+func (h *Handler) CaptureJob(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req CaptureJobRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	company, agency := scrapers.SplitAgency(validation.SanitizeString(req.Company, 500))
+	// The URL is canonical before it is checked, since that unwraps redirect links
+	job := scrapers.Job{
+		Title:       validation.SanitizeString(req.Title, 1000),
+		Company:     company,
+		Agency:      agency,
+		Location:    validation.SanitizeString(req.Location, 1000),
+		URL:         scrapers.CanonicalURL(strings.TrimSpace(req.URL)),
+		Description: scrapers.SanitizeHTML(req.Description),
+		PostedAt:    req.PostedAt,
+		Salary:      strings.TrimSpace(req.Salary),
+	}
+	if problems := job.Problems(); len(problems) > 0 {
+		h.error(w, "Invalid job: "+strings.Join(problems, ", "), http.StatusBadRequest)
+		return
+	}
+	if job.Salary == "" {
+		job.Salary = salary.Extract(matching.PlainText(job.Description))
+	}
+
+	// A live shared job under the URL is added as it is. Otherwise the user gets their own job
+	// holding what the page showed, which no one else sees.
+	var id string
+	var inserted bool
+	err := h.db.QueryRow(r.Context(),
+		"SELECT id FROM jobs WHERE url = $1 AND owner_id IS NULL AND deleted_at IS NULL", job.URL).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		pay := parseSalary(job.Salary)
+		err = h.db.QueryRow(r.Context(), `
+			INSERT INTO jobs (site, owner_id, title, company, location, url, description, posted_date, cached_at,
+				salary_min, salary_max, salary_currency, salary_period, salary_text, company_id, agency)
+			VALUES ('extension', $1, $2, $3, NULLIF($4, ''), $5, NULLIF($6, ''), $7, NOW(), $8, $9, $10, $11, $12, $13, NULLIF($14, ''))
+			ON CONFLICT (owner_id, url) WHERE owner_id IS NOT NULL DO UPDATE SET
+				title = EXCLUDED.title,
+				company = EXCLUDED.company,
+				location = EXCLUDED.location,
+				description = EXCLUDED.description,
+				posted_date = EXCLUDED.posted_date,
+				salary_min = EXCLUDED.salary_min,
+				salary_max = EXCLUDED.salary_max,
+				salary_currency = EXCLUDED.salary_currency,
+				salary_period = EXCLUDED.salary_period,
+				salary_text = EXCLUDED.salary_text,
+				company_id = EXCLUDED.company_id,
+				agency = EXCLUDED.agency,
+				cached_at = NOW(),
+				deleted_at = NULL
+			RETURNING id, xmax = 0
+		`, userID, job.Title, job.Company, job.Location, job.URL, job.Description, job.PostedAt,
+			pay.Min, pay.Max, pay.Currency, pay.Period, pay.Text, h.upsertCompany(r.Context(), job.Company, 0), job.Agency,
+		).Scan(&id, &inserted)
+		if err == nil {
+			h.work.Go(func(ctx context.Context) { h.geocodeJobLocations(ctx, []string{job.Location}) })
+		}
+	}
+	if err != nil {
+		h.internalError(w, r, "Failed to store job", err)
+		return
+	}
+	if _, err := h.db.Exec(r.Context(), "INSERT INTO user_jobs (user_id, job_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", userID, id); err != nil {
+		h.internalError(w, r, "Failed to store job", err)
+		return
+	}
+
+	stored, err := h.jobs.Job(r.Context(), userID, id)
+	if err != nil {
+		h.internalError(w, r, "Failed to get job", err)
+		return
+	}
+	setDescription(stored, stored.Description)
+	status := http.StatusOK
+	if inserted {
+		status = http.StatusCreated
+	}
+	h.json(w, stored, status)
+}
+
+// ManualApplicationRequest is an application the user made by hand: to a job already stored,
+// by job_id, or to the page described by url, title and company
+type ManualApplicationRequest struct {
+	JobID     string     `json:"job_id"`
+	URL       string     `json:"url"`
+	Title     string     `json:"title"`
+	Company   string     `json:"company"`
+	Status    string     `json:"status"`     // As the user tracks it, e.g. "applied"; defaults to submitted
+	AppliedAt *time.Time `json:"applied_at"` // Defaults to now
+}
+
+// RecordManualApplication handles POST /api/v1/applications/manual, tracking an application
+// the user submitted themselves alongside jobapply's own
+func (h *Handler) RecordManualApplication(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req ManualApplicationRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+
+	app := store.ImportedApplication{
+		AppliedAt:      time.Now(),
+		Status:         models.ApplicationSubmitted,
+		ExternalStatus: validation.SanitizeString(req.Status, 100),
+		Source:         "manual",
+	}
+	if req.AppliedAt != nil {
+		if req.AppliedAt.After(time.Now()) {
+			h.error(w, "applied_at is in the future", http.StatusBadRequest)
+			return
+		}
+		app.AppliedAt = *req.AppliedAt
+	}
+	if status, ok := importStatuses[strings.ToLower(app.ExternalStatus)]; ok {
+		app.Status = status
+	}
+
+	if req.JobID != "" {
+		if !h.validateUUID(w, req.JobID, "job ID") {
+			return
+		}
+		job, err := h.jobs.Job(r.Context(), userID, req.JobID)
+		if errors.Is(err, store.ErrNotFound) {
+			h.error(w, "Job not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			h.internalError(w, r, "Failed to get job", err)
+			return
+		}
+		app.URL, app.Title, app.Company = job.URL, job.Title, job.Company
+	} else {
+		company, _ := scrapers.SplitAgency(validation.SanitizeString(req.Company, 500))
+		job := scrapers.Job{Title: validation.SanitizeString(req.Title, 1000), Company: company, URL: scrapers.CanonicalURL(strings.TrimSpace(req.URL))}
+		if problems := job.Problems(); len(problems) > 0 {
+			h.error(w, "Pass job_id, or url, title and company: "+strings.Join(problems, ", "), http.StatusBadRequest)
+			return
+		}
+		app.URL, app.Title, app.Company = job.URL, job.Title, job.Company
+	}
+
+	result, err := h.applications.ImportApplications(r.Context(), userID, []store.ImportedApplication{app})
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+	if len(result.IDs) == 0 {
+		h.error(w, "You already have an application for this job", http.StatusConflict)
+		return
+	}
+	h.json(w, map[string]string{"id": result.IDs[0], "status": string(app.Status)}, http.StatusCreated)
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/yourusername/jobapply/internal/services"
+	"github.com/yourusername/jobapply/internal/validation"
+)
+
+var flagNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,63}$`)
+
+// FlagRequest creates or replaces a feature flag; the name comes from the URL
+type FlagRequest struct {
+	Description string   `json:"description"`
+	Enabled     bool     `json:"enabled"`
+	Percentage  int      `json:"percentage"`
+	Users       []string `json:"users"`
+}
+
+// Validate checks the rollout percentage and user IDs
+func (req *FlagRequest) Validate() error {
+	var v validation.Collector
+	req.Description = validation.SanitizeString(req.Description, 500)
+	v.Check(req.Percentage >= 0 && req.Percentage <= 100, "percentage", "percentage must be between 0 and 100")
+	for _, id := range req.Users {
+		if !validation.ValidateUUID(id) {
+			v.Check(false, "users", "users must be user IDs")
+			break
+		}
+	}
+	return v.Err()
+}
+
+// SetFlags enables feature flags and their endpoints
+func (h *Handler) SetFlags(f *services.Flags) {
+	h.flags = f
+}
+
+// GetMyFlags lists the feature flags that are on for the authenticated user, so clients can
+// show or hide features to match
+func (h *Handler) GetMyFlags(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if h.flags == nil {
+		h.json(w, map[string][]string{"flags": {}}, http.StatusOK)
+		return
+	}
+
+	names, err := h.flags.EnabledFor(r.Context(), userID)
+	if err != nil {
+		h.internalError(w, r, "Failed to get feature flags", err)
+		return
+	}
+	h.json(w, map[string][]string{"flags": names}, http.StatusOK)
+}
+
+// ListFlags returns every feature flag with its rollout
+func (h *Handler) ListFlags(w http.ResponseWriter, r *http.Request) {
+	if h.flags == nil {
+		h.json(w, []services.Flag{}, http.StatusOK)
+		return
+	}
+	flags, err := h.flags.List(r.Context())
+	if err != nil {
+		h.internalError(w, r, "Failed to list feature flags", err)
+		return
+	}
+	h.json(w, flags, http.StatusOK)
+}
+
+// GetFlag returns one feature flag
+func (h *Handler) GetFlag(w http.ResponseWriter, r *http.Request) {
+	if h.flags == nil {
+		h.error(w, "Feature flag not found", http.StatusNotFound)
+		return
+	}
+	flag, err := h.flags.Get(r.Context(), chi.URLParam(r, "name"))
+	if errors.Is(err, services.ErrUnknownFlag) {
+		h.error(w, "Feature flag not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.internalError(w, r, "Failed to get feature flag", err)
+		return
+	}
+	h.json(w, flag, http.StatusOK)
+}
+
+// PutFlag creates or replaces a feature flag. Changes apply at once on this instance and
+// within 30 seconds on others.
+func (h *Handler) PutFlag(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	if !flagNameRegex.MatchString(name) {
+		h.error(w, "Flag names are up to 64 lowercase letters, digits, '_', '.' or '-'", http.StatusBadRequest)
+		return
+	}
+	var req FlagRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	if h.flags == nil {
+		h.error(w, "Feature flags are not enabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	flag, err := h.flags.Put(r.Context(), services.Flag{
+		Name:        name,
+		Description: req.Description,
+		Enabled:     req.Enabled,
+		Percentage:  req.Percentage,
+		Users:       req.Users,
+	})
+	if err != nil {
+		h.internalError(w, r, "Failed to save feature flag", err)
+		return
+	}
+	h.json(w, flag, http.StatusOK)
+}
+
+// DeleteFlag removes a feature flag, turning it off for everyone
+func (h *Handler) DeleteFlag(w http.ResponseWriter, r *http.Request) {
+	if h.flags == nil {
+		h.error(w, "Feature flag not found", http.StatusNotFound)
+		return
+	}
+	err := h.flags.Delete(r.Context(), chi.URLParam(r, "name"))
+	if errors.Is(err, services.ErrUnknownFlag) {
+		h.error(w, "Feature flag not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.internalError(w, r, "Failed to delete feature flag", err)
+		return
+	}
+	h.json(w, message{"message": "Feature flag deleted"}, http.StatusOK)
+}
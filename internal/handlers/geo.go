@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yourusername/jobapply/internal/apierror"
+	"github.com/yourusername/jobapply/internal/geo"
+	"github.com/yourusername/jobapply/internal/logging"
+	"github.com/yourusername/jobapply/internal/models"
+)
+
+// geocodeProfile resolves the profile address in the background and stores its coordinates.
+// Failures only mean distance filters are unavailable, so they are logged and dropped.
+func (h *Handler) geocodeProfile(ctx context.Context, userID string, addr models.Address) {
+	if h.geocoder == nil {
+		return
+	}
+
+	query := addressQuery(addr)
+	if query == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	point, err := h.geocoder.Geocode(ctx, query)
+	if err != nil {
+		if !errors.Is(err, geo.ErrNotFound) {
+			logging.FromContext(ctx).Warn("Geocoding profile failed", "user_id", userID, "error", err)
+		}
+		return
+	}
+
+	// Only store if the address hasn't changed again while we were geocoding
+	if err := h.users.SetProfileLocation(ctx, userID, addr, point.Lat, point.Lng); err != nil {
+		logging.FromContext(ctx).Error("Failed to store profile coordinates", "user_id", userID, "error", err)
+	}
+}
+
+// geocodeJobLocations resolves distinct job locations in the background. Many jobs share a
+// location string, so each is geocoded once and written to every matching job.
+func (h *Handler) geocodeJobLocations(ctx context.Context, locations []string) {
+	if h.geocoder == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	seen := make(map[string]bool)
+	for _, location := range locations {
+		location = strings.TrimSpace(location)
+		if location == "" || seen[location] || geo.IsRemote(location) {
+			continue
+		}
+		seen[location] = true
+
+		// Skip locations already resolved (or already tried) by an earlier scrape
+		var pending bool
+		err := h.db.QueryRow(ctx,
+			"SELECT EXISTS (SELECT 1 FROM jobs WHERE location = $1 AND geocoded_at IS NULL)", location).Scan(&pending)
+		if err != nil || !pending {
+			continue
+		}
+
+		// Copy coordinates from an earlier job at the same place before spending a request
+		result, err := h.db.Exec(ctx, `
+			UPDATE jobs SET latitude = prev.latitude, longitude = prev.longitude, geocoded_at = NOW()
+			FROM (SELECT latitude, longitude FROM jobs WHERE location = $1 AND geocoded_at IS NOT NULL LIMIT 1) prev
+			WHERE jobs.location = $1 AND jobs.geocoded_at IS NULL`, location)
+		if err == nil && result.RowsAffected() > 0 {
+			continue
+		}
+
+		var lat, lng *float64
+		point, err := h.geocoder.Geocode(ctx, location)
+		if err == nil {
+			lat, lng = &point.Lat, &point.Lng
+		} else if !errors.Is(err, geo.ErrNotFound) {
+			logging.FromContext(ctx).Warn("Geocoding job location failed", "location", location, "error", err)
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		// Unknown places are marked as tried too so they aren't looked up on every scrape
+		_, err = h.db.Exec(ctx,
+			"UPDATE jobs SET latitude = $1, longitude = $2, geocoded_at = NOW() WHERE location = $3",
+			lat, lng, location)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to store job coordinates", "location", location, "error", err)
+		}
+	}
+}
+
+// addressQuery formats an address as a single-line geocoding query
+func addressQuery(addr models.Address) string {
+	var parts []string
+	for _, part := range []string{addr.Street, addr.City, addr.State, addr.ZipCode} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// searchOrigin resolves where a distance search is measured from: near=me or no near means
+// the profile address, explicit lat/lng win, and any other near is a place name
+func (h *Handler) searchOrigin(ctx context.Context, near, latParam, lngParam string, profile *models.UserProfile) (geo.Point, error) {
+	if latParam != "" || lngParam != "" {
+		lat, errLat := strconv.ParseFloat(latParam, 64)
+		lng, errLng := strconv.ParseFloat(lngParam, 64)
+		if errLat != nil || errLng != nil || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+			return geo.Point{}, apierror.New(http.StatusBadRequest, "Invalid lat/lng")
+		}
+		return geo.Point{Lat: lat, Lng: lng}, nil
+	}
+	if near = strings.TrimSpace(near); near != "" && !strings.EqualFold(near, "me") {
+		return h.geocodePlace(ctx, near)
+	}
+	if profile == nil || profile.Latitude == nil || profile.Longitude == nil {
+		return geo.Point{}, apierror.New(http.StatusBadRequest, "Your address has not been geocoded yet; add an address to your profile or pass lat/lng")
+	}
+	return geo.Point{Lat: *profile.Latitude, Lng: *profile.Longitude}, nil
+}
+
+// geocodePlace resolves a place name for a distance search. A job location already geocoded
+// under the same name is reused, which spares the geocoder's rate limit for common cities.
+func (h *Handler) geocodePlace(ctx context.Context, place string) (geo.Point, error) {
+	var point geo.Point
+	err := h.db.QueryRow(ctx, `
+		SELECT latitude, longitude FROM jobs
+		WHERE LOWER(location) = LOWER($1) AND latitude IS NOT NULL AND longitude IS NOT NULL
+		LIMIT 1`, place).Scan(&point.Lat, &point.Lng)
+	if err == nil {
+		return point, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return geo.Point{}, err
+	}
+
+	if h.geocoder == nil {
+		return geo.Point{}, apierror.New(http.StatusBadRequest, "Searching near a place is not available; pass lat/lng or near=me")
+	}
+	found, err := h.geocoder.Geocode(ctx, place)
+	if errors.Is(err, geo.ErrNotFound) {
+		return geo.Point{}, apierror.New(http.StatusBadRequest, "Place not found: "+place)
+	}
+	if err != nil {
+		return geo.Point{}, apierror.Wrap(err, http.StatusBadGateway, "Geocoding is unavailable, try again later")
+	}
+	return *found, nil
+}
+
+// haversineSQL is the great-circle distance in miles between two coordinate pairs of SQL
+// expressions
+func haversineSQL(lat1, lng1, lat2, lng2 string) string {
+	return fmt.Sprintf(`(%f * 2 * ASIN(SQRT(
+		POWER(SIN(RADIANS(%s - %s) / 2), 2) +
+		COS(RADIANS(%s)) * COS(RADIANS(%s)) * POWER(SIN(RADIANS(%s - %s) / 2), 2))))`,
+		geo.EarthRadiusMiles, lat2, lat1, lat1, lat2, lng2, lng1)
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yourusername/jobapply/internal/logging"
+	"github.com/yourusername/jobapply/internal/matching"
+)
+
+const (
+	// ghostBatch bounds the jobs one ScoreGhostJobs run scores
+	ghostBatch = 1000
+	// ghostMinDescription is the shortest description compared across companies; short
+	// boilerplate matches too easily
+	ghostMinDescription = 200
+)
+
+// ScoreGhostJobs rates how likely each job is a ghost job or an agency repost (see
+// matching.GhostScore) for the hide_ghosts filter on GET /jobs. Jobs are scored when new or
+// refreshed by a scrape, and rescored daily as other postings come and go. It is run by the
+// scheduler.
+func (h *Handler) ScoreGhostJobs(ctx context.Context) error {
+	rows, err := h.db.Query(ctx, `
+		SELECT j.id,
+			CASE WHEN LENGTH(j.description) >= $1 THEN (
+				SELECT COUNT(DISTINCT normalize_company_name(d.company)) FROM jobs d
+				WHERE d.description_hash = j.description_hash AND d.deleted_at IS NULL AND d.owner_id IS NULL
+				AND normalize_company_name(d.company) <> normalize_company_name(j.company)
+			) ELSE 0 END,
+			(SELECT COUNT(*) FROM jobs r
+				WHERE normalize_company_name(r.company) = normalize_company_name(j.company) AND LOWER(r.title) = LOWER(j.title)
+				AND r.url <> j.url AND r.owner_id IS NULL AND r.scraped_at > NOW() - INTERVAL '90 days'),
+			j.salary_min IS NULL AND j.salary_max IS NULL,
+			j.agency IS NOT NULL OR COALESCE(c.staffing_agency, FALSE)
+		FROM jobs j
+		LEFT JOIN companies c ON c.id = j.company_id
+		WHERE j.deleted_at IS NULL
+		AND (j.ghost_scored_at IS NULL OR j.ghost_scored_at < j.cached_at OR j.ghost_scored_at < NOW() - INTERVAL '1 day')
+		ORDER BY j.ghost_scored_at NULLS FIRST
+		LIMIT $2
+	`, ghostMinDescription, ghostBatch)
+	if err != nil {
+		return fmt.Errorf("failed to find jobs to score: %w", err)
+	}
+
+	var ids, reasons []string // Reasons as JSON arrays, since a text[][] can't be ragged
+	var scores []int
+	var signals matching.GhostSignals
+	var id string
+	_, err = pgx.ForEachRow(rows, []any{&id, &signals.DuplicateCompanies, &signals.Reposts, &signals.NoSalary, &signals.Agency}, func() error {
+		score, why := matching.GhostScore(signals)
+		encoded, err := json.Marshal(why)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+		scores = append(scores, score)
+		reasons = append(reasons, string(encoded))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to score jobs: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if _, err := h.db.Exec(ctx, `
+		UPDATE jobs SET ghost_score = t.score, ghost_scored_at = NOW(),
+			ghost_reasons = ARRAY(SELECT jsonb_array_elements_text(t.reasons::jsonb))
+		FROM unnest($1::uuid[], $2::int[], $3::text[]) AS t(id, score, reasons)
+		WHERE jobs.id = t.id
+	`, ids, scores, reasons); err != nil {
+		return fmt.Errorf("failed to store ghost scores: %w", err)
+	}
+	logging.FromContext(ctx).Info("Ghost jobs scored", "jobs", len(ids))
+	return nil
+}
@@ -4,54 +4,114 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"os"
-	"path/filepath"
+	"path"
+	"strings"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yourusername/jobapply/internal/apierror"
+	"github.com/yourusername/jobapply/internal/autofill"
+	"github.com/yourusername/jobapply/internal/events"
+	"github.com/yourusername/jobapply/internal/geo"
+	"github.com/yourusername/jobapply/internal/logging"
 	"github.com/yourusername/jobapply/internal/models"
+	"github.com/yourusername/jobapply/internal/resume"
+	"github.com/yourusername/jobapply/internal/scanner"
+	"github.com/yourusername/jobapply/internal/services"
+	"github.com/yourusername/jobapply/internal/shutdown"
+	"github.com/yourusername/jobapply/internal/storage"
+	"github.com/yourusername/jobapply/internal/store"
 	"github.com/yourusername/jobapply/internal/validation"
 )
 
 type Handler struct {
-	db            *pgxpool.Pool
-	uploadDir     string
-	maxUploadSize int64
+	db               *pgxpool.Pool // For queries not yet moved into the store layer
+	stores           *store.Store  // For writes spanning several stores, through InTx
+	users            store.UserStore
+	jobs             store.JobStore
+	applications     store.ApplicationStore
+	notifications    store.NotificationStore
+	outbox           store.OutboxStore
+	events           events.Bus
+	deliveries       *services.Outbox // Dispatches the outbox; nil if not running
+	storage          storage.Storage
+	maxUploadSize    int64
+	maxResumePages   int // 0 allows any number
+	resumeParser     *resume.Parser
+	previewer        *resume.Previewer
+	geocoder         geo.Geocoder // nil disables geocoding
+	router           geo.Router   // nil disables commute estimates
+	commuteModes     []string
+	fileScanner      scanner.Scanner
+	uploadSigningKey []byte // Signs short-lived /uploads links; must match across instances
+	work             *shutdown.Coordinator
+	healthChecks     []healthCheck // Extra readiness checks registered with AddHealthCheck
+	scheduler        *services.Scheduler
+	scrapeCache      *services.ScrapeCache
+	flags            *services.Flags
+	retention        RetentionPolicy
+	impersonationTTL time.Duration // How long impersonation tokens last
+	authMode         string        // One of the AuthMode constants; "" is bearer
+	cookies          CookieAuth
 }
 
-func New(db *pgxpool.Pool, uploadDir string, maxUploadSize int64) *Handler {
-	return &Handler{
-		db:            db,
-		uploadDir:     uploadDir,
-		maxUploadSize: maxUploadSize,
-	}
+func New(db *pgxpool.Pool, stores *store.Store, files storage.Storage, maxUploadSize int64, resumeParser *resume.Parser, geocoder geo.Geocoder, fileScanner scanner.Scanner, uploadSigningKey []byte) *Handler {
+	if fileScanner == nil {
+		fileScanner = scanner.Noop{}
+	}
+	h := &Handler{
+		db:               db,
+		stores:           stores,
+		users:            stores.Users,
+		jobs:             stores.Jobs,
+		applications:     stores.Applications,
+		notifications:    stores.Notifications,
+		outbox:           stores.Outbox,
+		storage:          files,
+		maxUploadSize:    maxUploadSize,
+		resumeParser:     resumeParser,
+		geocoder:         geocoder,
+		fileScanner:      fileScanner,
+		uploadSigningKey: uploadSigningKey,
+		scrapeCache:      services.NewScrapeCache(db, services.DefaultCacheTTL, nil),
+		work:             shutdown.New(),
+		impersonationTTL: defaultImpersonationTTL,
+	}
+	h.SetEventBus(events.NewLocal())
+	return h
 }
 
-// Health check
-func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
-	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
-	defer cancel()
+// SetEventBus replaces the in-process event bus, e.g. with one shared between instances, and
+// subscribes the notification inbox to it. The outbox should deliver to the same bus.
+func (h *Handler) SetEventBus(bus events.Bus) {
+	h.events = bus
+	bus.Subscribe("inbox", h.notifyEvent, events.ApplicationStatusChanged, events.ScrapeCompleted, events.ScrapeFailed)
+	bus.Subscribe("snapshots", h.snapshotApplication, events.ApplicationCreated)
+}
 
-	resp := map[string]string{
-		"status": "ok",
-		"time":   time.Now().Format(time.RFC3339),
-	}
+// SetOutbox makes the outbox's dead letters visible to the admin endpoints
+func (h *Handler) SetOutbox(o *services.Outbox) {
+	h.deliveries = o
+}
 
-	if err := h.db.Ping(ctx); err != nil {
-		resp["status"] = "error"
-		resp["database"] = "disconnected"
-		w.WriteHeader(http.StatusServiceUnavailable)
-	} else {
-		resp["database"] = "connected"
-		w.WriteHeader(http.StatusOK)
+// publish queues an event about a change the handler made in the outbox, logging rather than
+// failing the request if it can't
+func (h *Handler) publish(ctx context.Context, typ events.Type, userID string, data any) {
+	if err := h.outbox.Enqueue(ctx, events.New(typ, userID, data)); err != nil {
+		logging.FromContext(ctx).Error("Failed to queue event", "event_type", typ, "error", err)
 	}
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(resp)
+// Drain stops new scrapes and waits for in-flight ones, and the background work they
+// started, until ctx ends. Call it before shutting down the HTTP server and the database.
+func (h *Handler) Drain(ctx context.Context) error {
+	return h.work.Drain(ctx)
 }
 
 // CreateProfile updates the authenticated user's profile
@@ -63,37 +123,21 @@ func (h *Handler) CreateProfile(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req models.UserProfile
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.error(w, "Invalid request body", http.StatusBadRequest)
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	if err := validateProfile(&req); err != nil {
+		h.fail(w, r, apierror.Invalid(err))
 		return
 	}
 
-	query := `
-		UPDATE user_profiles
-		SET full_name = $1, phone = $2, address = $3, work_history = $4, education = $5, skills = $6, updated_at = NOW()
-		WHERE id = $7
-		RETURNING id, full_name, email, phone, address, work_history, education, resume_url, skills, created_at, updated_at
-	`
-
-	var profile models.UserProfile
-	err := h.db.QueryRow(r.Context(), query,
-		req.FullName,
-		req.Phone,
-		toJSON(req.Address), toJSON(req.WorkHistory), toJSON(req.Education),
-		req.Skills,
-		userID,
-	).Scan(
-		&profile.ID, &profile.FullName, &profile.Email, &profile.Phone,
-		scanJSON(&profile.Address), scanJSON(&profile.WorkHistory), scanJSON(&profile.Education),
-		&profile.ResumeURL, &profile.Skills, &profile.CreatedAt, &profile.UpdatedAt,
-	)
-
+	profile, err := h.saveProfile(r.Context(), userID, &req)
 	if err != nil {
-		h.error(w, fmt.Sprintf("Failed to update profile: %v", err), http.StatusInternalServerError)
+		h.internalError(w, r, "Failed to update profile", err)
 		return
 	}
 
-	h.json(w, profile, http.StatusOK)
+	h.json(w, *profile, http.StatusOK)
 }
 
 // GetProfile gets the authenticated user's profile
@@ -104,16 +148,17 @@ func (h *Handler) GetProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	profile, err := h.getUserProfile(r.Context(), userID)
+	profile, err := h.users.Profile(r.Context(), userID)
 	if err != nil {
-		if err.Error() == "profile not found" {
+		if errors.Is(err, store.ErrNotFound) {
 			h.error(w, "Profile not found", http.StatusNotFound)
 			return
 		}
-		h.error(w, fmt.Sprintf("Failed to get profile: %v", err), http.StatusInternalServerError)
+		h.internalError(w, r, "Failed to get profile", err)
 		return
 	}
 
+	profile.ResumePreviewURL = h.resumePreviewURL(r.Context(), profile.ResumeURL)
 	h.json(w, *profile, http.StatusOK)
 }
 
@@ -125,29 +170,62 @@ func (h *Handler) UploadResume(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	resumeURL, key, warning, ok := h.storeResumeUpload(w, r, userID)
+	if !ok {
+		return
+	}
+
+	// Update profile with resume URL only (no parsing)
+	query := `
+		UPDATE user_profiles
+		SET resume_url = $1, updated_at = NOW()
+		WHERE id = $2
+	`
+	result, err := h.db.Exec(r.Context(), query, resumeURL, userID)
+	if err != nil || result.RowsAffected() == 0 {
+		h.releaseUpload(r.Context(), key)
+		h.error(w, "Failed to update profile", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]string{
+		"resume_url": resumeURL,
+		"message":    "Resume uploaded successfully. Please add work history manually.",
+	}
+	if warning != "" {
+		response["warning"] = warning
+	}
+
+	h.json(w, response, http.StatusOK)
+}
+
+// storeResumeUpload validates the multipart "resume" file and saves it to storage under a random key
+// for userID. warning is set for files that are accepted but likely to cause trouble.
+// On failure the error response has already been written and ok is false.
+func (h *Handler) storeResumeUpload(w http.ResponseWriter, r *http.Request, userID string) (resumeURL, key, warning string, ok bool) {
 	// Limit form parsing size to prevent memory exhaustion
 	if err := r.ParseMultipartForm(h.maxUploadSize); err != nil {
 		h.error(w, "File too large or invalid request", http.StatusBadRequest)
-		return
+		return "", "", "", false
 	}
 
 	file, header, err := r.FormFile("resume")
 	if err != nil {
 		h.error(w, "Failed to read file", http.StatusBadRequest)
-		return
+		return "", "", "", false
 	}
 	defer file.Close()
 
 	// Double-check file size to prevent bypasses
 	if header.Size > h.maxUploadSize {
 		h.error(w, "File too large (max 5MB)", http.StatusBadRequest)
-		return
+		return "", "", "", false
 	}
 
 	// Minimum file size check (prevent empty or tiny malicious files)
 	if header.Size < 100 {
 		h.error(w, "File too small to be a valid resume", http.StatusBadRequest)
-		return
+		return "", "", "", false
 	}
 
 	// Sanitize original filename to prevent path traversal
@@ -156,109 +234,81 @@ func (h *Handler) UploadResume(w http.ResponseWriter, r *http.Request) {
 	// Validate file extension using whitelist
 	if !validation.ValidateFileExtension(sanitizedName, []string{".pdf"}) {
 		h.error(w, "Only PDF files allowed", http.StatusBadRequest)
-		return
+		return "", "", "", false
 	}
 
 	// Read file content to verify it's actually a PDF (magic number check)
 	buffer := make([]byte, 512)
 	n, err := file.Read(buffer)
 	if err != nil && err != io.EOF {
-		h.error(w, "Failed to read file", http.StatusInternalServerError)
-		return
+		h.internalError(w, r, "Failed to read file", err)
+		return "", "", "", false
 	}
 
 	// Check PDF magic number signature (%PDF)
 	if n < 4 || !bytes.HasPrefix(buffer[:n], []byte("%PDF")) {
 		h.error(w, "Invalid PDF file (file content does not match PDF format)", http.StatusBadRequest)
-		return
+		return "", "", "", false
 	}
 
-	// Reset file pointer to beginning for copying
+	// Scan for malware before anything touches disk. Fail closed if the scanner is unavailable.
 	if _, err := file.Seek(0, 0); err != nil {
-		h.error(w, "Failed to process file", http.StatusInternalServerError)
-		return
+		h.internalError(w, r, "Failed to process file", err)
+		return "", "", "", false
+	}
+	if err := h.fileScanner.Scan(r.Context(), file); err != nil {
+		if errors.Is(err, scanner.ErrInfected) {
+			logging.FromContext(r.Context()).Warn("Rejected infected upload", "error", err)
+			h.error(w, "File rejected: malware detected", http.StatusUnprocessableEntity)
+			return "", "", "", false
+		}
+		logging.FromContext(r.Context()).Error("Upload scan failed", "error", err)
+		h.error(w, "File could not be scanned, please try again later", http.StatusServiceUnavailable)
+		return "", "", "", false
 	}
 
-	// Generate secure random filename (prevents guessing and overwrites)
-	filename := fmt.Sprintf("%s.pdf", uuid.New().String())
-	filePath := filepath.Join(h.uploadDir, filename)
-
-	os.MkdirAll(h.uploadDir, 0755)
-
-	dst, err := os.Create(filePath)
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		h.internalError(w, r, "Failed to process file", err)
+		return "", "", "", false
+	}
+	data, err := io.ReadAll(file)
 	if err != nil {
-		h.error(w, "Failed to save file", http.StatusInternalServerError)
-		return
+		h.internalError(w, r, "Failed to read file", err)
+		return "", "", "", false
 	}
-	defer dst.Close()
 
-	if _, err := io.Copy(dst, file); err != nil {
-		h.error(w, "Failed to save file", http.StatusInternalServerError)
-		return
+	// Embedded JavaScript is neutralized before anything touches disk
+	data, info, err := resume.SanitizePDF(data)
+	switch {
+	case errors.Is(err, resume.ErrEncrypted):
+		h.error(w, "Password-protected PDFs can't be read. Remove the password and upload it again.", http.StatusBadRequest)
+		return "", "", "", false
+	case errors.Is(err, resume.ErrScriptNotRemovable):
+		h.error(w, "PDF contains embedded scripts that can't be removed. Print or export it to a new PDF and upload that.", http.StatusBadRequest)
+		return "", "", "", false
+	case err != nil:
+		h.internalError(w, r, "Failed to process file", err)
+		return "", "", "", false
 	}
-
-	resumeURL := fmt.Sprintf("/uploads/%s", filename)
-
-	// Update profile with resume URL only (no parsing)
-	query := `
-		UPDATE user_profiles
-		SET resume_url = $1, updated_at = NOW()
-		WHERE id = $2
-	`
-	result, err := h.db.Exec(r.Context(), query, resumeURL, userID)
-	if err != nil || result.RowsAffected() == 0 {
-		os.Remove(filePath)
-		h.error(w, "Failed to update profile", http.StatusInternalServerError)
-		return
+	if h.maxResumePages > 0 && info.Pages > h.maxResumePages {
+		h.error(w, fmt.Sprintf("Resume has %d pages (max %d)", info.Pages, h.maxResumePages), http.StatusBadRequest)
+		return "", "", "", false
 	}
-
-	response := map[string]string{
-		"resume_url": resumeURL,
-		"message":    "Resume uploaded successfully. Please add work history manually.",
+	if info.ScriptsRemoved > 0 {
+		logging.FromContext(r.Context()).Info("Removed scripts from upload", "scripts", info.ScriptsRemoved)
 	}
 
-	h.json(w, response, http.StatusOK)
-}
-
-// GetJobs gets all scraped jobs
-func (h *Handler) GetJobs(w http.ResponseWriter, r *http.Request) {
-	query := `
-		SELECT id, title, company, location, url, scraped_at
-		FROM jobs
-		ORDER BY scraped_at DESC
-		LIMIT 50
-	`
-
-	rows, err := h.db.Query(r.Context(), query)
+	key, err = h.saveUpload(r.Context(), userID, bytes.NewReader(data), int64(len(data)), "application/pdf")
 	if err != nil {
-		h.error(w, fmt.Sprintf("Failed to get jobs: %v", err), http.StatusInternalServerError)
-		return
+		h.internalError(w, r, "Failed to save file", err)
+		return "", "", "", false
 	}
-	defer rows.Close()
 
-	type Job struct {
-		ID        string    `json:"id"`
-		Title     string    `json:"title"`
-		Company   string    `json:"company"`
-		Location  string    `json:"location"`
-		URL       string    `json:"url"`
-		ScrapedAt time.Time `json:"scraped_at"`
-	}
-
-	jobs := []Job{}
-	for rows.Next() {
-		var job Job
-		var location *string
-		if err := rows.Scan(&job.ID, &job.Title, &job.Company, &location, &job.URL, &job.ScrapedAt); err != nil {
-			continue
-		}
-		if location != nil {
-			job.Location = *location
-		}
-		jobs = append(jobs, job)
+	if h.previewer != nil {
+		h.work.Go(func(ctx context.Context) { h.generatePreview(ctx, key, data) })
 	}
 
-	h.json(w, jobs, http.StatusOK)
+	return fmt.Sprintf("/uploads/%s", key), key, h.missingTextWarning(r.Context(), data), true
 }
 
 // DeleteProfile deletes the authenticated user's profile and associated data
@@ -270,29 +320,32 @@ func (h *Handler) DeleteProfile(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get profile first to delete resume file
-	profile, err := h.getUserProfile(r.Context(), userID)
-	if err == nil && profile.ResumeURL != nil && *profile.ResumeURL != "" {
-		// Delete the resume file if it exists
-		filename := filepath.Base(*profile.ResumeURL)
-		filePath := filepath.Join(h.uploadDir, filename)
-		os.Remove(filePath) // Ignore errors - file might not exist
-	}
+	profile, err := h.users.Profile(r.Context(), userID)
 
 	// Delete the user profile
-	result, err := h.db.Exec(r.Context(), "DELETE FROM user_profiles WHERE id = $1", userID)
-	if err != nil {
-		h.error(w, fmt.Sprintf("Failed to delete profile: %v", err), http.StatusInternalServerError)
+	if err := h.users.DeleteUser(r.Context(), userID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			h.error(w, "Profile not found", http.StatusNotFound)
+			return
+		}
+		h.internalError(w, r, "Failed to delete profile", err)
 		return
 	}
-
-	if result.RowsAffected() == 0 {
-		h.error(w, "Profile not found", http.StatusNotFound)
-		return
+	if err == nil && profile.ResumeURL != nil && *profile.ResumeURL != "" {
+		// Other users may have uploaded the same file
+		h.releaseUpload(r.Context(), uploadKey(*profile.ResumeURL))
 	}
 
 	h.json(w, map[string]string{"message": "Profile deleted successfully"}, http.StatusOK)
 }
 
+type ValidationResponse struct {
+	IsComplete        bool     `json:"is_complete"`
+	YearsOfExperience float64  `json:"years_of_experience"`
+	MissingFields     []string `json:"missing_fields,omitempty"`
+	Message           string   `json:"message,omitempty"`
+}
+
 // ValidateProfile checks if the authenticated user's profile is complete enough for job searching
 func (h *Handler) ValidateProfile(w http.ResponseWriter, r *http.Request) {
 	userID := getUserIDFromContext(r.Context())
@@ -301,7 +354,7 @@ func (h *Handler) ValidateProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	profile, err := h.getUserProfile(r.Context(), userID)
+	profile, err := h.users.Profile(r.Context(), userID)
 	if err != nil {
 		h.error(w, "Profile not found", http.StatusNotFound)
 		return
@@ -342,13 +395,6 @@ func (h *Handler) ValidateProfile(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	type ValidationResponse struct {
-		IsComplete        bool     `json:"is_complete"`
-		YearsOfExperience float64  `json:"years_of_experience"`
-		MissingFields     []string `json:"missing_fields,omitempty"`
-		Message           string   `json:"message,omitempty"`
-	}
-
 	response := ValidationResponse{
 		IsComplete:        len(missingFields) == 0,
 		YearsOfExperience: totalYears,
@@ -364,7 +410,8 @@ func (h *Handler) ValidateProfile(w http.ResponseWriter, r *http.Request) {
 	h.json(w, response, http.StatusOK)
 }
 
-// GetApplications gets applications for the authenticated user
+// GetApplications gets applications for the authenticated user, optionally filtered by ?tag=.
+// With ?deleted=true it lists deleted applications that can still be restored instead.
 func (h *Handler) GetApplications(w http.ResponseWriter, r *http.Request) {
 	userID := getUserIDFromContext(r.Context())
 	if userID == "" {
@@ -372,53 +419,153 @@ func (h *Handler) GetApplications(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	query := `
-		SELECT a.id, a.status, a.applied_at, a.filled_fields, j.title, j.company, j.url
-		FROM applications a
-		JOIN jobs j ON a.job_id = j.id
-		WHERE a.user_id = $1
-		ORDER BY a.applied_at DESC
-	`
+	if r.URL.Query().Get("deleted") == "true" {
+		applications, err := h.applications.DeletedApplications(r.Context(), userID)
+		if err != nil {
+			h.internalError(w, r, "Failed to get applications", err)
+			return
+		}
+		h.json(w, applications, http.StatusOK)
+		return
+	}
 
-	rows, err := h.db.Query(r.Context(), query, userID)
+	var tags []string
+	for _, tag := range r.URL.Query()["tag"] {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	// Users can have thousands of applications, so they are streamed as they are read
+	list := newJSONStream(w, "", "")
+	err := h.applications.EachApplication(r.Context(), userID, tags, func(app *models.Application) error {
+		return list.add(app)
+	})
+	if err == nil {
+		err = list.close()
+	}
 	if err != nil {
-		h.error(w, fmt.Sprintf("Failed to get applications: %v", err), http.StatusInternalServerError)
+		h.failStream(w, r, list, "Failed to get applications", err)
+	}
+}
+
+// DeleteApplication soft-deletes an application. It can be restored until the purge task
+// removes it.
+func (h *Handler) DeleteApplication(w http.ResponseWriter, r *http.Request) {
+	h.setApplicationDeleted(w, r, true)
+}
+
+// RestoreApplication undeletes an application
+func (h *Handler) RestoreApplication(w http.ResponseWriter, r *http.Request) {
+	h.setApplicationDeleted(w, r, false)
+}
+
+// GetApplicationEvents returns an application's status history, oldest first
+func (h *Handler) GetApplicationEvents(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
-	defer rows.Close()
 
-	type Application struct {
-		ID           string    `json:"id"`
-		Status       string    `json:"status"`
-		AppliedAt    time.Time `json:"applied_at"`
-		FieldsFilled []string  `json:"fields_filled"`
-		JobTitle     string    `json:"job_title"`
-		Company      string    `json:"company"`
-		JobURL       string    `json:"job_url"`
+	applicationID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, applicationID, "application ID") {
+		return
 	}
 
-	applications := []Application{}
-	for rows.Next() {
-		var app Application
-		var filledFieldsJSON []byte
-		if err := rows.Scan(&app.ID, &app.Status, &app.AppliedAt, &filledFieldsJSON, &app.JobTitle, &app.Company, &app.JobURL); err != nil {
-			continue
+	events, err := h.applications.ApplicationEvents(r.Context(), userID, applicationID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			h.error(w, "Application not found", http.StatusNotFound)
+			return
 		}
+		h.internalError(w, r, "Failed to get application history", err)
+		return
+	}
+	h.json(w, events, http.StatusOK)
+}
 
-		// Parse filled_fields JSON
-		if len(filledFieldsJSON) > 0 {
-			var fieldsData map[string][]string
-			if err := json.Unmarshal(filledFieldsJSON, &fieldsData); err == nil {
-				if fields, ok := fieldsData["fields"]; ok {
-					app.FieldsFilled = fields
-				}
+// GetApplicationQuestions returns the custom questions from every page of the application
+// form that it paused for, with the user's answers. Unanswered questions get a suggested
+// answer from the employer's application template or the profile.
+func (h *Handler) GetApplicationQuestions(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	applicationID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, applicationID, "application ID") {
+		return
+	}
+
+	pages, err := h.applications.ApplicationQuestions(r.Context(), userID, applicationID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			h.error(w, "Application not found", http.StatusNotFound)
+			return
+		}
+		h.internalError(w, r, "Failed to get application questions", err)
+		return
+	}
+
+	profile, err := h.autofillProfile(r.Context(), userID)
+	if err != nil {
+		h.internalError(w, r, "Failed to get application questions", err)
+		return
+	}
+	template, err := h.applicationTemplate(r.Context(), userID, applicationID)
+	if err != nil {
+		h.internalError(w, r, "Failed to get application questions", err)
+		return
+	}
+	now := time.Now()
+	for i := range pages {
+		for j := range pages[i].Questions {
+			q := &pages[i].Questions[j]
+			class := autofill.ClassifyQuestion(q.Question)
+			q.Class = string(class)
+			if q.Answer != nil {
+				continue
+			}
+			// The employer's template wins over what the profile implies
+			if answer, ok := templateAnswer(template, q); ok {
+				q.SuggestedAnswer = &answer
+			} else if answer, ok := autofill.AutoAnswer(class, profile, now); ok {
+				q.SuggestedAnswer = &answer
 			}
 		}
+	}
+	h.json(w, pages, http.StatusOK)
+}
+
+func (h *Handler) setApplicationDeleted(w http.ResponseWriter, r *http.Request, deleted bool) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 
-		applications = append(applications, app)
+	applicationID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, applicationID, "application ID") {
+		return
+	}
+
+	update, message := h.applications.DeleteApplication, "Application deleted successfully"
+	if !deleted {
+		update, message = h.applications.RestoreApplication, "Application restored successfully"
+	}
+	if err := update(r.Context(), userID, applicationID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			h.error(w, "Application not found", http.StatusNotFound)
+			return
+		}
+		h.internalError(w, r, "Failed to update application", err)
+		return
 	}
 
-	h.json(w, applications, http.StatusOK)
+	h.json(w, map[string]string{"message": message}, http.StatusOK)
 }
 
 // Helper functions
@@ -428,10 +575,24 @@ func (h *Handler) json(w http.ResponseWriter, data interface{}, status int) {
 	json.NewEncoder(w).Encode(data)
 }
 
+// error sends msg in the error envelope, with the default code for status
 func (h *Handler) error(w http.ResponseWriter, msg string, status int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+	apierror.Write(w, apierror.New(status, msg))
+}
+
+// fail maps err onto the error envelope. Server-side failures are logged with their cause,
+// which is never sent to the client.
+func (h *Handler) fail(w http.ResponseWriter, r *http.Request, err error) {
+	apiErr := apierror.From(err)
+	if apiErr.Status >= http.StatusInternalServerError {
+		logging.FromContext(r.Context()).Error(apiErr.Message, "status", apiErr.Status, "error", err)
+	}
+	apierror.Write(w, apiErr)
+}
+
+// internalError logs err and sends msg as a 500
+func (h *Handler) internalError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	h.fail(w, r, apierror.Wrap(err, http.StatusInternalServerError, msg))
 }
 
 // validateUUID validates a UUID string and sends error response if invalid
@@ -443,60 +604,40 @@ func (h *Handler) validateUUID(w http.ResponseWriter, id, fieldName string) bool
 	return true
 }
 
-// getUserProfile fetches a user profile by ID from the database
-func (h *Handler) getUserProfile(ctx context.Context, userID string) (*models.UserProfile, error) {
-	query := `
-		SELECT id, full_name, email, phone, address, work_history, education, resume_url, skills, created_at, updated_at
-		FROM user_profiles WHERE id = $1
-	`
+const maxProfileSkills = 100
 
-	var profile models.UserProfile
-	err := h.db.QueryRow(ctx, query, userID).Scan(
-		&profile.ID, &profile.FullName, &profile.Email, &profile.Phone,
-		scanJSON(&profile.Address), scanJSON(&profile.WorkHistory), scanJSON(&profile.Education),
-		&profile.ResumeURL, &profile.Skills, &profile.CreatedAt, &profile.UpdatedAt,
-	)
+// validateProfile checks the editable profile fields. Work history and education entries
+// are checked when edited through their own endpoints.
+func validateProfile(p *models.UserProfile) error {
+	var v validation.Collector
+	v.Check(len(p.FullName) <= 100, "full_name", "full_name must be at most 100 characters")
+	v.Check(p.Phone == "" || validation.ValidatePhone(p.Phone), "phone", "phone is not a valid phone number")
+	v.Check(p.DesiredSalary == nil || *p.DesiredSalary >= 0, "desired_salary", "desired_salary cannot be negative")
+	v.Check(len(p.Skills) <= maxProfileSkills, "skills", fmt.Sprintf("at most %d skills are allowed", maxProfileSkills))
+	if p.Address != nil {
+		v.Add("address", validation.ValidateAddress(p.Address.Street, p.Address.City, p.Address.ZipCode))
+	}
+	return v.Err()
+}
 
+// saveProfile writes the editable profile fields and returns the stored profile
+func (h *Handler) saveProfile(ctx context.Context, userID string, req *models.UserProfile) (*models.UserProfile, error) {
+	req.Skills = h.normalizeSkills(ctx, req.Skills)
+	profile, err := h.users.SaveProfile(ctx, userID, req)
 	if err != nil {
-		if err.Error() == "no rows in result set" {
-			return nil, fmt.Errorf("profile not found")
-		}
 		return nil, err
 	}
 
-	return &profile, nil
-}
-
-func toJSON(v interface{}) []byte {
-	if v == nil {
-		return nil
+	// A changed address clears the stored coordinates
+	if profile.Address != nil && profile.Latitude == nil {
+		addr := *profile.Address
+		h.work.Go(func(ctx context.Context) { h.geocodeProfile(ctx, userID, addr) })
 	}
-	b, _ := json.Marshal(v)
-	return b
-}
 
-func scanJSON(v interface{}) interface{} {
-	return &jsonScanner{v: v}
+	return profile, nil
 }
 
-type jsonScanner struct {
-	v interface{}
-}
-
-func (s *jsonScanner) Scan(src interface{}) error {
-	if src == nil {
-		return nil
-	}
-
-	var b []byte
-	switch v := src.(type) {
-	case []byte:
-		b = v
-	case string:
-		b = []byte(v)
-	default:
-		return fmt.Errorf("cannot scan type %T into json", src)
-	}
-
-	return json.Unmarshal(b, s.v)
+// uploadKey extracts the storage key from a "/uploads/<key>" URL
+func uploadKey(resumeURL string) string {
+	return path.Base(resumeURL)
 }
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/yourusername/jobapply/internal/storage"
+)
+
+const (
+	healthOK       = "ok"
+	healthDegraded = "degraded" // Working, but an optional dependency is down
+	healthError    = "error"
+)
+
+// ComponentHealth is the state of one dependency in a readiness report
+type ComponentHealth struct {
+	Status  string         `json:"status"`
+	Error   string         `json:"error,omitempty"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+type HealthResponse struct {
+	Status     string                     `json:"status"`
+	Time       string                     `json:"time"`
+	Components map[string]ComponentHealth `json:"components,omitempty"`
+}
+
+type healthCheck struct {
+	name     string
+	required bool
+	check    func(ctx context.Context) error
+}
+
+// AddHealthCheck adds a dependency to the readiness report. A failing required check makes
+// the instance unready; a failing optional one only marks it degraded.
+func (h *Handler) AddHealthCheck(name string, required bool, check func(ctx context.Context) error) {
+	h.healthChecks = append(h.healthChecks, healthCheck{name: name, required: required, check: check})
+}
+
+// Healthz is the liveness probe: it only reports that the process is serving requests, so a
+// database outage doesn't get every instance restarted
+func (h *Handler) Healthz(w http.ResponseWriter, r *http.Request) {
+	h.json(w, HealthResponse{Status: healthOK, Time: time.Now().Format(time.RFC3339)}, http.StatusOK)
+}
+
+// Readyz is the readiness probe. It checks each dependency concurrently and reports them
+// individually; the instance is unready if the database or storage is unreachable.
+func (h *Handler) Readyz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+	defer cancel()
+
+	checks := []healthCheck{{name: "storage", required: true, check: h.pingStorage}}
+	checks = append(checks, h.healthChecks...)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	components := make(map[string]ComponentHealth, len(checks)+2)
+	required := map[string]bool{"database": true}
+	set := func(name string, c ComponentHealth) {
+		mu.Lock()
+		components[name] = c
+		mu.Unlock()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		set("database", h.databaseHealth(ctx))
+	}()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		set("scrapers", h.scraperHealth(ctx))
+	}()
+	for _, c := range checks {
+		required[c.name] = c.required
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.check(ctx); err != nil {
+				set(c.name, ComponentHealth{Status: healthError, Error: err.Error()})
+				return
+			}
+			set(c.name, ComponentHealth{Status: healthOK})
+		}()
+	}
+	wg.Wait()
+
+	resp := HealthResponse{Status: healthOK, Time: time.Now().Format(time.RFC3339), Components: components}
+	for name, c := range components {
+		if c.Status == healthOK {
+			continue
+		}
+		if required[name] {
+			resp.Status = healthError
+		} else if resp.Status == healthOK {
+			resp.Status = healthDegraded
+		}
+	}
+
+	status := http.StatusOK
+	if resp.Status == healthError {
+		status = http.StatusServiceUnavailable
+	}
+	h.json(w, resp, status)
+}
+
+func (h *Handler) databaseHealth(ctx context.Context) ComponentHealth {
+	stats := h.db.Stat()
+	c := ComponentHealth{Status: healthOK, Details: map[string]any{
+		"total_conns":    stats.TotalConns(),
+		"idle_conns":     stats.IdleConns(),
+		"acquired_conns": stats.AcquiredConns(),
+		"max_conns":      stats.MaxConns(),
+	}}
+	if err := h.db.Ping(ctx); err != nil {
+		c.Status = healthError
+		c.Error = err.Error()
+	}
+	return c
+}
+
+func (h *Handler) pingStorage(ctx context.Context) error {
+	if pinger, ok := h.storage.(storage.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// scraperHealth reports when each site last returned jobs. It is informational: a stale
+// site doesn't make the instance unready.
+func (h *Handler) scraperHealth(ctx context.Context) ComponentHealth {
+	rows, err := h.db.Query(ctx, "SELECT site, MAX(cached_at) FROM jobs WHERE cached_at IS NOT NULL GROUP BY site")
+	if err != nil {
+		return ComponentHealth{Status: healthError, Error: err.Error()}
+	}
+	defer rows.Close()
+
+	lastSuccess := map[string]time.Time{}
+	for rows.Next() {
+		var site string
+		var at time.Time
+		if err := rows.Scan(&site, &at); err == nil {
+			lastSuccess[site] = at
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return ComponentHealth{Status: healthError, Error: err.Error()}
+	}
+	return ComponentHealth{Status: healthOK, Details: map[string]any{"last_success": lastSuccess}}
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yourusername/jobapply/internal/logging"
+)
+
+const (
+	idempotencyHeader = "Idempotency-Key"
+	// idempotencyTTL is how long a response is replayed for its key
+	idempotencyTTL = 24 * time.Hour
+	// idempotencyAbandoned is how long a key stays claimed by a request that never finished, e.g.
+	// because the server crashed, before a retry may run it again
+	idempotencyAbandoned = 10 * time.Minute
+	// Responses larger than this aren't kept; a retry runs the request again
+	maxIdempotentResponse = 1 << 20
+	maxIdempotencyKeyLen  = 255
+)
+
+// Idempotent lets clients retry a POST safely by sending an Idempotency-Key header. The first
+// request with a key runs normally and its response is stored; repeats within 24 hours get that
+// response back, marked with Idempotent-Replayed: true, without running the handler again. A
+// key reused with a different body gets a 422, and one whose first request is still running a
+// 409. Server errors aren't stored, so those can be retried. It must run after AuthMiddleware,
+// since keys are scoped to the user.
+func (h *Handler) Idempotent(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyHeader)
+		userID := getUserIDFromContext(r.Context())
+		if key == "" || userID == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLen {
+			h.error(w, fmt.Sprintf("%s must be at most %d characters", idempotencyHeader, maxIdempotencyKeyLen), http.StatusBadRequest)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			h.error(w, "Failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		sum := sha256.Sum256([]byte(r.Method + " " + r.URL.Path + "\n" + string(body)))
+		fingerprint := sum[:]
+
+		claimed, err := h.claimIdempotencyKey(r.Context(), userID, key, fingerprint)
+		if err != nil {
+			h.internalError(w, r, "Failed to check idempotency key", err)
+			return
+		}
+		if !claimed {
+			h.replayIdempotent(w, r, userID, key, fingerprint)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		completed := false
+		defer func() {
+			// Also runs when the handler panics, so the key isn't left claimed
+			ctx := context.WithoutCancel(r.Context())
+			var err error
+			if completed && rec.status < http.StatusInternalServerError && !rec.overflow {
+				_, err = h.db.Exec(ctx, `
+					UPDATE idempotency_keys SET status = $1, content_type = $2, body = $3
+					WHERE user_id = $4 AND key = $5
+				`, rec.status, rec.Header().Get("Content-Type"), rec.body.Bytes(), userID, key)
+			} else {
+				_, err = h.db.Exec(ctx, "DELETE FROM idempotency_keys WHERE user_id = $1 AND key = $2", userID, key)
+			}
+			if err != nil {
+				logging.FromContext(ctx).Error("Failed to record idempotent response", "error", err)
+			}
+		}()
+		next.ServeHTTP(rec, r)
+		completed = true
+	})
+}
+
+// claimIdempotencyKey records the key as in progress, reporting false if an unexpired request
+// already holds it
+func (h *Handler) claimIdempotencyKey(ctx context.Context, userID, key string, fingerprint []byte) (bool, error) {
+	var claimed bool
+	err := h.db.QueryRow(ctx, `
+		INSERT INTO idempotency_keys (user_id, key, fingerprint)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, key) DO UPDATE
+		SET fingerprint = EXCLUDED.fingerprint, status = NULL, content_type = NULL, body = NULL, created_at = NOW()
+		WHERE idempotency_keys.created_at < $4
+			OR (idempotency_keys.status IS NULL AND idempotency_keys.created_at < $5)
+		RETURNING TRUE
+	`, userID, key, fingerprint, time.Now().Add(-idempotencyTTL), time.Now().Add(-idempotencyAbandoned)).Scan(&claimed)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	return claimed, err
+}
+
+// replayIdempotent answers a repeated request from the stored response
+func (h *Handler) replayIdempotent(w http.ResponseWriter, r *http.Request, userID, key string, fingerprint []byte) {
+	var stored []byte
+	var status *int
+	var contentType *string
+	var body []byte
+	err := h.db.QueryRow(r.Context(),
+		"SELECT fingerprint, status, content_type, body FROM idempotency_keys WHERE user_id = $1 AND key = $2",
+		userID, key).Scan(&stored, &status, &contentType, &body)
+	if err != nil {
+		// Deleted since the claim failed, because the original request failed; let the client retry
+		h.error(w, "A request with this Idempotency-Key just failed; retry it", http.StatusConflict)
+		return
+	}
+	switch {
+	case !bytes.Equal(stored, fingerprint):
+		h.error(w, "Idempotency-Key was already used for a different request", http.StatusUnprocessableEntity)
+	case status == nil:
+		h.error(w, "A request with this Idempotency-Key is still in progress", http.StatusConflict)
+	default:
+		if contentType != nil && *contentType != "" {
+			w.Header().Set("Content-Type", *contentType)
+		}
+		w.Header().Set("Idempotent-Replayed", "true")
+		w.WriteHeader(*status)
+		w.Write(body)
+	}
+}
+
+// responseRecorder passes a response through while keeping a copy of it, up to
+// maxIdempotentResponse bytes
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+	overflow    bool
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.status = status
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(p []byte) (int, error) {
+	rec.wroteHeader = true
+	if !rec.overflow {
+		if rec.body.Len()+len(p) > maxIdempotentResponse {
+			rec.overflow = true
+			rec.body.Reset()
+		} else {
+			rec.body.Write(p)
+		}
+	}
+	return rec.ResponseWriter.Write(p)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (rec *responseRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// CleanIdempotencyKeys deletes stored responses whose keys have expired. It is run by the
+// scheduler.
+func (h *Handler) CleanIdempotencyKeys(ctx context.Context) error {
+	result, err := h.db.Exec(ctx, "DELETE FROM idempotency_keys WHERE created_at < $1", time.Now().Add(-idempotencyTTL))
+	if err != nil {
+		return fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+	logging.FromContext(ctx).Info("Idempotency keys cleaned", "keys_deleted", result.RowsAffected())
+	return nil
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/yourusername/jobapply/internal/audit"
+	"github.com/yourusername/jobapply/internal/logging"
+)
+
+const (
+	defaultImpersonationTTL = time.Hour
+	maxImpersonationTTL     = 8 * time.Hour
+)
+
+// impersonation is what an impersonation token carries besides the user it acts as
+type impersonation struct {
+	AdminID     string // The admin who issued it
+	AllowWrites bool   // Otherwise only GET, HEAD and OPTIONS requests are allowed
+}
+
+type ImpersonateRequest struct {
+	// Reason is kept in the audit log, e.g. a support ticket
+	Reason      string `json:"reason"`
+	AllowWrites bool   `json:"allow_writes"`
+}
+
+type ImpersonateResponse struct {
+	Token       string    `json:"token"`
+	UserID      string    `json:"user_id"`
+	AllowWrites bool      `json:"allow_writes"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// SetImpersonationTTL sets how long impersonation tokens last, capped at 8 hours
+func (h *Handler) SetImpersonationTTL(ttl time.Duration) {
+	h.impersonationTTL = min(ttl, maxImpersonationTTL)
+}
+
+// Impersonate issues an admin a short-lived bearer token that acts as another user, so they
+// can see what the user sees. It is read-only unless allow_writes is set, can't reach the
+// admin or account settings endpoints, and every request made with it, reads and rejected
+// writes included, is recorded in the audit log against the admin.
+func (h *Handler) Impersonate(w http.ResponseWriter, r *http.Request) {
+	adminID := getUserIDFromContext(r.Context())
+	if adminID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userID := chi.URLParam(r, "user_id")
+	if !h.validateUUID(w, userID, "user ID") {
+		return
+	}
+	if userID == adminID {
+		h.error(w, "You can't impersonate yourself", http.StatusBadRequest)
+		return
+	}
+
+	var req ImpersonateRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		h.error(w, "A reason is required", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.users.UserByID(r.Context(), userID)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+	if user.IsAdmin {
+		h.error(w, "Admins can't be impersonated", http.StatusForbidden)
+		return
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(h.impersonationTTL)
+	claims := jwt.MapClaims{
+		"user_id":         userID,
+		"email":           user.Email,
+		"impersonator_id": adminID,
+		"allow_writes":    req.AllowWrites,
+		"exp":             expiresAt.Unix(),
+		"iat":             now.Unix(),
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(jwtSecret))
+	if err != nil {
+		h.internalError(w, r, "Failed to issue token", err)
+		return
+	}
+
+	audit.SetSubject(r.Context(), userID)
+	audit.Record(r.Context(), "impersonation", userID, nil, map[string]any{
+		"reason":       req.Reason,
+		"allow_writes": req.AllowWrites,
+		"expires_at":   expiresAt,
+	})
+	logging.FromContext(r.Context()).Info("Impersonation token issued",
+		"target_user_id", userID, "allow_writes", req.AllowWrites, "expires_at", expiresAt)
+
+	h.json(w, ImpersonateResponse{
+		Token:       token,
+		UserID:      userID,
+		AllowWrites: req.AllowWrites,
+		ExpiresAt:   expiresAt,
+	}, http.StatusCreated)
+}
+
+// NoImpersonation keeps impersonation sessions away from account settings: credentials,
+// extension keys, sharing and deleting the account stay with the user
+func (h *Handler) NoImpersonation(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if getImpersonationFromContext(r.Context()) != nil {
+			h.error(w, "Not available while impersonating", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// BlockImpersonatedWrites rejects requests that can change state when they were made with a
+// read-only impersonation token. It must run after Audit, so rejected attempts are logged
+// too. The GraphQL endpoint isn't behind it: its queries are POSTs as well, so it rejects
+// mutations itself.
+func (h *Handler) BlockImpersonatedWrites(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !safeMethod(r.Method) && ReadOnlyImpersonation(r.Context()) {
+			h.error(w, "This impersonation session is read-only", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
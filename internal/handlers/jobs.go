@@ -0,0 +1,703 @@
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/yourusername/jobapply/internal/apierror"
+	"github.com/yourusername/jobapply/internal/geo"
+	"github.com/yourusername/jobapply/internal/logging"
+	"github.com/yourusername/jobapply/internal/matching"
+	"github.com/yourusername/jobapply/internal/models"
+	"github.com/yourusername/jobapply/internal/salary"
+	"github.com/yourusername/jobapply/internal/scrapers"
+	"github.com/yourusername/jobapply/internal/store"
+)
+
+const (
+	defaultJobsLimit = 50
+	maxJobsLimit     = 100
+	// sort=match scores in memory, so only the newest jobs are considered
+	maxScoredJobs = 500
+)
+
+type JobsResponse struct {
+	Jobs       []models.JobListing `json:"jobs"`
+	Total      int                 `json:"total"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
+
+// sqlConditions accumulates WHERE clauses with positional arguments
+type sqlConditions struct {
+	where []string
+	args  []interface{}
+}
+
+// arg adds a query argument and returns its placeholder
+func (c *sqlConditions) arg(v interface{}) string {
+	c.args = append(c.args, v)
+	return fmt.Sprintf("$%d", len(c.args))
+}
+
+func (c *sqlConditions) add(clause string) {
+	c.where = append(c.where, clause)
+}
+
+func (c *sqlConditions) sql() string {
+	if len(c.where) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(c.where, " AND ")
+}
+
+// GetJobs lists scraped jobs, newest first, with optional filters:
+//
+//	q             full-text search over title, company and description
+//	location      substring match on the job location
+//	site          source site (e.g. "muse")
+//	status        "open" or "closed" (postings the expiry checker found removed)
+//	tag           only jobs with this tag; repeat to require several
+//	posted_since  YYYY-MM-DD, RFC3339, or a day count like "7d"
+//	remote        true/false
+//	salary_min    jobs whose annual USD salary range reaches at least this much (alias min_salary)
+//	salary_max    jobs whose annual USD salary range starts at or below this much
+//	within_miles  distance from the user's geocoded address, or from lat/lng when given;
+//	              include_remote=true keeps remote jobs in the results
+//	radius_km     the same in kilometres
+//	near          with a distance, "me" (the default) or a place name like "Chicago", so
+//	              suburbs are found that a location match would miss
+//	hide_ghosts   true hides likely ghost jobs and agency reposts (ghost_score of 60 or more)
+//	max_ghost_score  hides jobs scoring above this instead
+//	commute_mode  "driving" (default) or "transit", for the commute fields
+//	max_commute_minutes  only jobs whose estimated commute is at most this; include_remote=true
+//	              keeps remote jobs
+//	sort          "recent" (default), "match" for best profile fit first, "salary" for highest pay
+//	              first, or "commute" for the shortest commute first
+//	min_score     with any sort, drop jobs scoring below this
+//	exclude_applied  true hides jobs the user already applied to
+//	include_description  true adds each job's scraped description
+//	limit, cursor pagination; pass next_cursor from the previous page
+func (h *Handler) GetJobs(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	q := r.URL.Query()
+	var conds sqlConditions
+
+	sortBy := q.Get("sort")
+	switch sortBy {
+	case "":
+		sortBy = "recent"
+	case "recent", "match", "salary", "commute":
+	default:
+		h.error(w, "sort must be recent, match, salary or commute", http.StatusBadRequest)
+		return
+	}
+
+	minScore := 0
+	if v := q.Get("min_score"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || n > 100 {
+			h.error(w, "min_score must be between 0 and 100", http.StatusBadRequest)
+			return
+		}
+		minScore = n
+	}
+
+	// Scores need the profile; without one jobs are listed unscored
+	profile, err := h.users.Profile(r.Context(), userID)
+	if err != nil {
+		profile = nil
+	}
+	if profile == nil && (sortBy == "match" || minScore > 0) {
+		h.error(w, "Complete your profile to rank jobs by match", http.StatusBadRequest)
+		return
+	}
+
+	// Only jobs found by the user's own searches, minus the ones they dismissed or blocked
+	user := conds.arg(userID)
+	conds.add("deleted_at IS NULL")
+	conds.add("EXISTS (SELECT 1 FROM user_jobs uj WHERE uj.job_id = jobs.id AND uj.user_id = " + user + ")")
+	conds.add("NOT EXISTS (SELECT 1 FROM job_dismissals d WHERE d.job_url = jobs.url AND d.user_id = " + user + ")")
+	conds.add("NOT " + blockedJobSQL(user))
+	if q.Get("exclude_applied") == "true" {
+		conds.add("NOT EXISTS (SELECT 1 FROM applications a WHERE a.job_id = jobs.id AND a.user_id = " + user + " AND a.deleted_at IS NULL)")
+	}
+
+	if search := strings.TrimSpace(q.Get("q")); search != "" {
+		conds.add("search_vector @@ websearch_to_tsquery('english', " + conds.arg(search) + ")")
+	}
+
+	if location := strings.TrimSpace(q.Get("location")); location != "" {
+		conds.add("location ILIKE " + conds.arg("%"+escapeLike(location)+"%"))
+	}
+
+	for _, tag := range q["tag"] {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			conds.add(store.JobTagFilterSQL(user, conds.arg(tag)))
+		}
+	}
+
+	switch status := q.Get("status"); status {
+	case "":
+	case "open", "closed":
+		conds.add("status = " + conds.arg(status))
+	default:
+		h.error(w, "status must be open or closed", http.StatusBadRequest)
+		return
+	}
+
+	if site := strings.TrimSpace(q.Get("site")); site != "" {
+		conds.add("site = " + conds.arg(site))
+	}
+
+	if since := q.Get("posted_since"); since != "" {
+		t, err := parseSince(since)
+		if err != nil {
+			h.error(w, "posted_since must be YYYY-MM-DD, RFC3339, or a number of days like 7d", http.StatusBadRequest)
+			return
+		}
+		// Jobs without a posting date fall back to when we first saw them
+		conds.add("COALESCE(posted_date, scraped_at) >= " + conds.arg(t))
+	}
+
+	remoteClause := "(location ILIKE '%remote%' OR location ILIKE '%anywhere%')"
+	switch q.Get("remote") {
+	case "":
+	case "true":
+		conds.add(remoteClause)
+	case "false":
+		conds.add("NOT COALESCE(" + remoteClause + ", FALSE)")
+	default:
+		h.error(w, "remote must be true or false", http.StatusBadRequest)
+		return
+	}
+
+	for _, param := range []string{"salary_min", "min_salary", "salary_max"} {
+		value := q.Get(param)
+		if value == "" {
+			continue
+		}
+		amount, err := strconv.Atoi(value)
+		if err != nil || amount < 0 {
+			h.error(w, param+" must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		if param != "salary_max" {
+			conds.add("COALESCE(salary_max, salary_min) >= " + conds.arg(amount))
+		} else {
+			conds.add("COALESCE(salary_min, salary_max) <= " + conds.arg(amount))
+		}
+	}
+
+	distanceSQL := "NULL::double precision"
+	within, radiusKm := q.Get("within_miles"), q.Get("radius_km")
+	if within != "" && radiusKm != "" {
+		h.error(w, "Pass within_miles or radius_km, not both", http.StatusBadRequest)
+		return
+	}
+	if within != "" || radiusKm != "" {
+		var radius float64
+		if within != "" {
+			radius, err = strconv.ParseFloat(within, 64)
+			if err != nil || radius <= 0 || radius > 500 {
+				h.error(w, "within_miles must be between 0 and 500", http.StatusBadRequest)
+				return
+			}
+		} else {
+			km, err := strconv.ParseFloat(radiusKm, 64)
+			if err != nil || km <= 0 || km > 800 {
+				h.error(w, "radius_km must be between 0 and 800", http.StatusBadRequest)
+				return
+			}
+			radius = km / geo.KmPerMile
+		}
+
+		origin, err := h.searchOrigin(r.Context(), q.Get("near"), q.Get("lat"), q.Get("lng"), profile)
+		if err != nil {
+			h.fail(w, r, apierror.From(err))
+			return
+		}
+
+		lat, lng := conds.arg(origin.Lat), conds.arg(origin.Lng)
+		distanceSQL = haversineSQL(lat, lng, "latitude", "longitude")
+
+		minLat, maxLat, minLng, maxLng := geo.BoundingBox(origin, radius)
+		nearby := fmt.Sprintf("(latitude BETWEEN %s AND %s AND longitude BETWEEN %s AND %s AND %s <= %s)",
+			conds.arg(minLat), conds.arg(maxLat), conds.arg(minLng), conds.arg(maxLng), distanceSQL, conds.arg(radius))
+		if q.Get("include_remote") == "true" {
+			nearby = "(" + nearby + " OR " + remoteClause + ")"
+		}
+		conds.add(nearby)
+	}
+
+	// Commutes are estimated in the background, so jobs not yet routed have none
+	commuteMode := q.Get("commute_mode")
+	if commuteMode == "" {
+		commuteMode = geo.ModeDriving
+	} else if commuteMode != geo.ModeDriving && commuteMode != geo.ModeTransit {
+		h.error(w, "commute_mode must be driving or transit", http.StatusBadRequest)
+		return
+	}
+	// The mode is inlined, being one of two constants, since an unused placeholder would break
+	// the count query
+	commute := commuteSQL(user, "'"+commuteMode+"'")
+	if v := q.Get("max_commute_minutes"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			h.error(w, "max_commute_minutes must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		within := commute + " <= " + conds.arg(n)
+		if q.Get("include_remote") == "true" {
+			within = "(" + within + " OR " + remoteClause + ")"
+		}
+		conds.add(within)
+	}
+
+	// Unscored jobs count as 0 until the ghost_scoring task reaches them
+	switch v := q.Get("max_ghost_score"); {
+	case v != "":
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || n > 100 {
+			h.error(w, "max_ghost_score must be between 0 and 100", http.StatusBadRequest)
+			return
+		}
+		conds.add("COALESCE(ghost_score, 0) <= " + conds.arg(n))
+	case q.Get("hide_ghosts") == "true":
+		conds.add(fmt.Sprintf("COALESCE(ghost_score, 0) < %d", matching.GhostThreshold))
+	}
+
+	includeDescription := q.Get("include_description") == "true"
+
+	limit := defaultJobsLimit
+	if l := q.Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 || n > maxJobsLimit {
+			h.error(w, fmt.Sprintf("limit must be between 1 and %d", maxJobsLimit), http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	// Total ignores the cursor so it stays the same across pages. With min_score it is
+	// only known after scoring, so it is recomputed below.
+	var total int
+	if err := h.db.QueryRow(r.Context(), "SELECT COUNT(*) FROM jobs "+conds.sql(), conds.args...).Scan(&total); err != nil {
+		h.internalError(w, r, "Failed to get jobs", err)
+		return
+	}
+
+	// Ranked and salary-sorted results are paged by offset since scores aren't stored
+	// and many jobs share the same salary
+	offset := 0
+	if cursor := q.Get("cursor"); cursor != "" {
+		var err error
+		if sortBy != "recent" {
+			offset, err = decodeOffsetCursor(cursor)
+		} else {
+			var scrapedAt time.Time
+			var id string
+			scrapedAt, id, err = decodeJobCursor(cursor)
+			conds.add(fmt.Sprintf("(scraped_at, id) < (%s, %s)", conds.arg(scrapedAt), conds.arg(id)))
+		}
+		if err != nil {
+			h.error(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Scored results are filtered and sorted in memory over a window of the newest jobs;
+	// otherwise one extra row is fetched to know whether another page exists
+	scoreInMemory := sortBy == "match" || minScore > 0
+	fetch, skip := limit+1, 0
+	if scoreInMemory {
+		fetch = maxScoredJobs
+	} else if sortBy == "salary" || sortBy == "commute" {
+		skip = offset
+	}
+
+	orderBy := "scraped_at DESC, id DESC"
+	if sortBy == "salary" {
+		orderBy = "COALESCE(salary_max, salary_min) DESC NULLS LAST, " + orderBy
+	} else if sortBy == "commute" {
+		orderBy = commute + " ASC NULLS LAST, " + orderBy
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, site, title, company, company_id, location, url, posted_date, salary_min, salary_max, salary_text, scraped_at, status, %s,
+			description, latitude, longitude, %s, %s, ghost_score, COALESCE(ghost_reasons, '{}')
+		FROM jobs
+		%s
+		ORDER BY %s
+		LIMIT %d OFFSET %d
+	`, distanceSQL, store.JobTagsSQL(user), commute, conds.sql(), orderBy, fetch, skip)
+
+	rows, err := h.db.Query(r.Context(), query, conds.args...)
+	if err != nil {
+		h.internalError(w, r, "Failed to get jobs", err)
+		return
+	}
+	defer rows.Close()
+
+	resp := JobsResponse{Jobs: []models.JobListing{}, Total: total}
+	descriptions := map[string]string{}
+	for rows.Next() {
+		var job models.JobListing
+		var location, description, salaryText *string
+		var lat, lng *float64
+		if err := rows.Scan(&job.ID, &job.Site, &job.Title, &job.Company, &job.CompanyID, &location, &job.URL,
+			&job.PostedDate, &job.SalaryMin, &job.SalaryMax, &salaryText, &job.ScrapedAt, &job.Status, &job.DistanceMiles,
+			&description, &lat, &lng, &job.Tags, &job.CommuteMinutes, &job.GhostScore, &job.GhostReasons); err != nil {
+			continue
+		}
+		if location != nil {
+			job.Location = *location
+		}
+		if salaryText != nil {
+			job.SalaryText = *salaryText
+		}
+		if includeDescription && description != nil {
+			descriptions[job.ID] = *description
+		}
+		if job.DistanceMiles != nil {
+			rounded := math.Round(*job.DistanceMiles*10) / 10
+			job.DistanceMiles = &rounded
+		}
+
+		if profile != nil {
+			features := matching.JobFeatures{
+				Title:         job.Title,
+				Location:      job.Location,
+				Remote:        geo.IsRemote(job.Location),
+				DistanceMiles: job.DistanceMiles,
+				SalaryMin:     job.SalaryMin,
+				SalaryMax:     job.SalaryMax,
+			}
+			if description != nil {
+				features.Description = *description
+			}
+			if features.DistanceMiles == nil && lat != nil && lng != nil && profile.Latitude != nil && profile.Longitude != nil {
+				d := geo.DistanceMiles(geo.Point{Lat: *profile.Latitude, Lng: *profile.Longitude}, geo.Point{Lat: *lat, Lng: *lng})
+				features.DistanceMiles = &d
+			}
+			score := matching.ScoreJob(features, profile).Total
+			if score < minScore {
+				continue
+			}
+			job.Score = &score
+		}
+
+		resp.Jobs = append(resp.Jobs, job)
+	}
+
+	if minScore > 0 {
+		// Only the fetched window was scored, so this is exact for the first page
+		// and a lower bound once paging with min_score
+		resp.Total = len(resp.Jobs)
+	}
+
+	switch {
+	case scoreInMemory && sortBy != "recent":
+		if sortBy == "match" {
+			sort.SliceStable(resp.Jobs, func(i, j int) bool { return *resp.Jobs[i].Score > *resp.Jobs[j].Score })
+		}
+		resp.Jobs = resp.Jobs[min(offset, len(resp.Jobs)):]
+		if len(resp.Jobs) > limit {
+			resp.Jobs = resp.Jobs[:limit]
+			resp.NextCursor = encodeOffsetCursor(offset + limit)
+		}
+	case sortBy == "salary" || sortBy == "commute":
+		if len(resp.Jobs) > limit {
+			resp.Jobs = resp.Jobs[:limit]
+			resp.NextCursor = encodeOffsetCursor(offset + limit)
+		}
+	case len(resp.Jobs) > limit:
+		// Extra rows were fetched to know whether another page exists
+		resp.Jobs = resp.Jobs[:limit]
+		last := resp.Jobs[limit-1]
+		resp.NextCursor = encodeJobCursor(last.ScrapedAt, last.ID)
+	}
+
+	if !includeDescription {
+		h.json(w, resp, http.StatusOK)
+		return
+	}
+
+	// Descriptions can make a page several megabytes, so it is encoded one job at a time
+	// rather than all at once
+	suffix := "}"
+	if resp.NextCursor != "" {
+		suffix = fmt.Sprintf(`,"next_cursor":%q}`, resp.NextCursor)
+	}
+	list := newJSONStream(w, fmt.Sprintf(`{"total":%d,"jobs":`, resp.Total), suffix)
+	for _, job := range resp.Jobs {
+		detail := models.JobDetail{JobListing: job}
+		setDescription(&detail, descriptions[job.ID])
+		if err := list.add(detail); err != nil {
+			return
+		}
+	}
+	list.close()
+}
+
+// GetRecommendedJobs is GET /jobs with sort=match and a smaller default page, hiding jobs
+// the user has already applied to
+func (h *Handler) GetRecommendedJobs(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	q.Set("sort", "match")
+	if q.Get("limit") == "" {
+		q.Set("limit", "20")
+	}
+	q.Set("exclude_applied", "true")
+	r.URL.RawQuery = q.Encode()
+	h.GetJobs(w, r)
+}
+
+// GetJob returns a job's full details. Descriptions missing from the scrape are fetched from
+// the posting page on first view and cached, so list views never wait on external pages.
+func (h *Handler) GetJob(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	jobID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, jobID, "job ID") {
+		return
+	}
+
+	job, err := h.jobs.Job(r.Context(), userID, jobID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			h.error(w, "Job not found", http.StatusNotFound)
+			return
+		}
+		h.internalError(w, r, "Failed to get job", err)
+		return
+	}
+
+	if strings.TrimSpace(job.Description) != "" {
+		setDescription(job, job.Description)
+		job.DescriptionSource = "scraped"
+	} else {
+		ctx, cancel := context.WithTimeout(r.Context(), 20*time.Second)
+		defer cancel()
+
+		fetched, err := scrapers.NewDescriptionFetcher().Fetch(ctx, job.URL)
+		if err != nil {
+			logging.FromContext(r.Context()).Warn("Failed to fetch job description", "job_id", jobID, "error", err)
+		} else {
+			setDescription(job, fetched)
+			job.DescriptionSource = "fetched"
+
+			// The full posting often states the pay the listing left out
+			var pay store.Salary
+			if job.SalaryMin == nil && job.SalaryMax == nil {
+				pay = parseSalary(salary.Extract(fetched))
+				job.SalaryMin, job.SalaryMax = pay.Min, pay.Max
+				if pay.Text != nil {
+					job.SalaryText = *pay.Text
+				}
+			}
+
+			if err := h.jobs.CacheDescription(r.Context(), jobID, fetched, pay); err != nil {
+				logging.FromContext(r.Context()).Error("Failed to cache job description", "job_id", jobID, "error", err)
+			}
+		}
+	}
+
+	if profile, err := h.users.Profile(r.Context(), userID); err == nil {
+		score := matching.ScoreJob(matching.JobFeatures{
+			Title:       job.Title,
+			Description: job.Description,
+			Location:    job.Location,
+			Remote:      geo.IsRemote(job.Location),
+			SalaryMin:   job.SalaryMin,
+			SalaryMax:   job.SalaryMax,
+		}, profile).Total
+		job.Score = &score
+	}
+
+	h.json(w, *job, http.StatusOK)
+}
+
+// setDescription fills in a job's description and its Markdown form. Descriptions are
+// sanitized when scraped, but rows stored before that are sanitized here too.
+func setDescription(job *models.JobDetail, description string) {
+	job.Description = scrapers.SanitizeHTML(description)
+	if job.Description != "" {
+		job.DescriptionMarkdown = scrapers.DescriptionMarkdown(job.Description)
+	}
+}
+
+// MatchJob compares the authenticated user's profile against a job description and
+// reports matched/missing keywords with an overall match score
+func (h *Handler) MatchJob(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	jobID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, jobID, "job ID") {
+		return
+	}
+
+	title, description, err := h.jobs.JobText(r.Context(), jobID)
+	if err != nil {
+		h.error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+
+	// ?profile_id= matches against a persona's resume and skills; defaults to the default persona
+	personaID := r.URL.Query().Get("profile_id")
+	if personaID != "" && !h.validateUUID(w, personaID, "profile ID") {
+		return
+	}
+
+	profile, _, err := h.profileForPersona(r.Context(), userID, personaID)
+	if err != nil {
+		h.error(w, "Profile not found", http.StatusNotFound)
+		return
+	}
+
+	// Title alone still carries signal ("Senior Go Engineer") when no description was scraped
+	jobText := title
+	if description != "" {
+		jobText += "\n" + matching.PlainText(description)
+	}
+
+	// Profiles saved before skill normalization may still hold aliases like "js"
+	profile.Skills = h.normalizeSkills(r.Context(), profile.Skills)
+
+	result := matching.KeywordGap(jobText, profile)
+	result.JobID = jobID
+
+	h.json(w, result, http.StatusOK)
+}
+
+// DismissJob hides a job from the user's list for good, including future re-scrapes of the same posting
+func (h *Handler) DismissJob(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	jobID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, jobID, "job ID") {
+		return
+	}
+
+	if err := h.jobs.Dismiss(r.Context(), userID, jobID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			h.error(w, "Job not found", http.StatusNotFound)
+			return
+		}
+		h.internalError(w, r, "Failed to dismiss job", err)
+		return
+	}
+
+	h.json(w, map[string]string{"message": "Job dismissed"}, http.StatusOK)
+}
+
+// UndismissJob brings a dismissed job back into the user's list
+func (h *Handler) UndismissJob(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	jobID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, jobID, "job ID") {
+		return
+	}
+
+	if err := h.jobs.Undismiss(r.Context(), userID, jobID); err != nil {
+		h.internalError(w, r, "Failed to restore job", err)
+		return
+	}
+
+	h.json(w, map[string]string{"message": "Job restored"}, http.StatusOK)
+}
+
+func encodeJobCursor(scrapedAt time.Time, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(scrapedAt.Format(time.RFC3339Nano) + "|" + id))
+}
+
+// Offset cursors are prefixed so they can't be confused with keyset cursors
+func encodeOffsetCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("offset|" + strconv.Itoa(offset)))
+}
+
+func decodeOffsetCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := strings.CutPrefix(string(raw), "offset|")
+	if !ok {
+		return 0, fmt.Errorf("malformed cursor")
+	}
+	offset, err := strconv.Atoi(n)
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("malformed cursor")
+	}
+	return offset, nil
+}
+
+func decodeJobCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return time.Time{}, "", fmt.Errorf("malformed cursor")
+	}
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return time.Time{}, "", err
+	}
+	return t, id, nil
+}
+
+// parseSince accepts a date, a timestamp, or a relative day count ("7d")
+func parseSince(s string) (time.Time, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return time.Time{}, fmt.Errorf("invalid day count")
+		}
+		return time.Now().AddDate(0, 0, -n), nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// escapeLike escapes LIKE wildcards so user input only matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/yourusername/jobapply/internal/events"
+	"github.com/yourusername/jobapply/internal/logging"
+	"github.com/yourusername/jobapply/internal/models"
+	"github.com/yourusername/jobapply/internal/store"
+)
+
+const (
+	defaultNotificationsLimit = 50
+	maxNotificationsLimit     = 200
+	// readNotificationRetention is how long read notifications are kept
+	readNotificationRetention = 90 * 24 * time.Hour
+)
+
+// NotificationsResponse is a page of the inbox with the counts after listing it
+type NotificationsResponse struct {
+	Notifications []models.Notification `json:"notifications"`
+	Unread        int                   `json:"unread"`
+	Unseen        int                   `json:"unseen"`
+}
+
+// NotificationCounts is how many notifications are unread, and how many are new since the
+// inbox was last listed, e.g. for a badge
+type NotificationCounts struct {
+	Unread int `json:"unread"`
+	Unseen int `json:"unseen"`
+}
+
+// GetNotifications lists the inbox, newest first, marking the listed notifications seen.
+// ?unread=true leaves out read ones; ?before=<created_at> pages back.
+func (h *Handler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	q := r.URL.Query()
+	query := store.NotificationQuery{UnreadOnly: q.Get("unread") == "true", Limit: defaultNotificationsLimit}
+	if l := q.Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 || n > maxNotificationsLimit {
+			h.error(w, fmt.Sprintf("limit must be between 1 and %d", maxNotificationsLimit), http.StatusBadRequest)
+			return
+		}
+		query.Limit = n
+	}
+	if b := q.Get("before"); b != "" {
+		before, err := time.Parse(time.RFC3339Nano, b)
+		if err != nil {
+			h.error(w, "before must be an RFC 3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		query.Before = &before
+	}
+
+	notifications, err := h.notifications.Notifications(r.Context(), userID, query)
+	if err != nil {
+		h.internalError(w, r, "Failed to get notifications", err)
+		return
+	}
+	counts, err := h.notifications.NotificationCounts(r.Context(), userID)
+	if err != nil {
+		h.internalError(w, r, "Failed to get notifications", err)
+		return
+	}
+	if notifications == nil {
+		notifications = []models.Notification{}
+	}
+	h.json(w, NotificationsResponse{Notifications: notifications, Unread: counts.Unread, Unseen: counts.Unseen}, http.StatusOK)
+}
+
+// GetNotificationCounts returns the unread and unseen counts without marking anything seen
+func (h *Handler) GetNotificationCounts(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	counts, err := h.notifications.NotificationCounts(r.Context(), userID)
+	if err != nil {
+		h.internalError(w, r, "Failed to count notifications", err)
+		return
+	}
+	h.json(w, NotificationCounts{Unread: counts.Unread, Unseen: counts.Unseen}, http.StatusOK)
+}
+
+// MarkNotificationRead marks one notification read
+func (h *Handler) MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	notificationID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, notificationID, "notification ID") {
+		return
+	}
+	err := h.notifications.MarkRead(r.Context(), userID, notificationID)
+	if errors.Is(err, store.ErrNotFound) {
+		h.error(w, "Notification not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.internalError(w, r, "Failed to mark notification read", err)
+		return
+	}
+	h.json(w, map[string]string{"message": "Notification marked read"}, http.StatusOK)
+}
+
+// MarkAllNotificationsRead empties the unread count
+func (h *Handler) MarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	marked, err := h.notifications.MarkAllRead(r.Context(), userID)
+	if err != nil {
+		h.internalError(w, r, "Failed to mark notifications read", err)
+		return
+	}
+	h.json(w, map[string]int{"marked": marked}, http.StatusOK)
+}
+
+// notifyEvent is the inbox's event subscriber: it turns paused applications, scrapes finding
+// new jobs and failing job sources into notifications. A failing source is only reported once
+// a day, however often the user retries.
+func (h *Handler) notifyEvent(ctx context.Context, e events.Event) error {
+	var n store.NewNotification
+	switch e.Type {
+	case events.ApplicationStatusChanged:
+		var data events.ApplicationStatusChangedData
+		if err := e.Decode(&data); err != nil {
+			return err
+		}
+		if data.To != string(models.ApplicationPaused) {
+			return nil
+		}
+		n = store.NewNotification{
+			Kind:      models.NotificationApplicationPaused,
+			Title:     "Your application to " + data.Company + " needs answers",
+			Body:      data.Title + " asked questions only you can answer; the application continues once they are answered.",
+			Link:      "/api/v1/applications/" + data.ApplicationID + "/questions",
+			DedupeKey: "event:" + e.ID,
+		}
+	case events.ScrapeCompleted:
+		var data events.ScrapeCompletedData
+		if err := e.Decode(&data); err != nil {
+			return err
+		}
+		if data.Inserted == 0 {
+			return nil
+		}
+		noun := "jobs"
+		if data.Inserted == 1 {
+			noun = "job"
+		}
+		n = store.NewNotification{
+			Kind:      models.NotificationNewJobs,
+			Title:     fmt.Sprintf("%d new %s for %q", data.Inserted, noun, data.Keywords),
+			Body:      "Found on " + data.Site + " for " + data.Location + ".",
+			Link:      "/api/v1/jobs?" + url.Values{"q": {data.Keywords}, "sort": {"recent"}}.Encode(),
+			DedupeKey: "event:" + e.ID,
+		}
+	case events.ScrapeFailed:
+		var data events.ScrapeFailedData
+		if err := e.Decode(&data); err != nil {
+			return err
+		}
+		n = store.NewNotification{
+			Kind:      models.NotificationScrapeFailed,
+			Title:     "Couldn't search " + data.Site,
+			Body:      fmt.Sprintf("The search for %q in %s failed; jobs already found are still listed. Try again later.", data.Keywords, data.Location),
+			DedupeKey: "scrape_failed:" + data.Site + ":" + e.Time.Format(time.DateOnly),
+		}
+	default:
+		return nil
+	}
+	_, err := h.notifications.Notify(ctx, e.UserID, n)
+	return err
+}
+
+// SendReminders adds reminders for applications paused more than a day and saved jobs not
+// applied to within a week, once each, and deletes notifications read long ago. It is run by
+// the scheduler.
+func (h *Handler) SendReminders(ctx context.Context) error {
+	reminders := 0
+
+	rows, err := h.db.Query(ctx, `
+		SELECT a.user_id, a.id, j.title, j.company, e.id
+		FROM applications a
+		JOIN jobs j ON j.id = a.job_id
+		JOIN LATERAL (
+			SELECT id, created_at FROM application_events WHERE application_id = a.id ORDER BY created_at DESC LIMIT 1
+		) e ON true
+		WHERE a.status = 'paused' AND a.deleted_at IS NULL AND e.created_at < NOW() - INTERVAL '1 day'
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to find paused applications: %w", err)
+	}
+	type paused struct{ userID, applicationID, title, company, eventID string }
+	var waiting []paused
+	for rows.Next() {
+		var p paused
+		if err := rows.Scan(&p.userID, &p.applicationID, &p.title, &p.company, &p.eventID); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to find paused applications: %w", err)
+		}
+		waiting = append(waiting, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to find paused applications: %w", err)
+	}
+	for _, p := range waiting {
+		// Keyed by the pause, so an application paused again later is reminded about again
+		added, err := h.notifications.Notify(ctx, p.userID, store.NewNotification{
+			Kind:      models.NotificationReminder,
+			Title:     "Your application to " + p.company + " is still waiting",
+			Body:      p.title + " has been paused for over a day waiting for your answers.",
+			Link:      "/api/v1/applications/" + p.applicationID + "/questions",
+			DedupeKey: "reminder:paused:" + p.eventID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add reminder: %w", err)
+		}
+		if added {
+			reminders++
+		}
+	}
+
+	// Jobs saved one to two weeks ago, so enabling reminders doesn't flood inboxes with every
+	// job ever saved
+	rows, err = h.db.Query(ctx, `
+		SELECT s.user_id, j.id, j.title, j.company
+		FROM saved_jobs s
+		JOIN jobs j ON j.id = s.job_id
+		WHERE s.saved_at < NOW() - INTERVAL '7 days' AND s.saved_at >= NOW() - INTERVAL '14 days'
+			AND j.deleted_at IS NULL AND j.status = 'open'
+			AND NOT EXISTS (SELECT 1 FROM applications a WHERE a.user_id = s.user_id AND a.job_id = j.id AND a.deleted_at IS NULL)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to find saved jobs: %w", err)
+	}
+	type saved struct{ userID, jobID, title, company string }
+	var unapplied []saved
+	for rows.Next() {
+		var s saved
+		if err := rows.Scan(&s.userID, &s.jobID, &s.title, &s.company); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to find saved jobs: %w", err)
+		}
+		unapplied = append(unapplied, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to find saved jobs: %w", err)
+	}
+	for _, s := range unapplied {
+		added, err := h.notifications.Notify(ctx, s.userID, store.NewNotification{
+			Kind:      models.NotificationReminder,
+			Title:     "Still interested in " + s.title + "?",
+			Body:      "You saved this " + s.company + " job a week ago and haven't applied yet.",
+			Link:      "/api/v1/jobs/" + s.jobID,
+			DedupeKey: "reminder:saved:" + s.jobID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add reminder: %w", err)
+		}
+		if added {
+			reminders++
+		}
+	}
+
+	deleted, err := h.notifications.DeleteReadNotifications(ctx, time.Now().Add(-readNotificationRetention))
+	if err != nil {
+		return fmt.Errorf("failed to delete old notifications: %w", err)
+	}
+
+	logging.FromContext(ctx).Info("Reminders sent", "reminders", reminders, "old_notifications_deleted", deleted)
+	return nil
+}
@@ -0,0 +1,413 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/yourusername/jobapply/internal/matching"
+	"github.com/yourusername/jobapply/internal/models"
+	"github.com/yourusername/jobapply/internal/openapi"
+	"github.com/yourusername/jobapply/internal/services"
+)
+
+// apiVersion is the version reported in the OpenAPI document
+const apiVersion = "1.0.0"
+
+type message = map[string]string
+
+// graphQLRequest and graphQLResponse describe the GraphQL endpoint, which is served by
+// internal/graph
+type graphQLRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+type graphQLResponse struct {
+	Data   map[string]any   `json:"data"`
+	Errors []map[string]any `json:"errors,omitempty"`
+}
+
+var tagParam = openapi.Param{Name: "tag", Description: "Only items with this tag; repeat to require several", Repeated: true}
+
+// deletedParam lists deleted records that can still be restored instead of live ones
+var deletedParam = openapi.Param{Name: "deleted", Type: "boolean", Description: "List deleted records that can still be restored"}
+
+// idempotencyParam makes a POST safe to retry; see Handler.Idempotent
+var idempotencyParam = openapi.Param{Name: "Idempotency-Key", Header: true,
+	Description: "Unique per logical request; repeats within 24 hours return the original response instead of running again"}
+
+// apiOperations documents every /api/v1 route. Keep it in step with the router in
+// cmd/api/main.go; UndocumentedRoutes reports any route missing here.
+var apiOperations = []openapi.Operation{
+	{Method: "POST", Path: "/api/v1/auth/signup", Tag: "auth", Public: true, Summary: "Create an account",
+		Request: SignupRequest{}, Response: AuthResponse{}, Status: http.StatusCreated},
+	{Method: "POST", Path: "/api/v1/auth/login", Tag: "auth", Public: true, Summary: "Log in and get a token",
+		Request: LoginRequest{}, Response: AuthResponse{}},
+	{Method: "GET", Path: "/api/v1/auth/me", Tag: "auth", Summary: "Get the logged-in user's profile",
+		Response: models.UserProfile{}},
+	{Method: "PUT", Path: "/api/v1/auth/password", Tag: "auth", Summary: "Change password",
+		Request: ChangePasswordRequest{}, Response: message{}},
+	{Method: "PUT", Path: "/api/v1/auth/email", Tag: "auth", Summary: "Change email; returns a new token",
+		Request: UpdateEmailRequest{}, Response: message{}},
+	{Method: "GET", Path: "/api/v1/auth/csrf", Tag: "auth", Summary: "Get the CSRF token for the session cookie",
+		Response: message{}},
+	{Method: "POST", Path: "/api/v1/auth/logout", Tag: "auth", Summary: "Clear the session cookies",
+		Response: message{}},
+
+	{Method: "POST", Path: "/api/v1/profile", Tag: "profile", Summary: "Create or update the profile",
+		Request: models.UserProfile{}, Response: models.UserProfile{}},
+	{Method: "GET", Path: "/api/v1/profile", Tag: "profile", Summary: "Get the profile",
+		Response: models.UserProfile{}},
+	{Method: "DELETE", Path: "/api/v1/profile", Tag: "profile", Summary: "Delete the account and all its data",
+		Response: message{}},
+	{Method: "GET", Path: "/api/v1/profile/validate", Tag: "profile", Summary: "Check the profile is complete enough to search",
+		Response: ValidationResponse{}},
+	{Method: "GET", Path: "/api/v1/profile/privacy", Tag: "profile", Summary: "Get fields that are never autofilled",
+		Response: AutofillPrivacy{}},
+	{Method: "PUT", Path: "/api/v1/profile/privacy", Tag: "profile", Summary: "Set fields that are never autofilled",
+		Request: AutofillPrivacy{}, Response: AutofillPrivacy{}},
+	{Method: "GET", Path: "/api/v1/profile/blocklist", Tag: "profile", Summary: "Get blocked companies and keywords",
+		Response: JobBlocklist{}},
+	{Method: "PUT", Path: "/api/v1/profile/blocklist", Tag: "profile", Summary: "Replace blocked companies and keywords",
+		Request: JobBlocklist{}, Response: JobBlocklist{}},
+	{Method: "POST", Path: "/api/v1/profile/work-history", Tag: "profile", Summary: "Add a work history entry",
+		Request: models.WorkHistory{}, Response: WorkHistoryResponse{}, Status: http.StatusCreated},
+	{Method: "PUT", Path: "/api/v1/profile/work-history/{idx}", Tag: "profile", Summary: "Replace a work history entry",
+		Request: models.WorkHistory{}, Response: WorkHistoryResponse{}},
+	{Method: "DELETE", Path: "/api/v1/profile/work-history/{idx}", Tag: "profile", Summary: "Remove a work history entry",
+		Response: WorkHistoryResponse{}},
+	{Method: "POST", Path: "/api/v1/profile/education", Tag: "profile", Summary: "Add an education entry",
+		Request: models.Education{}, Response: EducationResponse{}, Status: http.StatusCreated},
+	{Method: "PUT", Path: "/api/v1/profile/education/{idx}", Tag: "profile", Summary: "Replace an education entry",
+		Request: models.Education{}, Response: EducationResponse{}},
+	{Method: "DELETE", Path: "/api/v1/profile/education/{idx}", Tag: "profile", Summary: "Remove an education entry",
+		Response: EducationResponse{}},
+
+	{Method: "POST", Path: "/api/v1/profile/resume", Tag: "resume", Summary: "Upload a resume without parsing it",
+		Upload: "resume", Response: message{}},
+	{Method: "POST", Path: "/api/v1/profile/resume/parse", Tag: "resume", Summary: "Upload and parse a resume",
+		Upload: "resume", Response: models.ParsedResume{},
+		Params: []openapi.Param{{Name: "backend", Description: "Parser backend; defaults to the server's"}}},
+	{Method: "GET", Path: "/api/v1/profile/resume/diff", Tag: "resume", Summary: "Compare the stored resume with the profile",
+		Response: models.ProfileDiff{}},
+	{Method: "POST", Path: "/api/v1/profile/resume/confirm", Tag: "resume", Summary: "Apply accepted resume changes",
+		Request: models.ProfileMergeRequest{}, Response: models.UserProfile{}},
+	{Method: "GET", Path: "/api/v1/profile/resume/generate", Tag: "resume", Summary: "Render the profile as a PDF resume",
+		ContentType: "application/pdf",
+		Params:      []openapi.Param{{Name: "template", Description: "Layout; defaults to modern"}}},
+	{Method: "POST", Path: "/api/v1/profile/import/linkedin", Tag: "resume", Summary: "Import a LinkedIn data export",
+		Upload: "archive", Response: models.ProfileDiff{}},
+	{Method: "POST", Path: "/api/v1/profile/import/confirm", Tag: "resume", Summary: "Apply accepted import changes",
+		Request: models.ProfileMergeRequest{}, Response: models.UserProfile{}},
+
+	{Method: "GET", Path: "/api/v1/profiles", Tag: "personas", Summary: "List named profiles",
+		Response: []models.Persona{}, Params: []openapi.Param{deletedParam}},
+	{Method: "POST", Path: "/api/v1/profiles", Tag: "personas", Summary: "Create a named profile",
+		Request: PersonaRequest{}, Response: models.Persona{}, Status: http.StatusCreated},
+	{Method: "GET", Path: "/api/v1/profiles/{id}", Tag: "personas", Summary: "Get a named profile",
+		Response: models.Persona{}},
+	{Method: "PUT", Path: "/api/v1/profiles/{id}", Tag: "personas", Summary: "Update a named profile",
+		Request: PersonaRequest{}, Response: models.Persona{}},
+	{Method: "DELETE", Path: "/api/v1/profiles/{id}", Tag: "personas", Summary: "Delete a named profile; it can be restored until purged",
+		Response: message{}},
+	{Method: "POST", Path: "/api/v1/profiles/{id}/restore", Tag: "personas", Summary: "Restore a deleted named profile",
+		Response: models.Persona{}},
+	{Method: "PUT", Path: "/api/v1/profiles/{id}/default", Tag: "personas", Summary: "Make a named profile the default",
+		Response: models.Persona{}},
+	{Method: "POST", Path: "/api/v1/profiles/{id}/resume", Tag: "personas", Summary: "Upload a named profile's resume",
+		Upload: "resume", Response: message{}},
+
+	{Method: "GET", Path: "/api/v1/uploads/{key}", Tag: "uploads", Summary: "Download one of your uploads",
+		ContentType: "application/octet-stream"},
+	{Method: "GET", Path: "/api/v1/uploads/{key}/signed-url", Tag: "uploads", Summary: "Get a short-lived public link to an upload",
+		Response: SignedURLResponse{}},
+	{Method: "GET", Path: "/api/v1/skills/suggest", Tag: "profile", Summary: "Autocomplete skill names",
+		Response: []string{}, Params: []openapi.Param{{Name: "q", Description: "Prefix to complete"}}},
+
+	{Method: "GET", Path: "/api/v1/tags", Tag: "tags", Summary: "List tags with usage counts",
+		Response: []Tag{}},
+	{Method: "POST", Path: "/api/v1/tags", Tag: "tags", Summary: "Create a tag",
+		Request: TagRequest{}, Response: Tag{}, Status: http.StatusCreated},
+	{Method: "PUT", Path: "/api/v1/tags/{id}", Tag: "tags", Summary: "Rename or recolor a tag",
+		Request: TagRequest{}, Response: Tag{}},
+	{Method: "DELETE", Path: "/api/v1/tags/{id}", Tag: "tags", Summary: "Delete a tag",
+		Response: message{}},
+
+	{Method: "GET", Path: "/api/v1/applications", Tag: "applications", Summary: "List applications",
+		Response: []models.Application{}, Params: []openapi.Param{tagParam, deletedParam}},
+	{Method: "POST", Path: "/api/v1/applications", Tag: "applications", Summary: "Start an application to a job, or record an attempt already made",
+		Params: []openapi.Param{idempotencyParam}, Request: CreateApplicationRequest{}, Response: ApplicationStatusResponse{}, Status: http.StatusCreated},
+	{Method: "PATCH", Path: "/api/v1/applications/{id}/status", Tag: "applications", Summary: "Report an apply attempt's outcome or cancel; moves the status machine forbids are refused",
+		Request: UpdateApplicationStatusRequest{}, Response: ApplicationStatusResponse{}},
+	{Method: "POST", Path: "/api/v1/applications/import", Tag: "applications", Summary: "Import applications made elsewhere from a CSV with date, company, title, url and status columns",
+		Upload: "file", Response: ImportApplicationsResponse{}},
+	{Method: "DELETE", Path: "/api/v1/applications/{id}", Tag: "applications", Summary: "Delete an application; it can be restored until purged",
+		Response: message{}},
+	{Method: "POST", Path: "/api/v1/applications/{id}/restore", Tag: "applications", Summary: "Restore a deleted application",
+		Response: message{}},
+	{Method: "GET", Path: "/api/v1/applications/{id}/events", Tag: "applications", Summary: "List an application's status changes, oldest first",
+		Response: []models.ApplicationEvent{}},
+	{Method: "GET", Path: "/api/v1/applications/{id}/questions", Tag: "applications", Summary: "List the custom questions from each page of the form, with answers",
+		Response: []models.QuestionPage{}},
+	{Method: "GET", Path: "/api/v1/applications/{id}/snapshot", Tag: "applications", Summary: "Download the PDF of the job posting saved when you applied",
+		ContentType: "application/pdf"},
+	{Method: "GET", Path: "/api/v1/application-templates", Tag: "applications", Summary: "List application templates by employer",
+		Response: []models.ApplicationTemplate{}},
+	{Method: "POST", Path: "/api/v1/application-templates", Tag: "applications", Summary: "Save answers and a profile for an employer",
+		Request: ApplicationTemplateRequest{}, Response: models.ApplicationTemplate{}, Status: http.StatusCreated},
+	{Method: "PUT", Path: "/api/v1/application-templates/{id}", Tag: "applications", Summary: "Replace an application template",
+		Request: ApplicationTemplateRequest{}, Response: models.ApplicationTemplate{}},
+	{Method: "DELETE", Path: "/api/v1/application-templates/{id}", Tag: "applications", Summary: "Delete an application template",
+		Response: message{}},
+	{Method: "PUT", Path: "/api/v1/applications/{id}/tags/{tagId}", Tag: "applications", Summary: "Tag an application",
+		Response: message{}},
+	{Method: "DELETE", Path: "/api/v1/applications/{id}/tags/{tagId}", Tag: "applications", Summary: "Untag an application",
+		Response: message{}},
+
+	{Method: "POST", Path: "/api/v1/scrape", Tag: "jobs", Summary: "Scrape jobs matching keywords",
+		Params: []openapi.Param{idempotencyParam}, Request: ScrapeRequest{}, Response: ScrapeResponse{}},
+	{Method: "GET", Path: "/api/v1/jobs", Tag: "jobs", Summary: "Search scraped jobs",
+		Response: JobsResponse{}, Params: jobsParams},
+	{Method: "GET", Path: "/api/v1/jobs/saved", Tag: "jobs", Summary: "List saved jobs",
+		Response: []models.SavedJob{}, Params: []openapi.Param{tagParam}},
+	{Method: "GET", Path: "/api/v1/jobs/recommended", Tag: "jobs", Summary: "Best-matching jobs not yet applied to",
+		Response: JobsResponse{}, Params: jobsParams},
+	{Method: "GET", Path: "/api/v1/jobs/{id}", Tag: "jobs", Summary: "Get a job with its description",
+		Response: models.JobDetail{}},
+	{Method: "POST", Path: "/api/v1/jobs/{id}/match", Tag: "jobs", Summary: "Compare a job's keywords with the profile",
+		Response: matching.MatchResult{},
+		Params:   []openapi.Param{{Name: "profile_id", Description: "Named profile to match; defaults to the default one"}}},
+	{Method: "POST", Path: "/api/v1/jobs/{id}/dismiss", Tag: "jobs", Summary: "Hide a job",
+		Response: message{}},
+	{Method: "DELETE", Path: "/api/v1/jobs/{id}/dismiss", Tag: "jobs", Summary: "Unhide a job",
+		Response: message{}},
+	{Method: "PUT", Path: "/api/v1/jobs/{id}/tags/{tagId}", Tag: "jobs", Summary: "Tag a job",
+		Response: message{}},
+	{Method: "DELETE", Path: "/api/v1/jobs/{id}/tags/{tagId}", Tag: "jobs", Summary: "Untag a job",
+		Response: message{}},
+	{Method: "POST", Path: "/api/v1/jobs/{id}/save", Tag: "jobs", Summary: "Save a job, or update its notes and priority",
+		Request: SaveJobRequest{}, Response: message{}},
+	{Method: "DELETE", Path: "/api/v1/jobs/{id}/save", Tag: "jobs", Summary: "Remove a job from the saved list",
+		Response: message{}},
+
+	{Method: "GET", Path: "/api/v1/companies/{id}", Tag: "companies", Summary: "Get a company with its jobs and your applications",
+		Response: CompanyView{}},
+	{Method: "PUT", Path: "/api/v1/companies/{id}/notes", Tag: "companies", Summary: "Set your notes on a company",
+		Request: struct {
+			Notes string `json:"notes"`
+		}{}, Response: message{}},
+
+	{Method: "GET", Path: "/api/v1/organizations", Tag: "organizations", Summary: "List your organizations and your role in each",
+		Response: []Organization{}},
+	{Method: "POST", Path: "/api/v1/organizations", Tag: "organizations", Summary: "Create an organization, as its first coach",
+		Request: OrganizationRequest{}, Response: Organization{}, Status: http.StatusCreated},
+	{Method: "GET", Path: "/api/v1/organizations/{id}/members", Tag: "organizations", Summary: "List members and client consent (coaches only)",
+		Response: []OrganizationMember{}},
+	{Method: "POST", Path: "/api/v1/organizations/{id}/members", Tag: "organizations", Summary: "Add a coach or client by email (coaches only)",
+		Request: AddMemberRequest{}, Response: OrganizationMember{}, Status: http.StatusCreated},
+	{Method: "DELETE", Path: "/api/v1/organizations/{id}/members/{userId}", Tag: "organizations", Summary: "Remove a member, or leave",
+		Response: message{}},
+	{Method: "PUT", Path: "/api/v1/organizations/{id}/consent", Tag: "organizations", Summary: "Let the organization's coaches act for you",
+		Response: message{}},
+	{Method: "DELETE", Path: "/api/v1/organizations/{id}/consent", Tag: "organizations", Summary: "Stop the organization's coaches acting for you",
+		Response: message{}},
+
+	{Method: "GET", Path: "/api/v1/extension-keys", Tag: "extension", Summary: "List browser extension keys",
+		Response: []ExtensionKey{}},
+	{Method: "POST", Path: "/api/v1/extension-keys", Tag: "extension", Summary: "Create a browser extension key; the key is only returned now",
+		Request: CreateExtensionKeyRequest{}, Response: CreatedExtensionKey{}, Status: http.StatusCreated},
+	{Method: "DELETE", Path: "/api/v1/extension-keys/{id}", Tag: "extension", Summary: "Revoke a browser extension key",
+		Response: message{}},
+	{Method: "POST", Path: "/api/v1/jobs/capture", Tag: "extension", Summary: "Save the job page being viewed (extension key scope jobs:capture)",
+		Request: CaptureJobRequest{}, Response: models.JobDetail{}, Status: http.StatusCreated},
+	{Method: "POST", Path: "/api/v1/applications/manual", Tag: "extension", Summary: "Record an application made by hand (extension key scope applications:write)",
+		Request: ManualApplicationRequest{}, Response: struct {
+			ID     string `json:"id"`
+			Status string `json:"status"`
+		}{}, Status: http.StatusCreated},
+
+	{Method: "GET", Path: "/api/v1/notifications", Tag: "notifications", Summary: "List the notification inbox, newest first, marking the listed notifications seen",
+		Response: NotificationsResponse{}, Params: []openapi.Param{
+			{Name: "unread", Type: "boolean", Description: "Only unread notifications"},
+			{Name: "before", Description: "Only notifications created before this RFC 3339 time, for paging"},
+			{Name: "limit", Type: "integer", Description: "At most this many (default 50, max 200)"},
+		}},
+	{Method: "GET", Path: "/api/v1/notifications/count", Tag: "notifications", Summary: "Count unread and unseen notifications",
+		Response: NotificationCounts{}},
+	{Method: "POST", Path: "/api/v1/notifications/read-all", Tag: "notifications", Summary: "Mark every notification read",
+		Response: struct {
+			Marked int `json:"marked"`
+		}{}},
+	{Method: "POST", Path: "/api/v1/notifications/{id}/read", Tag: "notifications", Summary: "Mark a notification read",
+		Response: message{}},
+
+	{Method: "GET", Path: "/api/v1/stats-shares", Tag: "sharing", Summary: "List public stats links",
+		Response: []StatsShare{}},
+	{Method: "POST", Path: "/api/v1/stats-shares", Tag: "sharing", Summary: "Create a public link to some of your stats; the token is only returned now",
+		Request: CreateStatsShareRequest{}, Response: CreatedStatsShare{}, Status: http.StatusCreated},
+	{Method: "DELETE", Path: "/api/v1/stats-shares/{id}", Tag: "sharing", Summary: "Revoke a public stats link",
+		Response: message{}},
+	{Method: "GET", Path: "/api/v1/shared/stats/{token}", Tag: "sharing", Public: true, Summary: "The stats a share link shows",
+		Response: SharedStats{}},
+
+	{Method: "GET", Path: "/api/v1/admin/tasks", Tag: "admin", Summary: "List background tasks and their latest runs",
+		Response: []services.TaskStatus{}},
+	{Method: "GET", Path: "/api/v1/admin/tasks/{name}/runs", Tag: "admin", Summary: "List a task's recent runs",
+		Response: []services.TaskRun{}, Params: []openapi.Param{{Name: "limit", Type: "integer", Description: "Runs to return (1-100, default 20)"}}},
+	{Method: "POST", Path: "/api/v1/admin/tasks/{name}/run", Tag: "admin", Summary: "Run a task now",
+		Response: message{}, Status: http.StatusAccepted},
+	{Method: "GET", Path: "/api/v1/admin/flags", Tag: "admin", Summary: "List feature flags",
+		Response: []services.Flag{}},
+	{Method: "GET", Path: "/api/v1/admin/flags/{name}", Tag: "admin", Summary: "Get a feature flag",
+		Response: services.Flag{}},
+	{Method: "PUT", Path: "/api/v1/admin/flags/{name}", Tag: "admin", Summary: "Create or replace a feature flag",
+		Request: FlagRequest{}, Response: services.Flag{}},
+	{Method: "DELETE", Path: "/api/v1/admin/flags/{name}", Tag: "admin", Summary: "Delete a feature flag",
+		Response: message{}},
+	{Method: "DELETE", Path: "/api/v1/admin/scrape/cache", Tag: "admin", Summary: "Invalidate a search's cached results so the next scrape fetches fresh ones",
+		Response: map[string]int64{}, Params: []openapi.Param{
+			{Name: "keywords", Description: "The search's keywords (required)"},
+			{Name: "location", Description: "The search's location (required)"},
+		}},
+	{Method: "GET", Path: "/api/v1/admin/scrape-rejects", Tag: "admin", Summary: "List recent scraped jobs that failed validation",
+		Response: []ScrapeReject{}, Params: []openapi.Param{
+			{Name: "limit", Type: "integer", Description: "Rejects to return (1-500, default 50)"},
+			{Name: "site", Description: "Only rejects from this source, e.g. muse"},
+		}},
+	{Method: "POST", Path: "/api/v1/admin/companies/{id}/aliases", Tag: "admin", Summary: "Make another name resolve to a company, merging any company stored under it",
+		Request: CompanyAliasRequest{}, Response: map[string]any{}},
+	{Method: "DELETE", Path: "/api/v1/admin/companies/{id}/aliases", Tag: "admin", Summary: "Stop a name resolving to a company",
+		Response: message{}, Params: []openapi.Param{{Name: "name", Description: "The alias to remove"}}},
+	{Method: "GET", Path: "/api/v1/admin/apply-health", Tag: "admin", Summary: "Application success rates and failures by site",
+		Response: ApplyHealth{}, Params: []openapi.Param{{Name: "days", Type: "integer", Description: "Window in days (1-365, default 30)"}}},
+	{Method: "GET", Path: "/api/v1/admin/outbox/dead", Tag: "admin", Summary: "Events the outbox gave up delivering, most recent first",
+		Response: []services.DeadLetter{}, Params: []openapi.Param{{Name: "limit", Type: "integer", Description: "At most this many (default 20, max 100)"}}},
+	{Method: "POST", Path: "/api/v1/admin/outbox/{id}/retry", Tag: "admin", Summary: "Queue a dead-lettered event for delivery again",
+		Response: message{}, Status: http.StatusAccepted},
+	{Method: "GET", Path: "/api/v1/admin/retention/report", Tag: "admin", Summary: "What the retention task would remove now, without removing it",
+		Response: RetentionReport{}},
+	{Method: "GET", Path: "/api/v1/admin/audit", Tag: "admin", Summary: "Recent write requests, with who made them and what they changed",
+		Response: []AuditRecord{}, Params: []openapi.Param{
+			{Name: "user_id", Description: "Only requests made by or on behalf of this user"},
+			{Name: "since", Description: "Only requests at or after this RFC 3339 time"},
+			{Name: "limit", Type: "integer", Description: "At most this many (default 50, max 500)"}}},
+	{Method: "POST", Path: "/api/v1/admin/impersonate/{user_id}", Tag: "admin", Summary: "Get a short-lived token that acts as a user; everything done with it is audited",
+		Request: ImpersonateRequest{}, Response: ImpersonateResponse{}, Status: http.StatusCreated},
+
+	{Method: "GET", Path: "/api/v1/flags", Tag: "flags", Summary: "List the feature flags that are on for you",
+		Response: map[string][]string{}},
+
+	{Method: "GET", Path: "/api/v1/graphql", Tag: "graphql", Summary: "Run a GraphQL query passed as query parameters",
+		Params: []openapi.Param{{Name: "query"}, {Name: "operationName"}, {Name: "variables", Description: "JSON object"}}, Response: graphQLResponse{}},
+	{Method: "POST", Path: "/api/v1/graphql", Tag: "graphql", Summary: "Run a GraphQL query; the schema is served by introspection",
+		Request: graphQLRequest{}, Response: graphQLResponse{}},
+
+	{Method: "GET", Path: "/api/v1/openapi.json", Tag: "meta", Public: true, Summary: "This document"},
+}
+
+var jobsParams = []openapi.Param{
+	{Name: "q", Description: "Full-text search"},
+	{Name: "location"},
+	{Name: "site"},
+	tagParam,
+	{Name: "status", Enum: []string{"open", "closed"}},
+	{Name: "posted_since", Description: "YYYY-MM-DD, RFC 3339, or days ago like 7d"},
+	{Name: "remote", Type: "boolean"},
+	{Name: "salary_min", Type: "integer", Description: "Annual USD"},
+	{Name: "salary_max", Type: "integer", Description: "Annual USD"},
+	{Name: "within_miles", Type: "number", Description: "Distance from lat/lng, near, or the profile address"},
+	{Name: "radius_km", Type: "number", Description: "within_miles in kilometres"},
+	{Name: "near", Description: "Where distances are measured from: me (the profile address) or a place name"},
+	{Name: "lat", Type: "number"},
+	{Name: "lng", Type: "number"},
+	{Name: "include_remote", Type: "boolean", Description: "Keep remote jobs when filtering by distance"},
+	{Name: "exclude_applied", Type: "boolean"},
+	{Name: "include_description", Type: "boolean", Description: "Add each job's scraped description"},
+	{Name: "min_score", Type: "integer", Description: "0-100"},
+	{Name: "hide_ghosts", Type: "boolean", Description: "Hide likely ghost jobs and agency reposts"},
+	{Name: "max_ghost_score", Type: "integer", Description: "0-100; hide jobs scoring above this"},
+	{Name: "commute_mode", Enum: []string{"driving", "transit"}},
+	{Name: "max_commute_minutes", Type: "integer", Description: "Only jobs with an estimated commute at most this long"},
+	{Name: "sort", Enum: []string{"recent", "match", "salary", "commute"}},
+	{Name: "limit", Type: "integer"},
+	{Name: "cursor", Description: "next_cursor from the previous page"},
+}
+
+var apiSpec = sync.OnceValue(func() []byte {
+	doc := openapi.Build(openapi.Info{
+		Title:   "Job Apply API",
+		Version: apiVersion,
+	}, apiOperations)
+	spec, err := json.Marshal(doc)
+	if err != nil {
+		panic(err)
+	}
+	return spec
+})
+
+// OpenAPISpec serves the OpenAPI document for the API
+func (h *Handler) OpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(apiSpec())
+}
+
+// Swagger UI is loaded from unpkg at an exact version, and the browser refuses the files unless
+// they match these hashes, so a changed CDN file can't run on the API's origin. After changing
+// the version, run "make swagger-ui-sri" and paste its output here.
+const (
+	swaggerUIVersion         = "5.17.14"
+	swaggerUICSSIntegrity    = ""
+	swaggerUIBundleIntegrity = ""
+)
+
+// swaggerUIPage loads Swagger UI from a CDN and points it at the spec
+const swaggerUIPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Job Apply API</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui.css" integrity="` + swaggerUICSSIntegrity + `" crossorigin="anonymous">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui-bundle.js" integrity="` + swaggerUIBundleIntegrity + `" crossorigin="anonymous"></script>
+<script>SwaggerUIBundle({url: "/api/v1/openapi.json", dom_id: "#swagger-ui"});</script>
+</body>
+</html>`
+
+// APIDocs serves Swagger UI for browsing the spec. Until the asset hashes are filled in it
+// answers 503 rather than serve CDN code the browser can't check.
+func (h *Handler) APIDocs(w http.ResponseWriter, r *http.Request) {
+	if swaggerUICSSIntegrity == "" || swaggerUIBundleIntegrity == "" {
+		h.error(w, "Swagger UI assets are not pinned; run make swagger-ui-sri", http.StatusServiceUnavailable)
+		return
+	}
+	// The default policy only allows same-origin scripts; the UI is loaded from unpkg
+	w.Header().Set("Content-Security-Policy", "default-src 'self'; script-src 'self' 'unsafe-inline' https://unpkg.com; style-src 'self' 'unsafe-inline' https://unpkg.com; img-src 'self' data: https://unpkg.com")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(swaggerUIPage))
+}
+
+// UndocumentedRoutes lists /api/v1 routes in r that have no entry in the OpenAPI document
+func UndocumentedRoutes(r chi.Routes) []string {
+	documented := make(map[string]bool, len(apiOperations))
+	for _, op := range apiOperations {
+		documented[op.Method+" "+op.Path] = true
+	}
+
+	var missing []string
+	chi.Walk(r, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		route = strings.TrimSuffix(route, "/")
+		if strings.HasPrefix(route, "/api/v1/") && route != "/api/v1/docs" && !documented[method+" "+route] {
+			missing = append(missing, method+" "+route)
+		}
+		return nil
+	})
+	sort.Strings(missing)
+	return missing
+}
@@ -0,0 +1,397 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/yourusername/jobapply/internal/audit"
+	"github.com/yourusername/jobapply/internal/logging"
+	"github.com/yourusername/jobapply/internal/store"
+	"github.com/yourusername/jobapply/internal/validation"
+)
+
+// Organization member roles
+const (
+	RoleCoach  = "coach"
+	RoleClient = "client"
+)
+
+// onBehalfOfHeader names the client a coach is acting for
+const onBehalfOfHeader = "X-On-Behalf-Of"
+
+type Organization struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Role string `json:"role"` // The caller's role
+	// ConsentedAt is when the caller, as a client, consented to the coaches acting for them
+	ConsentedAt *time.Time `json:"consented_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type OrganizationMember struct {
+	UserID   string `json:"user_id"`
+	FullName string `json:"full_name"`
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+	// ConsentedAt is when a client let the organization's coaches act for them; nil until then
+	ConsentedAt *time.Time `json:"consented_at,omitempty"`
+	JoinedAt    time.Time  `json:"joined_at"`
+}
+
+type OrganizationRequest struct {
+	Name string `json:"name"`
+}
+
+// Validate sanitizes the name
+func (req *OrganizationRequest) Validate() error {
+	var v validation.Collector
+	req.Name = validation.SanitizeString(req.Name, 100)
+	v.Required("name", req.Name)
+	return v.Err()
+}
+
+type AddMemberRequest struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+// Validate checks the email and role
+func (req *AddMemberRequest) Validate() error {
+	var v validation.Collector
+	if v.Required("email", req.Email) {
+		v.Check(validation.ValidateEmail(req.Email), "email", "email is not a valid email address")
+	}
+	v.Check(req.Role == RoleCoach || req.Role == RoleClient, "role", "role must be coach or client")
+	return v.Err()
+}
+
+// ActingUserIDFromContext returns the coach acting for the authenticated user through
+// ActOnBehalf, or "" when users act for themselves
+func ActingUserIDFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value("acting_user_id").(string)
+	return actor
+}
+
+// ActOnBehalf lets a coach work on a client's account by sending the client's user ID in the
+// X-On-Behalf-Of header. The coach must share an organization with the client, and the
+// client must have consented there; the request then runs as the client, so every query is
+// scoped to the client's data. It must run after AuthMiddleware. Account settings, such as
+// the password or deleting the account, are routed outside it and can't be reached this way.
+func (h *Handler) ActOnBehalf(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientID := r.Header.Get(onBehalfOfHeader)
+		coachID := getUserIDFromContext(r.Context())
+		if clientID == "" || coachID == "" || clientID == coachID {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !h.validateUUID(w, clientID, onBehalfOfHeader) {
+			return
+		}
+
+		var allowed bool
+		err := h.db.QueryRow(r.Context(), `
+			SELECT EXISTS (
+				SELECT 1 FROM organization_members coach
+				JOIN organization_members client ON client.organization_id = coach.organization_id
+				WHERE coach.user_id = $1 AND coach.role = 'coach'
+				AND client.user_id = $2 AND client.role = 'client' AND client.consented_at IS NOT NULL
+			)
+		`, coachID, clientID).Scan(&allowed)
+		if err != nil {
+			h.internalError(w, r, "Failed to check client access", err)
+			return
+		}
+		if !allowed {
+			h.error(w, "You are not a coach for this client, or they haven't consented", http.StatusForbidden)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), "user_id", clientID)
+		ctx = context.WithValue(ctx, "acting_user_id", coachID)
+		ctx = logging.With(ctx, "user_id", clientID, "acting_user_id", coachID)
+		audit.SetSubject(ctx, clientID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// memberRole returns the user's role in the organization, or "" if they aren't a member
+func (h *Handler) memberRole(ctx context.Context, orgID, userID string) (string, error) {
+	var role string
+	err := h.db.QueryRow(ctx, "SELECT role FROM organization_members WHERE organization_id = $1 AND user_id = $2",
+		orgID, userID).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	return role, err
+}
+
+// requireCoach checks the caller coaches the organization in the URL, returning its ID
+func (h *Handler) requireCoach(w http.ResponseWriter, r *http.Request, userID string) (string, bool) {
+	orgID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, orgID, "organization ID") {
+		return "", false
+	}
+	role, err := h.memberRole(r.Context(), orgID, userID)
+	switch {
+	case err != nil:
+		h.internalError(w, r, "Failed to get organization", err)
+		return "", false
+	case role == "":
+		h.error(w, "Organization not found", http.StatusNotFound)
+		return "", false
+	case role != RoleCoach:
+		h.error(w, "Only coaches can manage the organization", http.StatusForbidden)
+		return "", false
+	}
+	return orgID, true
+}
+
+// ListOrganizations returns the organizations the user belongs to, with their role in each
+func (h *Handler) ListOrganizations(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	rows, err := h.db.Query(r.Context(), `
+		SELECT o.id, o.name, m.role, m.consented_at, o.created_at
+		FROM organizations o
+		JOIN organization_members m ON m.organization_id = o.id
+		WHERE m.user_id = $1
+		ORDER BY LOWER(o.name)
+	`, userID)
+	if err != nil {
+		h.internalError(w, r, "Failed to list organizations", err)
+		return
+	}
+	defer rows.Close()
+
+	orgs := []Organization{}
+	for rows.Next() {
+		var org Organization
+		if err := rows.Scan(&org.ID, &org.Name, &org.Role, &org.ConsentedAt, &org.CreatedAt); err != nil {
+			h.internalError(w, r, "Failed to list organizations", err)
+			return
+		}
+		orgs = append(orgs, org)
+	}
+	h.json(w, orgs, http.StatusOK)
+}
+
+// CreateOrganization creates an organization with the caller as its first coach
+func (h *Handler) CreateOrganization(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req OrganizationRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+
+	org := Organization{Name: req.Name, Role: RoleCoach}
+	err := pgx.BeginFunc(r.Context(), h.db, func(tx pgx.Tx) error {
+		err := tx.QueryRow(r.Context(), `
+			INSERT INTO organizations (name, created_by) VALUES ($1, $2)
+			RETURNING id, created_at
+		`, req.Name, userID).Scan(&org.ID, &org.CreatedAt)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(r.Context(),
+			"INSERT INTO organization_members (organization_id, user_id, role) VALUES ($1, $2, 'coach')", org.ID, userID)
+		return err
+	})
+	if err != nil {
+		h.internalError(w, r, "Failed to create organization", err)
+		return
+	}
+	h.json(w, org, http.StatusCreated)
+}
+
+// ListOrganizationMembers lists an organization's coaches and clients, with whether each
+// client has consented. Only coaches can see the member list.
+func (h *Handler) ListOrganizationMembers(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	orgID, ok := h.requireCoach(w, r, userID)
+	if !ok {
+		return
+	}
+
+	rows, err := h.db.Query(r.Context(), `
+		SELECT m.user_id, u.full_name, u.email, m.role, m.consented_at, m.joined_at
+		FROM organization_members m
+		JOIN user_profiles u ON u.id = m.user_id
+		WHERE m.organization_id = $1
+		ORDER BY m.role DESC, LOWER(u.full_name)
+	`, orgID)
+	if err != nil {
+		h.internalError(w, r, "Failed to list members", err)
+		return
+	}
+	defer rows.Close()
+
+	members := []OrganizationMember{}
+	for rows.Next() {
+		var m OrganizationMember
+		if err := rows.Scan(&m.UserID, &m.FullName, &m.Email, &m.Role, &m.ConsentedAt, &m.JoinedAt); err != nil {
+			h.internalError(w, r, "Failed to list members", err)
+			return
+		}
+		members = append(members, m)
+	}
+	h.json(w, members, http.StatusOK)
+}
+
+// AddOrganizationMember adds an existing user as a coach or client. Coaches can't act for a
+// new client until the client consents with PUT /organizations/{id}/consent.
+func (h *Handler) AddOrganizationMember(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	orgID, ok := h.requireCoach(w, r, userID)
+	if !ok {
+		return
+	}
+
+	var req AddMemberRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+
+	user, err := h.users.UserByEmail(r.Context(), req.Email)
+	if errors.Is(err, store.ErrNotFound) {
+		h.error(w, "No account with this email", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.internalError(w, r, "Failed to add member", err)
+		return
+	}
+
+	member := OrganizationMember{UserID: user.ID, FullName: user.FullName, Email: user.Email, Role: req.Role}
+	err = h.db.QueryRow(r.Context(), `
+		INSERT INTO organization_members (organization_id, user_id, role) VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+		RETURNING joined_at
+	`, orgID, user.ID, req.Role).Scan(&member.JoinedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		h.error(w, "User is already a member", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		h.internalError(w, r, "Failed to add member", err)
+		return
+	}
+	h.json(w, member, http.StatusCreated)
+}
+
+// RemoveOrganizationMember removes a member. Coaches can remove anyone; other members can
+// only leave. The last coach can't leave, so the organization is never unmanaged.
+func (h *Handler) RemoveOrganizationMember(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	orgID := chi.URLParam(r, "id")
+	memberID := chi.URLParam(r, "userId")
+	if !h.validateUUID(w, orgID, "organization ID") || !h.validateUUID(w, memberID, "user ID") {
+		return
+	}
+
+	role, err := h.memberRole(r.Context(), orgID, userID)
+	if err != nil {
+		h.internalError(w, r, "Failed to get organization", err)
+		return
+	}
+	if role == "" {
+		h.error(w, "Organization not found", http.StatusNotFound)
+		return
+	}
+	if role != RoleCoach && memberID != userID {
+		h.error(w, "Only coaches can remove other members", http.StatusForbidden)
+		return
+	}
+
+	result, err := h.db.Exec(r.Context(), `
+		DELETE FROM organization_members m
+		WHERE m.organization_id = $1 AND m.user_id = $2
+		AND (m.role <> 'coach' OR EXISTS (
+			SELECT 1 FROM organization_members c
+			WHERE c.organization_id = $1 AND c.role = 'coach' AND c.user_id <> $2
+		))
+	`, orgID, memberID)
+	if err != nil {
+		h.internalError(w, r, "Failed to remove member", err)
+		return
+	}
+	if result.RowsAffected() == 0 {
+		memberRole, err := h.memberRole(r.Context(), orgID, memberID)
+		switch {
+		case err != nil:
+			h.internalError(w, r, "Failed to remove member", err)
+		case memberRole == RoleCoach:
+			h.error(w, "An organization needs at least one coach", http.StatusConflict)
+		default:
+			h.error(w, "Member not found", http.StatusNotFound)
+		}
+		return
+	}
+	h.json(w, message{"message": "Member removed"}, http.StatusOK)
+}
+
+// GrantOrganizationConsent lets the organization's coaches act for the calling client
+func (h *Handler) GrantOrganizationConsent(w http.ResponseWriter, r *http.Request) {
+	h.setOrganizationConsent(w, r, true)
+}
+
+// RevokeOrganizationConsent stops the organization's coaches acting for the calling client.
+// The client stays a member.
+func (h *Handler) RevokeOrganizationConsent(w http.ResponseWriter, r *http.Request) {
+	h.setOrganizationConsent(w, r, false)
+}
+
+func (h *Handler) setOrganizationConsent(w http.ResponseWriter, r *http.Request, consent bool) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	orgID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, orgID, "organization ID") {
+		return
+	}
+
+	result, err := h.db.Exec(r.Context(), `
+		UPDATE organization_members SET consented_at = CASE WHEN $3 THEN COALESCE(consented_at, NOW()) END
+		WHERE organization_id = $1 AND user_id = $2 AND role = 'client'
+	`, orgID, userID, consent)
+	if err != nil {
+		h.internalError(w, r, "Failed to update consent", err)
+		return
+	}
+	if result.RowsAffected() == 0 {
+		h.error(w, "You are not a client of this organization", http.StatusNotFound)
+		return
+	}
+	if consent {
+		h.json(w, message{"message": "Coaches can now act on your behalf"}, http.StatusOK)
+	} else {
+		h.json(w, message{"message": "Consent revoked"}, http.StatusOK)
+	}
+}
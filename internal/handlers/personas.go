@@ -0,0 +1,418 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/yourusername/jobapply/internal/models"
+	"github.com/yourusername/jobapply/internal/validation"
+)
+
+var errPersonaNotFound = errors.New("persona not found")
+
+type PersonaRequest struct {
+	Name           string   `json:"name"`
+	Headline       string   `json:"headline"`
+	Summary        string   `json:"summary"`
+	TargetKeywords string   `json:"target_keywords"`
+	Skills         []string `json:"skills"`
+	IsDefault      bool     `json:"is_default"`
+}
+
+const personaColumns = `id, name, headline, summary, target_keywords, resume_url, skills, is_default, created_at, updated_at, deleted_at`
+
+// ListPersonas returns all named profiles for the authenticated user, default first. With
+// ?deleted=true it lists deleted profiles that can still be restored, most recent first.
+func (h *Handler) ListPersonas(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	query := `SELECT ` + personaColumns + ` FROM profile_personas WHERE user_id = $1 AND deleted_at IS NULL ORDER BY is_default DESC, name`
+	if r.URL.Query().Get("deleted") == "true" {
+		query = `SELECT ` + personaColumns + ` FROM profile_personas WHERE user_id = $1 AND deleted_at IS NOT NULL ORDER BY deleted_at DESC`
+	}
+	rows, err := h.db.Query(r.Context(), query, userID)
+	if err != nil {
+		h.internalError(w, r, "Failed to get profiles", err)
+		return
+	}
+	defer rows.Close()
+
+	personas := []models.Persona{}
+	for rows.Next() {
+		persona, err := scanPersona(rows)
+		if err != nil {
+			continue
+		}
+		personas = append(personas, *persona)
+	}
+
+	h.json(w, personas, http.StatusOK)
+}
+
+// GetPersona returns a single named profile
+func (h *Handler) GetPersona(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	personaID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, personaID, "profile ID") {
+		return
+	}
+
+	persona, err := h.getPersona(r.Context(), userID, personaID)
+	if err != nil {
+		h.error(w, "Profile not found", http.StatusNotFound)
+		return
+	}
+
+	h.json(w, *persona, http.StatusOK)
+}
+
+// CreatePersona adds a named profile. The first persona created becomes the default.
+func (h *Handler) CreatePersona(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	req, ok := h.decodePersonaRequest(w, r)
+	if !ok {
+		return
+	}
+
+	tx, err := h.db.Begin(r.Context())
+	if err != nil {
+		h.internalError(w, r, "Failed to create profile", err)
+		return
+	}
+	defer tx.Rollback(r.Context())
+
+	var existing int
+	if err := tx.QueryRow(r.Context(), "SELECT COUNT(*) FROM profile_personas WHERE user_id = $1 AND deleted_at IS NULL", userID).Scan(&existing); err != nil {
+		h.internalError(w, r, "Failed to create profile", err)
+		return
+	}
+
+	isDefault := req.IsDefault || existing == 0
+	if isDefault {
+		if _, err := tx.Exec(r.Context(), "UPDATE profile_personas SET is_default = FALSE WHERE user_id = $1", userID); err != nil {
+			h.internalError(w, r, "Failed to create profile", err)
+			return
+		}
+	}
+
+	persona, err := scanPersona(tx.QueryRow(r.Context(), `
+		INSERT INTO profile_personas (user_id, name, headline, summary, target_keywords, skills, is_default)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING `+personaColumns,
+		userID, req.Name, req.Headline, req.Summary, req.TargetKeywords, req.Skills, isDefault))
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			h.error(w, "A profile with that name already exists", http.StatusConflict)
+			return
+		}
+		h.internalError(w, r, "Failed to create profile", err)
+		return
+	}
+
+	if err := tx.Commit(r.Context()); err != nil {
+		h.internalError(w, r, "Failed to create profile", err)
+		return
+	}
+
+	h.json(w, *persona, http.StatusCreated)
+}
+
+// UpdatePersona replaces the editable fields of a named profile
+func (h *Handler) UpdatePersona(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	personaID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, personaID, "profile ID") {
+		return
+	}
+
+	req, ok := h.decodePersonaRequest(w, r)
+	if !ok {
+		return
+	}
+
+	persona, err := scanPersona(h.db.QueryRow(r.Context(), `
+		UPDATE profile_personas
+		SET name = $1, headline = $2, summary = $3, target_keywords = $4, skills = $5, updated_at = NOW()
+		WHERE id = $6 AND user_id = $7 AND deleted_at IS NULL
+		RETURNING `+personaColumns,
+		req.Name, req.Headline, req.Summary, req.TargetKeywords, req.Skills, personaID, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.error(w, "Profile not found", http.StatusNotFound)
+			return
+		}
+		if strings.Contains(err.Error(), "duplicate key") {
+			h.error(w, "A profile with that name already exists", http.StatusConflict)
+			return
+		}
+		h.internalError(w, r, "Failed to update profile", err)
+		return
+	}
+
+	h.json(w, *persona, http.StatusOK)
+}
+
+// SetDefaultPersona makes a named profile the one used when requests don't specify profile_id
+func (h *Handler) SetDefaultPersona(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	personaID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, personaID, "profile ID") {
+		return
+	}
+
+	tx, err := h.db.Begin(r.Context())
+	if err != nil {
+		h.internalError(w, r, "Failed to update profile", err)
+		return
+	}
+	defer tx.Rollback(r.Context())
+
+	// Clear the old default first; the partial unique index allows only one
+	if _, err := tx.Exec(r.Context(), "UPDATE profile_personas SET is_default = FALSE WHERE user_id = $1 AND id <> $2", userID, personaID); err != nil {
+		h.internalError(w, r, "Failed to update profile", err)
+		return
+	}
+
+	persona, err := scanPersona(tx.QueryRow(r.Context(), `
+		UPDATE profile_personas SET is_default = TRUE, updated_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+		RETURNING `+personaColumns, personaID, userID))
+	if err != nil {
+		h.error(w, "Profile not found", http.StatusNotFound)
+		return
+	}
+
+	if err := tx.Commit(r.Context()); err != nil {
+		h.internalError(w, r, "Failed to update profile", err)
+		return
+	}
+
+	h.json(w, *persona, http.StatusOK)
+}
+
+// DeletePersona soft-deletes a named profile. It stops being the default, and it and its
+// resume can be restored until the purge task removes them.
+func (h *Handler) DeletePersona(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	personaID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, personaID, "profile ID") {
+		return
+	}
+
+	result, err := h.db.Exec(r.Context(), `
+		UPDATE profile_personas SET deleted_at = NOW(), is_default = FALSE
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+	`, personaID, userID)
+	if err != nil {
+		h.internalError(w, r, "Failed to delete profile", err)
+		return
+	}
+	if result.RowsAffected() == 0 {
+		h.error(w, "Profile not found", http.StatusNotFound)
+		return
+	}
+
+	h.json(w, map[string]string{"message": "Profile deleted successfully"}, http.StatusOK)
+}
+
+// RestorePersona undeletes a named profile. It comes back as a non-default profile.
+func (h *Handler) RestorePersona(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	personaID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, personaID, "profile ID") {
+		return
+	}
+
+	persona, err := scanPersona(h.db.QueryRow(r.Context(), `
+		UPDATE profile_personas SET deleted_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+		RETURNING `+personaColumns, personaID, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.error(w, "Deleted profile not found", http.StatusNotFound)
+			return
+		}
+		if strings.Contains(err.Error(), "duplicate key") {
+			h.error(w, "A profile with that name already exists; rename it first", http.StatusConflict)
+			return
+		}
+		h.internalError(w, r, "Failed to restore profile", err)
+		return
+	}
+
+	h.json(w, *persona, http.StatusOK)
+}
+
+// UploadPersonaResume attaches a resume to a named profile, replacing any previous one
+func (h *Handler) UploadPersonaResume(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	personaID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, personaID, "profile ID") {
+		return
+	}
+
+	persona, err := h.getPersona(r.Context(), userID, personaID)
+	if err != nil {
+		h.error(w, "Profile not found", http.StatusNotFound)
+		return
+	}
+
+	resumeURL, key, warning, ok := h.storeResumeUpload(w, r, userID)
+	if !ok {
+		return
+	}
+
+	result, err := h.db.Exec(r.Context(),
+		"UPDATE profile_personas SET resume_url = $1, updated_at = NOW() WHERE id = $2 AND user_id = $3 AND deleted_at IS NULL",
+		resumeURL, personaID, userID)
+	if err != nil || result.RowsAffected() == 0 {
+		h.releaseUpload(r.Context(), key)
+		h.error(w, "Failed to update profile", http.StatusInternalServerError)
+		return
+	}
+
+	if persona.ResumeURL != nil && *persona.ResumeURL != "" {
+		h.releaseUpload(r.Context(), uploadKey(*persona.ResumeURL))
+	}
+
+	response := map[string]string{
+		"resume_url": resumeURL,
+		"message":    "Resume uploaded successfully",
+	}
+	if warning != "" {
+		response["warning"] = warning
+	}
+	h.json(w, response, http.StatusOK)
+}
+
+// decodePersonaRequest decodes and sanitizes a persona body, writing the error response on failure
+func (h *Handler) decodePersonaRequest(w http.ResponseWriter, r *http.Request) (*PersonaRequest, bool) {
+	var req PersonaRequest
+	if !h.decodeJSON(w, r, &req) {
+		return nil, false
+	}
+
+	req.Name = validation.SanitizeString(req.Name, 100)
+	if req.Name == "" {
+		h.error(w, "name is required", http.StatusBadRequest)
+		return nil, false
+	}
+	req.Headline = validation.SanitizeString(req.Headline, 200)
+	req.Summary = validation.SanitizeString(req.Summary, 5000)
+	req.TargetKeywords = validation.SanitizeString(req.TargetKeywords, 200)
+
+	skills := make([]string, 0, len(req.Skills))
+	for _, skill := range req.Skills {
+		if skill = validation.SanitizeString(skill, 100); skill != "" {
+			skills = append(skills, skill)
+		}
+	}
+	req.Skills = h.normalizeSkills(r.Context(), skills)
+
+	return &req, true
+}
+
+// getPersona loads one of the user's personas. An empty personaID selects the default
+// persona; if the user has none, (nil, nil) is returned so callers use the base profile.
+func (h *Handler) getPersona(ctx context.Context, userID, personaID string) (*models.Persona, error) {
+	var row pgx.Row
+	if personaID == "" {
+		row = h.db.QueryRow(ctx, `SELECT `+personaColumns+` FROM profile_personas WHERE user_id = $1 AND is_default AND deleted_at IS NULL`, userID)
+	} else {
+		row = h.db.QueryRow(ctx, `SELECT `+personaColumns+` FROM profile_personas WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`, personaID, userID)
+	}
+
+	persona, err := scanPersona(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if personaID == "" {
+			return nil, nil
+		}
+		return nil, errPersonaNotFound
+	}
+	return persona, err
+}
+
+// profileForPersona returns the base profile with the persona's resume and skills layered on top
+func (h *Handler) profileForPersona(ctx context.Context, userID, personaID string) (*models.UserProfile, *models.Persona, error) {
+	profile, err := h.users.Profile(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	persona, err := h.getPersona(ctx, userID, personaID)
+	if err != nil || persona == nil {
+		return profile, nil, err
+	}
+
+	if persona.ResumeURL != nil && *persona.ResumeURL != "" {
+		profile.ResumeURL = persona.ResumeURL
+	}
+	if len(persona.Skills) > 0 {
+		profile.Skills = persona.Skills
+	}
+
+	return profile, persona, nil
+}
+
+func scanPersona(row pgx.Row) (*models.Persona, error) {
+	var p models.Persona
+	var headline, summary, keywords *string
+	err := row.Scan(&p.ID, &p.Name, &headline, &summary, &keywords, &p.ResumeURL, &p.Skills,
+		&p.IsDefault, &p.CreatedAt, &p.UpdatedAt, &p.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+	if headline != nil {
+		p.Headline = *headline
+	}
+	if summary != nil {
+		p.Summary = *summary
+	}
+	if keywords != nil {
+		p.TargetKeywords = *keywords
+	}
+	return &p, nil
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/yourusername/jobapply/internal/apierror"
+	"github.com/yourusername/jobapply/internal/autofill"
+	"github.com/yourusername/jobapply/internal/models"
+)
+
+type AutofillPrivacy struct {
+	NeverAutofill   []string `json:"never_autofill"`
+	AvailableFields []string `json:"available_fields,omitempty"`
+}
+
+// GetAutofillPrivacy returns the profile fields the apply engine must never fill in
+func (h *Handler) GetAutofillPrivacy(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var never []string
+	err := h.db.QueryRow(r.Context(), "SELECT never_autofill FROM user_profiles WHERE id = $1", userID).Scan(&never)
+	if err != nil {
+		h.error(w, "Profile not found", http.StatusNotFound)
+		return
+	}
+	if never == nil {
+		never = []string{}
+	}
+
+	h.json(w, AutofillPrivacy{NeverAutofill: never, AvailableFields: autofill.Fields}, http.StatusOK)
+}
+
+// UpdateAutofillPrivacy replaces the list of never-autofill fields
+func (h *Handler) UpdateAutofillPrivacy(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req AutofillPrivacy
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+
+	never, err := autofill.ValidateFields(req.NeverAutofill)
+	if err != nil {
+		h.fail(w, r, apierror.Invalid(err))
+		return
+	}
+
+	result, err := h.db.Exec(r.Context(),
+		"UPDATE user_profiles SET never_autofill = $1, updated_at = NOW() WHERE id = $2", never, userID)
+	if err != nil {
+		h.internalError(w, r, "Failed to update privacy settings", err)
+		return
+	}
+	if result.RowsAffected() == 0 {
+		h.error(w, "Profile not found", http.StatusNotFound)
+		return
+	}
+
+	h.json(w, AutofillPrivacy{NeverAutofill: never, AvailableFields: autofill.Fields}, http.StatusOK)
+}
+
+// autofillProfile returns the user's profile with their never-autofill fields cleared, as the
+// apply engine may use it
+func (h *Handler) autofillProfile(ctx context.Context, userID string) (*models.UserProfile, error) {
+	profile, err := h.users.Profile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var never []string
+	if err := h.db.QueryRow(ctx, "SELECT never_autofill FROM user_profiles WHERE id = $1", userID).Scan(&never); err != nil {
+		return nil, err
+	}
+	redacted, _ := autofill.Redact(profile, never)
+	return redacted, nil
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/yourusername/jobapply/internal/apierror"
+	"github.com/yourusername/jobapply/internal/models"
+	"github.com/yourusername/jobapply/internal/store"
+	"github.com/yourusername/jobapply/internal/validation"
+)
+
+var errEntryNotFound = errors.New("entry not found")
+
+type WorkHistoryResponse struct {
+	WorkHistory []models.WorkHistory `json:"work_history"`
+	Warnings    []string             `json:"warnings,omitempty"`
+}
+
+type EducationResponse struct {
+	Education []models.Education `json:"education"`
+}
+
+// AddWorkHistory appends a work history entry to the authenticated user's profile
+func (h *Handler) AddWorkHistory(w http.ResponseWriter, r *http.Request) {
+	h.editWorkHistory(w, r, func(list []models.WorkHistory, entry *models.WorkHistory) ([]models.WorkHistory, int, error) {
+		return append(list, *entry), len(list), nil
+	})
+}
+
+// UpdateWorkHistory replaces the work history entry at {idx}
+func (h *Handler) UpdateWorkHistory(w http.ResponseWriter, r *http.Request) {
+	idx, ok := h.entryIndex(w, r)
+	if !ok {
+		return
+	}
+	h.editWorkHistory(w, r, func(list []models.WorkHistory, entry *models.WorkHistory) ([]models.WorkHistory, int, error) {
+		if idx >= len(list) {
+			return nil, 0, errEntryNotFound
+		}
+		list[idx] = *entry
+		return list, idx, nil
+	})
+}
+
+// DeleteWorkHistory removes the work history entry at {idx}
+func (h *Handler) DeleteWorkHistory(w http.ResponseWriter, r *http.Request) {
+	idx, ok := h.entryIndex(w, r)
+	if !ok {
+		return
+	}
+	h.editWorkHistory(w, r, func(list []models.WorkHistory, _ *models.WorkHistory) ([]models.WorkHistory, int, error) {
+		if idx >= len(list) {
+			return nil, 0, errEntryNotFound
+		}
+		return append(list[:idx], list[idx+1:]...), -1, nil
+	})
+}
+
+// AddEducation appends an education entry to the authenticated user's profile
+func (h *Handler) AddEducation(w http.ResponseWriter, r *http.Request) {
+	h.editEducation(w, r, func(list []models.Education, entry *models.Education) ([]models.Education, error) {
+		return append(list, *entry), nil
+	})
+}
+
+// UpdateEducation replaces the education entry at {idx}
+func (h *Handler) UpdateEducation(w http.ResponseWriter, r *http.Request) {
+	idx, ok := h.entryIndex(w, r)
+	if !ok {
+		return
+	}
+	h.editEducation(w, r, func(list []models.Education, entry *models.Education) ([]models.Education, error) {
+		if idx >= len(list) {
+			return nil, errEntryNotFound
+		}
+		list[idx] = *entry
+		return list, nil
+	})
+}
+
+// DeleteEducation removes the education entry at {idx}
+func (h *Handler) DeleteEducation(w http.ResponseWriter, r *http.Request) {
+	idx, ok := h.entryIndex(w, r)
+	if !ok {
+		return
+	}
+	h.editEducation(w, r, func(list []models.Education, _ *models.Education) ([]models.Education, error) {
+		if idx >= len(list) {
+			return nil, errEntryNotFound
+		}
+		return append(list[:idx], list[idx+1:]...), nil
+	})
+}
+
+// editWorkHistory decodes and validates the request entry (except for deletes), applies edit
+// under a row lock and returns the updated list with overlap warnings for the touched entry.
+// edit returns the index of the entry to check for overlaps, or -1 for none.
+func (h *Handler) editWorkHistory(w http.ResponseWriter, r *http.Request,
+	edit func(list []models.WorkHistory, entry *models.WorkHistory) ([]models.WorkHistory, int, error)) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var entry *models.WorkHistory
+	if r.Method != http.MethodDelete {
+		entry = &models.WorkHistory{}
+		if !h.decodeJSON(w, r, entry) {
+			return
+		}
+		*entry = models.WorkHistory{
+			Company:     validation.SanitizeString(entry.Company, 200),
+			Title:       validation.SanitizeString(entry.Title, 200),
+			StartDate:   validation.SanitizeString(entry.StartDate, 10),
+			EndDate:     validation.SanitizeString(entry.EndDate, 10),
+			Description: validation.SanitizeString(entry.Description, 5000),
+		}
+		if err := validation.ValidateWorkHistoryEntry(validation.WorkHistoryEntry{
+			Company:   entry.Company,
+			Title:     entry.Title,
+			StartDate: entry.StartDate,
+			EndDate:   entry.EndDate,
+		}); err != nil {
+			h.fail(w, r, apierror.Invalid(err))
+			return
+		}
+	}
+
+	touched := -1
+	list, err := h.users.UpdateWorkHistory(r.Context(), userID, func(list []models.WorkHistory) ([]models.WorkHistory, error) {
+		var err error
+		list, touched, err = edit(list, entry)
+		return list, err
+	})
+	if !h.handleListError(w, r, err) {
+		return
+	}
+
+	resp := WorkHistoryResponse{WorkHistory: list}
+	if resp.WorkHistory == nil {
+		resp.WorkHistory = []models.WorkHistory{}
+	}
+	if touched >= 0 {
+		current := list[touched]
+		for i, other := range list {
+			if i != touched && validation.DateRangesOverlap(current.StartDate, current.EndDate, other.StartDate, other.EndDate) {
+				resp.Warnings = append(resp.Warnings, fmt.Sprintf("Overlaps with %s at %s", other.Title, other.Company))
+			}
+		}
+	}
+
+	status := http.StatusOK
+	if r.Method == http.MethodPost {
+		status = http.StatusCreated
+	}
+	h.json(w, resp, status)
+}
+
+// editEducation is the education counterpart of editWorkHistory
+func (h *Handler) editEducation(w http.ResponseWriter, r *http.Request,
+	edit func(list []models.Education, entry *models.Education) ([]models.Education, error)) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var entry *models.Education
+	if r.Method != http.MethodDelete {
+		entry = &models.Education{}
+		if !h.decodeJSON(w, r, entry) {
+			return
+		}
+		*entry = models.Education{
+			School:   validation.SanitizeString(entry.School, 200),
+			Degree:   validation.SanitizeString(entry.Degree, 200),
+			Major:    validation.SanitizeString(entry.Major, 200),
+			GradYear: entry.GradYear,
+		}
+		if err := validation.ValidateEducationEntry(entry.School, entry.GradYear); err != nil {
+			h.fail(w, r, apierror.Invalid(err))
+			return
+		}
+	}
+
+	list, err := h.users.UpdateEducation(r.Context(), userID, func(list []models.Education) ([]models.Education, error) {
+		return edit(list, entry)
+	})
+	if !h.handleListError(w, r, err) {
+		return
+	}
+
+	resp := EducationResponse{Education: list}
+	if resp.Education == nil {
+		resp.Education = []models.Education{}
+	}
+
+	status := http.StatusOK
+	if r.Method == http.MethodPost {
+		status = http.StatusCreated
+	}
+	h.json(w, resp, status)
+}
+
+// handleListError writes the error response for a failed list update; returns true if err is nil
+func (h *Handler) handleListError(w http.ResponseWriter, r *http.Request, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, errEntryNotFound):
+		h.error(w, "Entry not found", http.StatusNotFound)
+	case errors.Is(err, store.ErrNotFound):
+		h.error(w, "Profile not found", http.StatusNotFound)
+	default:
+		h.internalError(w, r, "Failed to update profile", err)
+	}
+	return false
+}
+
+// entryIndex parses the {idx} URL parameter
+func (h *Handler) entryIndex(w http.ResponseWriter, r *http.Request) (int, bool) {
+	idx, err := strconv.Atoi(chi.URLParam(r, "idx"))
+	if err != nil || idx < 0 {
+		h.error(w, "Invalid entry index", http.StatusBadRequest)
+		return 0, false
+	}
+	return idx, true
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yourusername/jobapply/internal/logging"
+	"github.com/yourusername/jobapply/internal/store"
+)
+
+// PurgeDeleted permanently removes profiles, applications and jobs that were soft-deleted
+// more than retention ago, along with the profiles' resume files and the applications'
+// posting snapshots. It is run by the scheduler.
+func (h *Handler) PurgeDeleted(ctx context.Context, retention time.Duration) error {
+	cutoff := time.Now().Add(-retention)
+
+	rows, err := h.db.Query(ctx, "DELETE FROM profile_personas WHERE deleted_at < $1 RETURNING resume_url", cutoff)
+	if err != nil {
+		return fmt.Errorf("failed to purge profiles: %w", err)
+	}
+	var resumes []string
+	profiles := 0
+	for rows.Next() {
+		var resumeURL *string
+		if err := rows.Scan(&resumeURL); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to purge profiles: %w", err)
+		}
+		profiles++
+		if resumeURL != nil && *resumeURL != "" {
+			resumes = append(resumes, *resumeURL)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to purge profiles: %w", err)
+	}
+	for _, resumeURL := range resumes {
+		h.releaseUpload(ctx, uploadKey(resumeURL))
+	}
+
+	rows, err = h.db.Query(ctx, "DELETE FROM applications WHERE deleted_at < $1 RETURNING snapshot_key", cutoff)
+	if err != nil {
+		return fmt.Errorf("failed to purge applications: %w", err)
+	}
+	snapshots, err := pgx.CollectRows(rows, pgx.RowTo[*string])
+	if err != nil {
+		return fmt.Errorf("failed to purge applications: %w", err)
+	}
+	for _, key := range snapshots {
+		if key != nil {
+			h.storage.Delete(ctx, *key) // Ignore errors - file might not exist
+		}
+	}
+
+	// Jobs are only deleted while nothing refers to them, but check again in case that changed
+	jobs, err := h.db.Exec(ctx, `
+		DELETE FROM jobs
+		WHERE deleted_at < $1 AND NOT `+store.ArchivedJobSQL+`
+	`, cutoff)
+	if err != nil {
+		return fmt.Errorf("failed to purge jobs: %w", err)
+	}
+
+	logging.FromContext(ctx).Info("Deleted records purged",
+		"profiles", profiles, "applications", len(snapshots), "jobs", jobs.RowsAffected())
+	return nil
+}
@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/jobapply/internal/apierror"
+	"github.com/yourusername/jobapply/internal/logging"
+	"github.com/yourusername/jobapply/internal/models"
+	"github.com/yourusername/jobapply/internal/resume"
+	"github.com/yourusername/jobapply/internal/storage"
+	"github.com/yourusername/jobapply/internal/validation"
+)
+
+var errNoResume = errors.New("no resume uploaded")
+
+// maxImportSize bounds LinkedIn archives; the profile-only export is well under this
+const maxImportSize = 10 << 20
+
+// SetMaxResumePages rejects uploaded resumes with more pages; 0 allows any number
+func (h *Handler) SetMaxResumePages(n int) {
+	h.maxResumePages = n
+}
+
+// missingTextWarning explains that an uploaded resume has no text layer, which usually means
+// it is a scanned image. It returns "" if there is text or the check couldn't run.
+func (h *Handler) missingTextWarning(ctx context.Context, data []byte) string {
+	path, cleanup, err := writeTempPDF(data)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Resume text check failed", "error", err)
+		return ""
+	}
+	defer cleanup()
+
+	hasText, err := h.resumeParser.HasText(ctx, path)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Resume text check failed", "error", err)
+		return ""
+	}
+	if hasText {
+		return ""
+	}
+	return "No text could be read from this PDF; it is probably a scanned image. Parsing will be less accurate, and employers' applicant tracking systems may not be able to read it either."
+}
+
+// writeTempPDF saves data to a temp file for the PDF tools; cleanup must always be called
+func writeTempPDF(data []byte) (path string, cleanup func(), err error) {
+	tmp, err := os.CreateTemp("", "upload-*.pdf")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup = func() { os.Remove(tmp.Name()) }
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return tmp.Name(), cleanup, nil
+}
+
+// ParseResume extracts structured data from the authenticated user's uploaded resume.
+// Nothing is written to the profile; the result is returned for the user to review.
+func (h *Handler) ParseResume(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	_, parsed, ok := h.parseUserResume(w, r, userID)
+	if !ok {
+		return
+	}
+
+	h.json(w, parsed, http.StatusOK)
+}
+
+// ReviewResume parses the uploaded resume and returns it as a diff against the current profile
+func (h *Handler) ReviewResume(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	profile, parsed, ok := h.parseUserResume(w, r, userID)
+	if !ok {
+		return
+	}
+
+	h.json(w, resume.Diff(profile, parsed), http.StatusOK)
+}
+
+// ConfirmProfileMerge applies only the imported changes the user accepted during review.
+// Used for both resume parsing and LinkedIn imports.
+func (h *Handler) ConfirmProfileMerge(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.ProfileMergeRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+
+	h.applyMerge(w, r, userID, &req)
+}
+
+// applyMerge merges accepted changes into the stored profile, refusing if the profile
+// changed after the diff was generated (replace indexes would point at the wrong entries)
+func (h *Handler) applyMerge(w http.ResponseWriter, r *http.Request, userID string, req *models.ProfileMergeRequest) {
+	profile, err := h.users.Profile(r.Context(), userID)
+	if err != nil {
+		h.error(w, "Profile not found", http.StatusNotFound)
+		return
+	}
+
+	if !req.ProfileUpdatedAt.IsZero() && !req.ProfileUpdatedAt.Equal(profile.UpdatedAt) {
+		h.error(w, "Profile changed since the review was generated; please review again", http.StatusConflict)
+		return
+	}
+
+	if err := resume.ApplyMerge(profile, req); err != nil {
+		h.fail(w, r, apierror.Invalid(err))
+		return
+	}
+
+	updated, err := h.saveProfile(r.Context(), userID, profile)
+	if err != nil {
+		h.internalError(w, r, "Failed to update profile", err)
+		return
+	}
+
+	h.json(w, *updated, http.StatusOK)
+}
+
+// parseUserResume loads the profile and parses its resume, writing the error response on failure
+func (h *Handler) parseUserResume(w http.ResponseWriter, r *http.Request, userID string) (*models.UserProfile, *models.ParsedResume, bool) {
+	profile, err := h.users.Profile(r.Context(), userID)
+	if err != nil {
+		h.error(w, "Profile not found", http.StatusNotFound)
+		return nil, nil, false
+	}
+
+	// OCR of a multi-page scan can take a while; don't let it run unbounded
+	ctx, cancel := context.WithTimeout(r.Context(), 25*time.Second)
+	defer cancel()
+
+	filePath, cleanup, err := h.resumePath(ctx, profile)
+	if err != nil {
+		if errors.Is(err, errNoResume) || errors.Is(err, storage.ErrNotFound) {
+			h.error(w, "Upload a resume before parsing", http.StatusBadRequest)
+			return nil, nil, false
+		}
+		h.internalError(w, r, "Failed to read resume", err)
+		return nil, nil, false
+	}
+	defer cleanup()
+
+	parsed, err := h.resumeParser.Parse(ctx, filePath, r.URL.Query().Get("backend"))
+	if errors.Is(err, resume.ErrUnknownBackend) {
+		h.error(w, "Unknown parser backend", http.StatusBadRequest)
+		return nil, nil, false
+	}
+	if err != nil {
+		logging.FromContext(r.Context()).Warn("Resume parse failed", "error", err)
+		h.error(w, "Failed to parse resume", http.StatusUnprocessableEntity)
+		return nil, nil, false
+	}
+
+	return profile, parsed, true
+}
+
+// resumePath makes the profile's uploaded resume available as a local file, downloading
+// it from remote storage if needed. cleanup must be called once the file is no longer used.
+func (h *Handler) resumePath(ctx context.Context, profile *models.UserProfile) (path string, cleanup func(), err error) {
+	if profile.ResumeURL == nil || *profile.ResumeURL == "" {
+		return "", nil, errNoResume
+	}
+	return storage.Fetch(ctx, h.storage, uploadKey(*profile.ResumeURL))
+}
+
+// ImportLinkedIn accepts a LinkedIn data export ZIP and returns it as a diff against the
+// current profile. Accepted changes are applied through ConfirmProfileMerge.
+func (h *Handler) ImportLinkedIn(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseMultipartForm(maxImportSize); err != nil {
+		h.error(w, "File too large or invalid request", http.StatusBadRequest)
+		return
+	}
+
+	file, header, err := r.FormFile("archive")
+	if err != nil {
+		h.error(w, "Failed to read file", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	if !validation.ValidateFileExtension(validation.SanitizeFilename(header.Filename), []string{".zip"}) {
+		h.error(w, "Only ZIP archives allowed", http.StatusBadRequest)
+		return
+	}
+
+	parsed, err := resume.ParseLinkedInArchive(file, header.Size)
+	if err != nil {
+		if errors.Is(err, resume.ErrNotLinkedInArchive) {
+			h.error(w, "Archive does not contain LinkedIn profile data", http.StatusUnprocessableEntity)
+			return
+		}
+		h.error(w, "Invalid LinkedIn archive", http.StatusBadRequest)
+		return
+	}
+
+	profile, err := h.users.Profile(r.Context(), userID)
+	if err != nil {
+		h.error(w, "Profile not found", http.StatusNotFound)
+		return
+	}
+
+	h.json(w, resume.Diff(profile, parsed), http.StatusOK)
+}
+
+// GenerateResume renders the authenticated user's profile as a downloadable PDF resume
+func (h *Handler) GenerateResume(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	templateName := r.URL.Query().Get("template")
+	if templateName == "" {
+		templateName = "modern"
+	}
+
+	profile, err := h.users.Profile(r.Context(), userID)
+	if err != nil {
+		h.error(w, "Profile not found", http.StatusNotFound)
+		return
+	}
+
+	pdf, err := resume.BuildPDF(profile, templateName)
+	if err != nil {
+		if errors.Is(err, resume.ErrUnknownTemplate) {
+			h.error(w, fmt.Sprintf("Unknown template (available: %s)", strings.Join(resume.TemplateNames(), ", ")), http.StatusBadRequest)
+			return
+		}
+		h.internalError(w, r, "Failed to generate resume", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `attachment; filename="resume.pdf"`)
+	w.Header().Set("Content-Length", strconv.Itoa(len(pdf)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(pdf)
+}
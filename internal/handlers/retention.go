@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yourusername/jobapply/internal/logging"
+	"github.com/yourusername/jobapply/internal/storage"
+	"github.com/yourusername/jobapply/internal/store"
+)
+
+// retentionSampleSize caps the upload keys listed in a report
+const retentionSampleSize = 20
+
+// RetentionPolicy says how long data is kept. A zero duration keeps it forever.
+type RetentionPolicy struct {
+	// ClosedJobs is how long a closed posting is kept once closed. Jobs someone saved, tagged
+	// or applied to are kept regardless.
+	ClosedJobs time.Duration
+	// RejectedApplications is how long after applying an application the employer turned
+	// down keeps its answers and autofill details. Its status and dates stay for the stats.
+	RejectedApplications time.Duration
+	// OrphanUploads is how old an upload no profile refers to must be before it is deleted,
+	// so files whose upload is still being saved are left alone
+	OrphanUploads time.Duration
+}
+
+// SetRetention sets the policy the retention task applies
+func (h *Handler) SetRetention(p RetentionPolicy) {
+	h.retention = p
+}
+
+// RetentionReport is what a retention run removed, or with DryRun, would remove
+type RetentionReport struct {
+	DryRun               bool  `json:"dry_run"`
+	ClosedJobs           int64 `json:"closed_jobs"`
+	RejectedApplications int64 `json:"rejected_applications"`
+	OrphanUploads        int   `json:"orphan_uploads"`
+	OrphanUploadBytes    int64 `json:"orphan_upload_bytes"`
+	// OrphanUploadKeys lists the first few orphaned uploads
+	OrphanUploadKeys []string `json:"orphan_upload_keys,omitempty"`
+	// UploadsSkipped is set when the storage backend can't list its files
+	UploadsSkipped bool `json:"uploads_skipped,omitempty"`
+}
+
+// closedJobsSQL matches closed, unarchived jobs closed before $1
+const closedJobsSQL = `status = 'closed' AND COALESCE(closed_at, status_checked_at) < $1
+	AND NOT ` + store.ArchivedJobSQL
+
+// rejectedApplicationsSQL matches applications turned down before $1 that still hold details
+const rejectedApplicationsSQL = `anonymized_at IS NULL
+	AND external_status ~* '(reject|declin|not selected|unsuccessful)'
+	AND COALESCE(applied_at, created_at) < $1`
+
+// ApplyRetention runs the retention policy. It is run by the scheduler.
+func (h *Handler) ApplyRetention(ctx context.Context) error {
+	report, err := h.retentionRun(ctx, false)
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("Retention policy applied",
+		"closed_jobs", report.ClosedJobs, "rejected_applications", report.RejectedApplications,
+		"orphan_uploads", report.OrphanUploads, "uploads_skipped", report.UploadsSkipped)
+	return nil
+}
+
+// GetRetentionReport shows what the retention task would remove now, without removing it
+func (h *Handler) GetRetentionReport(w http.ResponseWriter, r *http.Request) {
+	report, err := h.retentionRun(r.Context(), true)
+	if err != nil {
+		h.internalError(w, r, "Failed to build retention report", err)
+		return
+	}
+	h.json(w, report, http.StatusOK)
+}
+
+func (h *Handler) retentionRun(ctx context.Context, dryRun bool) (*RetentionReport, error) {
+	report := &RetentionReport{DryRun: dryRun}
+	now := time.Now()
+
+	if p := h.retention.ClosedJobs; p > 0 {
+		n, err := h.retentionApply(ctx, dryRun, "jobs", closedJobsSQL, `
+			WITH deleted AS (DELETE FROM jobs WHERE `+closedJobsSQL+` RETURNING 1)
+			SELECT COUNT(*) FROM deleted
+		`, now.Add(-p))
+		if err != nil {
+			return nil, fmt.Errorf("failed to purge closed jobs: %w", err)
+		}
+		report.ClosedJobs = n
+	}
+
+	if p := h.retention.RejectedApplications; p > 0 {
+		// Answers live in application_questions too; they go in the same statement
+		n, err := h.retentionApply(ctx, dryRun, "applications", rejectedApplicationsSQL, `
+			WITH anonymized AS (
+				UPDATE applications
+				SET filled_fields = NULL, omitted_fields = NULL, custom_questions = NULL, user_answers = NULL,
+					error_log = NULL, current_url = NULL, persona_id = NULL, anonymized_at = NOW()
+				WHERE `+rejectedApplicationsSQL+`
+				RETURNING id
+			), questions AS (
+				DELETE FROM application_questions WHERE application_id IN (SELECT id FROM anonymized)
+			)
+			SELECT COUNT(*) FROM anonymized
+		`, now.Add(-p))
+		if err != nil {
+			return nil, fmt.Errorf("failed to anonymize rejected applications: %w", err)
+		}
+		report.RejectedApplications = n
+	}
+
+	if p := h.retention.OrphanUploads; p > 0 {
+		if err := h.orphanUploads(ctx, dryRun, now.Add(-p), report); err != nil {
+			return nil, fmt.Errorf("failed to delete orphaned uploads: %w", err)
+		}
+	}
+	return report, nil
+}
+
+// retentionApply counts the rows of table matching where, or runs apply, which must select
+// the number of rows it changed. Both take the cutoff as $1.
+func (h *Handler) retentionApply(ctx context.Context, dryRun bool, table, where, apply string, cutoff time.Time) (int64, error) {
+	if dryRun {
+		apply = `SELECT COUNT(*) FROM ` + table + ` WHERE ` + where
+	}
+	var n int64
+	err := h.db.QueryRow(ctx, apply, cutoff).Scan(&n)
+	return n, err
+}
+
+// orphanUploads deletes uploads last modified and last reused before cutoff that no profile,
+// persona or application refers to. Deleted personas count, since they can be restored; the purge removes
+// their files.
+func (h *Handler) orphanUploads(ctx context.Context, dryRun bool, cutoff time.Time, report *RetentionReport) error {
+	lister, ok := h.storage.(storage.Lister)
+	if !ok {
+		report.UploadsSkipped = true
+		return nil
+	}
+
+	// Read the references before listing, so files referenced since are too new to match
+	rows, err := h.db.Query(ctx, `
+		SELECT resume_url FROM user_profiles WHERE resume_url IS NOT NULL
+		UNION SELECT resume_url FROM profile_personas WHERE resume_url IS NOT NULL
+		UNION SELECT '/uploads/' || key FROM uploads WHERE last_used_at >= $1
+		UNION SELECT '/uploads/' || preview_key FROM uploads WHERE preview_key IS NOT NULL
+		UNION SELECT '/uploads/' || snapshot_key FROM applications WHERE snapshot_key IS NOT NULL
+	`, cutoff)
+	if err != nil {
+		return err
+	}
+	referenced, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (string, error) {
+		var url string
+		err := row.Scan(&url)
+		return uploadKey(url), err
+	})
+	if err != nil {
+		return err
+	}
+	keep := make(map[string]bool, len(referenced))
+	for _, key := range referenced {
+		keep[key] = true
+	}
+
+	var orphans []storage.Object
+	err = lister.List(ctx, func(obj storage.Object) error {
+		if !keep[obj.Key] && obj.Modified.Before(cutoff) {
+			orphans = append(orphans, obj)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range orphans {
+		if !dryRun {
+			if _, err := h.db.Exec(ctx, "DELETE FROM uploads WHERE key = $1", obj.Key); err != nil {
+				return err
+			}
+			if err := h.storage.Delete(ctx, obj.Key); err != nil {
+				return err
+			}
+		}
+		report.OrphanUploads++
+		report.OrphanUploadBytes += obj.Size
+		if len(report.OrphanUploadKeys) < retentionSampleSize {
+			report.OrphanUploadKeys = append(report.OrphanUploadKeys, obj.Key)
+		}
+	}
+	return nil
+}
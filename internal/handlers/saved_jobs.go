@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/yourusername/jobapply/internal/store"
+	"github.com/yourusername/jobapply/internal/validation"
+)
+
+const maxSavedJobPriority = 5
+
+type SaveJobRequest struct {
+	Notes    string `json:"notes"`
+	Priority int    `json:"priority"` // 0-5, higher sorts first
+}
+
+// Validate checks the priority and sanitizes the notes
+func (req *SaveJobRequest) Validate() error {
+	var v validation.Collector
+	v.Check(req.Priority >= 0 && req.Priority <= maxSavedJobPriority, "priority",
+		fmt.Sprintf("priority must be between 0 and %d", maxSavedJobPriority))
+	req.Notes = validation.SanitizeString(req.Notes, 2000)
+	return v.Err()
+}
+
+// SaveJob adds a job to the user's shortlist, or updates its notes and priority if already saved
+func (h *Handler) SaveJob(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	jobID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, jobID, "job ID") {
+		return
+	}
+
+	// The body is optional; an empty POST just saves the job
+	var req SaveJobRequest
+	if !h.decodeOptionalJSON(w, r, &req) {
+		return
+	}
+
+	if err := h.jobs.SaveJob(r.Context(), userID, jobID, req.Notes, req.Priority); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			h.error(w, "Job not found", http.StatusNotFound)
+			return
+		}
+		h.internalError(w, r, "Failed to save job", err)
+		return
+	}
+
+	h.json(w, map[string]string{"message": "Job saved"}, http.StatusOK)
+}
+
+// UnsaveJob removes a job from the user's shortlist
+func (h *Handler) UnsaveJob(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	jobID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, jobID, "job ID") {
+		return
+	}
+
+	if err := h.jobs.UnsaveJob(r.Context(), userID, jobID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			h.error(w, "Saved job not found", http.StatusNotFound)
+			return
+		}
+		h.internalError(w, r, "Failed to remove saved job", err)
+		return
+	}
+
+	h.json(w, map[string]string{"message": "Job removed from saved list"}, http.StatusOK)
+}
+
+// GetSavedJobs returns the user's shortlist, highest priority first, optionally filtered by ?tag=
+func (h *Handler) GetSavedJobs(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var tags []string
+	for _, tag := range r.URL.Query()["tag"] {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	saved, err := h.jobs.SavedJobs(r.Context(), userID, tags)
+	if err != nil {
+		h.internalError(w, r, "Failed to get saved jobs", err)
+		return
+	}
+
+	h.json(w, saved, http.StatusOK)
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/jobapply/internal/logging"
+	"github.com/yourusername/jobapply/internal/scrapers"
+)
+
+const (
+	// rejectFieldLimit truncates stored reject fields
+	rejectFieldLimit = 2000
+	// scrapeRejectRetention is how long rejects are kept for inspection
+	scrapeRejectRetention = 30 * 24 * time.Hour
+
+	defaultScrapeRejectsLimit = 50
+	maxScrapeRejectsLimit     = 500
+)
+
+// ScrapeReject is a scraped job that failed validation
+type ScrapeReject struct {
+	ID         string    `json:"id"`
+	Site       string    `json:"site"`
+	Title      string    `json:"title"`
+	Company    string    `json:"company"`
+	Location   string    `json:"location"`
+	URL        string    `json:"url"`
+	Reason     string    `json:"reason"`
+	RejectedAt time.Time `json:"rejected_at"`
+}
+
+// quarantineInvalid splits out the jobs that fail validation and stores them in scrape_rejects,
+// returning the rest. A failure to store the rejects is only logged; they are dropped either way.
+func (h *Handler) quarantineInvalid(ctx context.Context, site, searchHash string, jobs []scrapers.Job) []scrapers.Job {
+	valid := jobs[:0:0]
+	var titles, companies, locations, urls, reasons []string
+	for _, job := range jobs {
+		problems := job.Problems()
+		if len(problems) == 0 {
+			valid = append(valid, job)
+			continue
+		}
+		titles = append(titles, truncate(job.Title, rejectFieldLimit))
+		companies = append(companies, truncate(job.Company, rejectFieldLimit))
+		locations = append(locations, truncate(job.Location, rejectFieldLimit))
+		urls = append(urls, truncate(job.URL, rejectFieldLimit))
+		reasons = append(reasons, strings.Join(problems, "; "))
+	}
+	if len(reasons) == 0 {
+		return valid
+	}
+
+	logger := logging.FromContext(ctx)
+	logger.Warn("Scraped jobs failed validation", "site", site, "rejected", len(reasons), "accepted", len(valid))
+	_, err := h.db.Exec(ctx, `
+		INSERT INTO scrape_rejects (site, search_params_hash, title, company, location, url, reason)
+		SELECT $1, $2, title, company, location, url, reason
+		FROM unnest($3::text[], $4::text[], $5::text[], $6::text[], $7::text[]) AS r(title, company, location, url, reason)
+	`, site, searchHash, titles, companies, locations, urls, reasons)
+	if err != nil {
+		logger.Error("Failed to store scrape rejects", "error", err)
+	}
+	return valid
+}
+
+func truncate(s string, max int) string {
+	if r := []rune(s); len(r) > max {
+		return string(r[:max])
+	}
+	return s
+}
+
+// ListScrapeRejects returns the most recently rejected scraped jobs, to spot sources whose
+// markup has changed
+func (h *Handler) ListScrapeRejects(w http.ResponseWriter, r *http.Request) {
+	limit := defaultScrapeRejectsLimit
+	if l := r.URL.Query().Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 || n > maxScrapeRejectsLimit {
+			h.error(w, fmt.Sprintf("limit must be between 1 and %d", maxScrapeRejectsLimit), http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	args := []any{limit}
+	where := "TRUE"
+	if site := r.URL.Query().Get("site"); site != "" {
+		args = append(args, site)
+		where = "site = $2"
+	}
+	rows, err := h.db.Query(r.Context(), `
+		SELECT id, site, title, company, location, url, reason, rejected_at
+		FROM scrape_rejects WHERE `+where+`
+		ORDER BY rejected_at DESC LIMIT $1
+	`, args...)
+	if err != nil {
+		h.internalError(w, r, "Failed to list scrape rejects", err)
+		return
+	}
+	defer rows.Close()
+
+	rejects := []ScrapeReject{}
+	for rows.Next() {
+		var rej ScrapeReject
+		if err := rows.Scan(&rej.ID, &rej.Site, &rej.Title, &rej.Company, &rej.Location, &rej.URL, &rej.Reason, &rej.RejectedAt); err != nil {
+			h.internalError(w, r, "Failed to list scrape rejects", err)
+			return
+		}
+		rejects = append(rejects, rej)
+	}
+	h.json(w, rejects, http.StatusOK)
+}
@@ -1,53 +1,118 @@
 package handlers
 
 import (
-	"crypto/sha256"
-	"encoding/json"
+	"context"
 	"fmt"
-	"log"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/yourusername/jobapply/internal/apierror"
+	"github.com/yourusername/jobapply/internal/events"
+	"github.com/yourusername/jobapply/internal/logging"
+	"github.com/yourusername/jobapply/internal/salary"
 	"github.com/yourusername/jobapply/internal/scrapers"
+	"github.com/yourusername/jobapply/internal/services"
+	"github.com/yourusername/jobapply/internal/store"
+	"github.com/yourusername/jobapply/internal/tracing"
+	"github.com/yourusername/jobapply/internal/validation"
 )
 
 type ScrapeRequest struct {
-	Keywords string `json:"keywords"`
-	Location string `json:"location"`
+	Keywords  string `json:"keywords"`
+	Location  string `json:"location"`
+	ProfileID string `json:"profile_id"` // Optional persona whose target keywords are used when keywords is empty
+	// ForceRefresh scrapes even when the search is cached, refreshing the cache
+	ForceRefresh bool `json:"force_refresh"`
+}
+
+const maxSearchTermLength = 200
+
+// Validate trims the search terms and checks them. Keywords may be left to the persona.
+func (req *ScrapeRequest) Validate() error {
+	var v validation.Collector
+	req.Keywords = strings.TrimSpace(req.Keywords)
+	req.Location = strings.TrimSpace(req.Location)
+	if req.ProfileID == "" {
+		v.Required("keywords", req.Keywords)
+	} else {
+		v.Check(validation.ValidateUUID(req.ProfileID), "profile_id", "Invalid profile ID format")
+	}
+	v.Check(len(req.Keywords) <= maxSearchTermLength, "keywords", fmt.Sprintf("keywords must be at most %d characters", maxSearchTermLength))
+	if v.Required("location", req.Location) {
+		v.Check(len(req.Location) <= maxSearchTermLength, "location", fmt.Sprintf("location must be at most %d characters", maxSearchTermLength))
+	}
+	return v.Err()
 }
 
 type ScrapeResponse struct {
-	JobsScraped int  `json:"jobs_scraped"`
-	FromCache   bool `json:"from_cache"`
+	JobsScraped int `json:"jobs_scraped"` // Jobs stored by this scrape, or found in the cache
+	// What a fresh scrape did: jobs new to the database, known jobs refreshed, and jobs dropped
+	// as incomplete or duplicates. All zero for cached results.
+	JobsInserted int `json:"jobs_inserted"`
+	JobsUpdated  int `json:"jobs_updated"`
+	JobsSkipped  int `json:"jobs_skipped"`
+	// Jobs that failed validation and were quarantined instead of stored
+	JobsRejected int  `json:"jobs_rejected"`
+	FromCache    bool `json:"from_cache"`
 }
 
 // ScrapeJobs handles the POST /api/v1/scrape endpoint with caching
 func (h *Handler) ScrapeJobs(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	var req ScrapeRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.error(w, "Invalid request body", http.StatusBadRequest)
+	if !h.decodeJSON(w, r, &req) {
 		return
 	}
 
-	if req.Keywords == "" || req.Location == "" {
-		h.error(w, "keywords and location are required", http.StatusBadRequest)
+	if req.ProfileID != "" && req.Keywords == "" {
+		persona, err := h.getPersona(r.Context(), userID, req.ProfileID)
+		if err != nil {
+			h.error(w, "Profile not found", http.StatusNotFound)
+			return
+		}
+		req.Keywords = persona.TargetKeywords
+		if req.Keywords == "" {
+			h.fail(w, r, apierror.Invalid(validation.Errors{{Field: "keywords", Message: "keywords are required; the profile has no target keywords"}}))
+			return
+		}
+	}
+
+	// Shutdown waits for scrapes already under way, but doesn't let new ones start
+	done, err := h.work.Begin()
+	if err != nil {
+		h.error(w, "Server is shutting down, try again shortly", http.StatusServiceUnavailable)
 		return
 	}
+	defer done()
 
-	// Generate cache key from search params
-	searchHash := generateSearchHash(req.Keywords, req.Location)
+	searchHash := services.SearchKey(req.Keywords, req.Location)
+	logger := logging.FromContext(r.Context()).With("keywords", req.Keywords, "location", req.Location)
 
-	// Check cache first (jobs < 12 hours old)
-	cacheQuery := `
-		SELECT COUNT(*)
-		FROM jobs
-		WHERE search_params_hash = $1
-		AND cached_at > NOW() - INTERVAL '12 hours'
-	`
+	// Check the cache first, unless the client asked for fresh results
 	var cachedCount int
-	err := h.db.QueryRow(r.Context(), cacheQuery, searchHash).Scan(&cachedCount)
-
+	if !req.ForceRefresh {
+		cachedCount, err = h.scrapeCache.Fresh(r.Context(), "muse", searchHash)
+	}
 	if err == nil && cachedCount > 0 {
-		log.Printf("Cache hit for search: %s in %s (%d jobs)", req.Keywords, req.Location, cachedCount)
+		logger.Info("Scrape cache hit", "jobs", cachedCount)
+
+		// The jobs are shared, but each user only sees the ones their own searches found,
+		// minus anything on their blocklist
+		if _, err := h.db.Exec(r.Context(), `
+			INSERT INTO user_jobs (user_id, job_id)
+			SELECT $1, id FROM jobs WHERE site = 'muse' AND search_params_hash = $2 AND deleted_at IS NULL AND NOT `+blockedJobSQL("$1")+`
+			ON CONFLICT DO NOTHING
+		`, userID, searchHash); err != nil {
+			h.internalError(w, r, "Failed to store jobs", err)
+			return
+		}
+
 		h.json(w, ScrapeResponse{
 			JobsScraped: cachedCount,
 			FromCache:   true,
@@ -56,52 +121,148 @@ func (h *Handler) ScrapeJobs(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Cache miss - fetch from Muse API
-	log.Printf("Cache miss - calling Muse API for: %s in %s", req.Keywords, req.Location)
+	logger.Info("Scrape cache miss, calling Muse API", "force_refresh", req.ForceRefresh)
 
+	// The request's context is passed down, so a client that gives up stops the scrape too
+	scrapeCtx, span := tracing.Start(r.Context(), "muse.scrape", tracing.KindClient,
+		"scrape.keywords", req.Keywords, "scrape.location", req.Location)
 	scraper := scrapers.NewMuseScraper()
-	jobs, err := scraper.Scrape(req.Keywords, req.Location)
+	jobs, err := scraper.Scrape(scrapeCtx, req.Keywords, req.Location)
+	span.SetAttributes("scrape.jobs", len(jobs))
+	span.RecordError(err)
+	span.End()
+	if err != nil && r.Context().Err() != nil {
+		logger.Info("Scrape cancelled by the client")
+		return
+	}
 	if err != nil {
-		log.Printf("Scraping error: %v", err)
-		h.error(w, "Scraping failed: "+err.Error(), http.StatusInternalServerError)
+		h.publish(context.WithoutCancel(r.Context()), events.ScrapeFailed, userID, events.ScrapeFailedData{
+			Site: "muse", Keywords: req.Keywords, Location: req.Location, Error: err.Error(),
+		})
+		h.fail(w, r, apierror.Wrap(err, http.StatusBadGateway, "Job source is unavailable, try again later"))
 		return
 	}
 
-	log.Printf("Scraped %d jobs from Muse API", len(jobs))
+	logger.Info("Scraped jobs from Muse API", "jobs", len(jobs))
+
+	// Once fetched, the jobs are stored even if the client goes away, so its retry hits the cache
+	// instead of scraping again
+	ctx := context.WithoutCancel(r.Context())
 
-	// Insert jobs with cache metadata
-	insertQuery := `
-		INSERT INTO jobs (site, title, company, location, url, search_params_hash, cached_at)
-		VALUES ($1, $2, $3, $4, $5, $6, NOW())
-		ON CONFLICT (url) DO UPDATE SET
-			search_params_hash = EXCLUDED.search_params_hash,
-			cached_at = NOW()
-	`
+	fetched := len(jobs)
+	jobs = h.quarantineInvalid(ctx, "muse", searchHash, jobs)
 
-	jobsInserted := 0
+	// Companies are upserted first so the jobs can reference them
+	scraped := make([]store.ScrapedJob, 0, len(jobs))
+	locations := make([]string, 0, len(jobs))
+	companies := make(map[string]*string)
 	for _, job := range jobs {
-		_, err := h.db.Exec(r.Context(), insertQuery,
-			"muse", job.Title, job.Company, job.Location, job.URL, searchHash)
-		if err == nil {
-			jobsInserted++
+		locations = append(locations, job.Location)
+		companyID, ok := companies[job.Company]
+		if !ok {
+			companyID = h.upsertCompany(ctx, job.Company, job.CompanyRef)
+			companies[job.Company] = companyID
+		}
+		var description *string
+		if job.Description != "" {
+			description = &job.Description
 		}
+		var agency *string
+		if job.Agency != "" {
+			agency = &job.Agency
+		}
+		scraped = append(scraped, store.ScrapedJob{
+			Site: "muse", Title: job.Title, Company: job.Company, Location: job.Location, URL: job.URL,
+			Description: description, PostedAt: job.PostedAt, Pay: parseSalary(job.Salary), CompanyID: companyID,
+			Agency: agency,
+		})
+	}
+
+	result, err := h.jobs.UpsertJobs(ctx, searchHash, scraped)
+	if err != nil {
+		h.internalError(w, r, "Failed to store jobs", err)
+		return
+	}
+	if _, err := h.db.Exec(ctx, `
+		INSERT INTO user_jobs (user_id, job_id)
+		SELECT $1, id FROM jobs WHERE id = ANY($2::uuid[]) AND NOT `+blockedJobSQL("$1")+`
+		ON CONFLICT DO NOTHING
+	`, userID, result.IDs); err != nil {
+		h.internalError(w, r, "Failed to store jobs", err)
+		return
 	}
 
-	// Clean up old cached entries (> 24 hours)
-	deleteOldQuery := `
-		DELETE FROM jobs
-		WHERE cached_at < NOW() - INTERVAL '24 hours'
-	`
-	h.db.Exec(r.Context(), deleteOldQuery)
+	// Dropped if shutdown has begun; later scrapes retry anything left ungeocoded or unenriched
+	h.work.Go(func(ctx context.Context) { h.geocodeJobLocations(ctx, locations) })
+	h.work.Go(h.enrichCompanies)
+
+	logger.Info("Scrape finished", "jobs_inserted", result.Inserted, "jobs_updated", result.Updated, "jobs_skipped", result.Skipped)
+	h.publish(ctx, events.ScrapeCompleted, userID, events.ScrapeCompletedData{
+		Site: "muse", Keywords: req.Keywords, Location: req.Location,
+		Inserted: result.Inserted, Updated: result.Updated, Rejected: fetched - len(jobs),
+	})
 
 	h.json(w, ScrapeResponse{
-		JobsScraped: jobsInserted,
-		FromCache:   false,
+		JobsScraped:  len(result.IDs),
+		JobsInserted: result.Inserted,
+		JobsUpdated:  result.Updated,
+		JobsSkipped:  result.Skipped,
+		JobsRejected: fetched - len(jobs),
+		FromCache:    false,
 	}, http.StatusOK)
 }
 
-// generateSearchHash creates a unique hash for caching
-func generateSearchHash(keywords, location string) string {
-	data := fmt.Sprintf("%s|%s", keywords, location)
-	hash := sha256.Sum256([]byte(data))
-	return fmt.Sprintf("%x", hash)
+// CleanScrapeCache soft-deletes scraped jobs past their source's cache expiry, keeping
+// archived jobs (see store.ArchivedJobSQL). A later scrape finding them again revives them;
+// otherwise PurgeDeleted removes them. Scrape rejects older than 30 days are deleted too. It
+// is run by the scheduler.
+func (h *Handler) CleanScrapeCache(ctx context.Context) error {
+	deleted, err := h.scrapeCache.Expire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete stale jobs: %w", err)
+	}
+	rejects, err := h.db.Exec(ctx, "DELETE FROM scrape_rejects WHERE rejected_at < $1", time.Now().Add(-scrapeRejectRetention))
+	if err != nil {
+		return fmt.Errorf("failed to delete old scrape rejects: %w", err)
+	}
+	logging.FromContext(ctx).Info("Scrape cache cleaned", "jobs_deleted", deleted, "rejects_deleted", rejects.RowsAffected())
+	return nil
+}
+
+// SetScrapeCache replaces the scrape cache, to configure its TTLs
+func (h *Handler) SetScrapeCache(c *services.ScrapeCache) {
+	h.scrapeCache = c
+}
+
+// InvalidateScrapeCache handles DELETE /api/v1/admin/scrape/cache?keywords=&location=. The
+// next scrape of that search fetches fresh results for everyone, so it is admin-only; users
+// refresh their own scrape with force_refresh. Jobs already found stay listed.
+func (h *Handler) InvalidateScrapeCache(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	req := ScrapeRequest{Keywords: r.URL.Query().Get("keywords"), Location: r.URL.Query().Get("location")}
+	if err := req.Validate(); err != nil {
+		h.fail(w, r, apierror.Invalid(err))
+		return
+	}
+
+	invalidated, err := h.scrapeCache.Invalidate(r.Context(), "muse", services.SearchKey(req.Keywords, req.Location))
+	if err != nil {
+		h.internalError(w, r, "Failed to invalidate scrape cache", err)
+		return
+	}
+	h.json(w, map[string]int64{"jobs_invalidated": invalidated}, http.StatusOK)
+}
+
+// parseSalary normalizes scraped salary text for storage; everything is NULL if it can't be parsed
+func parseSalary(text string) store.Salary {
+	rng, ok := salary.Parse(text)
+	if !ok {
+		return store.Salary{}
+	}
+	return store.Salary{Min: &rng.Min, Max: &rng.Max, Currency: &rng.Currency, Period: &rng.Period, Text: &text}
 }
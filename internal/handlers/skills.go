@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/yourusername/jobapply/internal/logging"
+)
+
+const maxSkillSuggestions = 10
+
+// SuggestSkills handles GET /skills/suggest?q= for autocomplete, matching canonical names
+// and aliases by prefix. Typing "js" suggests "JavaScript".
+func (h *Handler) SuggestSkills(w http.ResponseWriter, r *http.Request) {
+	q := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("q")))
+	if q == "" {
+		h.json(w, []string{}, http.StatusOK)
+		return
+	}
+	if len(q) > 100 {
+		q = q[:100]
+	}
+
+	// Escape LIKE wildcards so "c%" doesn't match everything starting with c
+	pattern := escapeLike(q) + "%"
+
+	rows, err := h.db.Query(r.Context(), `
+		SELECT name FROM skills
+		WHERE LOWER(name) LIKE $1 OR EXISTS (SELECT 1 FROM unnest(aliases) AS alias WHERE alias LIKE $1)
+		ORDER BY LOWER(name) = $2 DESC, LOWER(name) LIKE $1 DESC, LENGTH(name), name
+		LIMIT $3
+	`, pattern, q, maxSkillSuggestions)
+	if err != nil {
+		h.internalError(w, r, "Failed to get skills", err)
+		return
+	}
+	defer rows.Close()
+
+	suggestions := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			continue
+		}
+		suggestions = append(suggestions, name)
+	}
+
+	h.json(w, suggestions, http.StatusOK)
+}
+
+// normalizeSkills maps each skill onto its canonical name ("js" -> "JavaScript") and drops
+// case-insensitive duplicates. Unknown skills are kept as entered. If the lookup fails the
+// skills are returned deduplicated but otherwise unchanged so saving a profile never fails on it.
+func (h *Handler) normalizeSkills(ctx context.Context, skills []string) []string {
+	if len(skills) == 0 {
+		return skills
+	}
+
+	canonical := make([]string, len(skills))
+	copy(canonical, skills)
+
+	rows, err := h.db.Query(ctx, `
+		SELECT s.ord, k.name
+		FROM unnest($1::text[]) WITH ORDINALITY AS s(input, ord)
+		JOIN LATERAL (
+			SELECT name FROM skills
+			WHERE LOWER(name) = LOWER(TRIM(s.input)) OR LOWER(TRIM(s.input)) = ANY(aliases)
+			ORDER BY LOWER(name) = LOWER(TRIM(s.input)) DESC
+			LIMIT 1
+		) k ON TRUE
+	`, skills)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Skill normalization failed", "error", err)
+	} else {
+		for rows.Next() {
+			var ord int
+			var name string
+			if err := rows.Scan(&ord, &name); err == nil && ord >= 1 && ord <= len(canonical) {
+				canonical[ord-1] = name
+			}
+		}
+		rows.Close()
+	}
+
+	seen := make(map[string]bool, len(canonical))
+	result := make([]string, 0, len(canonical))
+	for _, skill := range canonical {
+		skill = strings.TrimSpace(skill)
+		key := strings.ToLower(skill)
+		if skill == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, skill)
+	}
+	return result
+}
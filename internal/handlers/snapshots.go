@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/yourusername/jobapply/internal/events"
+	"github.com/yourusername/jobapply/internal/resume"
+	"github.com/yourusername/jobapply/internal/scrapers"
+)
+
+// snapshotApplication saves the posting of a newly created application as a PDF, so the
+// description the user applied against survives the posting being taken down. Imported
+// applications were made long before; the posting may have changed since, so they get none.
+func (h *Handler) snapshotApplication(ctx context.Context, e events.Event) error {
+	var data events.ApplicationCreatedData
+	if err := e.Decode(&data); err != nil {
+		return err
+	}
+	if data.Source == "import" {
+		return nil
+	}
+
+	var posting resume.Posting
+	var location, salary, description, snapshotKey *string
+	err := h.db.QueryRow(ctx, `
+		SELECT j.title, j.company, j.location, j.salary_text, j.url, j.description, a.snapshot_key
+		FROM applications a JOIN jobs j ON j.id = a.job_id
+		WHERE a.id = $1
+	`, data.ApplicationID).Scan(&posting.Title, &posting.Company, &location, &salary, &posting.URL, &description, &snapshotKey)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil // Deleted since
+	}
+	if err != nil {
+		return err
+	}
+	if snapshotKey != nil {
+		return nil // Events can be delivered more than once
+	}
+	posting.Location = deref(location)
+	posting.Salary = deref(salary)
+	posting.Description = scrapers.DescriptionText(deref(description))
+	posting.CapturedAt = e.Time
+
+	pdf := resume.BuildPostingPDF(posting)
+	key := uuid.New().String() + ".pdf"
+	if err := h.storage.Put(ctx, key, bytes.NewReader(pdf), int64(len(pdf)), "application/pdf"); err != nil {
+		return err
+	}
+	result, err := h.db.Exec(ctx, "UPDATE applications SET snapshot_key = $2 WHERE id = $1 AND snapshot_key IS NULL", data.ApplicationID, key)
+	if err != nil || result.RowsAffected() == 0 {
+		h.storage.Delete(ctx, key)
+	}
+	return err
+}
+
+// GetApplicationSnapshot streams the PDF of the job posting saved when the user applied
+func (h *Handler) GetApplicationSnapshot(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	applicationID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, applicationID, "application ID") {
+		return
+	}
+
+	var snapshotKey *string
+	err := h.db.QueryRow(r.Context(),
+		"SELECT snapshot_key FROM applications WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL",
+		applicationID, userID).Scan(&snapshotKey)
+	if errors.Is(err, pgx.ErrNoRows) {
+		h.error(w, "Application not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.internalError(w, r, "Failed to get snapshot", err)
+		return
+	}
+	if snapshotKey == nil {
+		h.error(w, "No snapshot was saved for this application", http.StatusNotFound)
+		return
+	}
+
+	h.streamUpload(w, r, *snapshotKey)
+}
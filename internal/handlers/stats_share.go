@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/yourusername/jobapply/internal/validation"
+)
+
+// Stats a share link can show. None of them identify the user or the employers.
+const (
+	ShareApplicationsPerWeek  = "applications_per_week"
+	ShareInterviewRate        = "interview_rate"
+	ShareApplicationsByStatus = "applications_by_status"
+)
+
+var shareableStats = []string{ShareApplicationsPerWeek, ShareInterviewRate, ShareApplicationsByStatus}
+
+const (
+	statsShareTokenPrefix = "jas_"
+	maxStatsShares        = 10
+	maxShareDays          = 365
+	// sharedWeeks is how many weeks applications_per_week covers, this one included
+	sharedWeeks = 12
+)
+
+// StatsShare is a public link to some of the user's stats; the token is only shown once
+type StatsShare struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	Stats        []string   `json:"stats"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	LastViewedAt *time.Time `json:"last_viewed_at,omitempty"`
+}
+
+// CreatedStatsShare is a new share with its link
+type CreatedStatsShare struct {
+	StatsShare
+	Token string `json:"token"`
+	Path  string `json:"path"` // The public URL's path; it can't be shown again
+}
+
+type CreateStatsShareRequest struct {
+	Name          string   `json:"name"`            // Who the link is for, e.g. "Coach"
+	Stats         []string `json:"stats"`           // Defaults to every stat
+	ExpiresInDays int      `json:"expires_in_days"` // 0 for a link that lasts until revoked
+}
+
+// Validate checks the request, defaulting the stats to all of them
+func (req *CreateStatsShareRequest) Validate() error {
+	var v validation.Collector
+	req.Name = validation.SanitizeString(req.Name, 100)
+	v.Required("name", req.Name)
+	if len(req.Stats) == 0 {
+		req.Stats = slices.Clone(shareableStats)
+	}
+	for _, stat := range req.Stats {
+		if !slices.Contains(shareableStats, stat) {
+			v.Check(false, "stats", "stats must be "+strings.Join(shareableStats, ", "))
+			break
+		}
+	}
+	slices.Sort(req.Stats)
+	req.Stats = slices.Compact(req.Stats)
+	v.Check(req.ExpiresInDays >= 0 && req.ExpiresInDays <= maxShareDays, "expires_in_days",
+		fmt.Sprintf("expires_in_days must be between 0 and %d", maxShareDays))
+	return v.Err()
+}
+
+const statsShareColumns = `id, name, stats, created_at, expires_at, last_viewed_at`
+
+func scanStatsShare(row pgx.CollectableRow) (StatsShare, error) {
+	var s StatsShare
+	err := row.Scan(&s.ID, &s.Name, &s.Stats, &s.CreatedAt, &s.ExpiresAt, &s.LastViewedAt)
+	return s, err
+}
+
+// ListStatsShares returns the user's share links, expired ones included
+func (h *Handler) ListStatsShares(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	rows, err := h.db.Query(r.Context(), `SELECT `+statsShareColumns+` FROM stats_shares WHERE user_id = $1 ORDER BY created_at`, userID)
+	if err != nil {
+		h.internalError(w, r, "Failed to get share links", err)
+		return
+	}
+	shares, err := pgx.CollectRows(rows, scanStatsShare)
+	if err != nil {
+		h.internalError(w, r, "Failed to get share links", err)
+		return
+	}
+	h.json(w, shares, http.StatusOK)
+}
+
+// CreateStatsShare creates a public link showing the chosen stats
+func (h *Handler) CreateStatsShare(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req CreateStatsShareRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+
+	token, err := newToken(statsShareTokenPrefix)
+	if err != nil {
+		h.internalError(w, r, "Failed to create share link", err)
+		return
+	}
+	rows, err := h.db.Query(r.Context(), `
+		INSERT INTO stats_shares (user_id, name, token_hash, stats, expires_at)
+		SELECT $1, $2, $3, $4, CASE WHEN $5::int > 0 THEN NOW() + make_interval(days => $5) END
+		WHERE (SELECT COUNT(*) FROM stats_shares WHERE user_id = $1) < $6
+		RETURNING `+statsShareColumns,
+		userID, req.Name, hashToken(token), req.Stats, req.ExpiresInDays, maxStatsShares)
+	if err != nil {
+		h.internalError(w, r, "Failed to create share link", err)
+		return
+	}
+	share, err := pgx.CollectExactlyOneRow(rows, scanStatsShare)
+	if errors.Is(err, pgx.ErrNoRows) {
+		h.error(w, fmt.Sprintf("You can have at most %d share links; delete one first", maxStatsShares), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		h.internalError(w, r, "Failed to create share link", err)
+		return
+	}
+	h.json(w, CreatedStatsShare{StatsShare: share, Token: token, Path: "/api/v1/shared/stats/" + token}, http.StatusCreated)
+}
+
+// DeleteStatsShare revokes a share link immediately
+func (h *Handler) DeleteStatsShare(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	shareID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, shareID, "share ID") {
+		return
+	}
+	result, err := h.db.Exec(r.Context(), "DELETE FROM stats_shares WHERE id = $1 AND user_id = $2", shareID, userID)
+	if err != nil {
+		h.internalError(w, r, "Failed to delete share link", err)
+		return
+	}
+	if result.RowsAffected() == 0 {
+		h.error(w, "Share link not found", http.StatusNotFound)
+		return
+	}
+	h.json(w, map[string]string{"message": "Share link deleted successfully"}, http.StatusOK)
+}
+
+// WeekCount is the number of applications sent in the week starting Monday Week
+type WeekCount struct {
+	Week  string `json:"week"` // YYYY-MM-DD
+	Count int    `json:"count"`
+}
+
+// SharedStats is what a share link shows: only the stats it was created with
+type SharedStats struct {
+	ApplicationsPerWeek []WeekCount `json:"applications_per_week,omitempty"`
+	// InterviewRate is the share of sent applications that reached an interview or offer, as
+	// recorded in their external status
+	InterviewRate        *float64       `json:"interview_rate,omitempty"`
+	ApplicationsByStatus map[string]int `json:"applications_by_status,omitempty"`
+	GeneratedAt          time.Time      `json:"generated_at"`
+}
+
+// interviewStatusPattern matches external statuses meaning an application got at least as far
+// as an interview
+const interviewStatusPattern = `(interview|offer|hired|accepted)`
+
+// GetSharedStats handles GET /api/v1/shared/stats/{token}, the public side of a share link.
+// Unknown, revoked and expired links all look the same.
+func (h *Handler) GetSharedStats(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+	var userID string
+	var stats []string
+	err := h.db.QueryRow(r.Context(), `
+		UPDATE stats_shares SET last_viewed_at = NOW()
+		WHERE token_hash = $1 AND (expires_at IS NULL OR expires_at > NOW())
+		RETURNING user_id, stats
+	`, hashToken(token)).Scan(&userID, &stats)
+	if errors.Is(err, pgx.ErrNoRows) {
+		h.error(w, "Share link not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.internalError(w, r, "Failed to get shared stats", err)
+		return
+	}
+
+	resp := SharedStats{GeneratedAt: time.Now().UTC()}
+	if slices.Contains(stats, ShareApplicationsPerWeek) {
+		rows, err := h.db.Query(r.Context(), `
+			SELECT to_char(w, 'YYYY-MM-DD'), COUNT(a.id)
+			FROM generate_series(date_trunc('week', NOW()) - make_interval(weeks => $2 - 1), date_trunc('week', NOW()), '1 week') w
+			LEFT JOIN applications a ON a.user_id = $1 AND a.deleted_at IS NULL AND a.status = 'submitted'
+				AND a.applied_at >= w AND a.applied_at < w + INTERVAL '1 week'
+			GROUP BY w ORDER BY w
+		`, userID, sharedWeeks)
+		if err != nil {
+			h.internalError(w, r, "Failed to get shared stats", err)
+			return
+		}
+		resp.ApplicationsPerWeek, err = pgx.CollectRows(rows, pgx.RowToStructByPos[WeekCount])
+		if err != nil {
+			h.internalError(w, r, "Failed to get shared stats", err)
+			return
+		}
+	}
+	if slices.Contains(stats, ShareInterviewRate) {
+		var sent, interviewed int
+		err := h.db.QueryRow(r.Context(), `
+			SELECT COUNT(*), COUNT(*) FILTER (WHERE external_status ~* $2)
+			FROM applications WHERE user_id = $1 AND deleted_at IS NULL AND status = 'submitted'
+		`, userID, interviewStatusPattern).Scan(&sent, &interviewed)
+		if err != nil {
+			h.internalError(w, r, "Failed to get shared stats", err)
+			return
+		}
+		rate := 0.0
+		if sent > 0 {
+			rate = math.Round(float64(interviewed)/float64(sent)*1000) / 1000
+		}
+		resp.InterviewRate = &rate
+	}
+	if slices.Contains(stats, ShareApplicationsByStatus) {
+		userStats, err := h.users.Stats(r.Context(), userID)
+		if err != nil {
+			h.internalError(w, r, "Failed to get shared stats", err)
+			return
+		}
+		resp.ApplicationsByStatus = userStats.ApplicationsByStatus
+	}
+
+	// The stats change as the user applies, and a link may be revoked at any time
+	w.Header().Set("Cache-Control", "no-store")
+	h.json(w, resp, http.StatusOK)
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/yourusername/jobapply/internal/logging"
+)
+
+// streamFlushEvery is how many list items are written between flushes, so clients and the
+// compression middleware receive a long list in pieces
+const streamFlushEvery = 100
+
+// jsonStream writes a JSON list one item at a time, so large lists are never held in memory
+// or encoded as a whole. The header is only sent with the first item, so an error before then
+// can still get a normal error response; see started.
+//
+// prefix and suffix surround the array, e.g. `{"jobs":` and `}` to send it as a field.
+type jsonStream struct {
+	w       http.ResponseWriter
+	enc     *json.Encoder
+	prefix  string
+	suffix  string
+	count   int
+	started bool
+}
+
+func newJSONStream(w http.ResponseWriter, prefix, suffix string) *jsonStream {
+	return &jsonStream{w: w, enc: json.NewEncoder(w), prefix: prefix, suffix: suffix}
+}
+
+func (s *jsonStream) start() {
+	s.started = true
+	s.w.Header().Set("Content-Type", "application/json")
+	s.w.WriteHeader(http.StatusOK)
+	io.WriteString(s.w, s.prefix+"[")
+}
+
+// add writes the next item
+func (s *jsonStream) add(item any) error {
+	if !s.started {
+		s.start()
+	}
+	if s.count > 0 {
+		if _, err := io.WriteString(s.w, ","); err != nil {
+			return err
+		}
+	}
+	if err := s.enc.Encode(item); err != nil {
+		return err
+	}
+	s.count++
+	if s.count%streamFlushEvery == 0 {
+		http.NewResponseController(s.w).Flush()
+	}
+	return nil
+}
+
+// close ends the list
+func (s *jsonStream) close() error {
+	if !s.started {
+		s.start()
+	}
+	_, err := io.WriteString(s.w, "]"+s.suffix+"\n")
+	return err
+}
+
+// failStream reports an error hit while producing the list. Before the first item it is sent as a
+// normal error response; after that the status is already sent, so the list is cut short,
+// leaving invalid JSON the client will reject, and the error is only logged.
+func (h *Handler) failStream(w http.ResponseWriter, r *http.Request, s *jsonStream, msg string, err error) {
+	if !s.started {
+		h.internalError(w, r, msg, err)
+		return
+	}
+	if r.Context().Err() != nil {
+		return // The client went away
+	}
+	logging.FromContext(r.Context()).Error(msg+" after the response started", "error", err)
+}
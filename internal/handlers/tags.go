@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/yourusername/jobapply/internal/validation"
+)
+
+const maxTagNameLength = 50
+
+var tagColorRegex = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+type Tag struct {
+	ID               string    `json:"id"`
+	Name             string    `json:"name"`
+	Color            *string   `json:"color,omitempty"` // #rrggbb
+	JobCount         int       `json:"job_count"`
+	ApplicationCount int       `json:"application_count"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type TagRequest struct {
+	Name  string  `json:"name"`
+	Color *string `json:"color"`
+}
+
+// ListTags returns the user's tags with how many jobs and applications carry each
+func (h *Handler) ListTags(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	rows, err := h.db.Query(r.Context(), `
+		SELECT t.id, t.name, t.color, t.created_at,
+			(SELECT COUNT(*) FROM job_tags jt WHERE jt.tag_id = t.id),
+			(SELECT COUNT(*) FROM application_tags apt WHERE apt.tag_id = t.id)
+		FROM tags t
+		WHERE t.user_id = $1
+		ORDER BY LOWER(t.name)
+	`, userID)
+	if err != nil {
+		h.internalError(w, r, "Failed to get tags", err)
+		return
+	}
+	defer rows.Close()
+
+	tags := []Tag{}
+	for rows.Next() {
+		var tag Tag
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.Color, &tag.CreatedAt, &tag.JobCount, &tag.ApplicationCount); err != nil {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+
+	h.json(w, tags, http.StatusOK)
+}
+
+// CreateTag adds a tag. Names are unique per user, ignoring case.
+func (h *Handler) CreateTag(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	req, ok := h.decodeTagRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var tag Tag
+	err := h.db.QueryRow(r.Context(), `
+		INSERT INTO tags (user_id, name, color) VALUES ($1, $2, $3)
+		RETURNING id, name, color, created_at
+	`, userID, req.Name, req.Color).Scan(&tag.ID, &tag.Name, &tag.Color, &tag.CreatedAt)
+	if err != nil {
+		h.tagWriteError(w, r, err)
+		return
+	}
+
+	h.json(w, tag, http.StatusCreated)
+}
+
+// UpdateTag renames or recolors a tag
+func (h *Handler) UpdateTag(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	tagID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, tagID, "tag ID") {
+		return
+	}
+
+	req, ok := h.decodeTagRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var tag Tag
+	err := h.db.QueryRow(r.Context(), `
+		UPDATE tags SET name = $3, color = $4
+		WHERE id = $1 AND user_id = $2
+		RETURNING id, name, color, created_at
+	`, tagID, userID, req.Name, req.Color).Scan(&tag.ID, &tag.Name, &tag.Color, &tag.CreatedAt)
+	if err != nil {
+		h.tagWriteError(w, r, err)
+		return
+	}
+
+	h.json(w, tag, http.StatusOK)
+}
+
+// DeleteTag deletes a tag and removes it from everything it was attached to
+func (h *Handler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	tagID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, tagID, "tag ID") {
+		return
+	}
+
+	result, err := h.db.Exec(r.Context(), "DELETE FROM tags WHERE id = $1 AND user_id = $2", tagID, userID)
+	if err != nil {
+		h.internalError(w, r, "Failed to delete tag", err)
+		return
+	}
+	if result.RowsAffected() == 0 {
+		h.error(w, "Tag not found", http.StatusNotFound)
+		return
+	}
+
+	h.json(w, map[string]string{"message": "Tag deleted"}, http.StatusOK)
+}
+
+// TagJob handles PUT /jobs/{id}/tags/{tagId}
+func (h *Handler) TagJob(w http.ResponseWriter, r *http.Request) {
+	// DO UPDATE rather than DO NOTHING so re-tagging still counts as a row and
+	// zero rows reliably means the job isn't visible to the user
+	h.setTag(w, r, `
+		INSERT INTO job_tags (tag_id, job_id)
+		SELECT $1, id FROM jobs
+		WHERE id = $2 AND deleted_at IS NULL AND EXISTS (SELECT 1 FROM user_jobs uj WHERE uj.job_id = jobs.id AND uj.user_id = $3)
+		ON CONFLICT (tag_id, job_id) DO UPDATE SET tagged_at = job_tags.tagged_at
+	`, "Job not found")
+}
+
+// UntagJob handles DELETE /jobs/{id}/tags/{tagId}
+func (h *Handler) UntagJob(w http.ResponseWriter, r *http.Request) {
+	h.setTag(w, r, `
+		DELETE FROM job_tags
+		WHERE tag_id = $1 AND job_id = $2 AND EXISTS (SELECT 1 FROM tags WHERE id = $1 AND user_id = $3)
+	`, "Tag not attached to job")
+}
+
+// TagApplication handles PUT /applications/{id}/tags/{tagId}
+func (h *Handler) TagApplication(w http.ResponseWriter, r *http.Request) {
+	h.setTag(w, r, `
+		INSERT INTO application_tags (tag_id, application_id)
+		SELECT $1, id FROM applications WHERE id = $2 AND user_id = $3 AND deleted_at IS NULL
+		ON CONFLICT (tag_id, application_id) DO UPDATE SET tagged_at = application_tags.tagged_at
+	`, "Application not found")
+}
+
+// UntagApplication handles DELETE /applications/{id}/tags/{tagId}
+func (h *Handler) UntagApplication(w http.ResponseWriter, r *http.Request) {
+	h.setTag(w, r, `
+		DELETE FROM application_tags
+		WHERE tag_id = $1 AND application_id = $2 AND EXISTS (SELECT 1 FROM tags WHERE id = $1 AND user_id = $3)
+	`, "Tag not attached to application")
+}
+
+// setTag checks the user owns the tag, then runs an attach or detach statement taking
+// (tag ID, item ID, user ID). notFound is returned when the statement affects no rows.
+func (h *Handler) setTag(w http.ResponseWriter, r *http.Request, query, notFound string) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	itemID, tagID := chi.URLParam(r, "id"), chi.URLParam(r, "tagId")
+	if !h.validateUUID(w, itemID, "ID") || !h.validateUUID(w, tagID, "tag ID") {
+		return
+	}
+
+	var owned bool
+	h.db.QueryRow(r.Context(), "SELECT EXISTS (SELECT 1 FROM tags WHERE id = $1 AND user_id = $2)", tagID, userID).Scan(&owned)
+	if !owned {
+		h.error(w, "Tag not found", http.StatusNotFound)
+		return
+	}
+
+	result, err := h.db.Exec(r.Context(), query, tagID, itemID, userID)
+	if err != nil {
+		h.internalError(w, r, "Failed to update tags", err)
+		return
+	}
+	if result.RowsAffected() == 0 {
+		h.error(w, notFound, http.StatusNotFound)
+		return
+	}
+
+	h.json(w, map[string]string{"message": "Tags updated"}, http.StatusOK)
+}
+
+func (h *Handler) decodeTagRequest(w http.ResponseWriter, r *http.Request) (*TagRequest, bool) {
+	var req TagRequest
+	if !h.decodeJSON(w, r, &req) {
+		return nil, false
+	}
+
+	req.Name = validation.SanitizeString(req.Name, maxTagNameLength)
+	if req.Name == "" {
+		h.error(w, "name is required", http.StatusBadRequest)
+		return nil, false
+	}
+	if req.Color != nil {
+		if *req.Color == "" {
+			req.Color = nil
+		} else if !tagColorRegex.MatchString(*req.Color) {
+			h.error(w, "color must be a hex color like #22c55e", http.StatusBadRequest)
+			return nil, false
+		}
+	}
+	return &req, true
+}
+
+func (h *Handler) tagWriteError(w http.ResponseWriter, r *http.Request, err error) {
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.As(err, &pgErr) && pgErr.Code == "23505":
+		h.error(w, "A tag with that name already exists", http.StatusConflict)
+	case errors.Is(err, pgx.ErrNoRows):
+		h.error(w, "Tag not found", http.StatusNotFound)
+	default:
+		h.internalError(w, r, "Failed to save tag", err)
+	}
+}
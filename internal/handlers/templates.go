@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/yourusername/jobapply/internal/models"
+	"github.com/yourusername/jobapply/internal/validation"
+)
+
+const maxTemplateAnswers = 100
+
+type ApplicationTemplateRequest struct {
+	// Employer is a company name or a domain; a URL is reduced to its host
+	Employer  string            `json:"employer"`
+	PersonaID *string           `json:"persona_id"`
+	Answers   map[string]string `json:"answers"`
+}
+
+const templateColumns = `id, employer, persona_id, answers, created_at, updated_at`
+
+// ListApplicationTemplates returns the user's application templates by employer
+func (h *Handler) ListApplicationTemplates(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	rows, err := h.db.Query(r.Context(), `SELECT `+templateColumns+` FROM application_templates WHERE user_id = $1 ORDER BY employer`, userID)
+	if err != nil {
+		h.internalError(w, r, "Failed to get application templates", err)
+		return
+	}
+	templates, err := pgx.CollectRows(rows, scanTemplate)
+	if err != nil {
+		h.internalError(w, r, "Failed to get application templates", err)
+		return
+	}
+	h.json(w, templates, http.StatusOK)
+}
+
+// CreateApplicationTemplate saves answers for an employer; each employer has one template
+func (h *Handler) CreateApplicationTemplate(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	req, ok := h.decodeTemplateRequest(w, r, userID)
+	if !ok {
+		return
+	}
+
+	rows, err := h.db.Query(r.Context(), `
+		INSERT INTO application_templates (user_id, employer, persona_id, answers)
+		VALUES ($1, $2, $3, $4)
+		RETURNING `+templateColumns,
+		userID, req.Employer, req.PersonaID, req.Answers)
+	if err == nil {
+		var template models.ApplicationTemplate
+		if template, err = pgx.CollectExactlyOneRow(rows, scanTemplate); err == nil {
+			h.json(w, template, http.StatusCreated)
+			return
+		}
+	}
+	if strings.Contains(err.Error(), "duplicate key") {
+		h.error(w, "A template for that employer already exists", http.StatusConflict)
+		return
+	}
+	h.internalError(w, r, "Failed to create application template", err)
+}
+
+// UpdateApplicationTemplate replaces a template's employer, profile and answers
+func (h *Handler) UpdateApplicationTemplate(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	templateID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, templateID, "template ID") {
+		return
+	}
+	req, ok := h.decodeTemplateRequest(w, r, userID)
+	if !ok {
+		return
+	}
+
+	rows, err := h.db.Query(r.Context(), `
+		UPDATE application_templates SET employer = $1, persona_id = $2, answers = $3, updated_at = NOW()
+		WHERE id = $4 AND user_id = $5
+		RETURNING `+templateColumns,
+		req.Employer, req.PersonaID, req.Answers, templateID, userID)
+	if err == nil {
+		var template models.ApplicationTemplate
+		if template, err = pgx.CollectExactlyOneRow(rows, scanTemplate); err == nil {
+			h.json(w, template, http.StatusOK)
+			return
+		}
+	}
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		h.error(w, "Template not found", http.StatusNotFound)
+	case strings.Contains(err.Error(), "duplicate key"):
+		h.error(w, "A template for that employer already exists", http.StatusConflict)
+	default:
+		h.internalError(w, r, "Failed to update application template", err)
+	}
+}
+
+// DeleteApplicationTemplate removes a template; applications already made keep their answers
+func (h *Handler) DeleteApplicationTemplate(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	templateID := chi.URLParam(r, "id")
+	if !h.validateUUID(w, templateID, "template ID") {
+		return
+	}
+
+	result, err := h.db.Exec(r.Context(), "DELETE FROM application_templates WHERE id = $1 AND user_id = $2", templateID, userID)
+	if err != nil {
+		h.internalError(w, r, "Failed to delete application template", err)
+		return
+	}
+	if result.RowsAffected() == 0 {
+		h.error(w, "Template not found", http.StatusNotFound)
+		return
+	}
+	h.json(w, map[string]string{"message": "Template deleted successfully"}, http.StatusOK)
+}
+
+func (h *Handler) decodeTemplateRequest(w http.ResponseWriter, r *http.Request, userID string) (*ApplicationTemplateRequest, bool) {
+	var req ApplicationTemplateRequest
+	if !h.decodeJSON(w, r, &req) {
+		return nil, false
+	}
+
+	req.Employer = normalizeEmployer(validation.SanitizeString(req.Employer, 200))
+	if req.Employer == "" {
+		h.error(w, "employer is required", http.StatusBadRequest)
+		return nil, false
+	}
+	if len(req.Answers) > maxTemplateAnswers {
+		h.error(w, "a template can hold at most 100 answers", http.StatusBadRequest)
+		return nil, false
+	}
+	answers := make(map[string]string, len(req.Answers))
+	for question, answer := range req.Answers {
+		// Keys are matched against question keys and texts case- and spacing-insensitively
+		question = strings.ToLower(strings.Join(strings.Fields(validation.SanitizeString(question, 500)), " "))
+		if question != "" {
+			answers[question] = validation.SanitizeString(answer, 5000)
+		}
+	}
+	req.Answers = answers
+
+	if req.PersonaID != nil {
+		if !h.validateUUID(w, *req.PersonaID, "profile ID") {
+			return nil, false
+		}
+		if _, err := h.getPersona(r.Context(), userID, *req.PersonaID); err != nil {
+			h.error(w, "Profile not found", http.StatusBadRequest)
+			return nil, false
+		}
+	}
+	return &req, true
+}
+
+// normalizeEmployer lowercases a company name or domain, reducing a URL to its host and
+// dropping "www."
+func normalizeEmployer(employer string) string {
+	employer = strings.ToLower(strings.TrimSpace(employer))
+	if strings.Contains(employer, "://") {
+		if u, err := url.Parse(employer); err == nil {
+			employer = u.Hostname()
+		}
+	}
+	return strings.TrimPrefix(employer, "www.")
+}
+
+// applicationTemplate finds the user's template for an application's employer: one naming the
+// job's company, or else one whose domain is the job URL's host or a parent of it. It returns
+// nil if there is none.
+func (h *Handler) applicationTemplate(ctx context.Context, userID, applicationID string) (*models.ApplicationTemplate, error) {
+	rows, err := h.db.Query(ctx, `
+		SELECT `+prefixColumns("t.", templateColumns)+`
+		FROM applications a
+		JOIN jobs j ON j.id = a.job_id
+		JOIN application_templates t ON t.user_id = a.user_id
+		WHERE a.id = $1 AND a.user_id = $2
+		AND (t.employer = LOWER(TRIM(j.company))
+			OR `+applyDomainSQL+` = t.employer
+			OR `+applyDomainSQL+` LIKE '%.' || t.employer)
+		ORDER BY t.employer = LOWER(TRIM(j.company)) DESC, LENGTH(t.employer) DESC
+		LIMIT 1
+	`, applicationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	template, err := pgx.CollectExactlyOneRow(rows, scanTemplate)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// templateAnswer looks a question up in a template by its field key, then by its text
+func templateAnswer(template *models.ApplicationTemplate, q *models.ApplicationQuestion) (string, bool) {
+	if template == nil {
+		return "", false
+	}
+	if answer, ok := template.Answers[strings.ToLower(q.Key)]; ok {
+		return answer, true
+	}
+	answer, ok := template.Answers[strings.ToLower(strings.Join(strings.Fields(q.Question), " "))]
+	return answer, ok
+}
+
+// prefixColumns qualifies each of a comma-separated column list with prefix
+func prefixColumns(prefix, columns string) string {
+	parts := strings.Split(columns, ", ")
+	for i, c := range parts {
+		parts[i] = prefix + c
+	}
+	return strings.Join(parts, ", ")
+}
+
+func scanTemplate(row pgx.CollectableRow) (models.ApplicationTemplate, error) {
+	var t models.ApplicationTemplate
+	err := row.Scan(&t.ID, &t.Employer, &t.PersonaID, &t.Answers, &t.CreatedAt, &t.UpdatedAt)
+	if t.Answers == nil {
+		t.Answers = map[string]string{}
+	}
+	return t, err
+}
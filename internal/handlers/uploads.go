@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/yourusername/jobapply/internal/logging"
+	"github.com/yourusername/jobapply/internal/resume"
+	"github.com/yourusername/jobapply/internal/storage"
+)
+
+// signedURLTTL keeps signed links short-lived; they are meant to be opened right away
+const signedURLTTL = 15 * time.Minute
+
+// uploadReuseGrace keeps a file that was just reused by another upload from being deleted
+// before that upload's reference to it is saved
+const uploadReuseGrace = time.Hour
+
+// uploadReferencedSQL is true for a row of uploads that a profile or persona refers to.
+// Deleted personas count, since they can be restored.
+const uploadReferencedSQL = `(EXISTS (SELECT 1 FROM user_profiles p WHERE p.resume_url = '/uploads/' || uploads.key)
+	OR EXISTS (SELECT 1 FROM profile_personas pp WHERE pp.resume_url = '/uploads/' || uploads.key))`
+
+type SignedURLResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// GetUpload streams one of the authenticated user's own uploads
+func (h *Handler) GetUpload(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	key := path.Base(chi.URLParam(r, "key"))
+	if !h.ownsUpload(r.Context(), userID, key) {
+		// Same response as a missing file so keys can't be probed
+		h.error(w, "File not found", http.StatusNotFound)
+		return
+	}
+
+	h.streamUpload(w, r, key)
+}
+
+// SignUploadURL issues a short-lived link to one of the user's uploads, for places that
+// can't send an Authorization header (plain <a href>, new browser tabs)
+func (h *Handler) SignUploadURL(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == "" {
+		h.error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	key := path.Base(chi.URLParam(r, "key"))
+	if !h.ownsUpload(r.Context(), userID, key) {
+		h.error(w, "File not found", http.StatusNotFound)
+		return
+	}
+
+	expiresAt := time.Now().Add(signedURLTTL).Truncate(time.Second)
+	params := url.Values{}
+	params.Set("expires", strconv.FormatInt(expiresAt.Unix(), 10))
+	params.Set("signature", h.signUpload(key, expiresAt.Unix()))
+
+	h.json(w, SignedURLResponse{
+		URL:       fmt.Sprintf("/uploads/%s?%s", url.PathEscape(key), params.Encode()),
+		ExpiresAt: expiresAt,
+	}, http.StatusOK)
+}
+
+// ServeUpload serves /uploads/{key} only with a valid, unexpired signature from SignUploadURL
+func (h *Handler) ServeUpload(w http.ResponseWriter, r *http.Request) {
+	key := path.Base(chi.URLParam(r, "*"))
+
+	expires, err := strconv.ParseInt(r.URL.Query().Get("expires"), 10, 64)
+	signature := r.URL.Query().Get("signature")
+	if err != nil || signature == "" ||
+		!hmac.Equal([]byte(signature), []byte(h.signUpload(key, expires))) {
+		h.error(w, "Invalid or missing signature", http.StatusForbidden)
+		return
+	}
+	if time.Now().Unix() > expires {
+		h.error(w, "Link has expired", http.StatusForbidden)
+		return
+	}
+
+	h.streamUpload(w, r, key)
+}
+
+func (h *Handler) streamUpload(w http.ResponseWriter, r *http.Request, key string) {
+	rc, err := h.storage.Open(r.Context(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			h.error(w, "File not found", http.StatusNotFound)
+			return
+		}
+		h.internalError(w, r, "Failed to read file", err)
+		return
+	}
+	defer rc.Close()
+
+	// Only PDFs are accepted on upload; previews are PNGs
+	contentType := "application/pdf"
+	if path.Ext(key) == ".png" {
+		contentType = "image/png"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", "inline")
+	w.Header().Set("Cache-Control", "private, no-store")
+	io.Copy(w, rc)
+}
+
+// ownsUpload reports whether key is the resume of the user's profile or one of their personas,
+// or the preview of one
+func (h *Handler) ownsUpload(ctx context.Context, userID, key string) bool {
+	var owned bool
+	err := h.db.QueryRow(ctx, `
+		WITH target AS (
+			SELECT '/uploads/' || COALESCE((SELECT key FROM uploads WHERE preview_key = $2), $2) AS url
+		)
+		SELECT EXISTS (SELECT 1 FROM user_profiles, target WHERE id = $1 AND resume_url = target.url)
+			OR EXISTS (SELECT 1 FROM profile_personas, target WHERE user_id = $1 AND resume_url = target.url)
+	`, userID, key).Scan(&owned)
+	return err == nil && owned
+}
+
+func (h *Handler) signUpload(key string, expires int64) string {
+	mac := hmac.New(sha256.New, h.uploadSigningKey)
+	fmt.Fprintf(mac, "%s|%d", key, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// saveUpload stores userID's file and returns its key. A file identical to one the same user
+// already stored is not written again; the stored copy's key is returned instead. Other users'
+// files are never reused, so an upload can't reveal that someone else has the same document.
+func (h *Handler) saveUpload(ctx context.Context, userID string, file io.ReadSeeker, size int64, contentType string) (string, error) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+
+	var key string
+	err := h.db.QueryRow(ctx, "UPDATE uploads SET last_used_at = NOW() WHERE user_id = $1 AND sha256 = $2 RETURNING key", userID, sum).Scan(&key)
+	if err == nil {
+		return key, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return "", err
+	}
+
+	// Random names can't be guessed or overwritten
+	key = uuid.New().String() + ".pdf"
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	if err := h.storage.Put(ctx, key, file, size, contentType); err != nil {
+		return "", err
+	}
+
+	// An identical file may have been stored meanwhile; keep whichever was recorded first
+	var stored string
+	err = h.db.QueryRow(ctx, `
+		INSERT INTO uploads (key, user_id, sha256, size) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, sha256) DO UPDATE SET last_used_at = NOW()
+		RETURNING key
+	`, key, userID, sum, size).Scan(&stored)
+	if err != nil || stored != key {
+		h.storage.Delete(ctx, key)
+	}
+	return stored, err
+}
+
+// releaseUpload deletes the file at key, and its preview, once nothing refers to it. It must be
+// called after the reference is removed. Files stored before uploads were recorded are deleted
+// the same way.
+func (h *Handler) releaseUpload(ctx context.Context, key string) {
+	var unused bool
+	var previewKey *string
+	err := h.db.QueryRow(ctx, `
+		WITH released AS (
+			DELETE FROM uploads
+			WHERE key = $1 AND last_used_at < $2 AND NOT `+uploadReferencedSQL+`
+			RETURNING preview_key
+		)
+		SELECT EXISTS (SELECT 1 FROM released)
+			OR NOT EXISTS (SELECT 1 FROM uploads WHERE key = $1)
+			AND NOT EXISTS (SELECT 1 FROM user_profiles WHERE resume_url = '/uploads/' || $1::text)
+			AND NOT EXISTS (SELECT 1 FROM profile_personas WHERE resume_url = '/uploads/' || $1::text),
+			(SELECT preview_key FROM released)
+	`, key, time.Now().Add(-uploadReuseGrace)).Scan(&unused, &previewKey)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to release upload", "key", key, "error", err)
+		return
+	}
+	if unused {
+		h.storage.Delete(ctx, key) // Ignore errors - file might not exist
+	}
+	if previewKey != nil {
+		h.storage.Delete(ctx, *previewKey)
+	}
+}
+
+// SetPreviewer turns on first-page previews of uploaded resumes; without one there are none
+func (h *Handler) SetPreviewer(p *resume.Previewer) {
+	h.previewer = p
+}
+
+// generatePreview renders the first page of the upload at key and stores it next to it,
+// unless an identical earlier upload already has one. Failures are only logged; the upload
+// works without a preview.
+func (h *Handler) generatePreview(ctx context.Context, key string, data []byte) {
+	log := logging.FromContext(ctx).With("key", key)
+
+	var hasPreview bool
+	err := h.db.QueryRow(ctx, "SELECT preview_key IS NOT NULL FROM uploads WHERE key = $1", key).Scan(&hasPreview)
+	if err != nil || hasPreview {
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			log.Warn("Failed to look up preview", "error", err)
+		}
+		return
+	}
+
+	pdfPath, cleanup, err := writeTempPDF(data)
+	if err != nil {
+		log.Warn("Failed to render preview", "error", err)
+		return
+	}
+	defer cleanup()
+	png, err := h.previewer.Render(ctx, pdfPath)
+	if err != nil {
+		log.Warn("Failed to render preview", "error", err)
+		return
+	}
+
+	previewKey := strings.TrimSuffix(key, path.Ext(key)) + ".png"
+	if err := h.storage.Put(ctx, previewKey, bytes.NewReader(png), int64(len(png)), "image/png"); err != nil {
+		log.Warn("Failed to save preview", "error", err)
+		return
+	}
+	result, err := h.db.Exec(ctx, "UPDATE uploads SET preview_key = $2 WHERE key = $1", key, previewKey)
+	if err != nil || result.RowsAffected() == 0 {
+		// The upload was released meanwhile
+		h.storage.Delete(ctx, previewKey)
+	}
+}
+
+// resumePreviewURL returns the preview of the resume at resumeURL, or nil if it has none
+func (h *Handler) resumePreviewURL(ctx context.Context, resumeURL *string) *string {
+	if resumeURL == nil || *resumeURL == "" {
+		return nil
+	}
+	var previewKey *string
+	err := h.db.QueryRow(ctx, "SELECT preview_key FROM uploads WHERE key = $1", uploadKey(*resumeURL)).Scan(&previewKey)
+	if err != nil || previewKey == nil {
+		return nil
+	}
+	previewURL := "/uploads/" + *previewKey
+	return &previewURL
+}
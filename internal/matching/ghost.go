@@ -0,0 +1,58 @@
+package matching
+
+// GhostSignals are what is known about a posting that suggests nobody is hiring for it, or
+// that an agency is fishing for candidates
+type GhostSignals struct {
+	// DuplicateCompanies is how many other companies posted the same description
+	DuplicateCompanies int
+	// Reposts is how many other postings of the same title at the same company were seen
+	// recently under different URLs
+	Reposts int
+	// NoSalary is set when the posting gives no pay
+	NoSalary bool
+	// Agency is set when the posting came through or from a staffing agency
+	Agency bool
+}
+
+// Ghost reasons, as listed with a score
+const (
+	GhostDuplicateDescription = "duplicate_description"
+	GhostReposted             = "reposted"
+	GhostNoSalary             = "no_salary"
+	GhostAgency               = "staffing_agency"
+)
+
+// GhostThreshold is the score from which a job is treated as a likely ghost or agency repost
+const GhostThreshold = 60
+
+// GhostScore is the confidence, 0-100, that a posting is a ghost job or an agency repost, with
+// the reasons that contributed. No single weak signal reaches the threshold on its own.
+func GhostScore(s GhostSignals) (int, []string) {
+	score := 0
+	reasons := []string{}
+	switch {
+	case s.DuplicateCompanies >= 2:
+		score += 45
+		reasons = append(reasons, GhostDuplicateDescription)
+	case s.DuplicateCompanies == 1:
+		score += 30
+		reasons = append(reasons, GhostDuplicateDescription)
+	}
+	switch {
+	case s.Reposts >= 3:
+		score += 35
+		reasons = append(reasons, GhostReposted)
+	case s.Reposts >= 1:
+		score += 20
+		reasons = append(reasons, GhostReposted)
+	}
+	if s.Agency {
+		score += 25
+		reasons = append(reasons, GhostAgency)
+	}
+	if s.NoSalary {
+		score += 10
+		reasons = append(reasons, GhostNoSalary)
+	}
+	return min(score, 100), reasons
+}
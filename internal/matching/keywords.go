@@ -0,0 +1,186 @@
+package matching
+
+import (
+	"html"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/jobapply/internal/models"
+)
+
+// keyword is a skill term recognized in job descriptions. Aliases are matched
+// case-insensitively; CaseSensitive terms ("Go", "R") are too ambiguous as plain words.
+type keyword struct {
+	Name          string
+	Aliases       []string
+	CaseSensitive bool
+}
+
+var vocabulary = []keyword{
+	// Languages
+	{Name: "Go", Aliases: []string{"Go", "Golang"}, CaseSensitive: true},
+	{Name: "Python"}, {Name: "Java"}, {Name: "JavaScript", Aliases: []string{"javascript", "js", "ecmascript"}},
+	{Name: "TypeScript", Aliases: []string{"typescript", "ts"}}, {Name: "Ruby"}, {Name: "PHP"}, {Name: "Rust"},
+	{Name: "C++", Aliases: []string{"c++", "cpp"}}, {Name: "C#", Aliases: []string{"c#", "csharp"}},
+	{Name: "Kotlin"}, {Name: "Swift"}, {Name: "Scala"}, {Name: "R", Aliases: []string{"R"}, CaseSensitive: true},
+	{Name: "SQL"}, {Name: "Bash", Aliases: []string{"bash", "shell scripting"}},
+	// Web and frameworks
+	{Name: "React", Aliases: []string{"react", "react.js", "reactjs"}}, {Name: "Vue", Aliases: []string{"vue", "vue.js", "vuejs"}},
+	{Name: "Angular"}, {Name: "Svelte"}, {Name: "Node.js", Aliases: []string{"node.js", "nodejs"}},
+	{Name: "Django"}, {Name: "Flask"}, {Name: "FastAPI"}, {Name: "Spring", Aliases: []string{"spring boot", "spring framework"}},
+	{Name: "Rails", Aliases: []string{"rails", "ruby on rails"}}, {Name: ".NET", Aliases: []string{".net", "dotnet", "asp.net"}},
+	{Name: "GraphQL"}, {Name: "REST", Aliases: []string{"restful", "rest api", "rest apis"}}, {Name: "gRPC"},
+	{Name: "HTML"}, {Name: "CSS"},
+	// Data
+	{Name: "PostgreSQL", Aliases: []string{"postgresql", "postgres"}}, {Name: "MySQL"}, {Name: "MongoDB", Aliases: []string{"mongodb", "mongo"}},
+	{Name: "Redis"}, {Name: "Elasticsearch"}, {Name: "Kafka"}, {Name: "Spark", Aliases: []string{"spark", "pyspark"}},
+	{Name: "Airflow"}, {Name: "Snowflake"}, {Name: "dbt"}, {Name: "Hadoop"}, {Name: "Tableau"}, {Name: "Power BI"},
+	{Name: "Pandas"}, {Name: "NumPy"}, {Name: "TensorFlow"}, {Name: "PyTorch"}, {Name: "scikit-learn", Aliases: []string{"scikit-learn", "sklearn"}},
+	{Name: "Machine Learning", Aliases: []string{"machine learning", "ml"}}, {Name: "Data Analysis"}, {Name: "ETL"},
+	{Name: "Excel", Aliases: []string{"Excel"}, CaseSensitive: true},
+	// Infrastructure
+	{Name: "AWS", Aliases: []string{"aws", "amazon web services"}}, {Name: "GCP", Aliases: []string{"gcp", "google cloud"}},
+	{Name: "Azure"}, {Name: "Docker"}, {Name: "Kubernetes", Aliases: []string{"kubernetes", "k8s"}},
+	{Name: "Terraform"}, {Name: "Ansible"}, {Name: "Linux"}, {Name: "CI/CD", Aliases: []string{"ci/cd", "continuous integration"}},
+	{Name: "Jenkins"}, {Name: "GitHub Actions"}, {Name: "Git"}, {Name: "Microservices", Aliases: []string{"microservices", "microservice"}},
+	// Practices and tools
+	{Name: "Agile"}, {Name: "Scrum"}, {Name: "Jira"}, {Name: "Figma"}, {Name: "Salesforce"},
+	{Name: "Unit Testing", Aliases: []string{"unit testing", "unit tests", "tdd"}},
+	{Name: "Project Management"}, {Name: "Product Management"}, {Name: "SEO"}, {Name: "Cybersecurity", Aliases: []string{"cybersecurity", "information security"}},
+}
+
+var tagRegex = regexp.MustCompile(`<[^>]*>`)
+
+// MatchResult is the keyword gap between a job posting and a user's profile
+type MatchResult struct {
+	JobID           string   `json:"job_id"`
+	MatchedKeywords []string `json:"matched_keywords"`
+	MissingKeywords []string `json:"missing_keywords"`
+	Score           int      `json:"score"` // 0-100, share of job keywords covered by the profile
+}
+
+// PlainText strips HTML tags and entities from a job description
+func PlainText(s string) string {
+	return html.UnescapeString(tagRegex.ReplaceAllString(s, " "))
+}
+
+// KeywordGap compares keywords found in the job text against the profile's skills and experience
+func KeywordGap(jobText string, profile *models.UserProfile) MatchResult {
+	result := MatchResult{
+		MatchedKeywords: []string{},
+		MissingKeywords: []string{},
+	}
+
+	jobKeywords := findKeywords(jobText)
+
+	// Skills the user listed that the vocabulary doesn't know about still count if the job mentions them
+	skillSet := make(map[string]bool)
+	for _, skill := range profile.Skills {
+		skill = strings.TrimSpace(html.UnescapeString(skill))
+		if skill == "" {
+			continue
+		}
+		skillSet[strings.ToLower(skill)] = true
+		if containsTerm(jobText, skill, false) {
+			jobKeywords[canonicalName(skill)] = true
+		}
+	}
+
+	profileText := profileText(profile)
+	profileKeywords := findKeywords(profileText)
+
+	for name := range jobKeywords {
+		if profileKeywords[name] || skillSet[strings.ToLower(name)] {
+			result.MatchedKeywords = append(result.MatchedKeywords, name)
+		} else {
+			result.MissingKeywords = append(result.MissingKeywords, name)
+		}
+	}
+
+	sort.Strings(result.MatchedKeywords)
+	sort.Strings(result.MissingKeywords)
+
+	if total := len(jobKeywords); total > 0 {
+		result.Score = int(math.Round(float64(len(result.MatchedKeywords)) / float64(total) * 100))
+	}
+
+	return result
+}
+
+// profileText flattens everything in the profile that can evidence a skill
+func profileText(profile *models.UserProfile) string {
+	var sb strings.Builder
+	for _, skill := range profile.Skills {
+		sb.WriteString(skill)
+		sb.WriteString("\n")
+	}
+	for _, work := range profile.WorkHistory {
+		sb.WriteString(work.Title + "\n" + work.Description + "\n")
+	}
+	for _, edu := range profile.Education {
+		sb.WriteString(edu.Degree + " " + edu.Major + "\n")
+	}
+	return html.UnescapeString(sb.String())
+}
+
+// findKeywords returns the canonical names of vocabulary terms present in text
+func findKeywords(text string) map[string]bool {
+	found := make(map[string]bool)
+	for _, kw := range vocabulary {
+		aliases := kw.Aliases
+		if len(aliases) == 0 {
+			aliases = []string{kw.Name}
+		}
+		for _, alias := range aliases {
+			if containsTerm(text, alias, kw.CaseSensitive) {
+				found[kw.Name] = true
+				break
+			}
+		}
+	}
+	return found
+}
+
+// canonicalName maps a free-text skill onto its vocabulary name when it is a known alias
+func canonicalName(skill string) string {
+	lower := strings.ToLower(skill)
+	for _, kw := range vocabulary {
+		if strings.ToLower(kw.Name) == lower {
+			return kw.Name
+		}
+		for _, alias := range kw.Aliases {
+			if !kw.CaseSensitive && alias == lower {
+				return kw.Name
+			}
+		}
+	}
+	return skill
+}
+
+// containsTerm reports whether term occurs in text as a whole word. Symbols count as
+// part of a word so "c" doesn't match inside "c++" and "java" doesn't match "javascript".
+func containsTerm(text, term string, caseSensitive bool) bool {
+	if !caseSensitive {
+		text = strings.ToLower(text)
+		term = strings.ToLower(term)
+	}
+	for offset := 0; offset < len(text); {
+		idx := strings.Index(text[offset:], term)
+		if idx < 0 {
+			return false
+		}
+		start := offset + idx
+		end := start + len(term)
+		if (start == 0 || !isWordByte(text[start-1])) && (end == len(text) || !isWordByte(text[end])) {
+			return true
+		}
+		offset = start + 1
+	}
+	return false
+}
+
+func isWordByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' || b == '+' || b == '#' || b == '_' || b == '&'
+}
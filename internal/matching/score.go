@@ -0,0 +1,147 @@
+package matching
+
+import (
+	"math"
+	"strings"
+
+	"github.com/yourusername/jobapply/internal/models"
+)
+
+// Weights of each component in the overall 0-100 recommendation score
+const (
+	skillsWeight   = 50
+	titleWeight    = 25
+	locationWeight = 15
+	salaryWeight   = 10
+)
+
+// Distances (miles) at which the location component starts to drop and reaches zero
+const (
+	nearbyMiles = 30.0
+	farMiles    = 150.0
+)
+
+// JobFeatures is what the scorer needs to know about a job
+type JobFeatures struct {
+	Title         string
+	Description   string // Plain text or HTML
+	Location      string
+	Remote        bool
+	DistanceMiles *float64 // From the user's address; nil if either side isn't geocoded
+	SalaryMin     *int
+	SalaryMax     *int
+}
+
+// Score is a job's fit for a user; each component is scaled to its weight
+type Score struct {
+	Total    int `json:"total"`
+	Skills   int `json:"skills"`
+	Title    int `json:"title"`
+	Location int `json:"location"`
+	Salary   int `json:"salary"`
+}
+
+// ScoreJob rates how well a job fits the profile. Components with no data to judge
+// (no salary listed, ungeocoded locations) get half credit so they neither help nor hurt much.
+func ScoreJob(job JobFeatures, profile *models.UserProfile) Score {
+	var s Score
+
+	gap := KeywordGap(job.Title+"\n"+PlainText(job.Description), profile)
+	if len(gap.MatchedKeywords)+len(gap.MissingKeywords) == 0 {
+		s.Skills = skillsWeight / 2
+	} else {
+		s.Skills = gap.Score * skillsWeight / 100
+	}
+
+	s.Title = int(math.Round(titleSimilarity(job.Title, profile) * titleWeight))
+	s.Location = int(math.Round(locationFit(job, profile) * locationWeight))
+	s.Salary = int(math.Round(salaryFit(job, profile) * salaryWeight))
+
+	s.Total = s.Skills + s.Title + s.Location + s.Salary
+	return s
+}
+
+// titleWords drops seniority and filler words so "Senior Backend Engineer II" ~ "Backend Engineer"
+var titleStopWords = map[string]bool{
+	"senior": true, "sr": true, "junior": true, "jr": true, "lead": true, "staff": true, "principal": true,
+	"i": true, "ii": true, "iii": true, "iv": true, "the": true, "and": true, "of": true, "for": true,
+	"a": true, "an": true, "to": true, "in": true, "with": true, "remote": true, "hybrid": true,
+}
+
+func titleWords(title string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '+' || r == '#')
+	})
+	words := fields[:0]
+	for _, f := range fields {
+		if !titleStopWords[f] {
+			words = append(words, f)
+		}
+	}
+	return words
+}
+
+// titleSimilarity is the best share of the job title's words found in any past job title
+func titleSimilarity(jobTitle string, profile *models.UserProfile) float64 {
+	jobWords := titleWords(jobTitle)
+	if len(jobWords) == 0 {
+		return 0
+	}
+
+	best := 0.0
+	for _, work := range profile.WorkHistory {
+		have := make(map[string]bool)
+		for _, w := range titleWords(work.Title) {
+			have[w] = true
+		}
+		matched := 0
+		for _, w := range jobWords {
+			if have[w] {
+				matched++
+			}
+		}
+		if sim := float64(matched) / float64(len(jobWords)); sim > best {
+			best = sim
+		}
+	}
+	return best
+}
+
+func locationFit(job JobFeatures, profile *models.UserProfile) float64 {
+	if job.Remote {
+		return 1
+	}
+	if job.DistanceMiles != nil {
+		d := *job.DistanceMiles
+		switch {
+		case d <= nearbyMiles:
+			return 1
+		case d >= farMiles:
+			return 0
+		default:
+			return 1 - (d-nearbyMiles)/(farMiles-nearbyMiles)
+		}
+	}
+	if profile.Address != nil && profile.Address.City != "" && job.Location != "" &&
+		strings.Contains(strings.ToLower(job.Location), strings.ToLower(profile.Address.City)) {
+		return 1
+	}
+	return 0.5
+}
+
+func salaryFit(job JobFeatures, profile *models.UserProfile) float64 {
+	if profile.DesiredSalary == nil || *profile.DesiredSalary <= 0 {
+		return 0.5
+	}
+	top := job.SalaryMax
+	if top == nil {
+		top = job.SalaryMin
+	}
+	if top == nil {
+		return 0.5
+	}
+	if *top >= *profile.DesiredSalary {
+		return 1
+	}
+	return float64(*top) / float64(*profile.DesiredSalary)
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/yourusername/jobapply/internal/apierror"
+	"github.com/yourusername/jobapply/internal/redis"
+)
+
+// rateLimitScript counts a request against a fixed window and tracks violations, atomically so
+// every instance sees the same counts. KEYS: request counter, violation counter. ARGV: limit,
+// window ms, violations before blocking, violation memory ms. Returns 1 allowed, 0 limited,
+// -1 blocked.
+const rateLimitScript = `
+local violations = tonumber(redis.call('GET', KEYS[2]) or '0')
+if violations > tonumber(ARGV[3]) then
+	return -1
+end
+local n = redis.call('INCR', KEYS[1])
+if n == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+if n > tonumber(ARGV[1]) then
+	redis.call('INCR', KEYS[2])
+	redis.call('PEXPIRE', KEYS[2], ARGV[4])
+	return 0
+end
+return 1
+`
+
+// RedisRateLimiter applies the same limits as RateLimiter, with the counts kept in Redis so
+// they hold across every instance behind a load balancer
+type RedisRateLimiter struct {
+	client *redis.Client
+	rate   int           // requests per window
+	window time.Duration // time window
+	prefix string
+}
+
+func NewRedisRateLimiter(client *redis.Client, requestsPerMinute int) *RedisRateLimiter {
+	return &RedisRateLimiter{
+		client: client,
+		rate:   requestsPerMinute,
+		window: time.Minute,
+		prefix: "jobapply:ratelimit:",
+	}
+}
+
+func (rl *RedisRateLimiter) allow(ctx context.Context, ip string) (int64, error) {
+	reply, err := rl.client.Do(ctx, "EVAL", rateLimitScript, 2,
+		rl.prefix+"requests:"+ip, rl.prefix+"violations:"+ip,
+		rl.rate, rl.window.Milliseconds(), maxViolations, violationMemory.Milliseconds())
+	if err != nil {
+		return 0, err
+	}
+	result, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected rate limit reply %v", reply)
+	}
+	return result, nil
+}
+
+// Middleware applies rate limiting. If Redis is unreachable requests are let through rather
+// than taking the API down with it.
+func (rl *RedisRateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result, err := rl.allow(r.Context(), ClientIP(r))
+		if err != nil {
+			slog.Warn("Rate limiter unavailable, allowing request", "error", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		switch result {
+		case -1:
+			apierror.Write(w, apierror.New(http.StatusTooManyRequests, "Too many violations. Temporarily blocked."))
+			return
+		case 0:
+			w.Header().Set("Retry-After", "60")
+			apierror.Write(w, apierror.New(http.StatusTooManyRequests, "Rate limit exceeded. Please try again later."))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
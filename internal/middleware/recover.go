@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+	"runtime/debug"
+
+	"github.com/yourusername/jobapply/internal/apierror"
+	"github.com/yourusername/jobapply/internal/logging"
+)
+
+// Recover turns a panicking handler into a logged 500 in the standard error envelope, instead
+// of a dropped connection
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec) // Deliberate abort; let net/http handle it
+			}
+			logging.FromContext(r.Context()).Error("Handler panicked", "panic", rec, "stack", string(debug.Stack()))
+			apierror.Write(w, apierror.New(http.StatusInternalServerError, "Internal server error"))
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
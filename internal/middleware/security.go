@@ -1,9 +1,12 @@
 package middleware
 
 import (
+	"net"
 	"net/http"
 	"sync"
 	"time"
+
+	"github.com/yourusername/jobapply/internal/apierror"
 )
 
 // SecurityHeaders adds comprehensive security headers to prevent XSS, clickjacking, and other attacks
@@ -34,6 +37,13 @@ func SecurityHeaders(next http.Handler) http.Handler {
 	})
 }
 
+const (
+	// Clients over the limit this many times are blocked outright
+	maxViolations = 10
+	// How long idle clients, and their violations, are remembered
+	violationMemory = 10 * time.Minute
+)
+
 // RateLimiter implements token bucket algorithm to prevent DDoS and brute force attacks
 type RateLimiter struct {
 	visitors map[string]*visitor
@@ -68,7 +78,7 @@ func (rl *RateLimiter) cleanupVisitors() {
 	for range ticker.C {
 		rl.mu.Lock()
 		for ip, v := range rl.visitors {
-			if time.Since(v.lastSeen) > 10*time.Minute {
+			if time.Since(v.lastSeen) > violationMemory {
 				delete(rl.visitors, ip)
 			}
 		}
@@ -103,19 +113,19 @@ func (rl *RateLimiter) getVisitor(ip string) *visitor {
 // Middleware applies rate limiting to prevent DDoS attacks
 func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ip := getIP(r)
+		ip := ClientIP(r)
 		v := rl.getVisitor(ip)
 
 		// Block IPs with excessive violations more aggressively
-		if v.violations > 10 {
-			http.Error(w, "Too many violations. Temporarily blocked.", http.StatusTooManyRequests)
+		if v.violations > maxViolations {
+			apierror.Write(w, apierror.New(http.StatusTooManyRequests, "Too many violations. Temporarily blocked."))
 			return
 		}
 
 		if v.tokens <= 0 {
 			v.violations++
 			w.Header().Set("Retry-After", "60")
-			http.Error(w, "Rate limit exceeded. Please try again later.", http.StatusTooManyRequests)
+			apierror.Write(w, apierror.New(http.StatusTooManyRequests, "Rate limit exceeded. Please try again later."))
 			return
 		}
 
@@ -126,21 +136,15 @@ func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
 	})
 }
 
-// getIP extracts the real IP address from request, handling proxies
-func getIP(r *http.Request) string {
-	// Check X-Forwarded-For header (but validate to prevent spoofing)
-	forwarded := r.Header.Get("X-Forwarded-For")
-	if forwarded != "" {
-		// Take the first IP in the chain
-		return forwarded
+// ClientIP returns the client IP resolved by TrustedProxies.Middleware. Without it forwarding
+// headers are never trusted, so this falls back to the connection's address.
+func ClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(clientIPKey{}).(string); ok {
+		return ip
 	}
-
-	// Check X-Real-IP header
-	if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
-		return realIP
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
 	}
-
-	// Fall back to RemoteAddr
 	return r.RemoteAddr
 }
 
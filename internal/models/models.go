@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 // Address represents a user's address
 type Address struct {
@@ -37,7 +40,327 @@ type UserProfile struct {
 	WorkHistory []WorkHistory `json:"work_history,omitempty"`
 	Education   []Education   `json:"education,omitempty"`
 	ResumeURL   *string       `json:"resume_url,omitempty"`
-	Skills      []string      `json:"skills,omitempty"`
-	CreatedAt   time.Time     `json:"created_at"`
-	UpdatedAt   time.Time     `json:"updated_at"`
+	// ResumePreviewURL is a PNG of the resume's first page, once one has been rendered
+	ResumePreviewURL *string  `json:"resume_preview_url,omitempty"`
+	Skills           []string `json:"skills,omitempty"`
+	// DesiredSalary is the minimum yearly salary the user is looking for, used in job scoring
+	DesiredSalary *int      `json:"desired_salary,omitempty"`
+	Latitude      *float64  `json:"latitude,omitempty"`  // Set by geocoding the address
+	Longitude     *float64  `json:"longitude,omitempty"` // Set by geocoding the address
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ContactInfo represents the contact details found at the top of a resume
+type ContactInfo struct {
+	FullName string `json:"full_name,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Phone    string `json:"phone,omitempty"`
+	Location string `json:"location,omitempty"`
+}
+
+// ParsedResume represents structured data extracted from an uploaded resume
+type ParsedResume struct {
+	Contact        ContactInfo   `json:"contact"`
+	WorkHistory    []WorkHistory `json:"work_history"`
+	Education      []Education   `json:"education"`
+	Skills         []string      `json:"skills"`
+	Certifications []string      `json:"certifications"`
+	Links          []string      `json:"links"`
+	UsedOCR        bool          `json:"used_ocr"`
+	Backend        string        `json:"backend"`
+}
+
+// Diff statuses used when reviewing imported data against the current profile
+const (
+	DiffNew       = "new"
+	DiffConflict  = "conflict"
+	DiffUnchanged = "unchanged"
+)
+
+// FieldDiff compares a single profile field with its imported value
+type FieldDiff struct {
+	Field   string `json:"field"`
+	Status  string `json:"status"`
+	Current string `json:"current,omitempty"`
+	Parsed  string `json:"parsed"`
+}
+
+// WorkHistoryDiff compares an imported work entry with the matching profile entry, if any
+type WorkHistoryDiff struct {
+	Status       string       `json:"status"`
+	CurrentIndex *int         `json:"current_index,omitempty"`
+	Current      *WorkHistory `json:"current,omitempty"`
+	Parsed       WorkHistory  `json:"parsed"`
+}
+
+// EducationDiff compares an imported education entry with the matching profile entry, if any
+type EducationDiff struct {
+	Status       string     `json:"status"`
+	CurrentIndex *int       `json:"current_index,omitempty"`
+	Current      *Education `json:"current,omitempty"`
+	Parsed       Education  `json:"parsed"`
+}
+
+// SkillDiff reports whether an imported skill is already on the profile
+type SkillDiff struct {
+	Skill  string `json:"skill"`
+	Status string `json:"status"`
+}
+
+// ProfileDiff is imported profile data laid out for review before merging
+type ProfileDiff struct {
+	ProfileUpdatedAt time.Time         `json:"profile_updated_at"`
+	Fields           []FieldDiff       `json:"fields"`
+	WorkHistory      []WorkHistoryDiff `json:"work_history"`
+	Education        []EducationDiff   `json:"education"`
+	Skills           []SkillDiff       `json:"skills"`
+	Certifications   []string          `json:"certifications"`
+	Links            []string          `json:"links"`
+	UsedOCR          bool              `json:"used_ocr"`
+	Backend          string            `json:"backend"`
+}
+
+// WorkHistoryChange is an accepted work entry; ReplaceIndex set means it overwrites a conflicting entry
+type WorkHistoryChange struct {
+	ReplaceIndex *int        `json:"replace_index,omitempty"`
+	Entry        WorkHistory `json:"entry"`
+}
+
+// EducationChange is an accepted education entry; ReplaceIndex set means it overwrites a conflicting entry
+type EducationChange struct {
+	ReplaceIndex *int      `json:"replace_index,omitempty"`
+	Entry        Education `json:"entry"`
+}
+
+// ProfileMergeRequest lists only the imported changes the user accepted
+type ProfileMergeRequest struct {
+	ProfileUpdatedAt time.Time           `json:"profile_updated_at"`
+	Fields           map[string]string   `json:"fields,omitempty"`
+	WorkHistory      []WorkHistoryChange `json:"work_history,omitempty"`
+	Education        []EducationChange   `json:"education,omitempty"`
+	Skills           []string            `json:"skills,omitempty"`
+}
+
+// Persona is a named variant of the user's profile (e.g. "Data Engineer") with its own
+// summary, resume and skills. Empty fields fall back to the base profile.
+type Persona struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	Headline       string    `json:"headline,omitempty"`
+	Summary        string    `json:"summary,omitempty"`
+	TargetKeywords string    `json:"target_keywords,omitempty"`
+	ResumeURL      *string   `json:"resume_url,omitempty"`
+	Skills         []string  `json:"skills,omitempty"`
+	IsDefault      bool      `json:"is_default"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	// DeletedAt is set on deleted profiles, listed with ?deleted=true until they are purged
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// ApplicationTemplate holds the answers to an employer's standard questions and the profile
+// to apply with, so repeated applications to the same employer are filled in the same way
+type ApplicationTemplate struct {
+	ID string `json:"id"`
+	// Employer is a lowercased company name or domain, e.g. "acme staffing" or "acme.com"
+	Employer string `json:"employer"`
+	// PersonaID is the named profile, and so the resume, to apply with; nil for the default
+	PersonaID *string `json:"persona_id,omitempty"`
+	// Answers maps a question's field key or text to the answer to give
+	Answers   map[string]string `json:"answers"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// User holds the account credentials behind a profile
+type User struct {
+	ID           string
+	FullName     string
+	Email        string
+	PasswordHash string
+	IsAdmin      bool
+}
+
+// UserStats summarizes a user's job search
+type UserStats struct {
+	JobsFound            int            `json:"jobs_found"`
+	JobsSaved            int            `json:"jobs_saved"`
+	Applications         int            `json:"applications"`
+	ApplicationsByStatus map[string]int `json:"applications_by_status"`
+}
+
+// JobListing is a scraped job as shown in the user's job lists
+type JobListing struct {
+	ID            string     `json:"id"`
+	Site          string     `json:"site"`
+	Title         string     `json:"title"`
+	Company       string     `json:"company"`
+	CompanyID     *string    `json:"company_id,omitempty"` // See GET /companies/{id}
+	Location      string     `json:"location"`
+	URL           string     `json:"url"`
+	PostedDate    *time.Time `json:"posted_date,omitempty"`
+	SalaryMin     *int       `json:"salary_min,omitempty"`
+	SalaryMax     *int       `json:"salary_max,omitempty"`  // Annualized, in USD
+	SalaryText    string     `json:"salary_text,omitempty"` // As written in the posting
+	ScrapedAt     time.Time  `json:"scraped_at"`
+	Status        string     `json:"status"` // "open", or "closed" once the posting was found removed
+	Tags          []string   `json:"tags,omitempty"`
+	DistanceMiles *float64   `json:"distance_miles,omitempty"`
+	// CommuteMinutes is the estimated travel time from the user's address by the requested
+	// commute_mode, once routed
+	CommuteMinutes *int `json:"commute_minutes,omitempty"`
+	// GhostScore is the 0-100 confidence that nobody is really hiring, or that an agency is
+	// reposting, with the reasons; nil until scored
+	GhostScore   *int     `json:"ghost_score,omitempty"`
+	GhostReasons []string `json:"ghost_reasons,omitempty"`
+	Score        *int     `json:"score,omitempty"` // 0-100 fit for the user's profile
+}
+
+// JobDetail is a job with its full description
+type JobDetail struct {
+	JobListing
+	Description string `json:"description"` // Sanitized HTML, or plain text
+	// DescriptionMarkdown is the description converted to Markdown, for clients that would
+	// rather not render HTML
+	DescriptionMarkdown string `json:"description_markdown,omitempty"`
+	// DescriptionSource is "scraped", "fetched" (loaded on demand just now) or "" if unavailable
+	DescriptionSource string `json:"description_source,omitempty"`
+	// Agency is the staffing agency the posting came through, e.g. "TekSystems" for
+	// "Google (via TekSystems)"
+	Agency string `json:"agency,omitempty"`
+}
+
+// SavedJob is a job on the user's shortlist
+type SavedJob struct {
+	JobListing
+	Notes    string    `json:"notes,omitempty"`
+	Priority int       `json:"priority"`
+	SavedAt  time.Time `json:"saved_at"`
+}
+
+// ApplicationStatus is an application's state, as stored in applications.status. Changes
+// must follow applicationTransitions; the store rejects any other.
+type ApplicationStatus string
+
+const (
+	ApplicationPending    ApplicationStatus = "pending"
+	ApplicationInProgress ApplicationStatus = "in_progress"
+	ApplicationPaused     ApplicationStatus = "paused"
+	ApplicationSubmitted  ApplicationStatus = "submitted"
+	ApplicationFailed     ApplicationStatus = "failed"
+	ApplicationCancelled  ApplicationStatus = "cancelled"
+	ApplicationTimeout    ApplicationStatus = "timeout"
+)
+
+// applicationTransitions lists the states each state may move to. Submitted and cancelled
+// are final; failed and timed out applications may be retried.
+var applicationTransitions = map[ApplicationStatus][]ApplicationStatus{
+	ApplicationPending:    {ApplicationInProgress, ApplicationCancelled},
+	ApplicationInProgress: {ApplicationPaused, ApplicationSubmitted, ApplicationFailed, ApplicationTimeout, ApplicationCancelled},
+	ApplicationPaused:     {ApplicationInProgress, ApplicationTimeout, ApplicationCancelled},
+	ApplicationFailed:     {ApplicationInProgress, ApplicationCancelled},
+	ApplicationTimeout:    {ApplicationInProgress, ApplicationCancelled},
+	ApplicationSubmitted:  {},
+	ApplicationCancelled:  {},
+}
+
+// Valid reports whether s is a known state
+func (s ApplicationStatus) Valid() bool {
+	_, ok := applicationTransitions[s]
+	return ok
+}
+
+// Initial reports whether an application may be created in state s
+func (s ApplicationStatus) Initial() bool {
+	return s == ApplicationPending || s == ApplicationInProgress
+}
+
+// Final reports whether s is a state an application never leaves
+func (s ApplicationStatus) Final() bool {
+	return s.Valid() && len(applicationTransitions[s]) == 0
+}
+
+// CanTransition reports whether an application in state s may move to state to
+func (s ApplicationStatus) CanTransition(to ApplicationStatus) bool {
+	return slices.Contains(applicationTransitions[s], to)
+}
+
+// ApplicationEvent records an application changing state. From is empty for the event
+// recording the application's creation.
+type ApplicationEvent struct {
+	ID            string            `json:"id"`
+	ApplicationID string            `json:"application_id"`
+	From          ApplicationStatus `json:"from,omitempty"`
+	To            ApplicationStatus `json:"to"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
+
+// QuestionPage is the custom questions found on one page of an application form, which the
+// application paused for, and the user's answers
+type QuestionPage struct {
+	Page      int                   `json:"page"` // 0 for the first page with questions
+	AskedAt   time.Time             `json:"asked_at"`
+	Questions []ApplicationQuestion `json:"questions"`
+}
+
+// ApplicationQuestion is a custom question and, once given, its answer
+type ApplicationQuestion struct {
+	Key      string `json:"key"`
+	Question string `json:"question"`
+	// Class is the kind of answer asked for, e.g. "years_of_experience"; see autofill.QuestionClass
+	Class      string     `json:"class"`
+	Answer     *string    `json:"answer,omitempty"`
+	AnsweredAt *time.Time `json:"answered_at,omitempty"`
+	// SuggestedAnswer is filled in from the profile for unanswered questions it can answer
+	SuggestedAnswer *string `json:"suggested_answer,omitempty"`
+}
+
+// Application is a submitted job application
+type Application struct {
+	ID           string    `json:"id"`
+	Status       string    `json:"status"` // One of the ApplicationStatus values
+	AppliedAt    time.Time `json:"applied_at"`
+	FieldsFilled []string  `json:"fields_filled"`
+	// Fields left blank because the user marked them never-autofill
+	FieldsOmitted []string `json:"fields_omitted,omitempty"`
+	ProfileID     *string  `json:"profile_id,omitempty"`
+	JobID         string   `json:"job_id"`
+	JobTitle      string   `json:"job_title"`
+	Company       string   `json:"company"`
+	JobURL        string   `json:"job_url"`
+	Tags          []string `json:"tags"`
+	// Source is "import" for applications imported from elsewhere, "manual" for ones recorded
+	// by the browser extension, and "" for jobapply's own
+	Source string `json:"source,omitempty"`
+	// ExternalStatus is the status as tracked elsewhere, e.g. "interviewing" in a spreadsheet
+	ExternalStatus string `json:"external_status,omitempty"`
+	// DeletedAt is set on deleted applications, listed with ?deleted=true until they are purged
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// NotificationKind says what a notification is about
+type NotificationKind string
+
+const (
+	// NotificationApplicationPaused: an application is waiting for answers to its questions
+	NotificationApplicationPaused NotificationKind = "application_paused"
+	// NotificationNewJobs: a scrape found jobs the user hasn't seen before
+	NotificationNewJobs NotificationKind = "new_jobs"
+	// NotificationReminder: something has been left waiting, e.g. a saved job not applied to
+	NotificationReminder NotificationKind = "reminder"
+	// NotificationScrapeFailed: a job source failed during a scrape
+	NotificationScrapeFailed NotificationKind = "scrape_failed"
+)
+
+// Notification is an entry in the user's in-app inbox
+type Notification struct {
+	ID    string           `json:"id"`
+	Kind  NotificationKind `json:"kind"`
+	Title string           `json:"title"`
+	Body  string           `json:"body,omitempty"`
+	// Link is the API path of what the notification is about, e.g. /api/v1/jobs/{id}
+	Link      string     `json:"link,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
 }
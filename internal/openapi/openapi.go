@@ -0,0 +1,356 @@
+// Package openapi builds an OpenAPI 3 document from a table of operations, deriving request
+// and response schemas from the Go types handlers encode, so the spec can't drift from the
+// JSON the API actually sends.
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version is the OpenAPI version documents are written against
+const Version = "3.0.3"
+
+var pathParamRegex = regexp.MustCompile(`\{([^}]+)\}`)
+
+// Operation describes one route. Request and Response are zero values of the types decoded
+// from and encoded to the body, e.g. LoginRequest{}; nil means no JSON body.
+type Operation struct {
+	Method  string
+	Path    string // chi pattern, e.g. /api/v1/jobs/{id}
+	Summary string
+	Tag     string
+	Public  bool // Served without a bearer token or session cookie
+	Params  []Param
+	Request any
+	// Upload names the multipart file field for upload endpoints, instead of a JSON request
+	Upload string
+	// Response defaults to status 200 unless Status is set
+	Response any
+	Status   int
+	// ContentType is set for non-JSON responses such as application/pdf
+	ContentType string
+}
+
+// Param is a query or header parameter. Path parameters are taken from the pattern automatically.
+type Param struct {
+	Name        string
+	Description string
+	Type        string // string (default), integer, number or boolean
+	Enum        []string
+	Repeated    bool // May be given more than once, e.g. ?tag=a&tag=b
+	Header      bool // Sent as a request header rather than in the query string
+}
+
+// Info is the document's title and version
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Document is an OpenAPI 3 document, ready to be encoded as JSON
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Paths      map[string]map[string]*opObject `json:"paths"`
+	Components components                      `json:"components"`
+}
+
+type components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]securityScheme `json:"securitySchemes"`
+}
+
+type securityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+}
+
+type opObject struct {
+	Summary     string                `json:"summary,omitempty"`
+	OperationID string                `json:"operationId"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []paramObject         `json:"parameters,omitempty"`
+	RequestBody *bodyObject           `json:"requestBody,omitempty"`
+	Responses   map[string]bodyObject `json:"responses"`
+	// Empty for public routes, bearerAuth or cookieAuth otherwise
+	Security *[]map[string][]string `json:"security,omitempty"`
+}
+
+type paramObject struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Explode     *bool   `json:"explode,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type bodyObject struct {
+	Description string                     `json:"description,omitempty"`
+	Required    bool                       `json:"required,omitempty"`
+	Content     map[string]mediaTypeObject `json:"content,omitempty"`
+}
+
+type mediaTypeObject struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema is the subset of JSON Schema used by OpenAPI 3.0 that Go types map onto
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+// errorSchema is the envelope every error is written in (see package apierror)
+var errorSchema = &Schema{
+	Type: "object",
+	Properties: map[string]*Schema{"error": {
+		Type: "object",
+		Properties: map[string]*Schema{
+			"code":    {Type: "string"},
+			"message": {Type: "string"},
+			"fields": {Type: "array", Items: &Schema{
+				Type:       "object",
+				Properties: map[string]*Schema{"field": {Type: "string"}, "message": {Type: "string"}},
+				Required:   []string{"field", "message"},
+			}},
+			"request_id": {Type: "string"},
+		},
+		Required: []string{"code", "message"},
+	}},
+	Required: []string{"error"},
+}
+
+// Build assembles the document for ops. Operations are documented as requiring a bearer
+// token or session cookie unless marked Public.
+func Build(info Info, ops []Operation) *Document {
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]map[string]*opObject),
+		Components: components{
+			Schemas: map[string]*Schema{"Error": errorSchema},
+			SecuritySchemes: map[string]securityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+				// In the cookie auth modes; writes also need the X-CSRF-Token header
+				"cookieAuth": {Type: "apiKey", In: "cookie", Name: "jobapply_session"},
+			},
+		},
+	}
+	schemas := &schemaBuilder{defs: doc.Components.Schemas}
+	errorResponse := bodyObject{
+		Description: "Error",
+		Content:     map[string]mediaTypeObject{"application/json": {Schema: &Schema{Ref: "#/components/schemas/Error"}}},
+	}
+
+	for _, op := range ops {
+		o := &opObject{
+			Summary:     op.Summary,
+			OperationID: operationID(op.Method, op.Path),
+			Responses:   map[string]bodyObject{"default": errorResponse},
+		}
+		if op.Tag != "" {
+			o.Tags = []string{op.Tag}
+		}
+		if op.Public {
+			o.Security = &[]map[string][]string{}
+		} else {
+			o.Security = &[]map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}}
+		}
+
+		for _, m := range pathParamRegex.FindAllStringSubmatch(op.Path, -1) {
+			o.Parameters = append(o.Parameters, paramObject{Name: m[1], In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+		for _, p := range op.Params {
+			o.Parameters = append(o.Parameters, queryParam(p))
+		}
+
+		switch {
+		case op.Upload != "":
+			o.RequestBody = &bodyObject{Required: true, Content: map[string]mediaTypeObject{
+				"multipart/form-data": {Schema: &Schema{
+					Type:       "object",
+					Properties: map[string]*Schema{op.Upload: {Type: "string", Format: "binary"}},
+					Required:   []string{op.Upload},
+				}},
+			}}
+		case op.Request != nil:
+			o.RequestBody = &bodyObject{Required: true, Content: map[string]mediaTypeObject{
+				"application/json": {Schema: schemas.schema(reflect.TypeOf(op.Request))},
+			}}
+		}
+
+		status := op.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		response := bodyObject{Description: http.StatusText(status)}
+		switch {
+		case op.ContentType != "":
+			response.Content = map[string]mediaTypeObject{op.ContentType: {Schema: &Schema{Type: "string", Format: "binary"}}}
+		case op.Response != nil:
+			response.Content = map[string]mediaTypeObject{"application/json": {Schema: schemas.schema(reflect.TypeOf(op.Response))}}
+		}
+		o.Responses[strconv.Itoa(status)] = response
+
+		path := doc.Paths[op.Path]
+		if path == nil {
+			path = make(map[string]*opObject)
+			doc.Paths[op.Path] = path
+		}
+		path[strings.ToLower(op.Method)] = o
+	}
+	return doc
+}
+
+func queryParam(p Param) paramObject {
+	typ := p.Type
+	if typ == "" {
+		typ = "string"
+	}
+	schema := &Schema{Type: typ, Enum: p.Enum}
+	param := paramObject{Name: p.Name, In: "query", Description: p.Description, Schema: schema}
+	if p.Header {
+		param.In = "header"
+	}
+	if p.Repeated {
+		explode := true
+		param.Explode = &explode
+		param.Schema = &Schema{Type: "array", Items: schema}
+	}
+	return param
+}
+
+// operationID derives a stable ID such as getApiV1JobsById for SDK generators
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, segment := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '-' }) {
+		if m := pathParamRegex.FindStringSubmatch(segment); m != nil {
+			b.WriteString("By")
+			segment = m[1]
+		}
+		b.WriteString(strings.ToUpper(segment[:1]) + segment[1:])
+	}
+	return b.String()
+}
+
+type schemaBuilder struct {
+	defs map[string]*Schema
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schema maps a Go type to a schema the way encoding/json would encode it. Named structs are
+// registered as components and referenced, so shared types appear once.
+func (b *schemaBuilder) schema(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Pointer {
+		s := b.schema(t.Elem())
+		if s.Ref != "" {
+			// $ref siblings are ignored in OpenAPI 3.0, so nullability needs a wrapper
+			return &Schema{Nullable: true, AllOf: []*Schema{s}}
+		}
+		s.Nullable = true
+		return s
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: b.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		name := t.Name()
+		if _, ok := b.defs[name]; !ok {
+			// Registered before recursing so self-referencing types terminate
+			b.defs[name] = &Schema{}
+			*b.defs[name] = *b.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	default:
+		// interface{} and anything else encode as arbitrary JSON
+		return &Schema{}
+	}
+}
+
+func (b *schemaBuilder) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	b.addFields(s, t)
+	sort.Strings(s.Required)
+	return s
+}
+
+// addFields adds t's JSON fields to s, flattening embedded structs like encoding/json does
+func (b *schemaBuilder) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				b.addFields(s, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		s.Properties[name] = b.schema(f.Type)
+		if !strings.Contains(","+opts+",", ",omitempty,") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
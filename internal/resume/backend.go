@@ -0,0 +1,167 @@
+package resume
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/yourusername/jobapply/internal/models"
+)
+
+// Backend names selectable via RESUME_PARSER_BACKEND or the ?backend= query parameter
+const (
+	BackendHeuristic = "heuristic"
+	BackendLLM       = "llm"
+)
+
+var ErrUnknownBackend = errors.New("unknown resume parser backend")
+
+// ResumeParserBackend turns extracted resume text into structured data
+type ResumeParserBackend interface {
+	Name() string
+	ParseText(ctx context.Context, text string) (*models.ParsedResume, error)
+}
+
+// HeuristicBackend is the default regex/layout based parser
+type HeuristicBackend struct{}
+
+func (HeuristicBackend) Name() string { return BackendHeuristic }
+
+func (HeuristicBackend) ParseText(ctx context.Context, text string) (*models.ParsedResume, error) {
+	return ParseText(text), nil
+}
+
+// LLMBackend asks an OpenAI-compatible chat completions endpoint to extract the resume as JSON.
+// It copes with two-column and creative layouts that defeat the heuristics.
+type LLMBackend struct {
+	endpoint string
+	apiKey   string
+	model    string
+	client   *http.Client
+}
+
+func NewLLMBackend(endpoint, apiKey, model string) *LLMBackend {
+	return &LLMBackend{
+		endpoint: endpoint,
+		apiKey:   apiKey,
+		model:    model,
+		client: &http.Client{
+			Timeout: 20 * time.Second,
+		},
+	}
+}
+
+func (b *LLMBackend) Name() string { return BackendLLM }
+
+// maxLLMInputChars keeps prompts (and cost) bounded; real resumes are far shorter
+const maxLLMInputChars = 20000
+
+const llmSystemPrompt = `You extract structured data from resume text. Respond with a single JSON object and nothing else, using exactly this shape:
+{"contact":{"full_name":"","email":"","phone":"","location":""},
+ "work_history":[{"company":"","title":"","start_date":"YYYY-MM-DD","end_date":"YYYY-MM-DD","description":""}],
+ "education":[{"school":"","degree":"","major":"","grad_year":0}],
+ "skills":[""],"certifications":[""],"links":[""]}
+Use the first day of the month when only month and year are known. Leave end_date empty for current positions. Omit anything not present in the text; never invent data.`
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model          string            `json:"model"`
+	Messages       []chatMessage     `json:"messages"`
+	Temperature    float64           `json:"temperature"`
+	ResponseFormat map[string]string `json:"response_format"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+func (b *LLMBackend) ParseText(ctx context.Context, text string) (*models.ParsedResume, error) {
+	if len(text) > maxLLMInputChars {
+		text = text[:maxLLMInputChars]
+	}
+
+	body, err := json.Marshal(chatRequest{
+		Model: b.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: llmSystemPrompt},
+			{Role: "user", Content: text},
+		},
+		Temperature:    0,
+		ResponseFormat: map[string]string{"type": "json_object"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if b.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+b.apiKey)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("LLM request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("LLM returned status %d: %s", resp.StatusCode, snippet)
+	}
+
+	var chatResp chatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
+		return nil, fmt.Errorf("failed to parse LLM response: %w", err)
+	}
+	if len(chatResp.Choices) == 0 {
+		return nil, fmt.Errorf("LLM returned no choices")
+	}
+
+	// Some models wrap JSON in a markdown fence even when asked not to
+	content := strings.TrimSpace(chatResp.Choices[0].Message.Content)
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.Trim(content, "`\n ")
+
+	var parsed models.ParsedResume
+	if err := json.Unmarshal([]byte(content), &parsed); err != nil {
+		return nil, fmt.Errorf("LLM output is not valid resume JSON: %w", err)
+	}
+
+	normalize(&parsed)
+	return &parsed, nil
+}
+
+// normalize ensures list fields encode as [] rather than null
+func normalize(parsed *models.ParsedResume) {
+	if parsed.WorkHistory == nil {
+		parsed.WorkHistory = []models.WorkHistory{}
+	}
+	if parsed.Education == nil {
+		parsed.Education = []models.Education{}
+	}
+	if parsed.Skills == nil {
+		parsed.Skills = []string{}
+	}
+	if parsed.Certifications == nil {
+		parsed.Certifications = []string{}
+	}
+	if parsed.Links == nil {
+		parsed.Links = []string{}
+	}
+}
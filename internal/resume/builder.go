@@ -0,0 +1,285 @@
+package resume
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/jobapply/internal/models"
+)
+
+var ErrUnknownTemplate = errors.New("unknown resume template")
+
+// Template controls the look of a generated resume
+type Template struct {
+	Name         string
+	NameSize     float64
+	BodySize     float64
+	Accent       [3]float64
+	CenterHeader bool
+	RuleUnder    bool // Horizontal rule under each section heading
+}
+
+var templates = map[string]Template{
+	"modern": {
+		Name:      "modern",
+		NameSize:  24,
+		BodySize:  10,
+		Accent:    [3]float64{0.12, 0.35, 0.62},
+		RuleUnder: true,
+	},
+	"classic": {
+		Name:         "classic",
+		NameSize:     20,
+		BodySize:     10.5,
+		CenterHeader: true,
+		RuleUnder:    true,
+	},
+	"compact": {
+		Name:     "compact",
+		NameSize: 16,
+		BodySize: 9,
+		Accent:   [3]float64{0.2, 0.2, 0.2},
+	},
+}
+
+// TemplateNames lists available templates for error messages and the frontend picker
+func TemplateNames() []string {
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// US Letter in points with 0.75" margins
+const (
+	pageWidth  = 612.0
+	pageHeight = 792.0
+	margin     = 54.0
+)
+
+var black = [3]float64{0, 0, 0}
+var gray = [3]float64{0.35, 0.35, 0.35}
+
+// resumeLayout tracks the write cursor while flowing content down the pages
+type resumeLayout struct {
+	doc *pdfDocument
+	tpl Template
+	y   float64
+}
+
+// BuildPDF renders the profile's work history, education and skills as a PDF resume
+func BuildPDF(profile *models.UserProfile, templateName string) ([]byte, error) {
+	tpl, ok := templates[templateName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTemplate, templateName)
+	}
+
+	l := &resumeLayout{doc: newPDFDocument(pageWidth, pageHeight), tpl: tpl}
+	l.newPage()
+
+	l.header(profile)
+
+	if len(profile.WorkHistory) > 0 {
+		l.heading("Experience")
+		for _, work := range profile.WorkHistory {
+			title := clean(work.Title)
+			if company := clean(work.Company); company != "" {
+				if title != "" {
+					title += " — "
+				}
+				title += company
+			}
+			l.entryHeader(title, formatDateRange(work.StartDate, work.EndDate))
+			for _, line := range strings.Split(clean(work.Description), "\n") {
+				line = strings.TrimSpace(bulletPrefix.ReplaceAllString(line, ""))
+				if line != "" {
+					l.paragraph("• ", line, fontRegular)
+				}
+			}
+			l.y -= tpl.BodySize * 0.6
+		}
+	}
+
+	if len(profile.Education) > 0 {
+		l.heading("Education")
+		for _, edu := range profile.Education {
+			degree := clean(edu.Degree)
+			if major := clean(edu.Major); major != "" && !strings.Contains(strings.ToLower(degree), strings.ToLower(major)) {
+				if degree != "" {
+					degree += ", "
+				}
+				degree += major
+			}
+			year := ""
+			if edu.GradYear > 0 {
+				year = fmt.Sprintf("%d", edu.GradYear)
+			}
+			l.entryHeader(clean(edu.School), year)
+			if degree != "" {
+				l.paragraph("", degree, fontItalic)
+			}
+			l.y -= tpl.BodySize * 0.6
+		}
+	}
+
+	if len(profile.Skills) > 0 {
+		l.heading("Skills")
+		skills := make([]string, 0, len(profile.Skills))
+		for _, s := range profile.Skills {
+			if s = clean(s); s != "" {
+				skills = append(skills, s)
+			}
+		}
+		l.paragraph("", strings.Join(skills, ", "), fontRegular)
+	}
+
+	return l.doc.bytes(), nil
+}
+
+func (l *resumeLayout) newPage() {
+	l.doc.addPage()
+	l.y = pageHeight - margin
+}
+
+// ensure starts a new page when fewer than height points remain
+func (l *resumeLayout) ensure(height float64) {
+	if l.y-height < margin {
+		l.newPage()
+	}
+}
+
+func (l *resumeLayout) header(profile *models.UserProfile) {
+	name := clean(profile.FullName)
+	l.y -= l.tpl.NameSize
+	l.doc.text(l.alignX(name, fontBold, l.tpl.NameSize), l.y, fontBold, l.tpl.NameSize, l.tpl.Accent, name)
+
+	var contact []string
+	for _, part := range []string{clean(profile.Email), clean(profile.Phone), clean(profileLocation(profile))} {
+		if part != "" {
+			contact = append(contact, part)
+		}
+	}
+	if len(contact) > 0 {
+		line := strings.Join(contact, "  |  ")
+		l.y -= l.tpl.BodySize * 1.8
+		l.doc.text(l.alignX(line, fontRegular, l.tpl.BodySize), l.y, fontRegular, l.tpl.BodySize, gray, line)
+	}
+	l.y -= l.tpl.BodySize
+}
+
+func (l *resumeLayout) alignX(s string, font pdfFont, size float64) float64 {
+	if !l.tpl.CenterHeader {
+		return margin
+	}
+	return (pageWidth - textWidth(s, font, size)) / 2
+}
+
+func (l *resumeLayout) heading(title string) {
+	size := l.tpl.BodySize * 1.25
+	// Keep the heading with at least a couple of lines of its section
+	l.ensure(size*2 + l.tpl.BodySize*4)
+	l.y -= size * 1.6
+	l.doc.text(margin, l.y, fontBold, size, l.tpl.Accent, strings.ToUpper(title))
+	if l.tpl.RuleUnder {
+		l.doc.line(margin, l.y-4, pageWidth-margin, l.y-4, 0.75, l.tpl.Accent)
+	}
+	l.y -= size * 0.8
+}
+
+// entryHeader writes a bold left-aligned title with right-aligned dates on the same line
+func (l *resumeLayout) entryHeader(title, dates string) {
+	size := l.tpl.BodySize * 1.05
+	l.ensure(size * 3)
+	l.y -= size * 1.5
+
+	dateWidth := 0.0
+	if dates != "" {
+		dateWidth = textWidth(dates, fontRegular, l.tpl.BodySize) + 12
+		l.doc.text(pageWidth-margin-textWidth(dates, fontRegular, l.tpl.BodySize), l.y, fontRegular, l.tpl.BodySize, gray, dates)
+	}
+
+	lines := wrapText(title, fontBold, size, pageWidth-2*margin-dateWidth)
+	for i, line := range lines {
+		if i > 0 {
+			l.y -= size * 1.3
+		}
+		l.doc.text(margin, l.y, fontBold, size, black, line)
+	}
+}
+
+// paragraph word-wraps text, indenting continuation lines past the prefix (e.g. a bullet)
+func (l *resumeLayout) paragraph(prefix, text string, font pdfFont) {
+	size := l.tpl.BodySize
+	indent := textWidth(prefix, font, size)
+	lines := wrapText(text, font, size, pageWidth-2*margin-indent)
+	for i, line := range lines {
+		l.ensure(size * 1.4)
+		l.y -= size * 1.4
+		if i == 0 && prefix != "" {
+			l.doc.text(margin, l.y, font, size, black, prefix)
+		}
+		l.doc.text(margin+indent, l.y, font, size, black, line)
+	}
+}
+
+// wrapText breaks s into lines no wider than maxWidth, splitting overlong words if needed
+func wrapText(s string, font pdfFont, size, maxWidth float64) []string {
+	var lines []string
+	var current string
+	for _, word := range strings.Fields(s) {
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if textWidth(candidate, font, size) <= maxWidth {
+			current = candidate
+			continue
+		}
+		if current != "" {
+			lines = append(lines, current)
+		}
+		runes := []rune(word)
+		for textWidth(string(runes), font, size) > maxWidth && len(runes) > 1 {
+			cut := len(runes) - 1
+			for cut > 1 && textWidth(string(runes[:cut]), font, size) > maxWidth {
+				cut--
+			}
+			lines = append(lines, string(runes[:cut]))
+			runes = runes[cut:]
+		}
+		current = string(runes)
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+// formatDateRange renders profile dates (2006-01-02) as "Jan 2020 – Present"
+func formatDateRange(start, end string) string {
+	format := func(s string) string {
+		t, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return s
+		}
+		return t.Format("Jan 2006")
+	}
+	if start == "" {
+		return format(end)
+	}
+	if end == "" {
+		return format(start) + " – Present"
+	}
+	return format(start) + " – " + format(end)
+}
+
+// clean undoes the HTML escaping applied when profile text is sanitized on input
+func clean(s string) string {
+	return strings.TrimSpace(html.UnescapeString(s))
+}
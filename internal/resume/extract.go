@@ -0,0 +1,41 @@
+package resume
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+)
+
+// TextExtractor pulls the embedded text layer out of a PDF file
+type TextExtractor interface {
+	ExtractText(ctx context.Context, path string) (string, error)
+}
+
+// PdfToText extracts text using poppler's pdftotext binary
+type PdfToText struct {
+	BinaryPath string
+}
+
+func NewPdfToText(binaryPath string) *PdfToText {
+	if binaryPath == "" {
+		binaryPath = "pdftotext"
+	}
+	return &PdfToText{BinaryPath: binaryPath}
+}
+
+// ExtractText returns the plain text of every page, preserving reading order
+func (p *PdfToText) ExtractText(ctx context.Context, path string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	// "-" writes to stdout instead of creating a .txt next to the upload
+	cmd := exec.CommandContext(ctx, p.BinaryPath, "-enc", "UTF-8", path, "-")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("pdftotext failed: %w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	return stdout.String(), nil
+}
@@ -0,0 +1,513 @@
+package resume
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/jobapply/internal/models"
+)
+
+// Resume sections recognized by heading text
+const (
+	sectionNone           = ""
+	sectionWork           = "work"
+	sectionEducation      = "education"
+	sectionSkills         = "skills"
+	sectionCertifications = "certifications"
+	sectionOther          = "other"
+)
+
+var sectionHeadings = map[string]string{
+	"experience":                     sectionWork,
+	"work experience":                sectionWork,
+	"professional experience":        sectionWork,
+	"relevant experience":            sectionWork,
+	"employment":                     sectionWork,
+	"employment history":             sectionWork,
+	"work history":                   sectionWork,
+	"career history":                 sectionWork,
+	"education":                      sectionEducation,
+	"education and training":         sectionEducation,
+	"academic background":            sectionEducation,
+	"skills":                         sectionSkills,
+	"technical skills":               sectionSkills,
+	"core competencies":              sectionSkills,
+	"technologies":                   sectionSkills,
+	"skills and tools":               sectionSkills,
+	"skills and technologies":        sectionSkills,
+	"certifications":                 sectionCertifications,
+	"certificates":                   sectionCertifications,
+	"licenses and certifications":    sectionCertifications,
+	"certifications and licenses":    sectionCertifications,
+	"summary":                        sectionOther,
+	"professional summary":           sectionOther,
+	"profile":                        sectionOther,
+	"objective":                      sectionOther,
+	"projects":                       sectionOther,
+	"personal projects":              sectionOther,
+	"awards":                         sectionOther,
+	"honors and awards":              sectionOther,
+	"publications":                   sectionOther,
+	"volunteer experience":           sectionOther,
+	"volunteering":                   sectionOther,
+	"interests":                      sectionOther,
+	"languages":                      sectionOther,
+	"references":                     sectionOther,
+	"activities":                     sectionOther,
+	"leadership":                     sectionOther,
+	"additional information":         sectionOther,
+	"contact":                        sectionOther,
+	"contact information":            sectionOther,
+	"professional development":       sectionOther,
+	"training":                       sectionOther,
+	"courses":                        sectionOther,
+	"relevant coursework":            sectionOther,
+	"military service":               sectionOther,
+	"affiliations":                   sectionOther,
+	"professional affiliations":      sectionOther,
+	"extracurricular activities":     sectionOther,
+	"achievements":                   sectionOther,
+	"key achievements":               sectionOther,
+	"hobbies":                        sectionOther,
+	"portfolio":                      sectionOther,
+	"links":                          sectionOther,
+	"about me":                       sectionOther,
+	"qualifications":                 sectionOther,
+	"summary of qualifications":      sectionOther,
+	"professional qualifications":    sectionOther,
+	"research experience":            sectionWork,
+	"internships":                    sectionWork,
+	"internship experience":          sectionWork,
+	"teaching experience":            sectionWork,
+	"technical experience":           sectionWork,
+	"industry experience":            sectionWork,
+	"work and leadership experience": sectionWork,
+}
+
+const (
+	monthPattern = `(?:jan|feb|mar|apr|may|jun|jul|aug|sep|sept|oct|nov|dec)[a-z]*\.?`
+	datePattern  = `(?:` + monthPattern + `\s+\d{4}|\d{1,2}/\d{4}|\d{4})`
+)
+
+var (
+	emailRegex     = regexp.MustCompile(`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`)
+	phoneRegex     = regexp.MustCompile(`\+?\(?\d[\d\s().\-]{8,}\d`)
+	linkRegex      = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s,;|<>()]+|\b(?:linkedin\.com|github\.com|gitlab\.com)/[^\s,;|<>()]+`)
+	locationRegex  = regexp.MustCompile(`\b[A-Z][a-zA-Z.]+(?:\s[A-Z][a-zA-Z.]+)*,\s*[A-Z]{2}\b`)
+	dateRangeRegex = regexp.MustCompile(`(?i)(` + datePattern + `)\s*(?:-|–|—|to)\s*(` + datePattern + `|present|current|now)`)
+	yearRegex      = regexp.MustCompile(`\b(?:19|20)\d{2}\b`)
+	monthYearRegex = regexp.MustCompile(`(?i)^(` + monthPattern + `)\s+(\d{4})$`)
+	slashDateRegex = regexp.MustCompile(`^(\d{1,2})/(\d{4})$`)
+	degreeRegex    = regexp.MustCompile(`(?i)\b(?:bachelor|master|associate|doctor(?:ate)?|ph\.?d|mba|b\.s\.?|b\.a\.?|m\.s\.?|m\.a\.?|bsc|msc|b\.?eng|m\.?eng|bs|ms|ba|diploma)\b`)
+	schoolRegex    = regexp.MustCompile(`(?i)\b(?:university|college|institute|school|academy|polytechnic)\b`)
+	majorRegex     = regexp.MustCompile(`(?i)\bin\s+([a-z][a-z&\s]+?)\s*(?:[,(|\-–—]|\d|$)`)
+	bulletPrefix   = regexp.MustCompile(`^[•●▪◦■\-*–·]+\s*`)
+	skillSplit     = regexp.MustCompile(`[,;|•·●▪]`)
+)
+
+var titleKeywords = []string{
+	"engineer", "developer", "manager", "analyst", "intern", "designer", "director",
+	"lead", "consultant", "specialist", "administrator", "coordinator", "architect",
+	"scientist", "associate", "assistant", "officer", "head", "president", "founder",
+	"programmer", "technician", "representative", "supervisor", "researcher", "teacher",
+}
+
+var months = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+// ParseText extracts structured resume data from plain text using layout heuristics
+func ParseText(text string) *models.ParsedResume {
+	lines := splitLines(text)
+
+	parsed := &models.ParsedResume{
+		WorkHistory:    []models.WorkHistory{},
+		Education:      []models.Education{},
+		Skills:         []string{},
+		Certifications: []string{},
+		Links:          []string{},
+	}
+
+	parsed.Contact = parseContact(lines)
+	parsed.Links = parseLinks(text)
+
+	sections := splitSections(lines)
+	parsed.WorkHistory = parseWorkHistory(sections[sectionWork])
+	parsed.Education = parseEducation(sections[sectionEducation])
+	parsed.Skills = parseList(sections[sectionSkills], true)
+	parsed.Certifications = parseList(sections[sectionCertifications], false)
+
+	return parsed
+}
+
+// splitLines normalizes line endings and page breaks, trimming each line
+func splitLines(text string) []string {
+	text = strings.NewReplacer("\r\n", "\n", "\r", "\n", "\f", "\n").Replace(text)
+	raw := strings.Split(text, "\n")
+	lines := make([]string, 0, len(raw))
+	for _, line := range raw {
+		lines = append(lines, strings.TrimSpace(line))
+	}
+	return lines
+}
+
+// headingSection returns the section a line introduces, or sectionNone if it isn't a heading
+func headingSection(line string) string {
+	if line == "" || len(line) > 40 {
+		return sectionNone
+	}
+	key := strings.ToLower(strings.TrimRight(line, ": "))
+	key = strings.Join(strings.Fields(strings.ReplaceAll(key, "&", "and")), " ")
+	return sectionHeadings[key]
+}
+
+// splitSections groups non-empty lines under the most recent recognized heading
+func splitSections(lines []string) map[string][]string {
+	sections := make(map[string][]string)
+	current := sectionNone
+	for _, line := range lines {
+		if section := headingSection(line); section != sectionNone {
+			current = section
+			continue
+		}
+		if line == "" || current == sectionNone || current == sectionOther {
+			continue
+		}
+		sections[current] = append(sections[current], line)
+	}
+	return sections
+}
+
+// parseContact looks for name, email, phone and location in the resume header
+func parseContact(lines []string) models.ContactInfo {
+	var contact models.ContactInfo
+
+	// Header block is everything before the first section heading
+	var header []string
+	for _, line := range lines {
+		if headingSection(line) != sectionNone {
+			break
+		}
+		if line != "" {
+			header = append(header, line)
+		}
+		if len(header) >= 15 {
+			break
+		}
+	}
+
+	for _, line := range lines {
+		if match := emailRegex.FindString(line); match != "" {
+			contact.Email = match
+			break
+		}
+	}
+
+	for _, line := range header {
+		if contact.Phone == "" {
+			for _, match := range phoneRegex.FindAllString(line, -1) {
+				if digits := countDigits(match); digits >= 10 && digits <= 15 && !dateRangeRegex.MatchString(match) {
+					contact.Phone = strings.TrimSpace(match)
+					break
+				}
+			}
+		}
+		if contact.Location == "" {
+			if match := locationRegex.FindString(line); match != "" {
+				contact.Location = match
+			}
+		}
+		if contact.FullName == "" && looksLikeName(line) {
+			contact.FullName = line
+		}
+	}
+
+	return contact
+}
+
+// looksLikeName accepts short lines of capitalized words with no contact details
+func looksLikeName(line string) bool {
+	if strings.ContainsAny(line, "@0123456789/:|,") {
+		return false
+	}
+	words := strings.Fields(line)
+	if len(words) < 2 || len(words) > 5 {
+		return false
+	}
+	for _, word := range words {
+		first := []rune(word)[0]
+		if !(first >= 'A' && first <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func countDigits(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			n++
+		}
+	}
+	return n
+}
+
+// parseLinks collects unique URLs (portfolio, LinkedIn, GitHub) anywhere in the text
+func parseLinks(text string) []string {
+	links := []string{}
+	seen := make(map[string]bool)
+	for _, match := range linkRegex.FindAllString(text, -1) {
+		link := strings.TrimRight(match, ".,;:")
+		key := strings.ToLower(link)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		links = append(links, link)
+	}
+	return links
+}
+
+// parseWorkHistory turns the experience section into entries, one per date range
+func parseWorkHistory(lines []string) []models.WorkHistory {
+	entries := []models.WorkHistory{}
+	var current *models.WorkHistory
+	var description []string
+	var pending []string // Short non-bullet lines that may be the next entry's header
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		description = append(description, pending...)
+		pending = nil
+		current.Description = strings.Join(description, "\n")
+		entries = append(entries, *current)
+		current = nil
+		description = nil
+	}
+
+	for _, line := range lines {
+		if match := dateRangeRegex.FindStringSubmatchIndex(line); match != nil {
+			header := append([]string{}, pending...)
+			pending = nil
+			flush()
+
+			rest := strings.TrimSpace(line[:match[0]] + " " + line[match[1]:])
+			rest = strings.Trim(rest, " |,-–—()")
+			if rest != "" {
+				header = append(header, rest)
+			}
+
+			title, company := splitTitleCompany(header)
+			current = &models.WorkHistory{
+				Title:     title,
+				Company:   company,
+				StartDate: normalizeDate(line[match[2]:match[3]]),
+				EndDate:   normalizeDate(line[match[4]:match[5]]),
+			}
+			continue
+		}
+
+		isBullet := bulletPrefix.MatchString(line)
+		text := bulletPrefix.ReplaceAllString(line, "")
+
+		if current == nil {
+			if !isBullet {
+				pending = append(pending, text)
+			}
+			continue
+		}
+
+		// Title or company on the line after the dates
+		if !isBullet && len(description) == 0 && len(text) <= 80 && (current.Title == "" || current.Company == "") {
+			if current.Title == "" && hasTitleKeyword(text) {
+				current.Title = text
+			} else if current.Company == "" {
+				current.Company = text
+			} else {
+				current.Title = text
+			}
+			continue
+		}
+
+		if isBullet || len(text) > 80 {
+			description = append(description, pending...)
+			pending = nil
+			description = append(description, text)
+		} else {
+			pending = append(pending, text)
+		}
+	}
+	flush()
+
+	return entries
+}
+
+// splitTitleCompany guesses which header fragment is the job title and which is the employer
+func splitTitleCompany(header []string) (string, string) {
+	var parts []string
+	for _, h := range header {
+		if strings.Contains(strings.ToLower(h), " at ") {
+			idx := strings.Index(strings.ToLower(h), " at ")
+			parts = append(parts, strings.TrimSpace(h[:idx]), strings.TrimSpace(h[idx+4:]))
+			continue
+		}
+		for _, sep := range []string{" | ", " - ", " – ", " — ", ", "} {
+			if strings.Contains(h, sep) {
+				for _, p := range strings.SplitN(h, sep, 2) {
+					parts = append(parts, strings.TrimSpace(p))
+				}
+				h = ""
+				break
+			}
+		}
+		if h != "" {
+			parts = append(parts, h)
+		}
+	}
+
+	switch len(parts) {
+	case 0:
+		return "", ""
+	case 1:
+		if hasTitleKeyword(parts[0]) {
+			return parts[0], ""
+		}
+		return "", parts[0]
+	}
+
+	// Use the last two fragments; earlier ones are usually stray header lines
+	a, b := parts[len(parts)-2], parts[len(parts)-1]
+	if hasTitleKeyword(b) && !hasTitleKeyword(a) {
+		return b, a
+	}
+	return a, b
+}
+
+func hasTitleKeyword(s string) bool {
+	lower := strings.ToLower(s)
+	for _, keyword := range titleKeywords {
+		if strings.Contains(lower, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeDate converts "Jan 2020", "01/2020" or "2020" into the profile's 2006-01-02 format.
+// Open-ended dates ("Present") return an empty string.
+func normalizeDate(s string) string {
+	s = strings.TrimSpace(s)
+	if m := monthYearRegex.FindStringSubmatch(s); m != nil {
+		month := months[strings.ToLower(m[1])[:3]]
+		return fmt.Sprintf("%s-%02d-01", m[2], month)
+	}
+	if m := slashDateRegex.FindStringSubmatch(s); m != nil {
+		var month int
+		fmt.Sscanf(m[1], "%d", &month)
+		if month < 1 || month > 12 {
+			month = 1
+		}
+		return fmt.Sprintf("%s-%02d-01", m[2], month)
+	}
+	if yearRegex.MatchString(s) && len(s) == 4 {
+		return s + "-01-01"
+	}
+	return ""
+}
+
+// parseEducation groups education lines into entries keyed on the school line
+func parseEducation(lines []string) []models.Education {
+	entries := []models.Education{}
+	var current *models.Education
+
+	for _, line := range lines {
+		text := bulletPrefix.ReplaceAllString(line, "")
+		isSchool := schoolRegex.MatchString(text)
+		isDegree := degreeRegex.MatchString(text) && !isSchool
+
+		if current == nil || (isSchool && current.School != "") || (isDegree && current.Degree != "") {
+			if current != nil {
+				entries = append(entries, *current)
+			}
+			current = &models.Education{}
+		}
+
+		if years := yearRegex.FindAllString(text, -1); len(years) > 0 {
+			fmt.Sscanf(years[len(years)-1], "%d", &current.GradYear)
+		}
+
+		switch {
+		case isSchool:
+			current.School = cleanEducationField(text)
+		case isDegree:
+			current.Degree = cleanEducationField(text)
+			current.Major = parseMajor(text)
+		case current.School == "" && current.Degree != "":
+			current.School = cleanEducationField(text)
+		}
+	}
+	if current != nil && (current.School != "" || current.Degree != "") {
+		entries = append(entries, *current)
+	}
+
+	return entries
+}
+
+// cleanEducationField drops years and trailing separators from an education line
+func cleanEducationField(s string) string {
+	s = dateRangeRegex.ReplaceAllString(s, "")
+	s = yearRegex.ReplaceAllString(s, "")
+	return strings.Trim(strings.TrimSpace(s), " |,-–—()")
+}
+
+func parseMajor(s string) string {
+	if m := majorRegex.FindStringSubmatch(s); m != nil {
+		return strings.TrimSpace(m[1])
+	}
+	// "B.S., Computer Science" style
+	if parts := strings.Split(s, ","); len(parts) > 1 {
+		return cleanEducationField(parts[1])
+	}
+	return ""
+}
+
+// parseList splits a section into unique items; split breaks comma/bullet separated lines apart
+func parseList(lines []string, split bool) []string {
+	items := []string{}
+	seen := make(map[string]bool)
+
+	add := func(item string) {
+		item = strings.Trim(strings.TrimSpace(item), ".")
+		if item == "" || len(item) > 100 {
+			return
+		}
+		key := strings.ToLower(item)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		items = append(items, item)
+	}
+
+	for _, line := range lines {
+		text := bulletPrefix.ReplaceAllString(line, "")
+		if !split {
+			add(text)
+			continue
+		}
+		// "Languages: Go, Python" - drop the category label
+		if idx := strings.Index(text, ":"); idx >= 0 && idx < 40 {
+			text = text[idx+1:]
+		}
+		for _, item := range skillSplit.Split(text, -1) {
+			add(item)
+		}
+	}
+
+	return items
+}
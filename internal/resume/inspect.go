@@ -0,0 +1,225 @@
+package resume
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+var (
+	// ErrEncrypted is returned for password-protected PDFs, which can't be read without the password
+	ErrEncrypted = errors.New("pdf is encrypted")
+	// ErrScriptNotRemovable is returned when JavaScript sits inside a compressed object stream,
+	// where it can't be neutralized without rewriting the file
+	ErrScriptNotRemovable = errors.New("pdf contains scripts that can't be removed")
+)
+
+// maxObjectStreamSize caps how much of each compressed object stream is inflated
+const maxObjectStreamSize = 8 << 20
+
+var (
+	objectHeader = regexp.MustCompile(`(\d+)\s+\d+\s+obj\b`)
+	pageObject   = regexp.MustCompile(`/Type\s*/Page\b`)
+)
+
+// PDFInfo is what SanitizePDF learned about a file
+type PDFInfo struct {
+	// Pages counts page objects; 0 if none were found, e.g. in an unusual encoding
+	Pages int
+	// ScriptsRemoved counts the JavaScript entries that were neutralized
+	ScriptsRemoved int
+}
+
+// SanitizePDF checks a PDF before it is stored and returns a copy with embedded JavaScript
+// neutralized. Script names are renamed in place ("/JS" becomes "/XS") so byte offsets, and
+// with them the cross-reference table, stay valid; viewers ignore the unknown keys.
+// Encrypted files return ErrEncrypted.
+func SanitizePDF(data []byte) ([]byte, PDFInfo, error) {
+	var info PDFInfo
+	clean := bytes.Clone(data)
+	pages := map[string]bool{}
+	compressedPages := 0
+	scriptsInStreams := false
+
+	s := &pdfScanner{data: data}
+	for s.i < len(data) {
+		switch c := data[s.i]; {
+		case c == '%':
+			s.skipComment()
+		case c == '(':
+			s.skipString()
+		case c == '/':
+			start := s.i
+			name := s.name()
+			switch name {
+			case "Encrypt":
+				return nil, info, ErrEncrypted
+			case "JavaScript", "JS":
+				clean[start+1] = 'X'
+				info.ScriptsRemoved++
+			}
+		case s.atStream():
+			dict := s.data[s.lastObject():s.i]
+			body := s.streamBody()
+			if bytes.Contains(dict, []byte("/ObjStm")) && bytes.Contains(dict, []byte("/FlateDecode")) {
+				objects, err := inflate(body)
+				if err != nil {
+					continue // Unreadable; viewers won't read anything from it either
+				}
+				compressedPages += len(pageObject.FindAllIndex(objects, -1))
+				if containsScript(objects) {
+					scriptsInStreams = true
+				}
+			}
+		default:
+			s.i++
+		}
+	}
+
+	// Incremental updates append new versions of changed objects, so pages are counted by
+	// object number
+	for _, loc := range pageObject.FindAllIndex(data, -1) {
+		headers := objectHeader.FindAllSubmatch(data[max(0, loc[0]-4096):loc[0]], -1)
+		if len(headers) == 0 {
+			continue
+		}
+		pages[string(headers[len(headers)-1][1])] = true
+	}
+	info.Pages = len(pages) + compressedPages
+
+	if scriptsInStreams {
+		return nil, info, ErrScriptNotRemovable
+	}
+	return clean, info, nil
+}
+
+// containsScript reports whether uncompressed PDF objects use a JavaScript name
+func containsScript(objects []byte) bool {
+	s := &pdfScanner{data: objects}
+	for s.i < len(objects) {
+		switch objects[s.i] {
+		case '%':
+			s.skipComment()
+		case '(':
+			s.skipString()
+		case '/':
+			if name := s.name(); name == "JavaScript" || name == "JS" {
+				return true
+			}
+		default:
+			s.i++
+		}
+	}
+	return false
+}
+
+func inflate(body []byte) ([]byte, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return io.ReadAll(io.LimitReader(zr, maxObjectStreamSize))
+}
+
+// pdfScanner walks the raw bytes of a PDF just far enough to tell names apart from strings,
+// comments and stream data
+type pdfScanner struct {
+	data []byte
+	i    int
+}
+
+func isPDFWhitespace(c byte) bool {
+	return c == 0 || c == '\t' || c == '\n' || c == '\f' || c == '\r' || c == ' '
+}
+
+func isPDFDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return isPDFWhitespace(c)
+}
+
+func (s *pdfScanner) skipComment() {
+	for s.i < len(s.data) && s.data[s.i] != '\n' && s.data[s.i] != '\r' {
+		s.i++
+	}
+}
+
+// skipString skips a literal string, which may contain balanced or escaped parentheses
+func (s *pdfScanner) skipString() {
+	depth := 0
+	for ; s.i < len(s.data); s.i++ {
+		switch s.data[s.i] {
+		case '\\':
+			s.i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				s.i++
+				return
+			}
+		}
+	}
+}
+
+// name reads the name at s.i, decoding #xx escapes
+func (s *pdfScanner) name() string {
+	s.i++ // The slash
+	var b []byte
+	for s.i < len(s.data) && !isPDFDelimiter(s.data[s.i]) {
+		c := s.data[s.i]
+		if c == '#' && s.i+2 < len(s.data) {
+			if v, err := strconv.ParseUint(string(s.data[s.i+1:s.i+3]), 16, 8); err == nil {
+				b = append(b, byte(v))
+				s.i += 3
+				continue
+			}
+		}
+		b = append(b, c)
+		s.i++
+	}
+	return string(b)
+}
+
+// atStream reports whether the "stream" keyword starts at s.i
+func (s *pdfScanner) atStream() bool {
+	if !bytes.HasPrefix(s.data[s.i:], []byte("stream")) {
+		return false
+	}
+	if s.i > 0 && !isPDFDelimiter(s.data[s.i-1]) {
+		return false // e.g. "endstream"
+	}
+	rest := s.data[s.i+len("stream"):]
+	return bytes.HasPrefix(rest, []byte("\r\n")) || bytes.HasPrefix(rest, []byte("\n"))
+}
+
+// streamBody returns the data after the "stream" keyword at s.i and moves past "endstream"
+func (s *pdfScanner) streamBody() []byte {
+	start := s.i + len("stream")
+	if s.data[start] == '\r' {
+		start++
+	}
+	start++
+	end := bytes.Index(s.data[start:], []byte("endstream"))
+	if end < 0 {
+		s.i = len(s.data)
+		return s.data[start:]
+	}
+	s.i = start + end + len("endstream")
+	return s.data[start : start+end]
+}
+
+// lastObject returns where the object containing s.i starts
+func (s *pdfScanner) lastObject() int {
+	if n := bytes.LastIndex(s.data[:s.i], []byte("obj")); n >= 0 {
+		return n
+	}
+	return 0
+}
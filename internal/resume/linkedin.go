@@ -0,0 +1,188 @@
+package resume
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/yourusername/jobapply/internal/models"
+)
+
+// BackendLinkedIn labels profile data imported from a LinkedIn data export
+const BackendLinkedIn = "linkedin"
+
+var ErrNotLinkedInArchive = errors.New("archive does not contain LinkedIn profile data")
+
+// maxCSVSize caps how much of each CSV is decompressed, guarding against zip bombs
+const maxCSVSize = 5 << 20
+
+// ParseLinkedInArchive reads the "Get a copy of your data" ZIP from LinkedIn and maps
+// Profile, Positions, Education, Skills and Certifications into a ParsedResume
+func ParseLinkedInArchive(r io.ReaderAt, size int64) (*models.ParsedResume, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid zip archive: %w", err)
+	}
+
+	// Exports nest files in a folder on some platforms, so match on base name only
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		files[strings.ToLower(path.Base(f.Name))] = f
+	}
+
+	parsed := &models.ParsedResume{Backend: BackendLinkedIn}
+	normalize(parsed)
+	found := false
+
+	if rows, err := readLinkedInCSV(files["profile.csv"], "first name"); err != nil {
+		return nil, err
+	} else if len(rows) > 0 {
+		found = true
+		row := rows[0]
+		parsed.Contact.FullName = strings.TrimSpace(row["first name"] + " " + row["last name"])
+		parsed.Contact.Location = row["geo location"]
+		for _, site := range strings.Split(strings.Trim(row["websites"], "[]"), ",") {
+			// Websites are exported as "[PORTFOLIO:https://...]"
+			if idx := strings.Index(site, "http"); idx >= 0 {
+				parsed.Links = append(parsed.Links, strings.TrimSpace(site[idx:]))
+			}
+		}
+	}
+
+	if rows, err := readLinkedInCSV(files["email addresses.csv"], "email address"); err != nil {
+		return nil, err
+	} else {
+		for _, row := range rows {
+			if parsed.Contact.Email == "" || strings.EqualFold(row["primary"], "yes") {
+				parsed.Contact.Email = row["email address"]
+			}
+		}
+	}
+
+	if rows, err := readLinkedInCSV(files["phonenumbers.csv"], "number"); err != nil {
+		return nil, err
+	} else if len(rows) > 0 {
+		parsed.Contact.Phone = rows[0]["number"]
+	}
+
+	if rows, err := readLinkedInCSV(files["positions.csv"], "company name"); err != nil {
+		return nil, err
+	} else {
+		found = found || len(rows) > 0
+		for _, row := range rows {
+			parsed.WorkHistory = append(parsed.WorkHistory, models.WorkHistory{
+				Company:     row["company name"],
+				Title:       row["title"],
+				StartDate:   normalizeDate(row["started on"]),
+				EndDate:     normalizeDate(row["finished on"]),
+				Description: row["description"],
+			})
+		}
+	}
+
+	if rows, err := readLinkedInCSV(files["education.csv"], "school name"); err != nil {
+		return nil, err
+	} else {
+		found = found || len(rows) > 0
+		for _, row := range rows {
+			edu := models.Education{
+				School: row["school name"],
+				Degree: row["degree name"],
+				Major:  parseMajor(row["degree name"]),
+			}
+			if years := yearRegex.FindAllString(row["end date"], -1); len(years) > 0 {
+				fmt.Sscanf(years[len(years)-1], "%d", &edu.GradYear)
+			}
+			parsed.Education = append(parsed.Education, edu)
+		}
+	}
+
+	if rows, err := readLinkedInCSV(files["skills.csv"], "name"); err != nil {
+		return nil, err
+	} else {
+		found = found || len(rows) > 0
+		for _, row := range rows {
+			if skill := strings.TrimSpace(row["name"]); skill != "" {
+				parsed.Skills = append(parsed.Skills, skill)
+			}
+		}
+	}
+
+	if rows, err := readLinkedInCSV(files["certifications.csv"], "name"); err != nil {
+		return nil, err
+	} else {
+		for _, row := range rows {
+			cert := strings.TrimSpace(row["name"])
+			if authority := strings.TrimSpace(row["authority"]); cert != "" && authority != "" {
+				cert += " (" + authority + ")"
+			}
+			if cert != "" {
+				parsed.Certifications = append(parsed.Certifications, cert)
+			}
+		}
+	}
+
+	if !found {
+		return nil, ErrNotLinkedInArchive
+	}
+
+	return parsed, nil
+}
+
+// readLinkedInCSV returns rows keyed by lowercase header name. Some exports prepend
+// notes above the header, so rows before the one containing headerKey are skipped.
+// A missing file yields no rows.
+func readLinkedInCSV(f *zip.File, headerKey string) ([]map[string]string, error) {
+	if f == nil {
+		return nil, nil
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	reader := csv.NewReader(io.LimitReader(rc, maxCSVSize))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var header []string
+	var rows []map[string]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+		}
+
+		if header == nil {
+			for _, col := range record {
+				if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(col, "\ufeff")), headerKey) {
+					header = make([]string, len(record))
+					for i, c := range record {
+						header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(c, "\ufeff")))
+					}
+					break
+				}
+			}
+			continue
+		}
+
+		row := make(map[string]string, len(header))
+		for i, col := range header {
+			if i < len(record) {
+				row[col] = strings.TrimSpace(record[i])
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,244 @@
+package resume
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/jobapply/internal/models"
+	"github.com/yourusername/jobapply/internal/validation"
+)
+
+// Profile fields that can be merged from imported data.
+// Email is deliberately excluded - it is the login identity and changes go through /auth/email.
+const (
+	FieldFullName = "full_name"
+	FieldPhone    = "phone"
+	FieldLocation = "location"
+)
+
+// Diff compares parsed resume data with the current profile without modifying either
+func Diff(profile *models.UserProfile, parsed *models.ParsedResume) *models.ProfileDiff {
+	diff := &models.ProfileDiff{
+		ProfileUpdatedAt: profile.UpdatedAt,
+		Fields:           []models.FieldDiff{},
+		WorkHistory:      []models.WorkHistoryDiff{},
+		Education:        []models.EducationDiff{},
+		Skills:           []models.SkillDiff{},
+		Certifications:   parsed.Certifications,
+		Links:            parsed.Links,
+		UsedOCR:          parsed.UsedOCR,
+		Backend:          parsed.Backend,
+	}
+
+	addField := func(field, current, parsedValue string, equal func(a, b string) bool) {
+		if parsedValue == "" {
+			return
+		}
+		status := models.DiffConflict
+		switch {
+		case current == "":
+			status = models.DiffNew
+		case equal(current, parsedValue):
+			status = models.DiffUnchanged
+		}
+		diff.Fields = append(diff.Fields, models.FieldDiff{
+			Field:   field,
+			Status:  status,
+			Current: current,
+			Parsed:  parsedValue,
+		})
+	}
+
+	addField(FieldFullName, profile.FullName, parsed.Contact.FullName, sameText)
+	addField(FieldPhone, profile.Phone, parsed.Contact.Phone, samePhone)
+	addField(FieldLocation, profileLocation(profile), parsed.Contact.Location, sameText)
+
+	for _, entry := range parsed.WorkHistory {
+		d := models.WorkHistoryDiff{Status: models.DiffNew, Parsed: entry}
+		for i := range profile.WorkHistory {
+			current := profile.WorkHistory[i]
+			if !sameText(current.Company, entry.Company) ||
+				(!sameText(current.Title, entry.Title) && current.StartDate != entry.StartDate) {
+				continue
+			}
+			idx := i
+			d.CurrentIndex = &idx
+			d.Current = &current
+			d.Status = models.DiffConflict
+			if sameText(current.Title, entry.Title) && current.StartDate == entry.StartDate &&
+				current.EndDate == entry.EndDate && sameText(current.Description, entry.Description) {
+				d.Status = models.DiffUnchanged
+			}
+			break
+		}
+		diff.WorkHistory = append(diff.WorkHistory, d)
+	}
+
+	for _, entry := range parsed.Education {
+		d := models.EducationDiff{Status: models.DiffNew, Parsed: entry}
+		for i := range profile.Education {
+			current := profile.Education[i]
+			if entry.School != "" && !sameText(current.School, entry.School) {
+				continue
+			}
+			if entry.School == "" && !sameText(current.Degree, entry.Degree) {
+				continue
+			}
+			idx := i
+			d.CurrentIndex = &idx
+			d.Current = &current
+			d.Status = models.DiffConflict
+			if sameText(current.Degree, entry.Degree) && sameText(current.Major, entry.Major) &&
+				current.GradYear == entry.GradYear {
+				d.Status = models.DiffUnchanged
+			}
+			break
+		}
+		diff.Education = append(diff.Education, d)
+	}
+
+	existing := make(map[string]bool, len(profile.Skills))
+	for _, skill := range profile.Skills {
+		existing[normalizeText(skill)] = true
+	}
+	for _, skill := range parsed.Skills {
+		status := models.DiffNew
+		if existing[normalizeText(skill)] {
+			status = models.DiffUnchanged
+		}
+		diff.Skills = append(diff.Skills, models.SkillDiff{Skill: skill, Status: status})
+	}
+
+	return diff
+}
+
+// ApplyMerge writes the accepted changes onto profile. Values are sanitized here since they
+// come back from the client and may have been edited during review.
+func ApplyMerge(profile *models.UserProfile, req *models.ProfileMergeRequest) error {
+	for field, value := range req.Fields {
+		value = validation.SanitizeString(value, 200)
+		switch field {
+		case FieldFullName:
+			if value == "" {
+				return &validation.FieldError{Field: "fields." + field, Message: "full_name cannot be empty"}
+			}
+			profile.FullName = value
+		case FieldPhone:
+			if value != "" && !validation.ValidatePhone(value) {
+				return &validation.FieldError{Field: "fields." + field, Message: "invalid phone number"}
+			}
+			profile.Phone = value
+		case FieldLocation:
+			city, state, _ := strings.Cut(value, ",")
+			if profile.Address == nil {
+				profile.Address = &models.Address{}
+			}
+			profile.Address.City = strings.TrimSpace(city)
+			profile.Address.State = strings.TrimSpace(state)
+		default:
+			return &validation.FieldError{Field: "fields." + field, Message: fmt.Sprintf("unknown field %q", field)}
+		}
+	}
+
+	// Validate every replacement index before touching the slices so a bad request changes nothing
+	for i, change := range req.WorkHistory {
+		if change.ReplaceIndex != nil && (*change.ReplaceIndex < 0 || *change.ReplaceIndex >= len(profile.WorkHistory)) {
+			return &validation.FieldError{
+				Field:   fmt.Sprintf("work_history[%d].replace_index", i),
+				Message: fmt.Sprintf("work_history replace_index %d out of range", *change.ReplaceIndex),
+			}
+		}
+	}
+	for i, change := range req.Education {
+		if change.ReplaceIndex != nil && (*change.ReplaceIndex < 0 || *change.ReplaceIndex >= len(profile.Education)) {
+			return &validation.FieldError{
+				Field:   fmt.Sprintf("education[%d].replace_index", i),
+				Message: fmt.Sprintf("education replace_index %d out of range", *change.ReplaceIndex),
+			}
+		}
+	}
+
+	for _, change := range req.WorkHistory {
+		entry := sanitizeWorkHistory(change.Entry)
+		if change.ReplaceIndex != nil {
+			profile.WorkHistory[*change.ReplaceIndex] = entry
+		} else {
+			profile.WorkHistory = append(profile.WorkHistory, entry)
+		}
+	}
+
+	for _, change := range req.Education {
+		entry := sanitizeEducation(change.Entry)
+		if change.ReplaceIndex != nil {
+			profile.Education[*change.ReplaceIndex] = entry
+		} else {
+			profile.Education = append(profile.Education, entry)
+		}
+	}
+
+	existing := make(map[string]bool, len(profile.Skills))
+	for _, skill := range profile.Skills {
+		existing[normalizeText(skill)] = true
+	}
+	for _, skill := range req.Skills {
+		skill = validation.SanitizeString(skill, 100)
+		if skill == "" || existing[normalizeText(skill)] {
+			continue
+		}
+		existing[normalizeText(skill)] = true
+		profile.Skills = append(profile.Skills, skill)
+	}
+
+	return nil
+}
+
+func sanitizeWorkHistory(w models.WorkHistory) models.WorkHistory {
+	return models.WorkHistory{
+		Company:     validation.SanitizeString(w.Company, 200),
+		Title:       validation.SanitizeString(w.Title, 200),
+		StartDate:   validation.SanitizeString(w.StartDate, 10),
+		EndDate:     validation.SanitizeString(w.EndDate, 10),
+		Description: validation.SanitizeString(w.Description, 5000),
+	}
+}
+
+func sanitizeEducation(e models.Education) models.Education {
+	return models.Education{
+		School:   validation.SanitizeString(e.School, 200),
+		Degree:   validation.SanitizeString(e.Degree, 200),
+		Major:    validation.SanitizeString(e.Major, 200),
+		GradYear: e.GradYear,
+	}
+}
+
+// profileLocation formats the profile address the same way resumes usually show it ("City, ST")
+func profileLocation(profile *models.UserProfile) string {
+	if profile.Address == nil || profile.Address.City == "" {
+		return ""
+	}
+	if profile.Address.State == "" {
+		return profile.Address.City
+	}
+	return profile.Address.City + ", " + profile.Address.State
+}
+
+func normalizeText(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+func sameText(a, b string) bool {
+	return normalizeText(a) == normalizeText(b)
+}
+
+func samePhone(a, b string) bool {
+	digits := func(s string) string {
+		var sb strings.Builder
+		for _, r := range s {
+			if r >= '0' && r <= '9' {
+				sb.WriteRune(r)
+			}
+		}
+		return sb.String()
+	}
+	return digits(a) == digits(b)
+}
@@ -0,0 +1,107 @@
+package resume
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OCR recognizes text from a PDF that has no usable text layer (scanned or image-only exports)
+type OCR interface {
+	Recognize(ctx context.Context, path string) (string, error)
+}
+
+// TesseractOCR rasterizes pages with pdftoppm and runs tesseract on each image
+type TesseractOCR struct {
+	TesseractPath string
+	PdftoppmPath  string
+	Language      string
+	DPI           int
+	MaxPages      int // Scanned resumes rarely exceed a few pages; caps CPU time on huge uploads
+}
+
+func NewTesseractOCR(tesseractPath, pdftoppmPath string) *TesseractOCR {
+	if tesseractPath == "" {
+		tesseractPath = "tesseract"
+	}
+	if pdftoppmPath == "" {
+		pdftoppmPath = "pdftoppm"
+	}
+	return &TesseractOCR{
+		TesseractPath: tesseractPath,
+		PdftoppmPath:  pdftoppmPath,
+		Language:      "eng",
+		DPI:           300,
+		MaxPages:      5,
+	}
+}
+
+// Available reports whether both binaries can be found on PATH
+func (t *TesseractOCR) Available() bool {
+	if _, err := exec.LookPath(t.TesseractPath); err != nil {
+		return false
+	}
+	if _, err := exec.LookPath(t.PdftoppmPath); err != nil {
+		return false
+	}
+	return true
+}
+
+// Recognize returns the OCR text of the first MaxPages pages joined by form feeds
+func (t *TesseractOCR) Recognize(ctx context.Context, path string) (string, error) {
+	tmpDir, err := os.MkdirTemp("", "resume-ocr-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Rasterize pages to PNG (page-1.png, page-2.png, ...)
+	args := []string{"-png", "-r", strconv.Itoa(t.DPI)}
+	if t.MaxPages > 0 {
+		args = append(args, "-l", strconv.Itoa(t.MaxPages))
+	}
+	args = append(args, path, filepath.Join(tmpDir, "page"))
+
+	if err := run(ctx, t.PdftoppmPath, args...); err != nil {
+		return "", fmt.Errorf("failed to rasterize pdf: %w", err)
+	}
+
+	images, err := filepath.Glob(filepath.Join(tmpDir, "page-*.png"))
+	if err != nil {
+		return "", err
+	}
+	if len(images) == 0 {
+		return "", fmt.Errorf("pdf produced no pages")
+	}
+	sort.Strings(images)
+
+	pages := make([]string, 0, len(images))
+	for _, img := range images {
+		var stdout bytes.Buffer
+		cmd := exec.CommandContext(ctx, t.TesseractPath, img, "stdout", "-l", t.Language)
+		cmd.Stdout = &stdout
+		if err := cmd.Run(); err != nil {
+			return "", fmt.Errorf("tesseract failed on %s: %w", filepath.Base(img), err)
+		}
+		pages = append(pages, stdout.String())
+	}
+
+	return strings.Join(pages, "\f"), nil
+}
+
+// run executes a command and folds stderr into the returned error
+func run(ctx context.Context, name string, args ...string) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return nil
+}
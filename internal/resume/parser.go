@@ -0,0 +1,124 @@
+package resume
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yourusername/jobapply/internal/logging"
+	"github.com/yourusername/jobapply/internal/models"
+)
+
+// DefaultMinTextLength is the number of non-whitespace characters below which the
+// text layer is treated as missing. Image-only exports (e.g. Canva) usually yield
+// nothing at all or a handful of stray glyphs.
+const DefaultMinTextLength = 50
+
+// Parser extracts resume text, falling back to OCR when the PDF has no text layer,
+// and hands it to a ResumeParserBackend for structuring
+type Parser struct {
+	extractor      TextExtractor
+	ocr            OCR // nil disables the fallback
+	minTextLength  int
+	backends       map[string]ResumeParserBackend
+	defaultBackend string
+}
+
+func NewParser(extractor TextExtractor, ocr OCR, minTextLength int) *Parser {
+	if minTextLength <= 0 {
+		minTextLength = DefaultMinTextLength
+	}
+	return &Parser{
+		extractor:      extractor,
+		ocr:            ocr,
+		minTextLength:  minTextLength,
+		backends:       map[string]ResumeParserBackend{BackendHeuristic: HeuristicBackend{}},
+		defaultBackend: BackendHeuristic,
+	}
+}
+
+// RegisterBackend makes a backend selectable by name
+func (p *Parser) RegisterBackend(backend ResumeParserBackend) {
+	p.backends[backend.Name()] = backend
+}
+
+// SetDefaultBackend picks the backend used when a request doesn't ask for one
+func (p *Parser) SetDefaultBackend(name string) error {
+	if _, ok := p.backends[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownBackend, name)
+	}
+	p.defaultBackend = name
+	return nil
+}
+
+// Parse extracts structured resume data from the PDF at path.
+// An empty backend name uses the configured default.
+func (p *Parser) Parse(ctx context.Context, path, backendName string) (*models.ParsedResume, error) {
+	if backendName == "" {
+		backendName = p.defaultBackend
+	}
+	backend, ok := p.backends[backendName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownBackend, backendName)
+	}
+
+	text, usedOCR, err := p.extractText(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := backend.ParseText(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	parsed.UsedOCR = usedOCR
+	parsed.Backend = backend.Name()
+	return parsed, nil
+}
+
+// HasText reports whether the PDF at path has a usable text layer. OCR is not tried.
+func (p *Parser) HasText(ctx context.Context, path string) (bool, error) {
+	text, err := p.extractor.ExtractText(ctx, path)
+	if err != nil {
+		return false, err
+	}
+	return textLength(text) >= p.minTextLength, nil
+}
+
+// extractText returns the PDF's text layer, or OCR output when the text layer is missing
+func (p *Parser) extractText(ctx context.Context, path string) (string, bool, error) {
+	text, err := p.extractor.ExtractText(ctx, path)
+	if err != nil && p.ocr == nil {
+		return "", false, err
+	}
+	if err != nil {
+		// Malformed text layers are common in scanned PDFs; OCR may still succeed
+		logging.FromContext(ctx).Warn("Text extraction failed, trying OCR", "error", err)
+	}
+
+	if err == nil && textLength(text) >= p.minTextLength {
+		return text, false, nil
+	}
+
+	if p.ocr == nil {
+		return text, false, nil
+	}
+
+	ocrText, ocrErr := p.ocr.Recognize(ctx, path)
+	if ocrErr != nil {
+		if err != nil {
+			return "", false, ocrErr
+		}
+		// Keep whatever little text we had rather than failing the parse
+		logging.FromContext(ctx).Warn("OCR fallback failed", "error", ocrErr)
+		return text, false, nil
+	}
+
+	return ocrText, true, nil
+}
+
+// textLength counts non-whitespace characters so blank pages full of newlines don't pass
+func textLength(s string) int {
+	return utf8.RuneCountInString(strings.Join(strings.Fields(s), ""))
+}
@@ -0,0 +1,67 @@
+package resume
+
+import (
+	"strings"
+	"time"
+)
+
+// Posting is a job posting as it read when the user applied
+type Posting struct {
+	Title       string
+	Company     string
+	Location    string
+	Salary      string
+	URL         string
+	Description string // Plain text
+	CapturedAt  time.Time
+}
+
+// BuildPostingPDF renders a posting as a printable snapshot, using the resume layout
+func BuildPostingPDF(p Posting) []byte {
+	tpl := templates["modern"]
+	l := &resumeLayout{doc: newPDFDocument(pageWidth, pageHeight), tpl: tpl}
+	l.newPage()
+
+	l.y -= tpl.NameSize
+	for i, line := range wrapText(p.Title, fontBold, tpl.NameSize, pageWidth-2*margin) {
+		if i > 0 {
+			l.y -= tpl.NameSize * 1.2
+		}
+		l.doc.text(margin, l.y, fontBold, tpl.NameSize, tpl.Accent, line)
+	}
+
+	var details []string
+	for _, part := range []string{p.Company, p.Location, p.Salary} {
+		if part = strings.TrimSpace(part); part != "" {
+			details = append(details, part)
+		}
+	}
+	if len(details) > 0 {
+		l.y -= tpl.BodySize * 1.8
+		l.doc.text(margin, l.y, fontRegular, tpl.BodySize, gray, strings.Join(details, "  |  "))
+	}
+	l.y -= tpl.BodySize * 1.6
+	l.doc.text(margin, l.y, fontItalic, tpl.BodySize*0.9, gray, "Captured "+p.CapturedAt.UTC().Format("Jan 2, 2006 15:04 MST"))
+	if p.URL != "" {
+		l.paragraph("", p.URL, fontItalic)
+	}
+
+	l.heading("Description")
+	if strings.TrimSpace(p.Description) == "" {
+		l.paragraph("", "The posting had no description.", fontItalic)
+	}
+	for _, line := range strings.Split(p.Description, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			l.y -= tpl.BodySize * 0.6
+			continue
+		}
+		if rest, ok := strings.CutPrefix(line, "• "); ok {
+			l.paragraph("• ", rest, fontRegular)
+			continue
+		}
+		l.paragraph("", line, fontRegular)
+	}
+
+	return l.doc.bytes()
+}
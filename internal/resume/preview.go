@@ -0,0 +1,48 @@
+package resume
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+)
+
+// Previewer renders the first page of a PDF as a PNG, so clients can show which resume is
+// attached without a PDF viewer
+type Previewer struct {
+	PdftoppmPath string
+	Width        int // Pixels; the height follows the page's aspect ratio
+}
+
+func NewPreviewer(pdftoppmPath string) *Previewer {
+	if pdftoppmPath == "" {
+		pdftoppmPath = "pdftoppm"
+	}
+	return &Previewer{PdftoppmPath: pdftoppmPath, Width: 600}
+}
+
+// Available reports whether pdftoppm can be found on PATH
+func (p *Previewer) Available() bool {
+	_, err := exec.LookPath(p.PdftoppmPath)
+	return err == nil
+}
+
+// Render returns the first page of the PDF at path as PNG data
+func (p *Previewer) Render(ctx context.Context, path string) ([]byte, error) {
+	tmpDir, err := os.MkdirTemp("", "resume-preview-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// -singlefile writes preview.png rather than numbering the page
+	out := filepath.Join(tmpDir, "preview")
+	err = run(ctx, p.PdftoppmPath, "-png", "-f", "1", "-l", "1", "-singlefile",
+		"-scale-to-x", strconv.Itoa(p.Width), "-scale-to-y", "-1", path, out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render preview: %w", err)
+	}
+	return os.ReadFile(out + ".png")
+}
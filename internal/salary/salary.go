@@ -0,0 +1,220 @@
+// Package salary normalizes free-form salary strings ("$120k–$150k", "€65.000", "45/hr")
+// into annual ranges in a single base currency so jobs can be filtered and sorted by pay.
+package salary
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// BaseCurrency is the currency all normalized amounts are expressed in
+const BaseCurrency = "USD"
+
+const (
+	PeriodHour  = "hour"
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+	PeriodYear  = "year"
+)
+
+// Approximate conversion rates to BaseCurrency. Good enough for filtering and ranking, not for
+// quoting exact figures; the original text is kept alongside the normalized values.
+var toBase = map[string]float64{
+	"USD": 1,
+	"EUR": 1.08,
+	"GBP": 1.27,
+	"CHF": 1.12,
+	"CAD": 0.73,
+	"AUD": 0.66,
+	"INR": 0.012,
+	"JPY": 0.0067,
+}
+
+// Hours, days, etc. in a working year
+var periodsPerYear = map[string]float64{
+	PeriodHour:  2080,
+	PeriodDay:   260,
+	PeriodWeek:  52,
+	PeriodMonth: 12,
+	PeriodYear:  1,
+}
+
+// Range is a salary annualized and converted to BaseCurrency
+type Range struct {
+	Min      int
+	Max      int
+	Currency string // Currency the salary was quoted in
+	Period   string // Period the salary was quoted per, inferred from the amount if not stated
+}
+
+var (
+	// Checked in order so "C$" isn't read as plain "$"
+	currencySymbols = []struct{ symbol, code string }{
+		{"us$", "USD"}, {"ca$", "CAD"}, {"c$", "CAD"}, {"au$", "AUD"}, {"a$", "AUD"},
+		{"$", "USD"}, {"€", "EUR"}, {"£", "GBP"}, {"¥", "JPY"}, {"₹", "INR"},
+	}
+	currencyCodeRegex = regexp.MustCompile(`(?i)\b(USD|EUR|GBP|CHF|CAD|AUD|INR|JPY)\b`)
+
+	amountRegex = regexp.MustCompile(`(\d[\d.,]*)\s?([kKmM]\b)?`)
+
+	periodRegex = regexp.MustCompile(`(?i)(?:/|\bper\b|\ban?\b)\s*(hour|hr|h|day|week|wk|month|mo|year|yr|annum)\b|\b(hourly|daily|weekly|monthly|yearly|annually|annual|p\.?a)\b`)
+
+	// Salary mentions inside longer text. A currency marker is required so years, team sizes
+	// and "401k" aren't mistaken for pay, except for explicit hourly rates like "45/hr".
+	currencyMarker = `(?:US\$|CA\$|C\$|AU\$|A\$|\$|€|£|¥|₹|\b(?:USD|EUR|GBP|CHF|CAD|AUD|INR|JPY)\s?)`
+	amount         = `\d[\d.,]*\d?(?:\s?[kKmM]\b)?`
+	rangeSep       = `\s?(?:-|–|—|to)\s?`
+	periodSuffix   = `(?:\s*(?:/|per|an?)\s*(?:hour|hr|h|day|week|wk|month|mo|year|yr|annum)\b|\s+(?:hourly|daily|weekly|monthly|yearly|annually|p\.?a\.?))?`
+	mentionRegex   = regexp.MustCompile(`(?i)` +
+		currencyMarker + `\s?` + amount + `(?:` + rangeSep + currencyMarker + `?\s?` + amount + `)?(?:\s?(?:USD|EUR|GBP|CHF|CAD|AUD|INR|JPY)\b)?` + periodSuffix +
+		`|\b` + amount + `(?:` + rangeSep + amount + `)?\s?(?:€|£|\b(?:USD|EUR|GBP|CHF|CAD|AUD|INR|JPY)\b)` + periodSuffix +
+		`|\b\d+(?:\.\d+)?\s?(?:/|per)\s?(?:hour|hr|h)\b`)
+)
+
+// Parse normalizes a salary string. Amounts without a currency are taken to be in
+// BaseCurrency. It returns false if no plausible salary is found.
+func Parse(s string) (Range, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Range{}, false
+	}
+
+	var amounts []float64
+	for _, m := range amountRegex.FindAllStringSubmatch(s, 2) {
+		value, ok := parseAmount(m[1])
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(m[2]) {
+		case "k":
+			value *= 1_000
+		case "m":
+			value *= 1_000_000
+		}
+		amounts = append(amounts, value)
+	}
+	if len(amounts) == 0 {
+		return Range{}, false
+	}
+	// "120-150k" applies the suffix to both ends
+	if len(amounts) == 2 && amounts[0] < amounts[1]/100 && amounts[1] >= 1_000 {
+		amounts[0] *= 1_000
+	}
+
+	low, high := amounts[0], amounts[len(amounts)-1]
+	if low > high {
+		low, high = high, low
+	}
+
+	currency := detectCurrency(s)
+	period := detectPeriod(s)
+	if period == "" {
+		period = inferPeriod(high)
+	}
+
+	factor := periodsPerYear[period] * toBase[currency]
+	r := Range{
+		Min:      int(math.Round(low * factor)),
+		Max:      int(math.Round(high * factor)),
+		Currency: currency,
+		Period:   period,
+	}
+	// Anything outside this is a misparse rather than a real salary
+	if r.Min < 1_000 || r.Max > 10_000_000 {
+		return Range{}, false
+	}
+	return r, true
+}
+
+// Extract finds the first salary mention in free text such as a job description and
+// returns it verbatim, or "" if there is none
+func Extract(text string) string {
+	for _, m := range mentionRegex.FindAllString(text, -1) {
+		if _, ok := Parse(m); ok {
+			return strings.TrimSpace(m)
+		}
+	}
+	return ""
+}
+
+// parseAmount reads a number written with either "," or "." as the thousands separator.
+// A final separator followed by exactly three digits is a thousands separator
+// ("65.000", "120,000"); otherwise it is the decimal point ("1.5", "65.000,50").
+func parseAmount(s string) (float64, bool) {
+	s = strings.TrimRight(s, ".,")
+	last := strings.LastIndexAny(s, ".,")
+	if last == -1 {
+		v, err := strconv.ParseFloat(s, 64)
+		return v, err == nil
+	}
+
+	other := ","
+	if s[last] == ',' {
+		other = "."
+	}
+	intPart, frac := s[:last], s[last+1:]
+	if len(frac) == 3 && !strings.Contains(intPart, other) {
+		intPart, frac = s, ""
+	}
+	intPart = strings.NewReplacer(",", "", ".", "").Replace(intPart)
+
+	v, err := strconv.ParseFloat(intPart, 64)
+	if err != nil {
+		return 0, false
+	}
+	if frac != "" {
+		f, err := strconv.ParseFloat("0."+frac, 64)
+		if err != nil {
+			return 0, false
+		}
+		v += f
+	}
+	return v, true
+}
+
+func detectCurrency(s string) string {
+	if m := currencyCodeRegex.FindStringSubmatch(s); m != nil {
+		return strings.ToUpper(m[1])
+	}
+	lower := strings.ToLower(s)
+	for _, c := range currencySymbols {
+		if strings.Contains(lower, c.symbol) {
+			return c.code
+		}
+	}
+	return BaseCurrency
+}
+
+func detectPeriod(s string) string {
+	m := periodRegex.FindStringSubmatch(s)
+	if m == nil {
+		return ""
+	}
+	switch strings.ToLower(m[1] + m[2]) {
+	case "hour", "hr", "h", "hourly":
+		return PeriodHour
+	case "day", "daily":
+		return PeriodDay
+	case "week", "wk", "weekly":
+		return PeriodWeek
+	case "month", "mo", "monthly":
+		return PeriodMonth
+	default:
+		return PeriodYear
+	}
+}
+
+// inferPeriod guesses the pay period from the size of the amount when the text doesn't say
+func inferPeriod(amount float64) string {
+	switch {
+	case amount < 300:
+		return PeriodHour
+	case amount < 20_000:
+		return PeriodMonth
+	default:
+		return PeriodYear
+	}
+}
@@ -0,0 +1,24 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrInfected is returned by Scan when the content matches a malware signature
+var ErrInfected = errors.New("file is infected")
+
+// Scanner checks uploaded content for malware before it is stored
+type Scanner interface {
+	// Scan returns an error wrapping ErrInfected (with the signature name) for infected
+	// content, or another error if the scan itself could not be completed
+	Scan(ctx context.Context, r io.Reader) error
+}
+
+// Noop accepts every file. Used when no scanner is configured.
+type Noop struct{}
+
+func (Noop) Scan(ctx context.Context, r io.Reader) error {
+	return nil
+}
@@ -0,0 +1,107 @@
+package scrapers
+
+import (
+	"net/url"
+	"strings"
+)
+
+// maxRedirectUnwrap bounds how many redirect wrappers CanonicalURL peels off one URL
+const maxRedirectUnwrap = 3
+
+// redirectors wrap the real destination in a query parameter
+var redirectors = map[string]string{
+	"google.com/url":              "q",
+	"l.facebook.com/l.php":        "u",
+	"lm.facebook.com/l.php":       "u",
+	"linkedin.com/redir/redirect": "url",
+	"t.umblr.com/redirect":        "z",
+	"out.reddit.com":              "url",
+	"click.appcast.io/track":      "url",
+}
+
+// trackingParams are dropped from job URLs; parameters starting with utm_ are too
+var trackingParams = map[string]bool{
+	"gclid": true, "fbclid": true, "msclkid": true, "dclid": true, "yclid": true,
+	"mc_cid": true, "mc_eid": true, "_hsenc": true, "_hsmi": true,
+	"rc": true, "ref": true, "refid": true, "trk": true, "trackingid": true, "src": true,
+	"gh_src": true, "lever-source": true, "lever-origin": true, "source": true,
+	"from": true, "vjs": true, "tk": true, "sjdu": true,
+}
+
+// mobileHostPrefixes are stripped so mobile and desktop links to a posting match
+var mobileHostPrefixes = []string{"www.", "m.", "mobile."}
+
+// CanonicalURL normalizes a job URL so the same posting reached through different links is
+// stored once: redirect wrappers are unwrapped, Indeed click links become viewjob links,
+// tracking parameters and fragments are dropped, the host is lowercased without www. or
+// mobile prefixes, and the remaining parameters are sorted. URLs that aren't absolute
+// http(s) are returned unchanged.
+func CanonicalURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return raw
+	}
+
+	for range maxRedirectUnwrap {
+		target := unwrapRedirect(u)
+		if target == nil {
+			break
+		}
+		u = target
+	}
+
+	host := strings.ToLower(u.Hostname())
+	for _, prefix := range mobileHostPrefixes {
+		if trimmed := strings.TrimPrefix(host, prefix); trimmed != host && strings.Contains(trimmed, ".") {
+			host = trimmed
+			break
+		}
+	}
+	if port := u.Port(); port != "" && !(u.Scheme == "http" && port == "80") && !(u.Scheme == "https" && port == "443") {
+		host += ":" + port
+	}
+	u.Host = host
+	u.User = nil
+	u.Fragment, u.RawFragment = "", ""
+
+	query := u.Query()
+	// Indeed's ad and click links all name the posting by jk
+	if isIndeed(host) && query.Get("jk") != "" && (strings.HasPrefix(u.Path, "/rc/clk") || strings.HasPrefix(u.Path, "/pagead/clk") || u.Path == "/viewjob") {
+		u.Path = "/viewjob"
+		query = url.Values{"jk": {query.Get("jk")}}
+	}
+	for key := range query {
+		lower := strings.ToLower(key)
+		if trackingParams[lower] || strings.HasPrefix(lower, "utm_") {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode() // Encode sorts by key
+
+	if len(u.Path) > 1 {
+		u.Path = strings.TrimRight(u.Path, "/")
+		u.RawPath = ""
+	}
+	return u.String()
+}
+
+// unwrapRedirect returns the destination of a known redirect wrapper, or nil
+func unwrapRedirect(u *url.URL) *url.URL {
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	param, ok := redirectors[host+strings.TrimRight(u.Path, "/")]
+	if !ok {
+		param, ok = redirectors[host]
+	}
+	if !ok {
+		return nil
+	}
+	target, err := url.Parse(u.Query().Get(param))
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil
+	}
+	return target
+}
+
+func isIndeed(host string) bool {
+	return host == "indeed.com" || strings.HasSuffix(host, ".indeed.com")
+}
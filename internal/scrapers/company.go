@@ -0,0 +1,56 @@
+package scrapers
+
+import (
+	"regexp"
+	"strings"
+)
+
+// viaAgency matches "Google (via TekSystems)" and "Google via TekSystems". It needs text
+// before "via", so names like "Via Transportation" are left alone. normalize_company_name
+// strips the same suffix in SQL.
+var viaAgency = regexp.MustCompile(`(?i)^(.*?\S)\s*[(\[]?\s*\bvia\s+(.+?)\s*[)\]]?\s*$`)
+
+var (
+	nonAlnum      = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+	legalSuffixes = regexp.MustCompile(`( (inc|incorporated|llc|llp|lp|ltd|limited|corp|corporation|co|company|gmbh|plc|ag|sa|sas|bv|nv|pte|pty|srl|spa|ab|oy))+$`)
+)
+
+// staffingAgencies are well-known agencies that post on behalf of other employers, by
+// normalized name
+var staffingAgencies = map[string]bool{
+	"teksystems": true, "tek systems": true, "robert half": true, "randstad": true, "adecco": true,
+	"kforce": true, "insight global": true, "aerotek": true, "apex systems": true, "manpower": true,
+	"manpowergroup": true, "kelly services": true, "hays": true, "modis": true, "akkodis": true,
+	"collabera": true, "cybercoders": true, "jobot": true, "allegis group": true, "experis": true,
+	"beacon hill staffing group": true, "motion recruitment": true, "addison group": true, "vaco": true,
+	"creative circle": true, "aston carter": true, "actalent": true, "express employment professionals": true,
+}
+
+// staffingWords mark a name as an agency's wherever they appear in it
+var staffingWords = regexp.MustCompile(`\b(staffing|recruiting|recruitment|recruiters|talent solutions|employment agency)\b`)
+
+// SplitAgency separates the employer from a staffing agency named with "via". agency is ""
+// when there is none.
+func SplitAgency(company string) (employer, agency string) {
+	company = strings.TrimSpace(company)
+	if m := viaAgency.FindStringSubmatch(company); m != nil {
+		return strings.TrimSpace(m[1]), strings.TrimSpace(m[2])
+	}
+	return company, ""
+}
+
+// NormalizeCompany is the Go twin of the normalize_company_name SQL function: lowercased,
+// punctuation collapsed to spaces, and legal suffixes like "LLC" and "Inc" dropped, so
+// "Google LLC" and "google" match. Any "via" agency suffix is dropped too.
+func NormalizeCompany(name string) string {
+	name, _ = SplitAgency(name)
+	name = strings.TrimSpace(nonAlnum.ReplaceAllString(strings.ToLower(name), " "))
+	return strings.TrimSpace(legalSuffixes.ReplaceAllString(name, ""))
+}
+
+// IsStaffingAgency reports whether a company name is a known staffing agency's or says it
+// recruits for others
+func IsStaffingAgency(name string) bool {
+	normalized := NormalizeCompany(name)
+	return normalized != "" && (staffingAgencies[normalized] || staffingWords.MatchString(normalized))
+}
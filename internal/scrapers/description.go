@@ -0,0 +1,163 @@
+package scrapers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/jobapply/internal/tracing"
+)
+
+// maxPageSize caps how much of a job page is read when fetching a description
+const maxPageSize = 2 << 20
+
+var (
+	jsonLDRegex     = regexp.MustCompile(`(?is)<script[^>]+type=["']application/ld\+json["'][^>]*>(.*?)</script>`)
+	noiseRegex      = regexp.MustCompile(`(?is)<(script|style|noscript|nav|header|footer|aside|form|svg)\b.*?</(script|style|noscript|nav|header|footer|aside|form|svg)>`)
+	containerRegex  = regexp.MustCompile(`(?is)<(article|main)\b[^>]*>(.*?)</(article|main)>`)
+	descClassRegex  = regexp.MustCompile(`(?is)<(div|section)\b[^>]*(class|id)=["'][^"']*(description|job-details|jobdetails|posting)[^"']*["'][^>]*>`)
+	bodyRegex       = regexp.MustCompile(`(?is)<body\b[^>]*>(.*)</body>`)
+	blockBreakRegex = regexp.MustCompile(`(?i)<(br|/p|/div|/ul|/ol|/h[1-6]|/tr|/section)\b[^>]*>`)
+	listItemRegex   = regexp.MustCompile(`(?i)<li\b[^>]*>`)
+	anyTagRegex     = regexp.MustCompile(`(?s)<[^>]*>`)
+	blankLinesRegex = regexp.MustCompile(`\n{3,}`)
+)
+
+// DescriptionFetcher loads a job posting page and extracts its description
+type DescriptionFetcher struct {
+	client *http.Client
+}
+
+func NewDescriptionFetcher() *DescriptionFetcher {
+	return &DescriptionFetcher{client: newPageClient()}
+}
+
+// Fetch returns the posting's description. Structured JobPosting data (schema.org JSON-LD,
+// which most job boards embed) is preferred; otherwise the main content block is
+// reduced to plain text, readability-style.
+func (f *DescriptionFetcher) Fetch(ctx context.Context, pageURL string) (string, error) {
+	status, body, err := fetchPage(ctx, f.client, pageURL)
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusOK {
+		return "", fmt.Errorf("page returned status %d", status)
+	}
+	return ExtractDescription(body)
+}
+
+// fetchPage GETs a job page as a browser would and returns its status and (size-capped) body
+func fetchPage(ctx context.Context, client *http.Client, pageURL string) (int, string, error) {
+	parsed, err := url.Parse(pageURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return 0, "", fmt.Errorf("unsupported job URL %q", pageURL)
+	}
+
+	ctx, span := tracing.Start(ctx, "GET "+parsed.Host, tracing.KindClient, "url.full", pageURL)
+	defer span.End()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; jobapply/1.0)")
+	req.Header.Set("Accept", "text/html")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		span.RecordError(err)
+		return 0, "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	span.SetAttributes("http.response.status_code", resp.StatusCode)
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return resp.StatusCode, "", fmt.Errorf("failed to read page: %w", err)
+	}
+	return resp.StatusCode, string(body), nil
+}
+
+// ExtractDescription pulls the job description out of a posting page's HTML. It is sanitized
+// HTML when the page has structured data, and plain text otherwise.
+func ExtractDescription(page string) (string, error) {
+	if desc := jsonLDDescription(page); desc != "" {
+		return SanitizeHTML(desc), nil
+	}
+
+	cleaned := noiseRegex.ReplaceAllString(page, " ")
+
+	// Prefer an element that is labelled as the description, then <article>/<main>, then <body>
+	content := ""
+	if loc := descClassRegex.FindStringIndex(cleaned); loc != nil {
+		content = cleaned[loc[0]:]
+	} else if m := containerRegex.FindStringSubmatch(cleaned); m != nil {
+		content = m[2]
+	} else if m := bodyRegex.FindStringSubmatch(cleaned); m != nil {
+		content = m[1]
+	}
+
+	text := htmlToText(content)
+	if len(text) < 100 {
+		return "", fmt.Errorf("no description found on page")
+	}
+	return text, nil
+}
+
+// jsonLDDescription finds a schema.org JobPosting and returns its description (usually HTML)
+func jsonLDDescription(page string) string {
+	for _, m := range jsonLDRegex.FindAllStringSubmatch(page, -1) {
+		var raw interface{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(m[1])), &raw); err != nil {
+			continue
+		}
+		if desc := findJobPosting(raw); desc != "" {
+			return desc
+		}
+	}
+	return ""
+}
+
+// findJobPosting walks JSON-LD, which may be a single object, an array, or an @graph
+func findJobPosting(v interface{}) string {
+	switch node := v.(type) {
+	case []interface{}:
+		for _, item := range node {
+			if desc := findJobPosting(item); desc != "" {
+				return desc
+			}
+		}
+	case map[string]interface{}:
+		if t, _ := node["@type"].(string); strings.EqualFold(t, "JobPosting") {
+			if desc, _ := node["description"].(string); strings.TrimSpace(desc) != "" {
+				return strings.TrimSpace(desc)
+			}
+		}
+		if graph, ok := node["@graph"]; ok {
+			return findJobPosting(graph)
+		}
+	}
+	return ""
+}
+
+// htmlToText keeps paragraph and list structure as line breaks and drops all markup
+func htmlToText(s string) string {
+	s = listItemRegex.ReplaceAllString(s, "\n• ")
+	s = blockBreakRegex.ReplaceAllString(s, "\n")
+	s = anyTagRegex.ReplaceAllString(s, " ")
+	s = html.UnescapeString(s)
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	s = strings.Join(lines, "\n")
+	s = blankLinesRegex.ReplaceAllString(s, "\n\n")
+	return strings.TrimSpace(s)
+}
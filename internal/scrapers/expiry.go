@@ -0,0 +1,84 @@
+package scrapers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	// Phrases job boards show on postings that are gone but still resolve
+	closedPhrases = []string{
+		"no longer accepting applications",
+		"no longer accepting applicants",
+		"this job is no longer available",
+		"this job has expired",
+		"this position has been filled",
+		"this job posting has been closed",
+		"this posting has closed",
+		"job is closed",
+		"position is no longer available",
+		"the job you are looking for is no longer",
+	}
+
+	validThroughRegex = regexp.MustCompile(`"validThrough"\s*:\s*"([^"]+)"`)
+)
+
+// PostingStatus is the result of revisiting a job posting
+type PostingStatus struct {
+	Closed bool
+	Reason string // Why the posting is considered closed
+}
+
+// PostingChecker revisits job postings to detect ones that were removed or closed
+type PostingChecker struct {
+	client *http.Client
+}
+
+func NewPostingChecker() *PostingChecker {
+	return &PostingChecker{client: newPageClient()}
+}
+
+// Check reports whether a posting is closed. Errors (timeouts, 5xx, rate limiting) mean the
+// status is unknown and the posting should be checked again later, not that it closed.
+func (c *PostingChecker) Check(ctx context.Context, pageURL string) (PostingStatus, error) {
+	status, body, err := fetchPage(ctx, c.client, pageURL)
+	if err != nil {
+		return PostingStatus{}, err
+	}
+
+	switch {
+	case status == http.StatusNotFound || status == http.StatusGone:
+		return PostingStatus{Closed: true, Reason: fmt.Sprintf("page returned %d", status)}, nil
+	case status != http.StatusOK:
+		return PostingStatus{}, fmt.Errorf("page returned status %d", status)
+	}
+
+	return PostingStatusFromPage(body, time.Now()), nil
+}
+
+// PostingStatusFromPage looks for an expired JobPosting validThrough date or a closed notice
+func PostingStatusFromPage(page string, now time.Time) PostingStatus {
+	if m := validThroughRegex.FindStringSubmatch(page); m != nil {
+		for _, layout := range []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02"} {
+			if t, err := time.Parse(layout, m[1]); err == nil {
+				if t.Before(now) {
+					return PostingStatus{Closed: true, Reason: "posting expired " + t.Format("2006-01-02")}
+				}
+				break
+			}
+		}
+	}
+
+	text := strings.ToLower(htmlToText(noiseRegex.ReplaceAllString(page, " ")))
+	for _, phrase := range closedPhrases {
+		if strings.Contains(text, phrase) {
+			return PostingStatus{Closed: true, Reason: fmt.Sprintf("page says %q", phrase)}
+		}
+	}
+
+	return PostingStatus{}
+}
@@ -1,22 +1,44 @@
 package scrapers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"time"
+
+	"github.com/yourusername/jobapply/internal/salary"
 )
 
 type Job struct {
-	Title    string
-	Company  string
-	Location string
-	URL      string
+	Title       string
+	Company     string
+	Location    string
+	URL         string
+	Description string     // Sanitized HTML, or plain text
+	PostedAt    *time.Time // nil if the source doesn't say
+	Salary      string     // Salary as written in the posting, "" if not mentioned
+	CompanyRef  int        // Source's own company ID for enrichment, 0 if unknown
+	Agency      string     // Staffing agency the posting came through, "" if direct
+}
+
+// CompanyInfo is what the source knows about a company
+type CompanyInfo struct {
+	Name        string
+	Industry    string
+	Size        string
+	Description string // Plain text
+	ProfileURL  string
 }
 
+// museAPI is The Muse's public API
+const museAPI = "https://www.themuse.com/api/public"
+
 type MuseScraper struct {
-	client *http.Client
+	client  *http.Client
+	baseURL string // museAPI, or a test server
 }
 
 func NewMuseScraper() *MuseScraper {
@@ -24,6 +46,7 @@ func NewMuseScraper() *MuseScraper {
 		client: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		baseURL: museAPI,
 	}
 }
 
@@ -34,16 +57,31 @@ type museResponse struct {
 }
 
 type museJob struct {
-	Name     string      `json:"name"`     // Job title
-	Company  museCompany `json:"company"`  // Company info
-	Locations []museLocation `json:"locations"` // Job locations
-	Refs     museRefs    `json:"refs"`     // URLs
+	Name            string         `json:"name"`             // Job title
+	Company         museCompany    `json:"company"`          // Company info
+	Locations       []museLocation `json:"locations"`        // Job locations
+	Refs            museRefs       `json:"refs"`             // URLs
+	Contents        string         `json:"contents"`         // Job description (HTML)
+	PublicationDate string         `json:"publication_date"` // RFC3339
 }
 
 type museCompany struct {
+	ID   int    `json:"id"`
 	Name string `json:"name"`
 }
 
+type museCompanyDetail struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Industries  []struct {
+		Name string `json:"name"`
+	} `json:"industries"`
+	Size struct {
+		Name string `json:"name"` // e.g. "Large Size"
+	} `json:"size"`
+	Refs museRefs `json:"refs"`
+}
+
 type museLocation struct {
 	Name string `json:"name"` // e.g., "New York, NY"
 }
@@ -52,9 +90,9 @@ type museRefs struct {
 	LandingPage string `json:"landing_page"` // Application URL
 }
 
-func (s *MuseScraper) Scrape(keywords, location string) ([]Job, error) {
+// Scrape searches The Muse. Cancelling ctx, e.g. when the client disconnects, abandons the request.
+func (s *MuseScraper) Scrape(ctx context.Context, keywords, location string) ([]Job, error) {
 	// Build The Muse API URL
-	baseURL := "https://www.themuse.com/api/public/jobs"
 	params := url.Values{}
 
 	// Muse API only supports category (broad) and location filters
@@ -68,11 +106,11 @@ func (s *MuseScraper) Scrape(keywords, location string) ([]Job, error) {
 	params.Add("page", "0")
 	params.Add("descending", "true")
 
-	apiURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
-	fmt.Printf("[DEBUG] Muse API URL: %s\n", apiURL)
+	apiURL := fmt.Sprintf("%s/jobs?%s", s.baseURL, params.Encode())
+	slog.Debug("Muse API request", "url", apiURL)
 
 	// Make HTTP request
-	resp, err := s.client.Get(apiURL)
+	resp, err := s.get(ctx, apiURL)
 	if err != nil {
 		return nil, fmt.Errorf("API request failed: %w", err)
 	}
@@ -88,30 +126,75 @@ func (s *MuseScraper) Scrape(keywords, location string) ([]Job, error) {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	fmt.Printf("[DEBUG] Muse API returned %d jobs\n", len(museResp.Results))
+	slog.Debug("Muse API response", "results", len(museResp.Results))
 
 	// Convert to our Job format
 	jobs := make([]Job, 0, len(museResp.Results))
 	for _, mj := range museResp.Results {
-		// Skip jobs without required fields
-		if mj.Name == "" || mj.Company.Name == "" || mj.Refs.LandingPage == "" {
-			continue
-		}
-
+		// Incomplete jobs are kept, so the caller's validation can report them
 		// Get first location if available
 		locationStr := ""
 		if len(mj.Locations) > 0 {
 			locationStr = mj.Locations[0].Name
 		}
 
+		var postedAt *time.Time
+		if t, err := time.Parse(time.RFC3339, mj.PublicationDate); err == nil {
+			postedAt = &t
+		}
+
+		company, agency := SplitAgency(mj.Company.Name)
 		jobs = append(jobs, Job{
-			Title:    mj.Name,
-			Company:  mj.Company.Name,
-			Location: locationStr,
-			URL:      mj.Refs.LandingPage,
+			Title:       mj.Name,
+			Company:     company,
+			Agency:      agency,
+			Location:    locationStr,
+			URL:         CanonicalURL(mj.Refs.LandingPage),
+			Description: SanitizeHTML(mj.Contents),
+			PostedAt:    postedAt,
+			// Muse has no salary field, but many postings state it in the description
+			Salary:     salary.Extract(htmlToText(mj.Contents)),
+			CompanyRef: mj.Company.ID,
 		})
 	}
 
-	fmt.Printf("[DEBUG] Converted %d valid jobs\n", len(jobs))
+	slog.Debug("Muse jobs converted", "jobs", len(jobs))
 	return jobs, nil
 }
+
+// Company fetches a company's profile from The Muse by its ID
+func (s *MuseScraper) Company(ctx context.Context, id int) (*CompanyInfo, error) {
+	resp, err := s.get(ctx, fmt.Sprintf("%s/companies/%d", s.baseURL, id))
+	if err != nil {
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	var detail museCompanyDetail
+	if err := json.NewDecoder(resp.Body).Decode(&detail); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	info := &CompanyInfo{
+		Name:        detail.Name,
+		Size:        detail.Size.Name,
+		Description: htmlToText(detail.Description),
+		ProfileURL:  detail.Refs.LandingPage,
+	}
+	if len(detail.Industries) > 0 {
+		info.Industry = detail.Industries[0].Name
+	}
+	return info, nil
+}
+
+func (s *MuseScraper) get(ctx context.Context, apiURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.Do(req)
+}
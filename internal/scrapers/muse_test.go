@@ -0,0 +1,98 @@
+package scrapers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+)
+
+// newMuseFixtureServer serves testdata/muse_jobs.json as The Muse's job search API and
+// returns a scraper pointed at it, and the query of the last search the server received
+func newMuseFixtureServer(t *testing.T) (*MuseScraper, *url.Values) {
+	t.Helper()
+	fixture, err := os.ReadFile("testdata/muse_jobs.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := &url.Values{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/jobs" {
+			http.NotFound(w, r)
+			return
+		}
+		*query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture)
+	}))
+	t.Cleanup(srv.Close)
+
+	s := NewMuseScraper()
+	s.baseURL = srv.URL
+	return s, query
+}
+
+func TestMuseScrapeParsesFixture(t *testing.T) {
+	s, query := newMuseFixtureServer(t)
+
+	jobs, err := s.Scrape(context.Background(), "Software Engineering", "New York, NY")
+	if err != nil {
+		t.Fatalf("Scrape: %v", err)
+	}
+	if got := query.Get("category"); got != "Software Engineering" {
+		t.Errorf("category = %q, want %q", got, "Software Engineering")
+	}
+	if got := query.Get("location"); got != "New York, NY" {
+		t.Errorf("location = %q, want %q", got, "New York, NY")
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("got %d jobs, want 2", len(jobs))
+	}
+
+	posted := time.Date(2025, 3, 4, 17, 21, 9, 0, time.UTC)
+	want := Job{
+		Title:       "Senior Backend Engineer",
+		Company:     "AcmePay",
+		Location:    "New York, NY",
+		URL:         "https://themuse.com/jobs/acmepay/senior-backend-engineer",
+		Description: "<p>Build the payments platform in Go.</p><p>Salary: $150,000 - $180,000 per year</p><ul><li>Go</li><li>PostgreSQL</li></ul>",
+		Salary:      "$150,000 - $180,000 per year",
+		CompanyRef:  4821,
+	}
+	got := jobs[0]
+	if got.PostedAt == nil || !got.PostedAt.Equal(posted) {
+		t.Errorf("PostedAt = %v, want %v", got.PostedAt, posted)
+	}
+	got.PostedAt = nil
+	if got != want {
+		t.Errorf("first job:\n got %+v\nwant %+v", got, want)
+	}
+
+	// An unparseable date, no locations and an agency-posted company
+	want = Job{
+		Title:       "Data Engineer",
+		Company:     "Northwind",
+		Agency:      "Robert Half",
+		URL:         "https://themuse.com/jobs/northwind/data-engineer",
+		Description: "Contract role maintaining ETL pipelines.",
+	}
+	if jobs[1] != want {
+		t.Errorf("second job:\n got %+v\nwant %+v", jobs[1], want)
+	}
+}
+
+func TestMuseScrapeRejectsErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	}))
+	t.Cleanup(srv.Close)
+
+	s := NewMuseScraper()
+	s.baseURL = srv.URL
+	if _, err := s.Scrape(context.Background(), "", ""); err == nil {
+		t.Fatal("Scrape succeeded on a 429 response")
+	}
+}
@@ -0,0 +1,364 @@
+package scrapers
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	htmlTagRegex      = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+	markdownEscapes   = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`)
+	markdownLineStart = regexp.MustCompile(`^(\s*)([-+#>])(\s)`)
+	markdownNumbered  = regexp.MustCompile(`^(\s*)(\d+)\.(\s)`)
+)
+
+// allowedTags are kept by SanitizeHTML; other elements are unwrapped, keeping their text
+var allowedTags = map[atom.Atom]bool{
+	atom.P: true, atom.Br: true, atom.Hr: true, atom.Div: true, atom.Span: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Ul: true, atom.Ol: true, atom.Li: true,
+	atom.Strong: true, atom.B: true, atom.Em: true, atom.I: true, atom.U: true,
+	atom.A: true, atom.Blockquote: true, atom.Pre: true, atom.Code: true,
+	atom.Table: true, atom.Thead: true, atom.Tbody: true, atom.Tr: true, atom.Th: true, atom.Td: true,
+}
+
+// droppedTags are removed along with everything inside them
+var droppedTags = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true,
+	atom.Iframe: true, atom.Object: true, atom.Embed: true, atom.Svg: true, atom.Math: true,
+	atom.Form: true, atom.Input: true, atom.Button: true, atom.Select: true, atom.Textarea: true,
+	atom.Head: true, atom.Title: true, atom.Meta: true, atom.Link: true,
+}
+
+// IsHTML reports whether a description contains markup; descriptions read from a page's text
+// are plain
+func IsHTML(s string) bool {
+	return htmlTagRegex.MatchString(s)
+}
+
+// SanitizeHTML makes a scraped description safe to render: scripts, styles, embeds and forms
+// are removed, other unknown elements are unwrapped, and every attribute is dropped except
+// http(s) and mailto links, which open in a new tab without a referrer. Plain text is
+// returned unchanged.
+func SanitizeHTML(s string) string {
+	if !IsHTML(s) {
+		return s
+	}
+	nodes, err := html.ParseFragment(strings.NewReader(s), &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div})
+	if err != nil {
+		return htmlToText(s)
+	}
+	var b strings.Builder
+	for _, n := range nodes {
+		writeSanitized(&b, n)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func writeSanitized(b *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		b.WriteString(html.EscapeString(n.Data))
+		return
+	case html.ElementNode:
+	default:
+		// Comments and doctypes are dropped
+		return
+	}
+
+	if droppedTags[n.DataAtom] {
+		return
+	}
+	keep := allowedTags[n.DataAtom]
+	if keep {
+		b.WriteString("<" + n.Data)
+		if n.DataAtom == atom.A {
+			if href := safeLink(attr(n, "href")); href != "" {
+				fmt.Fprintf(b, ` href="%s" rel="nofollow noopener noreferrer" target="_blank"`, html.EscapeString(href))
+			}
+		}
+		b.WriteString(">")
+		if n.DataAtom == atom.Br || n.DataAtom == atom.Hr {
+			return
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		writeSanitized(b, c)
+	}
+	if keep {
+		b.WriteString("</" + n.Data + ">")
+	}
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Namespace == "" && strings.EqualFold(a.Key, key) {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// safeLink returns href if it is an absolute http(s) or mailto link, and "" otherwise
+func safeLink(href string) string {
+	u, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return ""
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		if u.Host == "" {
+			return ""
+		}
+	case "mailto":
+	default:
+		return ""
+	}
+	return u.String()
+}
+
+// DescriptionMarkdown converts a description to Markdown, so clients can render it without an
+// HTML sanitizer of their own. HTML is sanitized first; plain text is escaped so it renders
+// as written.
+func DescriptionMarkdown(s string) string {
+	if !IsHTML(s) {
+		return escapeMarkdownText(s)
+	}
+	nodes, err := html.ParseFragment(strings.NewReader(SanitizeHTML(s)), &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div})
+	if err != nil {
+		return escapeMarkdownText(htmlToText(s))
+	}
+	m := &markdownWriter{}
+	for _, n := range nodes {
+		m.node(n)
+	}
+	return strings.TrimSpace(blankLinesRegex.ReplaceAllString(m.b.String(), "\n\n"))
+}
+
+// DescriptionText converts a description to plain text, keeping paragraphs and list items as
+// lines. Plain text is returned unchanged.
+func DescriptionText(s string) string {
+	if !IsHTML(s) {
+		return s
+	}
+	return htmlToText(s)
+}
+
+func escapeMarkdownText(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		line = markdownEscapes.Replace(line)
+		// Text that would start a list, heading or quote
+		line = markdownLineStart.ReplaceAllString(line, `$1\$2$3`)
+		lines[i] = markdownNumbered.ReplaceAllString(line, `$1$2\.$3`)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// markdownWriter renders sanitized HTML as Markdown. Lines are prefixed for the lists and
+// quotes they're nested in.
+type markdownWriter struct {
+	b      strings.Builder
+	prefix string
+	// atLineStart is set after a newline, so the next text gets the prefix
+	atLineStart bool
+	// inPre keeps whitespace as it is inside <pre>
+	inPre bool
+}
+
+func (m *markdownWriter) write(s string) {
+	for i, line := range strings.Split(s, "\n") {
+		if i > 0 {
+			m.b.WriteString("\n")
+			m.atLineStart = true
+		}
+		if line == "" {
+			continue
+		}
+		if m.atLineStart {
+			m.b.WriteString(m.prefix)
+			m.atLineStart = false
+		}
+		m.b.WriteString(line)
+	}
+}
+
+// block starts a new paragraph
+func (m *markdownWriter) block() {
+	if m.b.Len() == 0 {
+		return
+	}
+	if !m.atLineStart {
+		m.write("\n")
+	}
+	m.write("\n")
+}
+
+func (m *markdownWriter) children(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		m.node(c)
+	}
+}
+
+func (m *markdownWriter) node(n *html.Node) {
+	if n.Type == html.TextNode {
+		if m.inPre {
+			m.write(n.Data)
+			return
+		}
+		text := strings.Join(strings.Fields(n.Data), " ")
+		if text == "" {
+			if n.Data != "" && !m.atLineStart && m.b.Len() > 0 {
+				m.write(" ")
+			}
+			return
+		}
+		if strings.TrimLeft(n.Data, " \t\r\n") != n.Data && !m.atLineStart && m.b.Len() > 0 {
+			text = " " + text
+		}
+		if strings.TrimRight(n.Data, " \t\r\n") != n.Data {
+			text += " "
+		}
+		m.write(escapeMarkdownText(text))
+		return
+	}
+	if n.Type != html.ElementNode {
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.P, atom.Div, atom.Table:
+		m.block()
+		m.children(n)
+		m.block()
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		m.block()
+		m.write(strings.Repeat("#", int(n.Data[1]-'0')) + " ")
+		m.children(n)
+		m.block()
+	case atom.Br:
+		m.write("  \n")
+	case atom.Hr:
+		m.block()
+		m.write("---")
+		m.block()
+	case atom.Strong, atom.B:
+		m.wrap(n, "**")
+	case atom.Em, atom.I:
+		m.wrap(n, "*")
+	case atom.Code:
+		if m.inPre {
+			m.children(n)
+		} else {
+			m.write("`" + strings.ReplaceAll(textContent(n), "`", "'") + "`")
+		}
+	case atom.Pre:
+		m.block()
+		m.write("```\n")
+		m.inPre = true
+		m.children(n)
+		m.inPre = false
+		m.write("\n```")
+		m.block()
+	case atom.A:
+		href := attr(n, "href")
+		if href == "" {
+			m.children(n)
+			return
+		}
+		m.write("[")
+		m.children(n)
+		m.write("](" + strings.NewReplacer("(", "%28", ")", "%29", " ", "%20").Replace(href) + ")")
+	case atom.Ul, atom.Ol:
+		m.list(n)
+	case atom.Blockquote:
+		m.block()
+		saved := m.prefix
+		m.prefix += "> "
+		m.children(n)
+		m.prefix = saved
+		m.block()
+	case atom.Tr:
+		if !m.atLineStart && m.b.Len() > 0 {
+			m.write("\n")
+		}
+		cells := 0
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode {
+				if cells > 0 {
+					m.write(" | ")
+				}
+				m.children(c)
+				cells++
+			}
+		}
+		m.write("\n")
+	default:
+		m.children(n)
+	}
+}
+
+// wrap surrounds an element's text with a Markdown marker, which only works without
+// surrounding spaces
+func (m *markdownWriter) wrap(n *html.Node, marker string) {
+	if strings.TrimSpace(textContent(n)) == "" {
+		m.children(n)
+		return
+	}
+	m.write(marker)
+	m.children(n)
+	// Move trailing spaces outside the marker
+	trimmed := strings.TrimRight(m.b.String(), " ")
+	spaces := m.b.Len() - len(trimmed)
+	m.b.Reset()
+	m.b.WriteString(trimmed)
+	m.write(marker + strings.Repeat(" ", spaces))
+}
+
+func (m *markdownWriter) list(n *html.Node) {
+	if m.prefix == "" {
+		m.block()
+	} else if !m.atLineStart {
+		m.write("\n")
+	}
+	ordered := n.DataAtom == atom.Ol
+	saved := m.prefix
+	index := 0
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode || c.DataAtom != atom.Li {
+			continue
+		}
+		index++
+		marker := "- "
+		if ordered {
+			marker = fmt.Sprintf("%d. ", index)
+		}
+		if !m.atLineStart && m.b.Len() > 0 {
+			m.write("\n")
+		}
+		m.write(marker)
+		// Continuation lines and nested lists are indented under the item's text
+		m.prefix = saved + strings.Repeat(" ", len(marker))
+		m.children(c)
+		m.prefix = saved
+	}
+	if saved == "" {
+		m.block()
+	}
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(textContent(c))
+	}
+	return b.String()
+}
@@ -0,0 +1,56 @@
+package scrapers
+
+import (
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// Field limits for scraped jobs. Anything longer is a sign the source's markup changed and the
+// scraper picked up the wrong element.
+const (
+	maxTitleLen       = 300
+	maxCompanyLen     = 200
+	maxLocationLen    = 200
+	maxURLLen         = 2048
+	maxDescriptionLen = 200_000
+)
+
+// Problems checks a scraped job before it is stored, returning why it should be rejected, or
+// nil if it looks sound: required fields present, sane lengths, and an http(s) URL on a
+// public host.
+func (j *Job) Problems() []string {
+	var problems []string
+	required := func(field, value string, max int) {
+		switch {
+		case strings.TrimSpace(value) == "":
+			problems = append(problems, field+" is missing")
+		case utf8.RuneCountInString(value) > max:
+			problems = append(problems, field+" is too long")
+		}
+	}
+	required("title", j.Title, maxTitleLen)
+	required("company", j.Company, maxCompanyLen)
+	required("url", j.URL, maxURLLen)
+	if utf8.RuneCountInString(j.Location) > maxLocationLen {
+		problems = append(problems, "location is too long")
+	}
+	if len(j.Description) > maxDescriptionLen {
+		problems = append(problems, "description is too long")
+	}
+
+	if j.URL != "" {
+		u, err := url.Parse(j.URL)
+		switch {
+		case err != nil:
+			problems = append(problems, "url is not valid")
+		case u.Scheme != "http" && u.Scheme != "https":
+			problems = append(problems, "url scheme must be http or https")
+		case u.Host == "":
+			problems = append(problems, "url has no host")
+		case !PublicHost(u.Host):
+			problems = append(problems, "url host is not public")
+		}
+	}
+	return problems
+}
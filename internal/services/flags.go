@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yourusername/jobapply/internal/logging"
+)
+
+// flagCacheTTL bounds how stale an instance's flags can be after another instance changes them
+const flagCacheTTL = 30 * time.Second
+
+// ErrUnknownFlag is returned when a flag that doesn't exist is read or deleted
+var ErrUnknownFlag = errors.New("unknown feature flag")
+
+// Flag turns a feature on for some users without a deploy. A flag is on for a user when it is
+// enabled and either lists the user or the user falls in its rollout percentage.
+type Flag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Enabled is the kill switch: a disabled flag is off for everyone
+	Enabled bool `json:"enabled"`
+	// Percentage of users the flag is on for, 0-100. Each user lands in a stable bucket per
+	// flag, so raising the percentage only adds users.
+	Percentage int       `json:"percentage"`
+	Users      []string  `json:"users"` // On for these users whatever the percentage
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// On reports whether the flag is on for userID
+func (f *Flag) On(userID string) bool {
+	if !f.Enabled {
+		return false
+	}
+	if slices.Contains(f.Users, userID) {
+		return true
+	}
+	return userID != "" && flagBucket(f.Name, userID) < f.Percentage
+}
+
+// flagBucket places a user in 0-99 for a flag. Hashing the name too means the same users
+// aren't first in line for every rollout.
+func flagBucket(name, userID string) int {
+	h := fnv.New32a()
+	h.Write([]byte(name + ":" + userID))
+	return int(h.Sum32() % 100)
+}
+
+// Flags reads feature flags from the database, keeping them in memory for up to flagCacheTTL
+type Flags struct {
+	db *pgxpool.Pool
+
+	mu       sync.Mutex
+	flags    map[string]*Flag
+	loadedAt time.Time
+}
+
+func NewFlags(db *pgxpool.Pool) *Flags {
+	return &Flags{db: db}
+}
+
+// Enabled reports whether the named flag is on for userID. Unknown flags are off, and so is
+// everything if the flags can't be loaded, so a database problem can't switch features on.
+func (f *Flags) Enabled(ctx context.Context, name, userID string) bool {
+	flags, err := f.cached(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to load feature flags", "error", err)
+		return false
+	}
+	flag, ok := flags[name]
+	return ok && flag.On(userID)
+}
+
+// EnabledFor returns the names of the flags that are on for userID
+func (f *Flags) EnabledFor(ctx context.Context, userID string) ([]string, error) {
+	flags, err := f.cached(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for name, flag := range flags {
+		if flag.On(userID) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+func (f *Flags) cached(ctx context.Context) (map[string]*Flag, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.flags != nil && time.Since(f.loadedAt) < flagCacheTTL {
+		return f.flags, nil
+	}
+	list, err := f.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	f.flags = make(map[string]*Flag, len(list))
+	for i := range list {
+		f.flags[list[i].Name] = &list[i]
+	}
+	f.loadedAt = time.Now()
+	return f.flags, nil
+}
+
+// invalidate makes the next check reload, so changes take effect at once on this instance
+func (f *Flags) invalidate() {
+	f.mu.Lock()
+	f.flags = nil
+	f.mu.Unlock()
+}
+
+// List returns every flag, by name, straight from the database
+func (f *Flags) List(ctx context.Context) ([]Flag, error) {
+	rows, err := f.db.Query(ctx, `
+		SELECT name, description, enabled, percentage, users, updated_at
+		FROM feature_flags ORDER BY name
+	`)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanFlag)
+}
+
+// Get returns one flag, or ErrUnknownFlag
+func (f *Flags) Get(ctx context.Context, name string) (*Flag, error) {
+	rows, err := f.db.Query(ctx, `
+		SELECT name, description, enabled, percentage, users, updated_at
+		FROM feature_flags WHERE name = $1
+	`, name)
+	if err != nil {
+		return nil, err
+	}
+	flag, err := pgx.CollectExactlyOneRow(rows, scanFlag)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUnknownFlag
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &flag, nil
+}
+
+// Put creates or replaces a flag
+func (f *Flags) Put(ctx context.Context, flag Flag) (*Flag, error) {
+	if flag.Users == nil {
+		flag.Users = []string{}
+	}
+	rows, err := f.db.Query(ctx, `
+		INSERT INTO feature_flags (name, description, enabled, percentage, users)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (name) DO UPDATE SET description = EXCLUDED.description, enabled = EXCLUDED.enabled,
+			percentage = EXCLUDED.percentage, users = EXCLUDED.users, updated_at = NOW()
+		RETURNING name, description, enabled, percentage, users, updated_at
+	`, flag.Name, flag.Description, flag.Enabled, flag.Percentage, flag.Users)
+	if err != nil {
+		return nil, err
+	}
+	saved, err := pgx.CollectExactlyOneRow(rows, scanFlag)
+	if err != nil {
+		return nil, err
+	}
+	f.invalidate()
+	return &saved, nil
+}
+
+// Delete removes a flag, turning it off for everyone
+func (f *Flags) Delete(ctx context.Context, name string) error {
+	result, err := f.db.Exec(ctx, "DELETE FROM feature_flags WHERE name = $1", name)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrUnknownFlag
+	}
+	f.invalidate()
+	return nil
+}
+
+func scanFlag(row pgx.CollectableRow) (Flag, error) {
+	var flag Flag
+	err := row.Scan(&flag.Name, &flag.Description, &flag.Enabled, &flag.Percentage, &flag.Users, &flag.UpdatedAt)
+	return flag, err
+}
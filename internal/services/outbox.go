@@ -0,0 +1,288 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yourusername/jobapply/internal/events"
+	"github.com/yourusername/jobapply/internal/logging"
+)
+
+const (
+	// DefaultOutboxAttempts is how many times an event is tried before it is dead-lettered
+	DefaultOutboxAttempts = 10
+	// outboxBatch is how many events one dispatch claims
+	outboxBatch = 50
+	// outboxLease is how long a claimed event is left to its dispatcher before another may
+	// claim it, so events claimed by a crashed instance are retried
+	outboxLease = 5 * time.Minute
+	// sinkTimeout bounds one delivery to one sink
+	sinkTimeout = 30 * time.Second
+	// Retries back off from outboxRetryBase, doubling up to outboxRetryMax
+	outboxRetryBase = 10 * time.Second
+	outboxRetryMax  = time.Hour
+	// Delivered events are kept this long for debugging, dead letters longer for inspection
+	deliveredRetention  = 7 * 24 * time.Hour
+	deadLetterRetention = 30 * 24 * time.Hour
+)
+
+// ErrUnknownEvent is returned for an event that isn't in the outbox, or isn't dead
+var ErrUnknownEvent = errors.New("unknown event")
+
+// Sink receives events from the outbox. An error means the delivery is retried later.
+type Sink func(ctx context.Context, e events.Event) error
+
+type namedSink struct {
+	name string
+	sink Sink
+}
+
+// DeadLetter is an event that failed delivery too many times and is no longer retried
+type DeadLetter struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	Event       json.RawMessage `json:"event"`
+	Attempts    int             `json:"attempts"`
+	DeliveredTo []string        `json:"delivered_to"` // Sinks that did receive it
+	LastError   *string         `json:"last_error,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	DeadAt      time.Time       `json:"dead_at"`
+}
+
+// Outbox delivers the events stores queue in event_outbox to every sink, retrying failures
+// with backoff until each sink has the event or it runs out of attempts. Instances sharing a
+// database claim different events. Events are delivered at least once, roughly in order;
+// sinks should deduplicate by event ID.
+type Outbox struct {
+	db          *pgxpool.Pool
+	poll        time.Duration
+	maxAttempts int
+	sinks       []namedSink
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewOutbox returns a dispatcher that checks for new events every poll. Add sinks before
+// calling Start.
+func NewOutbox(db *pgxpool.Pool, poll time.Duration, maxAttempts int) *Outbox {
+	if maxAttempts < 1 {
+		maxAttempts = DefaultOutboxAttempts
+	}
+	return &Outbox{db: db, poll: poll, maxAttempts: maxAttempts}
+}
+
+// AddSink registers a destination. Names are recorded per event, so keep them stable.
+func (o *Outbox) AddSink(name string, sink Sink) {
+	o.sinks = append(o.sinks, namedSink{name: name, sink: sink})
+}
+
+// Start dispatches in the background until Stop
+func (o *Outbox) Start() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	ctx, cancel := context.WithCancel(context.Background())
+	o.cancel = cancel
+	o.done = make(chan struct{})
+	go func() {
+		defer close(o.done)
+		o.loop(ctx)
+	}()
+}
+
+// Stop ends dispatching and waits for the deliveries under way, until ctx ends. Events left
+// claimed are retried once their lease runs out.
+func (o *Outbox) Stop(ctx context.Context) error {
+	o.mu.Lock()
+	cancel, done := o.cancel, o.done
+	o.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (o *Outbox) loop(ctx context.Context) {
+	for ctx.Err() == nil {
+		claimed, err := o.Dispatch(ctx)
+		if err != nil && ctx.Err() == nil {
+			logging.FromContext(ctx).Error("Outbox dispatch failed", "error", err)
+		}
+		// A full batch suggests more are waiting
+		if claimed == outboxBatch && err == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(o.poll):
+		}
+	}
+}
+
+type outboxEntry struct {
+	id          string
+	event       events.Event
+	deliveredTo []string
+	attempts    int
+}
+
+// Dispatch claims a batch of due events and delivers them, returning how many it claimed
+func (o *Outbox) Dispatch(ctx context.Context) (int, error) {
+	rows, err := o.db.Query(ctx, `
+		UPDATE event_outbox SET next_attempt_at = NOW() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id FROM event_outbox
+			WHERE delivered_at IS NULL AND dead_at IS NULL AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, event, delivered_to, attempts
+	`, outboxBatch, outboxLease.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim events: %w", err)
+	}
+	var entries []outboxEntry
+	for rows.Next() {
+		var entry outboxEntry
+		var data []byte
+		if err := rows.Scan(&entry.id, &data, &entry.deliveredTo, &entry.attempts); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to claim events: %w", err)
+		}
+		if err := json.Unmarshal(data, &entry.event); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to read event %s: %w", entry.id, err)
+		}
+		entries = append(entries, entry)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to claim events: %w", err)
+	}
+	slices.SortFunc(entries, func(a, b outboxEntry) int { return a.event.Time.Compare(b.event.Time) })
+
+	// Outcomes are recorded even if shutdown begins mid-batch
+	recordCtx := context.WithoutCancel(ctx)
+	for _, entry := range entries {
+		if err := o.deliver(recordCtx, entry); err != nil {
+			return len(entries), err
+		}
+	}
+	return len(entries), nil
+}
+
+// deliver sends the event to the sinks that don't have it yet and records the outcome
+func (o *Outbox) deliver(ctx context.Context, entry outboxEntry) error {
+	var failures []error
+	for _, s := range o.sinks {
+		if slices.Contains(entry.deliveredTo, s.name) {
+			continue
+		}
+		sinkCtx, cancel := context.WithTimeout(ctx, sinkTimeout)
+		err := s.sink(sinkCtx, entry.event)
+		cancel()
+		if err != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", s.name, err))
+			continue
+		}
+		entry.deliveredTo = append(entry.deliveredTo, s.name)
+	}
+
+	if len(failures) == 0 {
+		_, err := o.db.Exec(ctx, `
+			UPDATE event_outbox SET delivered_to = $2, delivered_at = NOW(), last_error = NULL WHERE id = $1
+		`, entry.id, entry.deliveredTo)
+		return err
+	}
+
+	attempts := entry.attempts + 1
+	lastError := errors.Join(failures...).Error()
+	logger := logging.FromContext(ctx).With("event_id", entry.id, "event_type", entry.event.Type, "attempts", attempts, "error", lastError)
+	if attempts >= o.maxAttempts {
+		logger.Error("Event dead-lettered after repeated delivery failures")
+		_, err := o.db.Exec(ctx, `
+			UPDATE event_outbox SET delivered_to = $2, attempts = $3, last_error = $4, dead_at = NOW() WHERE id = $1
+		`, entry.id, entry.deliveredTo, attempts, lastError)
+		return err
+	}
+	logger.Warn("Event delivery failed, will retry")
+	_, err := o.db.Exec(ctx, `
+		UPDATE event_outbox
+		SET delivered_to = $2, attempts = $3, last_error = $4, next_attempt_at = NOW() + make_interval(secs => $5)
+		WHERE id = $1
+	`, entry.id, entry.deliveredTo, attempts, lastError, retryDelay(attempts).Seconds())
+	return err
+}
+
+// retryDelay is the backoff after the given number of failed attempts
+func retryDelay(attempts int) time.Duration {
+	delay := outboxRetryBase
+	for i := 1; i < attempts && delay < outboxRetryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, outboxRetryMax)
+}
+
+// DeadLetters lists dead-lettered events, most recent first
+func (o *Outbox) DeadLetters(ctx context.Context, limit int) ([]DeadLetter, error) {
+	rows, err := o.db.Query(ctx, `
+		SELECT id, type, event, attempts, delivered_to, last_error, created_at, dead_at
+		FROM event_outbox WHERE dead_at IS NOT NULL
+		ORDER BY dead_at DESC
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (DeadLetter, error) {
+		var d DeadLetter
+		err := row.Scan(&d.ID, &d.Type, &d.Event, &d.Attempts, &d.DeliveredTo, &d.LastError, &d.CreatedAt, &d.DeadAt)
+		return d, err
+	})
+}
+
+// Retry puts a dead-lettered event back in the queue with its attempts reset. Sinks that
+// already have it don't get it again.
+func (o *Outbox) Retry(ctx context.Context, id string) error {
+	tag, err := o.db.Exec(ctx, `
+		UPDATE event_outbox SET dead_at = NULL, attempts = 0, next_attempt_at = NOW()
+		WHERE id = $1 AND dead_at IS NOT NULL
+	`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUnknownEvent
+	}
+	return nil
+}
+
+// Clean deletes delivered events after a week and dead letters after 30 days. It is run by
+// the scheduler.
+func (o *Outbox) Clean(ctx context.Context) error {
+	delivered, err := o.db.Exec(ctx, "DELETE FROM event_outbox WHERE delivered_at < $1", time.Now().Add(-deliveredRetention))
+	if err != nil {
+		return fmt.Errorf("failed to delete delivered events: %w", err)
+	}
+	dead, err := o.db.Exec(ctx, "DELETE FROM event_outbox WHERE dead_at < $1", time.Now().Add(-deadLetterRetention))
+	if err != nil {
+		return fmt.Errorf("failed to delete dead letters: %w", err)
+	}
+	logging.FromContext(ctx).Info("Outbox cleaned", "delivered", delivered.RowsAffected(), "dead_letters", dead.RowsAffected())
+	return nil
+}
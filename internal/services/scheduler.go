@@ -0,0 +1,386 @@
+// Package services holds long-running services that sit alongside the HTTP API, such as the
+// scheduler for recurring background work and feature flags.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yourusername/jobapply/internal/logging"
+	"github.com/yourusername/jobapply/internal/tracing"
+)
+
+// How a run was started
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+)
+
+// Run statuses
+const (
+	RunRunning   = "running"
+	RunSucceeded = "succeeded"
+	RunFailed    = "failed"
+)
+
+const (
+	// Run history older than this is deleted after each run of the task
+	runRetention = 30 * 24 * time.Hour
+	// How long to wait for a run's outcome to be stored once its context is cancelled
+	recordTimeout = 5 * time.Second
+)
+
+var (
+	ErrUnknownTask = errors.New("unknown task")
+	// ErrTaskRunning is returned when a task is triggered while a run of it is in progress
+	ErrTaskRunning = errors.New("task is already running")
+	ErrStopped     = errors.New("scheduler is stopped")
+)
+
+// Task is recurring background work. Run must return when ctx is cancelled, and must be
+// safe to cut short: an interrupted run is simply repeated next time.
+type Task struct {
+	Name     string
+	Interval time.Duration // Zero means the task only runs when triggered manually
+	Run      func(ctx context.Context) error
+}
+
+// TaskRun is one recorded run of a task
+type TaskRun struct {
+	ID          string     `json:"id"`
+	Task        string     `json:"task"`
+	Trigger     string     `json:"trigger"`
+	Status      string     `json:"status"`
+	Error       *string    `json:"error,omitempty"`
+	TriggeredBy *string    `json:"triggered_by,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
+// TaskStatus describes a registered task and its latest run
+type TaskStatus struct {
+	Name     string     `json:"name"`
+	Interval string     `json:"interval,omitempty"`
+	Enabled  bool       `json:"enabled"`
+	Running  bool       `json:"running"` // On this instance
+	LastRun  *TaskRun   `json:"last_run,omitempty"`
+	NextRun  *time.Time `json:"next_run,omitempty"`
+}
+
+type task struct {
+	Task
+	enabled bool
+	running atomic.Bool
+}
+
+// Scheduler runs registered tasks on their intervals and on demand, recording each run in
+// task_runs. When several instances share a database, a Postgres advisory lock makes sure
+// only one of them runs a given task at a time, and the recorded history spaces runs out
+// across all of them.
+type Scheduler struct {
+	db       *pgxpool.Pool
+	disabled map[string]bool
+	tasks    []*task
+
+	ctx    context.Context // Cancelled by Stop
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+// NewScheduler returns a scheduler that won't run the named tasks on a schedule. They can
+// still be triggered manually.
+func NewScheduler(db *pgxpool.Pool, disabled []string) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Scheduler{db: db, disabled: make(map[string]bool), ctx: ctx, cancel: cancel}
+	for _, name := range disabled {
+		s.disabled[name] = true
+	}
+	return s
+}
+
+// Register adds a task. Names must be unique; register every task before calling Start.
+func (s *Scheduler) Register(t Task) {
+	if s.task(t.Name) != nil {
+		panic(fmt.Sprintf("services: task %q registered twice", t.Name))
+	}
+	s.tasks = append(s.tasks, &task{Task: t, enabled: t.Interval > 0 && !s.disabled[t.Name]})
+}
+
+// Start begins running enabled tasks on their intervals
+func (s *Scheduler) Start() {
+	for _, t := range s.tasks {
+		if t.enabled {
+			s.spawn(func() { s.loop(t) })
+		}
+	}
+}
+
+// Stop cancels running tasks and waits for them to record how they ended, until ctx ends
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+	s.cancel()
+
+	finished := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Trigger starts a run of the named task in the background, whether or not it is enabled
+func (s *Scheduler) Trigger(name, userID string) error {
+	t := s.task(name)
+	if t == nil {
+		return ErrUnknownTask
+	}
+	if !t.running.CompareAndSwap(false, true) {
+		return ErrTaskRunning
+	}
+	started := s.spawn(func() {
+		defer t.running.Store(false)
+		s.execute(t, TriggerManual, &userID)
+	})
+	if !started {
+		t.running.Store(false)
+		return ErrStopped
+	}
+	return nil
+}
+
+// Tasks lists registered tasks in registration order, with their latest runs
+func (s *Scheduler) Tasks(ctx context.Context) ([]TaskStatus, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT DISTINCT ON (task) `+runColumns+`
+		FROM task_runs
+		ORDER BY task, started_at DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	last, err := scanRuns(rows)
+	if err != nil {
+		return nil, err
+	}
+	lastByTask := make(map[string]*TaskRun, len(last))
+	for i := range last {
+		lastByTask[last[i].Task] = &last[i]
+	}
+
+	statuses := make([]TaskStatus, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		status := TaskStatus{
+			Name:    t.Name,
+			Enabled: t.enabled,
+			Running: t.running.Load(),
+			LastRun: lastByTask[t.Name],
+		}
+		if t.Interval > 0 {
+			status.Interval = t.Interval.String()
+		}
+		if t.enabled {
+			next := time.Now()
+			if status.LastRun != nil && status.LastRun.StartedAt.Add(t.Interval).After(next) {
+				next = status.LastRun.StartedAt.Add(t.Interval)
+			}
+			status.NextRun = &next
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Runs lists the named task's most recent runs, newest first
+func (s *Scheduler) Runs(ctx context.Context, name string, limit int) ([]TaskRun, error) {
+	if s.task(name) == nil {
+		return nil, ErrUnknownTask
+	}
+	rows, err := s.db.Query(ctx, `
+		SELECT `+runColumns+`
+		FROM task_runs
+		WHERE task = $1
+		ORDER BY started_at DESC
+		LIMIT $2
+	`, name, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanRuns(rows)
+}
+
+const runColumns = "id, task, trigger, status, error, triggered_by, started_at, finished_at"
+
+func scanRuns(rows pgx.Rows) ([]TaskRun, error) {
+	defer rows.Close()
+	runs := []TaskRun{}
+	for rows.Next() {
+		var run TaskRun
+		if err := rows.Scan(&run.ID, &run.Task, &run.Trigger, &run.Status, &run.Error, &run.TriggeredBy,
+			&run.StartedAt, &run.FinishedAt); err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+func (s *Scheduler) task(name string) *task {
+	for _, t := range s.tasks {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// spawn runs fn in the background unless the scheduler has stopped
+func (s *Scheduler) spawn(fn func()) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return false
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		fn()
+	}()
+	return true
+}
+
+// loop runs t whenever it is due until the scheduler stops. A task is due an interval after
+// its last recorded run on any instance, so restarts don't rerun everything at once.
+func (s *Scheduler) loop(t *task) {
+	var lastAttempt time.Time
+	for {
+		due := s.lastStarted(t).Add(t.Interval)
+		// Don't retry sooner than the interval if the run couldn't be recorded
+		if next := lastAttempt.Add(t.Interval); next.After(due) {
+			due = next
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-time.After(time.Until(due)):
+		}
+
+		lastAttempt = time.Now()
+		if t.running.CompareAndSwap(false, true) {
+			s.execute(t, TriggerSchedule, nil)
+			t.running.Store(false)
+		}
+	}
+}
+
+// lastStarted returns when t last started, or the zero time if it never has or that is unknown
+func (s *Scheduler) lastStarted(t *task) time.Time {
+	var started *time.Time
+	if err := s.db.QueryRow(s.ctx, "SELECT MAX(started_at) FROM task_runs WHERE task = $1", t.Name).Scan(&started); err != nil || started == nil {
+		return time.Time{}
+	}
+	return *started
+}
+
+// execute runs t once and records the outcome, unless another instance is already running it
+func (s *Scheduler) execute(t *task, trigger string, userID *string) {
+	ctx := s.ctx
+	logger := logging.FromContext(ctx).With("task", t.Name, "trigger", trigger)
+
+	// The lock belongs to the session, so the connection is held for the whole run
+	conn, err := s.db.Acquire(ctx)
+	if err != nil {
+		logger.Error("Failed to start task", "error", err)
+		return
+	}
+	defer conn.Release()
+	lockKey := "task:" + t.Name
+	var locked bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock(hashtext($1))", lockKey).Scan(&locked); err != nil {
+		logger.Error("Failed to start task", "error", err)
+		return
+	}
+	if !locked {
+		logger.Info("Task is running on another instance; skipped")
+		return
+	}
+
+	recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), recordTimeout)
+	defer cancel()
+	defer func() {
+		if _, err := conn.Exec(recordCtx, "SELECT pg_advisory_unlock(hashtext($1))", lockKey); err != nil {
+			// Don't return a connection still holding the lock to the pool
+			conn.Conn().Close(recordCtx)
+		}
+	}()
+
+	// Holding the lock means no other run is live, so any left 'running' was interrupted
+	if _, err := s.db.Exec(ctx, `
+		UPDATE task_runs SET status = 'failed', error = 'interrupted', finished_at = NOW()
+		WHERE task = $1 AND status = 'running'
+	`, t.Name); err != nil {
+		logger.Warn("Failed to close interrupted task runs", "error", err)
+	}
+	var runID string
+	if err := s.db.QueryRow(ctx, `
+		INSERT INTO task_runs (task, trigger, triggered_by) VALUES ($1, $2, $3) RETURNING id
+	`, t.Name, trigger, userID).Scan(&runID); err != nil {
+		logger.Error("Failed to record task run", "error", err)
+		return
+	}
+
+	logger.Info("Task started")
+	started := time.Now()
+	runErr := s.runTask(ctx, t)
+
+	status := RunSucceeded
+	var errMsg *string
+	if runErr != nil {
+		status = RunFailed
+		msg := runErr.Error()
+		errMsg = &msg
+		logger.Error("Task failed", "duration", time.Since(started), "error", runErr)
+	} else {
+		logger.Info("Task finished", "duration", time.Since(started))
+	}
+
+	// Recorded even if shutdown cancelled the run
+	if _, err := s.db.Exec(recordCtx, `
+		UPDATE task_runs SET status = $2, error = $3, finished_at = NOW() WHERE id = $1
+	`, runID, status, errMsg); err != nil {
+		logger.Error("Failed to record task result", "error", err)
+	}
+	if _, err := s.db.Exec(recordCtx, "DELETE FROM task_runs WHERE task = $1 AND started_at < $2",
+		t.Name, time.Now().Add(-runRetention)); err != nil {
+		logger.Warn("Failed to prune task history", "error", err)
+	}
+}
+
+// runTask calls t.Run in a span, turning a panic into a failed run
+func (s *Scheduler) runTask(ctx context.Context, t *task) (err error) {
+	ctx, span := tracing.Start(ctx, "task."+t.Name, tracing.KindInternal)
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+		span.RecordError(err)
+		span.End()
+	}()
+	return t.Run(ctx)
+}
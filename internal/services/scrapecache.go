@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yourusername/jobapply/internal/store"
+)
+
+// CacheTTL controls how long a source's scraped jobs are reused
+type CacheTTL struct {
+	// Fresh is how long a search is answered from the cache instead of scraping again
+	Fresh time.Duration
+	// Expire is how long a job not found again by any scrape stays listed; archived jobs stay
+	// regardless
+	Expire time.Duration
+}
+
+// DefaultCacheTTL applies to sources without their own TTL
+var DefaultCacheTTL = CacheTTL{Fresh: 12 * time.Hour, Expire: 24 * time.Hour}
+
+// ScrapeCache is the cache of scraped jobs in the jobs table. Jobs are shared between users
+// and keyed by source and search, so repeated searches don't use up a source's API quota.
+type ScrapeCache struct {
+	db       *pgxpool.Pool
+	defaults CacheTTL
+	sources  map[string]CacheTTL
+}
+
+// NewScrapeCache uses defaults for any source not in sources
+func NewScrapeCache(db *pgxpool.Pool, defaults CacheTTL, sources map[string]CacheTTL) *ScrapeCache {
+	return &ScrapeCache{db: db, defaults: defaults, sources: sources}
+}
+
+// TTL returns the source's TTLs
+func (c *ScrapeCache) TTL(source string) CacheTTL {
+	if ttl, ok := c.sources[source]; ok {
+		return ttl
+	}
+	return c.defaults
+}
+
+// SearchKey is the cache key for a search, stored as jobs.search_params_hash
+func SearchKey(keywords, location string) string {
+	hash := sha256.Sum256([]byte(keywords + "|" + location))
+	return fmt.Sprintf("%x", hash)
+}
+
+// Fresh counts the source's jobs cached for the search within its Fresh TTL; 0 means the
+// search must be scraped
+func (c *ScrapeCache) Fresh(ctx context.Context, source, key string) (int, error) {
+	var count int
+	err := c.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM jobs
+		WHERE site = $1 AND search_params_hash = $2 AND deleted_at IS NULL
+		AND cached_at > NOW() - make_interval(secs => $3)
+	`, source, key, c.TTL(source).Fresh.Seconds()).Scan(&count)
+	return count, err
+}
+
+// Invalidate detaches the source's jobs from the search, so the next search scrapes again.
+// The jobs stay listed for the users who have them until they expire.
+func (c *ScrapeCache) Invalidate(ctx context.Context, source, key string) (int64, error) {
+	result, err := c.db.Exec(ctx, `
+		UPDATE jobs SET search_params_hash = NULL
+		WHERE site = $1 AND search_params_hash = $2 AND deleted_at IS NULL
+	`, source, key)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+// Expire soft-deletes jobs not refreshed within their source's Expire TTL, keeping archived
+// jobs. A later scrape finding them again revives them.
+func (c *ScrapeCache) Expire(ctx context.Context) (int64, error) {
+	sources := make([]string, 0, len(c.sources))
+	expiries := make([]float64, 0, len(c.sources))
+	for source, ttl := range c.sources {
+		sources = append(sources, source)
+		expiries = append(expiries, ttl.Expire.Seconds())
+	}
+	result, err := c.db.Exec(ctx, `
+		UPDATE jobs SET deleted_at = NOW()
+		WHERE deleted_at IS NULL
+		AND cached_at < NOW() - make_interval(secs => COALESCE(
+			(SELECT t.expire FROM unnest($1::text[], $2::float8[]) AS t(source, expire) WHERE t.source = jobs.site), $3))
+		AND NOT `+store.ArchivedJobSQL+`
+	`, sources, expiries, c.defaults.Expire.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,88 @@
+// Package shutdown coordinates long-running work - scrapes and the background jobs they start -
+// with server shutdown, so a SIGTERM doesn't cut them off halfway.
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrShuttingDown is returned by Begin once Drain has started
+var ErrShuttingDown = errors.New("server is shutting down")
+
+// cancelGrace is how long Drain waits after cancelling work for it to notice and clean up,
+// e.g. to record an interrupted application as paused
+const cancelGrace = 5 * time.Second
+
+// Coordinator tracks in-flight work. Once Drain starts no new work is accepted; in-flight
+// work runs to completion, or is cancelled when the drain deadline passes.
+type Coordinator struct {
+	ctx    context.Context // Cancelled when work must stop
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	draining bool
+	inFlight sync.WaitGroup
+}
+
+func New() *Coordinator {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Coordinator{ctx: ctx, cancel: cancel}
+}
+
+// Begin registers a unit of work, such as a scrape running inside a request. done must be
+// called when it finishes.
+func (c *Coordinator) Begin() (done func(), err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.draining {
+		return nil, ErrShuttingDown
+	}
+	c.inFlight.Add(1)
+	return sync.OnceFunc(c.inFlight.Done), nil
+}
+
+// Go runs fn in the background as tracked work. Its context is cancelled if shutdown can't
+// wait for it any longer. Work submitted after Drain has started is dropped, so fn must be
+// something that is safe to skip or is retried later.
+func (c *Coordinator) Go(fn func(ctx context.Context)) {
+	done, err := c.Begin()
+	if err != nil {
+		return
+	}
+	go func() {
+		defer done()
+		fn(c.ctx)
+	}()
+}
+
+// Drain stops accepting work and waits for in-flight work to finish. If ctx ends first, the
+// remaining work is cancelled and given a short grace period to wind down, and ctx's error
+// is returned.
+func (c *Coordinator) Drain(ctx context.Context) error {
+	c.mu.Lock()
+	c.draining = true
+	c.mu.Unlock()
+	defer c.cancel()
+
+	finished := make(chan struct{})
+	go func() {
+		c.inFlight.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+	}
+
+	c.cancel()
+	select {
+	case <-finished:
+	case <-time.After(cancelGrace):
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Local stores objects as files in a directory. Only suitable for single-instance deployments.
+type Local struct {
+	Dir string
+}
+
+func NewLocal(dir string) *Local {
+	return &Local{Dir: dir}
+}
+
+// Path returns the file for key. filepath.Base prevents keys from escaping Dir.
+func (l *Local) Path(key string) string {
+	return filepath.Join(l.Dir, filepath.Base(key))
+}
+
+// Ping checks the directory exists, or can be created, and is writable
+func (l *Local) Ping(ctx context.Context) error {
+	if err := os.MkdirAll(l.Dir, 0755); err != nil {
+		return fmt.Errorf("failed to create upload dir: %w", err)
+	}
+	f, err := os.CreateTemp(l.Dir, ".ping-*")
+	if err != nil {
+		return fmt.Errorf("upload dir is not writable: %w", err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+func (l *Local) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if err := os.MkdirAll(l.Dir, 0755); err != nil {
+		return fmt.Errorf("failed to create upload dir: %w", err)
+	}
+
+	path := l.Path(key)
+	dst, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(dst, r); err != nil {
+		dst.Close()
+		os.Remove(path)
+		return err
+	}
+	return dst.Close()
+}
+
+func (l *Local) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	f, err := os.Open(l.Path(key))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+// List walks the directory. Hidden files, such as Ping's probes, are skipped.
+func (l *Local) List(ctx context.Context, fn func(Object) error) error {
+	entries, err := os.ReadDir(l.Dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		info, err := e.Info()
+		if os.IsNotExist(err) {
+			continue // Deleted since the directory was read
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(Object{Key: e.Name(), Size: info.Size(), Modified: info.ModTime()}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *Local) Delete(ctx context.Context, key string) error {
+	err := os.Remove(l.Path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var ErrNotFound = errors.New("object not found")
+
+// Storage persists uploaded files by key. Keys are flat names such as "<uuid>.pdf".
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object; deleting a missing key is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// Pinger is implemented by backends that can check they are reachable without touching an object
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Object describes a stored object
+type Object struct {
+	Key      string
+	Size     int64
+	Modified time.Time
+}
+
+// Lister is implemented by backends that can enumerate their objects. fn is called once per
+// object; an error from it stops the listing and is returned.
+type Lister interface {
+	List(ctx context.Context, fn func(Object) error) error
+}
+
+// localPather is implemented by backends whose objects already live on the local filesystem
+type localPather interface {
+	Path(key string) string
+}
+
+// Fetch makes the object available as a local file for tools that need a path (pdftotext,
+// tesseract). Remote objects are downloaded to a temp file; cleanup must always be called.
+func Fetch(ctx context.Context, s Storage, key string) (path string, cleanup func(), err error) {
+	if local, ok := s.(localPather); ok {
+		path = local.Path(key)
+		if _, err := os.Stat(path); err != nil {
+			if os.IsNotExist(err) {
+				return "", nil, ErrNotFound
+			}
+			return "", nil, err
+		}
+		return path, func() {}, nil
+	}
+
+	rc, err := s.Open(ctx, key)
+	if err != nil {
+		return "", nil, err
+	}
+	defer rc.Close()
+
+	tmp, err := os.CreateTemp("", "upload-*-"+filepath.Base(key))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	cleanup = func() { os.Remove(tmp.Name()) }
+
+	if _, err := io.Copy(tmp, rc); err != nil {
+		tmp.Close()
+		cleanup()
+		return "", nil, fmt.Errorf("failed to download %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	return tmp.Name(), cleanup, nil
+}
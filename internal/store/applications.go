@@ -0,0 +1,361 @@
+package store
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/yourusername/jobapply/internal/audit"
+	"github.com/yourusername/jobapply/internal/events"
+	"github.com/yourusername/jobapply/internal/models"
+	"github.com/yourusername/jobapply/internal/validation"
+)
+
+type pgApplicationStore struct {
+	db     dbtx
+	commit *commitHooks // Set inside InTx
+}
+
+// afterCommit runs fn once the caller's writes are committed: now, or inside InTx once the
+// outermost transaction commits
+func (s *pgApplicationStore) afterCommit(fn func()) {
+	if s.commit == nil {
+		fn()
+		return
+	}
+	s.commit.fns = append(s.commit.fns, fn)
+}
+
+func (s *pgApplicationStore) Applications(ctx context.Context, userID string, tags []string) ([]models.Application, error) {
+	applications := []models.Application{}
+	err := s.EachApplication(ctx, userID, tags, func(app *models.Application) error {
+		applications = append(applications, *app)
+		return nil
+	})
+	return applications, err
+}
+
+func (s *pgApplicationStore) EachApplication(ctx context.Context, userID string, tags []string, fn func(*models.Application) error) error {
+	args := []any{userID}
+	where := "a.user_id = $1 AND a.deleted_at IS NULL"
+	for _, tag := range tags {
+		where += ` AND EXISTS (SELECT 1 FROM application_tags apt JOIN tags t ON t.id = apt.tag_id
+			WHERE apt.application_id = a.id AND t.user_id = $1 AND LOWER(t.name) = LOWER(` + placeholder(&args, tag) + `))`
+	}
+	return s.eachApplication(ctx, where, "a.applied_at DESC", args, fn)
+}
+
+func (s *pgApplicationStore) DeletedApplications(ctx context.Context, userID string) ([]models.Application, error) {
+	applications := []models.Application{}
+	err := s.eachApplication(ctx, "a.user_id = $1 AND a.deleted_at IS NOT NULL", "a.deleted_at DESC", []any{userID},
+		func(app *models.Application) error {
+			applications = append(applications, *app)
+			return nil
+		})
+	return applications, err
+}
+
+func (s *pgApplicationStore) eachApplication(ctx context.Context, where, orderBy string, args []any, fn func(*models.Application) error) error {
+	rows, err := s.db.Query(ctx, `
+		SELECT a.id, a.status, a.applied_at, a.filled_fields, a.omitted_fields, a.persona_id, a.job_id, j.title, j.company, j.url,
+			ARRAY(SELECT t.name FROM application_tags apt JOIN tags t ON t.id = apt.tag_id
+				WHERE apt.application_id = a.id ORDER BY LOWER(t.name)),
+			COALESCE(a.source, ''), COALESCE(a.external_status, ''), a.deleted_at
+		FROM applications a
+		JOIN jobs j ON a.job_id = j.id
+		WHERE `+where+`
+		ORDER BY `+orderBy, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var app models.Application
+		var filledFieldsJSON []byte
+		if err := rows.Scan(&app.ID, &app.Status, &app.AppliedAt, &filledFieldsJSON, &app.FieldsOmitted, &app.ProfileID,
+			&app.JobID, &app.JobTitle, &app.Company, &app.JobURL, &app.Tags, &app.Source, &app.ExternalStatus, &app.DeletedAt); err != nil {
+			return err
+		}
+
+		// filled_fields is stored as {"fields": [...]}
+		if len(filledFieldsJSON) > 0 {
+			var fieldsData map[string][]string
+			if err := json.Unmarshal(filledFieldsJSON, &fieldsData); err == nil {
+				app.FieldsFilled = fieldsData["fields"]
+			}
+		}
+
+		if err := fn(&app); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (s *pgApplicationStore) DeleteApplication(ctx context.Context, userID, applicationID string) error {
+	return s.setDeleted(ctx, userID, applicationID, true)
+}
+
+func (s *pgApplicationStore) RestoreApplication(ctx context.Context, userID, applicationID string) error {
+	return s.setDeleted(ctx, userID, applicationID, false)
+}
+
+func (s *pgApplicationStore) setDeleted(ctx context.Context, userID, applicationID string, deleted bool) error {
+	result, err := s.db.Exec(ctx, `
+		UPDATE applications SET deleted_at = CASE WHEN $3 THEN NOW() END
+		WHERE id = $1 AND user_id = $2 AND (deleted_at IS NULL) = $3
+	`, applicationID, userID, deleted)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *pgApplicationStore) CreateApplication(ctx context.Context, userID, jobID string, personaID *string, status models.ApplicationStatus) (string, error) {
+	if !status.Initial() {
+		return "", fmt.Errorf("%w: applications can't start %s", ErrInvalidTransition, status)
+	}
+	created := events.ApplicationCreatedData{ApplicationPayload: events.ApplicationPayload{JobID: jobID}, Status: string(status)}
+	err := pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			INSERT INTO applications (user_id, job_id, persona_id, status)
+			SELECT $1, id, $3, $4 FROM jobs
+			WHERE id = $2 AND deleted_at IS NULL AND EXISTS (SELECT 1 FROM user_jobs WHERE job_id = jobs.id AND user_id = $1)
+			AND ($3::uuid IS NULL OR EXISTS (SELECT 1 FROM profile_personas WHERE id = $3 AND user_id = $1 AND deleted_at IS NULL))
+			RETURNING id, (SELECT title FROM jobs WHERE id = $2), (SELECT company FROM jobs WHERE id = $2)
+		`, userID, jobID, personaID, status).Scan(&created.ApplicationID, &created.Title, &created.Company)
+		if err != nil {
+			return notFound(err)
+		}
+		if err := recordEvent(ctx, tx, created.ApplicationID, "", status); err != nil {
+			return err
+		}
+		return enqueueEvent(ctx, tx, events.New(events.ApplicationCreated, userID, created))
+	})
+	if err != nil {
+		return "", err
+	}
+	return created.ApplicationID, nil
+}
+
+func (s *pgApplicationStore) ImportApplications(ctx context.Context, userID string, apps []ImportedApplication) (*ImportResult, error) {
+	result := &ImportResult{IDs: []string{}, Duplicates: []int{}}
+	err := pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		for i, app := range apps {
+			if !app.Status.Valid() {
+				return &validation.FieldError{Field: "status", Message: fmt.Sprintf("unknown status %q", app.Status)}
+			}
+			// A live shared job under the URL is reused as it is. Otherwise the job is the user's
+			// own, holding the title and company they gave; one they already have is reused.
+			var jobID string
+			err := tx.QueryRow(ctx,
+				"SELECT id FROM jobs WHERE url = $1 AND owner_id IS NULL AND deleted_at IS NULL", app.URL).Scan(&jobID)
+			if errors.Is(err, pgx.ErrNoRows) {
+				err = tx.QueryRow(ctx, `
+					INSERT INTO jobs (site, owner_id, title, company, url, cached_at) VALUES ('import', $1, $2, $3, $4, NOW())
+					ON CONFLICT (owner_id, url) WHERE owner_id IS NOT NULL DO UPDATE SET deleted_at = NULL
+					RETURNING id
+				`, userID, app.Title, app.Company, app.URL).Scan(&jobID)
+			}
+			if err != nil {
+				return err
+			}
+			if _, err := tx.Exec(ctx, `
+				INSERT INTO user_jobs (user_id, job_id) VALUES ($1, $2) ON CONFLICT DO NOTHING
+			`, userID, jobID); err != nil {
+				return err
+			}
+
+			var id string
+			err = tx.QueryRow(ctx, `
+				INSERT INTO applications (user_id, job_id, status, applied_at, created_at, source, external_status)
+				SELECT $1, $2, $3, $4, $4, COALESCE(NULLIF($6, ''), 'import'), NULLIF($5, '')
+				WHERE NOT EXISTS (SELECT 1 FROM applications WHERE user_id = $1 AND job_id = $2 AND deleted_at IS NULL)
+				RETURNING id
+			`, userID, jobID, app.Status, app.AppliedAt, app.ExternalStatus, app.Source).Scan(&id)
+			if errors.Is(err, pgx.ErrNoRows) {
+				result.Duplicates = append(result.Duplicates, i)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			// The history starts when the application was made, not when it was imported
+			if _, err := tx.Exec(ctx, `
+				INSERT INTO application_events (application_id, to_status, created_at) VALUES ($1, $2, $3)
+			`, id, app.Status, app.AppliedAt); err != nil {
+				return err
+			}
+			if err := enqueueEvent(ctx, tx, events.New(events.ApplicationCreated, userID, events.ApplicationCreatedData{
+				ApplicationPayload: events.ApplicationPayload{ApplicationID: id, JobID: jobID, Title: app.Title, Company: app.Company},
+				Status:             string(app.Status),
+				Source:             cmp.Or(app.Source, "import"),
+			})); err != nil {
+				return err
+			}
+			result.IDs = append(result.IDs, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *pgApplicationStore) ApplicationQuestions(ctx context.Context, userID, applicationID string) ([]models.QuestionPage, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT q.page_index, q.asked_at, q.question_key, q.question, q.answer, q.answered_at
+		FROM applications a
+		LEFT JOIN application_questions q ON q.application_id = a.id
+		WHERE a.id = $1 AND a.user_id = $2 AND a.deleted_at IS NULL
+		ORDER BY q.page_index, q.question_key
+	`, applicationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pages := []models.QuestionPage{}
+	found := false
+	for rows.Next() {
+		found = true
+		var page *int
+		var askedAt *time.Time
+		var key, text *string
+		var question models.ApplicationQuestion
+		if err := rows.Scan(&page, &askedAt, &key, &text, &question.Answer, &question.AnsweredAt); err != nil {
+			return nil, err
+		}
+		if page == nil {
+			// The application has no questions
+			continue
+		}
+		question.Key, question.Question = *key, *text
+		if len(pages) == 0 || pages[len(pages)-1].Page != *page {
+			pages = append(pages, models.QuestionPage{Page: *page, AskedAt: *askedAt})
+		}
+		last := &pages[len(pages)-1]
+		last.Questions = append(last.Questions, question)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrNotFound
+	}
+	return pages, nil
+}
+
+func (s *pgApplicationStore) UpdateStatus(ctx context.Context, userID, applicationID string, update ApplicationUpdate) error {
+	var filledFields []byte
+	if update.FieldsFilled != nil {
+		filledFields = toJSON(map[string][]string{"fields": update.FieldsFilled})
+	}
+	var current models.ApplicationStatus
+	err := pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			SELECT status FROM applications WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL FOR UPDATE
+		`, applicationID, userID).Scan(&current)
+		if err != nil {
+			return notFound(err)
+		}
+		if current.Final() {
+			return fmt.Errorf("%w: %s is final", ErrInvalidTransition, current)
+		}
+		if current != update.Status && !current.CanTransition(update.Status) {
+			return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, current, update.Status)
+		}
+
+		if _, err := tx.Exec(ctx, `
+			UPDATE applications SET
+				status = $1,
+				filled_fields = COALESCE($2, filled_fields),
+				omitted_fields = COALESCE($3, omitted_fields),
+				error_log = CASE WHEN $4::text IS NULL THEN error_log ELSE NULLIF($4, '') END,
+				applied_at = CASE WHEN $1 = 'submitted' THEN NOW() ELSE applied_at END
+			WHERE id = $5
+		`, update.Status, filledFields, update.FieldsOmitted, update.ErrorLog, applicationID); err != nil {
+			return err
+		}
+		if current == update.Status {
+			return nil
+		}
+		if err := recordEvent(ctx, tx, applicationID, current, update.Status); err != nil {
+			return err
+		}
+		changed := events.ApplicationStatusChangedData{
+			ApplicationPayload: events.ApplicationPayload{ApplicationID: applicationID},
+			From:               string(current),
+			To:                 string(update.Status),
+		}
+		err = tx.QueryRow(ctx, `
+			SELECT j.id, j.title, j.company FROM applications a JOIN jobs j ON j.id = a.job_id WHERE a.id = $1
+		`, applicationID).Scan(&changed.JobID, &changed.Title, &changed.Company)
+		if err != nil {
+			return err
+		}
+		return enqueueEvent(ctx, tx, events.New(events.ApplicationStatusChanged, userID, changed))
+	})
+	if err == nil && current != update.Status {
+		s.afterCommit(func() {
+			audit.Record(ctx, "application", applicationID,
+				map[string]any{"status": current}, map[string]any{"status": update.Status})
+		})
+	}
+	return err
+}
+
+// recordEvent logs a status change in application_events
+func recordEvent(ctx context.Context, tx pgx.Tx, applicationID string, from, to models.ApplicationStatus) error {
+	var fromStatus *string
+	if from != "" {
+		fromStatus = (*string)(&from)
+	}
+	_, err := tx.Exec(ctx, `
+		INSERT INTO application_events (application_id, from_status, to_status) VALUES ($1, $2, $3)
+	`, applicationID, fromStatus, to)
+	return err
+}
+
+func (s *pgApplicationStore) ApplicationEvents(ctx context.Context, userID, applicationID string) ([]models.ApplicationEvent, error) {
+	var exists bool
+	err := s.db.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM applications WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL)
+	`, applicationID, userID).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	rows, err := s.db.Query(ctx, `
+		SELECT id, application_id, COALESCE(from_status, ''), to_status, created_at
+		FROM application_events WHERE application_id = $1
+		ORDER BY created_at, id
+	`, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.ApplicationEvent{}
+	for rows.Next() {
+		var event models.ApplicationEvent
+		if err := rows.Scan(&event.ID, &event.ApplicationID, &event.From, &event.To, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,274 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/jobapply/internal/models"
+)
+
+type pgJobStore struct {
+	db dbtx
+}
+
+func (s *pgJobStore) Job(ctx context.Context, userID, jobID string) (*models.JobDetail, error) {
+	var job models.JobDetail
+	var location, description, salaryText *string
+	err := s.db.QueryRow(ctx, `
+		SELECT id, site, title, company, company_id, location, url, posted_date, salary_min, salary_max, salary_text, scraped_at, status, description,
+			COALESCE(agency, ''), `+JobTagsSQL("$2")+`
+		FROM jobs
+		WHERE id = $1 AND deleted_at IS NULL AND EXISTS (SELECT 1 FROM user_jobs WHERE job_id = jobs.id AND user_id = $2)
+	`, jobID, userID).Scan(&job.ID, &job.Site, &job.Title, &job.Company, &job.CompanyID, &location, &job.URL,
+		&job.PostedDate, &job.SalaryMin, &job.SalaryMax, &salaryText, &job.ScrapedAt, &job.Status, &description, &job.Agency, &job.Tags)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	job.Location = deref(location)
+	job.SalaryText = deref(salaryText)
+	job.Description = deref(description)
+	return &job, nil
+}
+
+func (s *pgJobStore) JobsByID(ctx context.Context, userID string, jobIDs []string) ([]models.JobListing, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT id, site, title, company, company_id, location, url, posted_date, salary_min, salary_max, salary_text, scraped_at, status,
+			`+JobTagsSQL("$2")+`
+		FROM jobs
+		WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
+		AND (EXISTS (SELECT 1 FROM user_jobs WHERE job_id = jobs.id AND user_id = $2)
+			OR EXISTS (SELECT 1 FROM applications WHERE job_id = jobs.id AND user_id = $2 AND deleted_at IS NULL))
+	`, jobIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []models.JobListing{}
+	for rows.Next() {
+		var job models.JobListing
+		var location, salaryText *string
+		if err := rows.Scan(&job.ID, &job.Site, &job.Title, &job.Company, &job.CompanyID, &location, &job.URL,
+			&job.PostedDate, &job.SalaryMin, &job.SalaryMax, &salaryText, &job.ScrapedAt, &job.Status, &job.Tags); err != nil {
+			return nil, err
+		}
+		job.Location = deref(location)
+		job.SalaryText = deref(salaryText)
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+func (s *pgJobStore) JobText(ctx context.Context, jobID string) (string, string, error) {
+	var title string
+	var description *string
+	err := s.db.QueryRow(ctx, "SELECT title, description FROM jobs WHERE id = $1 AND deleted_at IS NULL", jobID).Scan(&title, &description)
+	if err != nil {
+		return "", "", notFound(err)
+	}
+	return title, deref(description), nil
+}
+
+func (s *pgJobStore) UpsertJobs(ctx context.Context, searchHash string, jobs []ScrapedJob) (*UpsertResult, error) {
+	result := &UpsertResult{IDs: []string{}}
+
+	// ON CONFLICT can't touch the same row twice in one statement, so repeated URLs are dropped
+	var (
+		sites, titles, companies, locations, urls []string
+		descriptions, currencies, periods, texts  []*string
+		companyIDs, agencies                      []*string
+		posted                                    []*time.Time
+		mins, maxes                               []*int
+	)
+	seen := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		if job.Title == "" || job.URL == "" || seen[job.URL] {
+			result.Skipped++
+			continue
+		}
+		seen[job.URL] = true
+		sites = append(sites, job.Site)
+		titles = append(titles, job.Title)
+		companies = append(companies, job.Company)
+		locations = append(locations, job.Location)
+		urls = append(urls, job.URL)
+		descriptions = append(descriptions, job.Description)
+		posted = append(posted, job.PostedAt)
+		mins = append(mins, job.Pay.Min)
+		maxes = append(maxes, job.Pay.Max)
+		currencies = append(currencies, job.Pay.Currency)
+		periods = append(periods, job.Pay.Period)
+		texts = append(texts, job.Pay.Text)
+		companyIDs = append(companyIDs, job.CompanyID)
+		agencies = append(agencies, job.Agency)
+	}
+	if len(urls) == 0 {
+		return result, nil
+	}
+
+	// xmax is zero only on rows this statement inserted. Scrapes only write shared jobs, never
+	// the copies users captured or imported.
+	rows, err := s.db.Query(ctx, `
+		INSERT INTO jobs (site, title, company, location, url, description, posted_date, search_params_hash, cached_at,
+			salary_min, salary_max, salary_currency, salary_period, salary_text, company_id, agency)
+		SELECT site, title, company, location, url, description, posted_date, $8, NOW(),
+			salary_min, salary_max, salary_currency, salary_period, salary_text, company_id::uuid, agency
+		FROM unnest($1::text[], $2::text[], $3::text[], $4::text[], $5::text[], $6::text[], $7::timestamptz[],
+			$9::int[], $10::int[], $11::text[], $12::text[], $13::text[], $14::text[], $15::text[])
+			AS t(site, title, company, location, url, description, posted_date,
+				salary_min, salary_max, salary_currency, salary_period, salary_text, company_id, agency)
+		ON CONFLICT (url) WHERE owner_id IS NULL DO UPDATE SET
+			company_id = COALESCE(EXCLUDED.company_id, jobs.company_id),
+			agency = COALESCE(EXCLUDED.agency, jobs.agency),
+			description = COALESCE(EXCLUDED.description, jobs.description),
+			posted_date = COALESCE(EXCLUDED.posted_date, jobs.posted_date),
+			salary_min = COALESCE(EXCLUDED.salary_min, jobs.salary_min),
+			salary_max = COALESCE(EXCLUDED.salary_max, jobs.salary_max),
+			salary_currency = COALESCE(EXCLUDED.salary_currency, jobs.salary_currency),
+			salary_period = COALESCE(EXCLUDED.salary_period, jobs.salary_period),
+			salary_text = COALESCE(EXCLUDED.salary_text, jobs.salary_text),
+			search_params_hash = EXCLUDED.search_params_hash,
+			cached_at = NOW(),
+			deleted_at = NULL
+		RETURNING id, xmax = 0
+	`, sites, titles, companies, locations, urls, descriptions, posted, searchHash,
+		mins, maxes, currencies, periods, texts, companyIDs, agencies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var inserted bool
+		if err := rows.Scan(&id, &inserted); err != nil {
+			return nil, err
+		}
+		result.IDs = append(result.IDs, id)
+		if inserted {
+			result.Inserted++
+		} else {
+			result.Updated++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *pgJobStore) CacheDescription(ctx context.Context, jobID, description string, pay Salary) error {
+	_, err := s.db.Exec(ctx, `
+		UPDATE jobs SET description = $1,
+			salary_min = COALESCE(salary_min, $3), salary_max = COALESCE(salary_max, $4),
+			salary_currency = COALESCE(salary_currency, $5), salary_period = COALESCE(salary_period, $6),
+			salary_text = COALESCE(salary_text, $7)
+		WHERE id = $2
+	`, description, jobID, pay.Min, pay.Max, pay.Currency, pay.Period, pay.Text)
+	return err
+}
+
+// Dismissals are keyed by URL so they survive the job being re-scraped under a new ID
+func (s *pgJobStore) Dismiss(ctx context.Context, userID, jobID string) error {
+	result, err := s.db.Exec(ctx, `
+		INSERT INTO job_dismissals (user_id, job_url)
+		SELECT $1, url FROM jobs WHERE id = $2 AND deleted_at IS NULL
+		ON CONFLICT DO NOTHING
+	`, userID, jobID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		// Either the job doesn't exist or it was already dismissed
+		var exists bool
+		if err := s.db.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM jobs WHERE id = $1 AND deleted_at IS NULL)", jobID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return ErrNotFound
+		}
+	}
+	return nil
+}
+
+func (s *pgJobStore) Undismiss(ctx context.Context, userID, jobID string) error {
+	_, err := s.db.Exec(ctx, `
+		DELETE FROM job_dismissals
+		WHERE user_id = $1 AND job_url = (SELECT url FROM jobs WHERE id = $2)
+	`, userID, jobID)
+	return err
+}
+
+func (s *pgJobStore) SaveJob(ctx context.Context, userID, jobID, notes string, priority int) error {
+	result, err := s.db.Exec(ctx, `
+		INSERT INTO saved_jobs (user_id, job_id, notes, priority)
+		SELECT $1, id, $3, $4 FROM jobs WHERE id = $2 AND deleted_at IS NULL
+		ON CONFLICT (user_id, job_id) DO UPDATE SET
+			notes = EXCLUDED.notes,
+			priority = EXCLUDED.priority,
+			updated_at = NOW()
+	`, userID, jobID, notes, priority)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *pgJobStore) UnsaveJob(ctx context.Context, userID, jobID string) error {
+	result, err := s.db.Exec(ctx, "DELETE FROM saved_jobs WHERE user_id = $1 AND job_id = $2", userID, jobID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *pgJobStore) SavedJobs(ctx context.Context, userID string, tags []string) ([]models.SavedJob, error) {
+	args := []any{userID}
+	where := "s.user_id = $1"
+	for _, tag := range tags {
+		where += " AND " + JobTagFilterSQL("$1", placeholder(&args, tag))
+	}
+
+	rows, err := s.db.Query(ctx, `
+		SELECT jobs.id, jobs.site, jobs.title, jobs.company, jobs.company_id, jobs.location, jobs.url, jobs.posted_date,
+			jobs.salary_min, jobs.salary_max, jobs.salary_text, jobs.scraped_at, jobs.status, `+JobTagsSQL("$1")+`,
+			s.notes, s.priority, s.saved_at
+		FROM saved_jobs s
+		JOIN jobs ON jobs.id = s.job_id
+		WHERE `+where+`
+		ORDER BY s.priority DESC, s.saved_at DESC
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	saved := []models.SavedJob{}
+	for rows.Next() {
+		var job models.SavedJob
+		var location, notes, salaryText *string
+		if err := rows.Scan(&job.ID, &job.Site, &job.Title, &job.Company, &job.CompanyID, &location, &job.URL,
+			&job.PostedDate, &job.SalaryMin, &job.SalaryMax, &salaryText, &job.ScrapedAt, &job.Status, &job.Tags,
+			&notes, &job.Priority, &job.SavedAt); err != nil {
+			return nil, err
+		}
+		job.Location = deref(location)
+		job.SalaryText = deref(salaryText)
+		job.Notes = deref(notes)
+		saved = append(saved, job)
+	}
+	return saved, rows.Err()
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,101 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yourusername/jobapply/internal/models"
+)
+
+type pgNotificationStore struct {
+	db dbtx
+}
+
+func (s *pgNotificationStore) Notify(ctx context.Context, userID string, n NewNotification) (bool, error) {
+	tag, err := s.db.Exec(ctx, `
+		INSERT INTO notifications (user_id, kind, title, body, link, dedupe_key)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''))
+		ON CONFLICT (user_id, dedupe_key) DO NOTHING
+	`, userID, n.Kind, n.Title, n.Body, n.Link, n.DedupeKey)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (s *pgNotificationStore) Notifications(ctx context.Context, userID string, q NotificationQuery) ([]models.Notification, error) {
+	var notifications []models.Notification
+	err := pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			SELECT id, kind, title, body, COALESCE(link, ''), created_at, read_at
+			FROM notifications
+			WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL) AND ($3::timestamptz IS NULL OR created_at < $3)
+			ORDER BY created_at DESC
+			LIMIT $4
+		`, userID, q.UnreadOnly, q.Before, q.Limit)
+		if err != nil {
+			return err
+		}
+		notifications, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Notification, error) {
+			var n models.Notification
+			err := row.Scan(&n.ID, &n.Kind, &n.Title, &n.Body, &n.Link, &n.CreatedAt, &n.ReadAt)
+			return n, err
+		})
+		if err != nil || len(notifications) == 0 {
+			return err
+		}
+
+		ids := make([]string, len(notifications))
+		for i, n := range notifications {
+			ids[i] = n.ID
+		}
+		_, err = tx.Exec(ctx, `
+			UPDATE notifications SET seen_at = NOW() WHERE id = ANY($1::uuid[]) AND seen_at IS NULL
+		`, ids)
+		return err
+	})
+	return notifications, err
+}
+
+func (s *pgNotificationStore) NotificationCounts(ctx context.Context, userID string) (NotificationCounts, error) {
+	var c NotificationCounts
+	err := s.db.QueryRow(ctx, `
+		SELECT COUNT(*) FILTER (WHERE read_at IS NULL), COUNT(*) FILTER (WHERE seen_at IS NULL)
+		FROM notifications WHERE user_id = $1
+	`, userID).Scan(&c.Unread, &c.Unseen)
+	return c, err
+}
+
+func (s *pgNotificationStore) MarkRead(ctx context.Context, userID, notificationID string) error {
+	tag, err := s.db.Exec(ctx, `
+		UPDATE notifications SET read_at = COALESCE(read_at, NOW()), seen_at = COALESCE(seen_at, NOW())
+		WHERE id = $1 AND user_id = $2
+	`, notificationID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *pgNotificationStore) MarkAllRead(ctx context.Context, userID string) (int, error) {
+	tag, err := s.db.Exec(ctx, `
+		UPDATE notifications SET read_at = NOW(), seen_at = COALESCE(seen_at, NOW())
+		WHERE user_id = $1 AND read_at IS NULL
+	`, userID)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+func (s *pgNotificationStore) DeleteReadNotifications(ctx context.Context, before time.Time) (int, error) {
+	tag, err := s.db.Exec(ctx, "DELETE FROM notifications WHERE read_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
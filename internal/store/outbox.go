@@ -0,0 +1,33 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/yourusername/jobapply/internal/events"
+)
+
+type pgOutboxStore struct {
+	db dbtx
+}
+
+func (s *pgOutboxStore) Enqueue(ctx context.Context, e events.Event) error {
+	return enqueueEvent(ctx, s.db, e)
+}
+
+// enqueueEvent adds an event to the outbox through db, which should be the transaction making
+// the change the event reports
+func enqueueEvent(ctx context.Context, db dbtx, e events.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	var userID *string
+	if e.UserID != "" {
+		userID = &e.UserID
+	}
+	_, err = db.Exec(ctx, `
+		INSERT INTO event_outbox (id, type, user_id, event, created_at) VALUES ($1, $2, $3, $4, $5)
+	`, e.ID, e.Type, userID, data, e.Time)
+	return err
+}
@@ -0,0 +1,94 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// dbtx is what the Postgres stores query through: the pool, or a transaction inside InTx
+type dbtx interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// JobTagsSQL selects the names of the user's tags on a row of jobs as a text array. user is
+// the placeholder holding the user ID, e.g. "$1".
+func JobTagsSQL(user string) string {
+	return `ARRAY(SELECT t.name FROM job_tags jt JOIN tags t ON t.id = jt.tag_id
+		WHERE jt.job_id = jobs.id AND t.user_id = ` + user + ` ORDER BY LOWER(t.name))`
+}
+
+// JobTagFilterSQL is a predicate that a row of jobs carries the user's tag with the given name
+func JobTagFilterSQL(user, name string) string {
+	return `EXISTS (SELECT 1 FROM job_tags jt JOIN tags t ON t.id = jt.tag_id
+		WHERE jt.job_id = jobs.id AND t.user_id = ` + user + ` AND LOWER(t.name) = LOWER(` + name + `))`
+}
+
+// ArchivedJobSQL is true for a row of jobs that someone saved, tagged or applied to. Those
+// jobs are the permanent archive: the scrape cache expiry and the purge leave them alone, so
+// saved lists and applications (whose rows cascade with their job) never lose them. Deleted
+// applications count, since they can be restored.
+const ArchivedJobSQL = `(EXISTS (SELECT 1 FROM saved_jobs s WHERE s.job_id = jobs.id)
+	OR EXISTS (SELECT 1 FROM job_tags t WHERE t.job_id = jobs.id)
+	OR EXISTS (SELECT 1 FROM applications a WHERE a.job_id = jobs.id))`
+
+// placeholder returns the next positional parameter for args, after appending v to it
+func placeholder(args *[]any, v any) string {
+	*args = append(*args, v)
+	return fmt.Sprintf("$%d", len(*args))
+}
+
+// notFound maps pgx's no-rows error to ErrNotFound
+func notFound(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// isUniqueViolation reports whether err is a unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+func toJSON(v any) []byte {
+	if v == nil {
+		return nil
+	}
+	b, _ := json.Marshal(v)
+	return b
+}
+
+func scanJSON(v any) any {
+	return &jsonScanner{v: v}
+}
+
+type jsonScanner struct {
+	v any
+}
+
+func (s *jsonScanner) Scan(src any) error {
+	if src == nil {
+		return nil
+	}
+
+	var b []byte
+	switch v := src.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan type %T into json", src)
+	}
+
+	return json.Unmarshal(b, s.v)
+}
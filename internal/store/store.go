@@ -0,0 +1,287 @@
+// Package store holds the data access layer. Handlers depend on the interfaces here rather than
+// on SQL, so they can be tested against fakes; the Postgres implementations use pgx.
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yourusername/jobapply/internal/encryption"
+	"github.com/yourusername/jobapply/internal/events"
+	"github.com/yourusername/jobapply/internal/models"
+)
+
+var (
+	// ErrNotFound means the requested row doesn't exist or isn't visible to the user
+	ErrNotFound = errors.New("not found")
+	// ErrConflict means a write would violate a uniqueness constraint, e.g. a taken email
+	ErrConflict = errors.New("conflict")
+	// ErrInvalidTransition means an application can't move from its current status to the
+	// requested one
+	ErrInvalidTransition = errors.New("invalid status transition")
+)
+
+// UserStore manages accounts and their profiles
+type UserStore interface {
+	// CreateUser, UpdatePassword, UpdateEmail and DeleteUser queue the user.* event reporting
+	// the change in the same transaction
+	CreateUser(ctx context.Context, fullName, email, passwordHash string) (*models.User, error)
+	UserByEmail(ctx context.Context, email string) (*models.User, error)
+	UserByID(ctx context.Context, userID string) (*models.User, error)
+	UpdatePassword(ctx context.Context, userID, passwordHash string) error
+	UpdateEmail(ctx context.Context, userID, email string) error
+	// DeleteUser removes the account and, through cascades, everything it owns
+	DeleteUser(ctx context.Context, userID string) error
+
+	Profile(ctx context.Context, userID string) (*models.UserProfile, error)
+	// SaveProfile writes the editable profile fields. A changed address clears the stored
+	// coordinates until SetProfileLocation fills them in again.
+	SaveProfile(ctx context.Context, userID string, profile *models.UserProfile) (*models.UserProfile, error)
+	// SetProfileLocation stores coordinates for addr, unless the address has changed since
+	SetProfileLocation(ctx context.Context, userID string, addr models.Address, lat, lng float64) error
+	// UpdateWorkHistory and UpdateEducation apply edit to the list under a row lock, so
+	// concurrent edits can't drop each other's entries. An error from edit aborts the update.
+	UpdateWorkHistory(ctx context.Context, userID string, edit func([]models.WorkHistory) ([]models.WorkHistory, error)) ([]models.WorkHistory, error)
+	UpdateEducation(ctx context.Context, userID string, edit func([]models.Education) ([]models.Education, error)) ([]models.Education, error)
+
+	// Stats counts the user's jobs, saved jobs and applications
+	Stats(ctx context.Context, userID string) (*models.UserStats, error)
+
+	// ReencryptProfiles rewrites every phone number and address not stored under the current
+	// encryption key, returning how many profiles changed. Run after adding or rotating a key.
+	ReencryptProfiles(ctx context.Context) (int, error)
+}
+
+// Salary is a parsed pay range as stored on a job; nil fields are unknown
+type Salary struct {
+	Min, Max *int // Annualized, in USD
+	Currency *string
+	Period   *string
+	Text     *string // As written in the posting
+}
+
+// ScrapedJob is a job from a job board, ready to store
+type ScrapedJob struct {
+	Site, Title, Company, Location, URL string
+	Description                         *string
+	PostedAt                            *time.Time
+	Pay                                 Salary
+	CompanyID                           *string
+	Agency                              *string // Staffing agency the posting came through
+}
+
+// UpsertResult is what UpsertJobs did with each job
+type UpsertResult struct {
+	IDs      []string // Every job stored, new or refreshed
+	Inserted int
+	Updated  int
+	// Skipped counts jobs without a title or URL, and repeats of a URL earlier in the list
+	Skipped int
+}
+
+// JobStore manages jobs as seen by one user
+type JobStore interface {
+	// Job returns a job found by the user's searches, with the user's tags
+	Job(ctx context.Context, userID, jobID string) (*models.JobDetail, error)
+	// JobsByID returns the listed jobs the user found or applied to, in no particular order.
+	// Jobs that don't exist or aren't visible to the user are left out.
+	JobsByID(ctx context.Context, userID string, jobIDs []string) ([]models.JobListing, error)
+	// JobText returns a job's title and raw description, empty if none was scraped
+	JobText(ctx context.Context, jobID string) (title, description string, err error)
+	// UpsertJobs stores the jobs found by one search in a single statement, refreshing jobs
+	// already known by URL, and reviving them if deleted, and marking them all with searchHash
+	// for the scrape cache
+	UpsertJobs(ctx context.Context, searchHash string, jobs []ScrapedJob) (*UpsertResult, error)
+	// CacheDescription stores a description fetched after the scrape, filling in salary
+	// fields only where the scrape left them empty
+	CacheDescription(ctx context.Context, jobID, description string, pay Salary) error
+
+	Dismiss(ctx context.Context, userID, jobID string) error
+	Undismiss(ctx context.Context, userID, jobID string) error
+
+	// SaveJob adds a job to the shortlist or updates its notes and priority
+	SaveJob(ctx context.Context, userID, jobID, notes string, priority int) error
+	UnsaveJob(ctx context.Context, userID, jobID string) error
+	// SavedJobs lists the shortlist, highest priority first, limited to jobs with all tags
+	SavedJobs(ctx context.Context, userID string, tags []string) ([]models.SavedJob, error)
+}
+
+// ApplicationStore manages submitted applications
+type ApplicationStore interface {
+	// Applications lists the user's applications, newest first, limited to those with all tags.
+	// Deleted applications are left out.
+	Applications(ctx context.Context, userID string, tags []string) ([]models.Application, error)
+	// EachApplication calls fn with each application Applications would list, as rows are
+	// read, so long lists can be streamed. An error from fn stops the iteration and is returned.
+	EachApplication(ctx context.Context, userID string, tags []string, fn func(*models.Application) error) error
+	// DeletedApplications lists the user's deleted applications that can still be restored,
+	// most recently deleted first
+	DeletedApplications(ctx context.Context, userID string) ([]models.Application, error)
+	// DeleteApplication hides an application from every other query until it is restored
+	// with RestoreApplication or purged
+	DeleteApplication(ctx context.Context, userID, applicationID string) error
+	RestoreApplication(ctx context.Context, userID, applicationID string) error
+
+	// CreateApplication records a new application for one of the user's jobs and returns its
+	// ID. personaID may be nil for the base profile. status must be pending or in_progress.
+	// It returns ErrNotFound if the user has no such job or persona.
+	CreateApplication(ctx context.Context, userID, jobID string, personaID *string, status models.ApplicationStatus) (string, error)
+	// ImportApplications records applications made outside jobapply. A live shared job with the
+	// URL is used as it is; otherwise the job is one only the user sees. Applications the user
+	// already has for a job are skipped. Without a Source, an application's source is "import".
+	// An unknown status fails the import with a *validation.FieldError.
+	ImportApplications(ctx context.Context, userID string, apps []ImportedApplication) (*ImportResult, error)
+	// ApplicationQuestions lists every page of questions with its answers, first page first.
+	// Pages are only those migrated from custom_questions until the apply engine adds them.
+	ApplicationQuestions(ctx context.Context, userID, applicationID string) ([]models.QuestionPage, error)
+	// UpdateStatus moves the application to status, recording the fields filled and omitted
+	// and, for failures, errorLog. Moving to submitted stamps applied_at. A move the status
+	// machine doesn't allow, or any update to a final application, returns
+	// ErrInvalidTransition; staying in the same status only updates the fields. Each new
+	// application and change of status queues an event.
+	UpdateStatus(ctx context.Context, userID, applicationID string, update ApplicationUpdate) error
+	// ApplicationEvents lists the application's status changes, oldest first
+	ApplicationEvents(ctx context.Context, userID, applicationID string) ([]models.ApplicationEvent, error)
+}
+
+// ImportedApplication is an application made outside jobapply
+type ImportedApplication struct {
+	AppliedAt      time.Time
+	Company, Title string
+	URL            string // Canonical job URL, or a synthetic one unique to the application
+	Status         models.ApplicationStatus
+	ExternalStatus string // As the user tracked it; "" if not given
+	Source         string // "import", or "manual" for ones recorded by the browser extension
+}
+
+// ImportResult is what ImportApplications did with each application, by its index in the input
+type ImportResult struct {
+	IDs        []string // The new applications' IDs
+	Duplicates []int    // Indexes of applications the user already had
+}
+
+// ApplicationUpdate is the outcome of an apply attempt. Nil fields leave what is stored; an
+// empty ErrorLog clears it.
+type ApplicationUpdate struct {
+	Status        models.ApplicationStatus
+	FieldsFilled  []string
+	FieldsOmitted []string
+	ErrorLog      *string
+}
+
+// NotificationStore manages the in-app notification inbox
+type NotificationStore interface {
+	// Notify adds a notification to the user's inbox. A notification with the same DedupeKey
+	// as an earlier one for the user is dropped, and Notify returns false.
+	Notify(ctx context.Context, userID string, n NewNotification) (bool, error)
+	// Notifications lists the user's notifications, newest first, and marks them seen
+	Notifications(ctx context.Context, userID string, q NotificationQuery) ([]models.Notification, error)
+	// NotificationCounts counts the user's unread and unseen notifications
+	NotificationCounts(ctx context.Context, userID string) (NotificationCounts, error)
+	// MarkRead marks one notification read, returning ErrNotFound if the user has no such
+	// notification. MarkAllRead marks every one read and returns how many were unread.
+	MarkRead(ctx context.Context, userID, notificationID string) error
+	MarkAllRead(ctx context.Context, userID string) (int, error)
+	// DeleteReadNotifications removes notifications read before the cutoff, for every user
+	DeleteReadNotifications(ctx context.Context, before time.Time) (int, error)
+}
+
+// NewNotification is a notification to add to an inbox
+type NewNotification struct {
+	Kind        models.NotificationKind
+	Title, Body string
+	Link        string // API path of what it's about; may be ""
+	// DedupeKey identifies what the notification is about, e.g. "reminder:saved:<job ID>",
+	// so producers that run repeatedly notify once; "" never deduplicates
+	DedupeKey string
+}
+
+// NotificationQuery selects notifications to list
+type NotificationQuery struct {
+	UnreadOnly bool
+	Before     *time.Time // Only notifications created before this, for paging
+	Limit      int
+}
+
+// NotificationCounts is how many notifications are unread, and how many haven't been listed yet
+type NotificationCounts struct {
+	Unread, Unseen int
+}
+
+// OutboxStore queues events for delivery by services.Outbox. Stores queue the events about
+// their own changes in the same transaction; Enqueue is for everything else.
+type OutboxStore interface {
+	Enqueue(ctx context.Context, e events.Event) error
+}
+
+// Store bundles the stores a Handler needs
+type Store struct {
+	Users         UserStore
+	Jobs          JobStore
+	Applications  ApplicationStore
+	Notifications NotificationStore
+	Outbox        OutboxStore
+
+	db     dbtx // nil for stores not backed by Postgres
+	cipher *encryption.Cipher
+	commit *commitHooks // Set inside InTx
+}
+
+// commitHooks holds work, such as audit records, that must wait until the outermost InTx
+// transaction commits
+type commitHooks struct {
+	fns []func()
+}
+
+// NewPostgres returns stores backed by pool. Profile phone numbers and addresses are encrypted
+// with cipher, which may be nil to store them in plaintext.
+func NewPostgres(pool *pgxpool.Pool, cipher *encryption.Cipher) *Store {
+	return newPostgres(pool, cipher, nil)
+}
+
+func newPostgres(db dbtx, cipher *encryption.Cipher, commit *commitHooks) *Store {
+	return &Store{
+		Users:         &pgUserStore{db: db, cipher: cipher},
+		Jobs:          &pgJobStore{db: db},
+		Applications:  &pgApplicationStore{db: db, commit: commit},
+		Notifications: &pgNotificationStore{db: db},
+		Outbox:        &pgOutboxStore{db: db},
+		db:            db,
+		cipher:        cipher,
+		commit:        commit,
+	}
+}
+
+// InTx runs fn with stores bound to a single transaction. The transaction commits if fn
+// returns nil; any error - a failed write, or something outside the database such as the
+// browser failing mid-apply - rolls back every write fn made through tx, along with the
+// events they queued, and is returned unchanged. Calls nest as savepoints. Changes are
+// audited only once the outermost transaction commits. Stores not backed by Postgres run fn
+// directly.
+func (s *Store) InTx(ctx context.Context, fn func(tx *Store) error) error {
+	if s.db == nil {
+		return fn(s)
+	}
+	commit := s.commit
+	if commit == nil {
+		commit = &commitHooks{}
+	}
+	queued := len(commit.fns)
+	err := pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		return fn(newPostgres(tx, s.cipher, commit))
+	})
+	if err != nil {
+		// A rolled-back savepoint drops its hooks; the outer transaction may still commit
+		commit.fns = commit.fns[:queued]
+		return err
+	}
+	if s.commit == nil {
+		for _, f := range commit.fns {
+			f()
+		}
+	}
+	return nil
+}
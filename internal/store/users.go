@@ -0,0 +1,393 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yourusername/jobapply/internal/audit"
+	"github.com/yourusername/jobapply/internal/encryption"
+	"github.com/yourusername/jobapply/internal/events"
+	"github.com/yourusername/jobapply/internal/models"
+)
+
+type pgUserStore struct {
+	db     dbtx
+	cipher *encryption.Cipher // Encrypts phone and address; nil stores them in plaintext
+}
+
+const profileColumns = `id, full_name, email, phone, address, work_history, education, resume_url, skills,
+	desired_salary, latitude, longitude, created_at, updated_at`
+
+// scanProfile reads a row of profileColumns, decrypting the phone number and address
+func (s *pgUserStore) scanProfile(ctx context.Context, row pgx.Row) (*models.UserProfile, error) {
+	var profile models.UserProfile
+	var phone, address *string
+	err := row.Scan(
+		&profile.ID, &profile.FullName, &profile.Email, &phone,
+		&address, scanJSON(&profile.WorkHistory), scanJSON(&profile.Education),
+		&profile.ResumeURL, &profile.Skills, &profile.DesiredSalary, &profile.Latitude, &profile.Longitude, &profile.CreatedAt, &profile.UpdatedAt,
+	)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	if profile.Phone, err = s.cipher.Decrypt(ctx, deref(phone)); err != nil {
+		return nil, fmt.Errorf("phone: %w", err)
+	}
+	if profile.Address, err = s.openAddress(ctx, address); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// sealAddress encodes addr as JSON and encrypts it for the address column; nil is stored as NULL
+func (s *pgUserStore) sealAddress(ctx context.Context, addr *models.Address) (*string, error) {
+	if addr == nil {
+		return nil, nil
+	}
+	sealed, err := s.cipher.Encrypt(ctx, string(toJSON(addr)))
+	if err != nil {
+		return nil, fmt.Errorf("address: %w", err)
+	}
+	return &sealed, nil
+}
+
+// openAddress reverses sealAddress. Addresses stored before encryption are plain JSON.
+func (s *pgUserStore) openAddress(ctx context.Context, stored *string) (*models.Address, error) {
+	if stored == nil || *stored == "" {
+		return nil, nil
+	}
+	plain, err := s.cipher.Decrypt(ctx, *stored)
+	if err != nil {
+		return nil, fmt.Errorf("address: %w", err)
+	}
+	var addr *models.Address
+	if err := json.Unmarshal([]byte(plain), &addr); err != nil {
+		return nil, fmt.Errorf("address: %w", err)
+	}
+	return addr, nil
+}
+
+// sameAddress compares addresses by value; the stored ones can't be compared in SQL, since
+// encrypting the same address twice gives different ciphertexts
+func sameAddress(a, b *models.Address) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// lockedAddress reads the user's current address under a row lock
+func (s *pgUserStore) lockedAddress(ctx context.Context, tx pgx.Tx, userID string) (*models.Address, error) {
+	var stored *string
+	if err := tx.QueryRow(ctx, "SELECT address FROM user_profiles WHERE id = $1 FOR UPDATE", userID).Scan(&stored); err != nil {
+		return nil, notFound(err)
+	}
+	return s.openAddress(ctx, stored)
+}
+
+func (s *pgUserStore) CreateUser(ctx context.Context, fullName, email, passwordHash string) (*models.User, error) {
+	user := models.User{PasswordHash: passwordHash}
+	err := pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			INSERT INTO user_profiles (full_name, email, password_hash)
+			VALUES ($1, $2, $3)
+			RETURNING id, full_name, email
+		`, fullName, email, passwordHash).Scan(&user.ID, &user.FullName, &user.Email)
+		if err != nil {
+			return err
+		}
+		return enqueueEvent(ctx, tx, events.New(events.UserSignedUp, user.ID, nil))
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrConflict
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (s *pgUserStore) UserByEmail(ctx context.Context, email string) (*models.User, error) {
+	return s.user(ctx, "email", email)
+}
+
+func (s *pgUserStore) UserByID(ctx context.Context, userID string) (*models.User, error) {
+	return s.user(ctx, "id", userID)
+}
+
+// user looks a user up by column, which must be a trusted constant
+func (s *pgUserStore) user(ctx context.Context, column, value string) (*models.User, error) {
+	var user models.User
+	err := s.db.QueryRow(ctx, fmt.Sprintf("SELECT id, full_name, email, password_hash, is_admin FROM user_profiles WHERE %s = $1", column), value).
+		Scan(&user.ID, &user.FullName, &user.Email, &user.PasswordHash, &user.IsAdmin)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+func (s *pgUserStore) UpdatePassword(ctx context.Context, userID, passwordHash string) error {
+	return s.updateAccount(ctx, userID, events.UserPasswordChanged,
+		"UPDATE user_profiles SET password_hash = $2, updated_at = NOW() WHERE id = $1", passwordHash)
+}
+
+func (s *pgUserStore) UpdateEmail(ctx context.Context, userID, email string) error {
+	err := s.updateAccount(ctx, userID, events.UserEmailChanged,
+		"UPDATE user_profiles SET email = $2, updated_at = NOW() WHERE id = $1", email)
+	if isUniqueViolation(err) {
+		return ErrConflict
+	}
+	return err
+}
+
+func (s *pgUserStore) DeleteUser(ctx context.Context, userID string) error {
+	return s.updateAccount(ctx, userID, events.UserDeleted, "DELETE FROM user_profiles WHERE id = $1")
+}
+
+// updateAccount runs a statement changing the user's account row, which takes the user ID as
+// $1 followed by args, and queues the event reporting it in the same transaction
+func (s *pgUserStore) updateAccount(ctx context.Context, userID string, typ events.Type, sql string, args ...any) error {
+	return pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, sql, append([]any{userID}, args...)...)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			return ErrNotFound
+		}
+		return enqueueEvent(ctx, tx, events.New(typ, userID, nil))
+	})
+}
+
+func (s *pgUserStore) Profile(ctx context.Context, userID string) (*models.UserProfile, error) {
+	return s.scanProfile(ctx, s.db.QueryRow(ctx, "SELECT "+profileColumns+" FROM user_profiles WHERE id = $1", userID))
+}
+
+func (s *pgUserStore) SaveProfile(ctx context.Context, userID string, profile *models.UserProfile) (*models.UserProfile, error) {
+	phone, err := s.cipher.Encrypt(ctx, profile.Phone)
+	if err != nil {
+		return nil, fmt.Errorf("phone: %w", err)
+	}
+	address, err := s.sealAddress(ctx, profile.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	current, err := s.lockedAddress(ctx, tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var before *models.UserProfile
+	if audit.Enabled(ctx) {
+		before, err = s.scanProfile(ctx, tx.QueryRow(ctx, "SELECT "+profileColumns+" FROM user_profiles WHERE id = $1", userID))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	saved, err := s.scanProfile(ctx, tx.QueryRow(ctx, `
+		UPDATE user_profiles
+		SET full_name = $1, phone = $2, address = $3, work_history = $4, education = $5, skills = $6,
+			desired_salary = $8, updated_at = NOW(),
+			latitude = CASE WHEN $9 THEN latitude END,
+			longitude = CASE WHEN $9 THEN longitude END
+		WHERE id = $7
+		RETURNING `+profileColumns,
+		profile.FullName,
+		phone,
+		address, toJSON(profile.WorkHistory), toJSON(profile.Education),
+		profile.Skills,
+		userID,
+		profile.DesiredSalary,
+		sameAddress(current, profile.Address),
+	))
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	// Encrypted fields, and the location geocoded from the address, stay out of the log
+	audit.Record(ctx, "profile", userID, before, saved, "phone", "address", "latitude", "longitude")
+	return saved, nil
+}
+
+func (s *pgUserStore) SetProfileLocation(ctx context.Context, userID string, addr models.Address, lat, lng float64) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	current, err := s.lockedAddress(ctx, tx, userID)
+	if err != nil || !sameAddress(current, &addr) {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "UPDATE user_profiles SET latitude = $1, longitude = $2 WHERE id = $3", lat, lng, userID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (s *pgUserStore) UpdateWorkHistory(ctx context.Context, userID string, edit func([]models.WorkHistory) ([]models.WorkHistory, error)) ([]models.WorkHistory, error) {
+	var list []models.WorkHistory
+	err := s.updateProfileList(ctx, userID, "work_history", &list, func() error {
+		var err error
+		list, err = edit(list)
+		return err
+	})
+	return list, err
+}
+
+func (s *pgUserStore) UpdateEducation(ctx context.Context, userID string, edit func([]models.Education) ([]models.Education, error)) ([]models.Education, error) {
+	var list []models.Education
+	err := s.updateProfileList(ctx, userID, "education", &list, func() error {
+		var err error
+		list, err = edit(list)
+		return err
+	})
+	return list, err
+}
+
+// updateProfileList loads a JSONB list column into dest under a row lock, runs mutate and
+// writes dest back in the same transaction. column must be a trusted constant - it is
+// interpolated into the SQL.
+func (s *pgUserStore) updateProfileList(ctx context.Context, userID, column string, dest any, mutate func() error) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, fmt.Sprintf("SELECT %s FROM user_profiles WHERE id = $1 FOR UPDATE", column), userID).
+		Scan(scanJSON(dest))
+	if err != nil {
+		return notFound(err)
+	}
+
+	if err := mutate(); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, fmt.Sprintf("UPDATE user_profiles SET %s = $1, updated_at = NOW() WHERE id = $2", column),
+		toJSON(dest), userID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (s *pgUserStore) Stats(ctx context.Context, userID string) (*models.UserStats, error) {
+	stats := models.UserStats{ApplicationsByStatus: map[string]int{}}
+	err := s.db.QueryRow(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM user_jobs uj JOIN jobs j ON j.id = uj.job_id WHERE uj.user_id = $1 AND j.deleted_at IS NULL),
+			(SELECT COUNT(*) FROM saved_jobs WHERE user_id = $1)
+	`, userID).Scan(&stats.JobsFound, &stats.JobsSaved)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(ctx, "SELECT status, COUNT(*) FROM applications WHERE user_id = $1 AND deleted_at IS NULL GROUP BY status", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		stats.ApplicationsByStatus[status] = count
+		stats.Applications += count
+	}
+	return &stats, rows.Err()
+}
+
+// reencryptBatch is how many profiles ReencryptProfiles reads at a time
+const reencryptBatch = 100
+
+func (s *pgUserStore) ReencryptProfiles(ctx context.Context) (int, error) {
+	type contact struct {
+		id             string
+		phone, address *string
+	}
+
+	changed := 0
+	after := ""
+	for {
+		rows, err := s.db.Query(ctx, `
+			SELECT id, phone, address FROM user_profiles
+			WHERE id::text > $1 ORDER BY id::text LIMIT $2
+		`, after, reencryptBatch)
+		if err != nil {
+			return changed, err
+		}
+		var batch []contact
+		for rows.Next() {
+			var c contact
+			if err := rows.Scan(&c.id, &c.phone, &c.address); err != nil {
+				rows.Close()
+				return changed, err
+			}
+			batch = append(batch, c)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return changed, err
+		}
+		if len(batch) == 0 {
+			return changed, nil
+		}
+		after = batch[len(batch)-1].id
+
+		for _, c := range batch {
+			if !s.cipher.NeedsRotation(deref(c.phone)) && !s.cipher.NeedsRotation(deref(c.address)) {
+				continue
+			}
+			phone, err := s.reencrypt(ctx, c.phone)
+			if err != nil {
+				return changed, fmt.Errorf("profile %s phone: %w", c.id, err)
+			}
+			address, err := s.reencrypt(ctx, c.address)
+			if err != nil {
+				return changed, fmt.Errorf("profile %s address: %w", c.id, err)
+			}
+			// Skipped if the profile was saved meanwhile; the save already used the current key
+			result, err := s.db.Exec(ctx, `
+				UPDATE user_profiles SET phone = $1, address = $2
+				WHERE id = $3 AND phone IS NOT DISTINCT FROM $4 AND address IS NOT DISTINCT FROM $5
+			`, phone, address, c.id, c.phone, c.address)
+			if err != nil {
+				return changed, err
+			}
+			changed += int(result.RowsAffected())
+		}
+	}
+}
+
+// reencrypt decrypts a stored value and encrypts it again with the current key
+func (s *pgUserStore) reencrypt(ctx context.Context, stored *string) (*string, error) {
+	if stored == nil {
+		return nil, nil
+	}
+	plain, err := s.cipher.Decrypt(ctx, *stored)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := s.cipher.Encrypt(ctx, plain)
+	if err != nil {
+		return nil, err
+	}
+	return &sealed, nil
+}
@@ -0,0 +1,102 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/yourusername/jobapply/internal/logging"
+)
+
+// maxStatementLength caps SQL recorded on database spans
+const maxStatementLength = 2000
+
+// Middleware starts a server span per request, continuing the caller's trace if it sent a
+// traceparent header. Spans are named after the matched route pattern, not the raw path,
+// so /jobs/{id} requests group together.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if exporter.Load() == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, span := Start(Extract(r.Context(), r.Header), r.Method, KindServer,
+			"http.request.method", r.Method,
+			"url.path", r.URL.Path,
+		)
+		defer span.End()
+		ctx = logging.With(ctx, "trace_id", span.TraceID())
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		if rc := chi.RouteContext(r.Context()); rc != nil && rc.RoutePattern() != "" {
+			span.SetName(r.Method + " " + rc.RoutePattern())
+			span.SetAttributes("http.route", rc.RoutePattern())
+		}
+		span.SetAttributes("http.response.status_code", rec.status)
+		if rec.status >= http.StatusInternalServerError {
+			span.RecordError(errors.New(http.StatusText(rec.status)))
+		}
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// QueryTracer is a pgx.QueryTracer recording a client span per query
+type QueryTracer struct{}
+
+type querySpanKey struct{}
+
+var _ pgx.QueryTracer = QueryTracer{}
+
+func (QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	// Only trace queries made on behalf of a traced operation, not pool housekeeping
+	if FromContext(ctx) == nil {
+		return ctx
+	}
+
+	statement := strings.Join(strings.Fields(data.SQL), " ")
+	if len(statement) > maxStatementLength {
+		statement = statement[:maxStatementLength]
+	}
+	ctx, span := Start(ctx, queryName(statement), KindClient,
+		"db.system", "postgresql",
+		"db.statement", statement,
+	)
+	return context.WithValue(ctx, querySpanKey{}, span)
+}
+
+func (QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span, _ := ctx.Value(querySpanKey{}).(*Span)
+	if span == nil {
+		return
+	}
+	span.SetAttributes("db.rows_affected", data.CommandTag.RowsAffected())
+	span.RecordError(data.Err)
+	span.End()
+}
+
+// queryName names a database span after the statement's operation, e.g. "SELECT"
+func queryName(statement string) string {
+	if op, _, ok := strings.Cut(statement, " "); ok {
+		return strings.ToUpper(op)
+	}
+	return "query"
+}
@@ -0,0 +1,180 @@
+// Package tracing records request, database and scraper spans and exports them to an
+// OpenTelemetry collector over OTLP/HTTP (JSON encoding). It implements the small subset of
+// OpenTelemetry this service needs: W3C traceparent propagation, span attributes and errors,
+// and batched export. Tracing is off until Init is called with an endpoint.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Span kinds, as numbered by OTLP
+const (
+	KindInternal = 1
+	KindServer   = 2
+	KindClient   = 3
+)
+
+type contextKey struct{}
+
+var (
+	exporter atomic.Pointer[Exporter]
+
+	traceparentRegex = regexp.MustCompile(`^00-([0-9a-f]{32})-([0-9a-f]{16})-[0-9a-f]{2}$`)
+)
+
+// Span is one timed operation. A nil *Span is valid and does nothing, which is what Start
+// returns while tracing is disabled.
+type Span struct {
+	traceID  [16]byte
+	spanID   [8]byte
+	parentID [8]byte
+	name     string
+	kind     int
+	start    time.Time
+
+	mu     sync.Mutex
+	end    time.Time
+	attrs  map[string]any
+	errMsg string
+	ended  bool
+}
+
+// Start begins a span as a child of the span in ctx, if any, and returns a context carrying it.
+// Attributes are given as alternating keys and values.
+func Start(ctx context.Context, name string, kind int, attrs ...any) (context.Context, *Span) {
+	if exporter.Load() == nil {
+		return ctx, nil
+	}
+
+	span := &Span{name: name, kind: kind, start: time.Now()}
+	if parent := FromContext(ctx); parent != nil {
+		span.traceID = parent.traceID
+		span.parentID = parent.spanID
+	} else if remote, ok := ctx.Value(remoteParentKey{}).(remoteParent); ok {
+		span.traceID = remote.traceID
+		span.parentID = remote.spanID
+	} else {
+		rand.Read(span.traceID[:])
+	}
+	rand.Read(span.spanID[:])
+	span.SetAttributes(attrs...)
+
+	return context.WithValue(ctx, contextKey{}, span), span
+}
+
+// FromContext returns the current span, or nil
+func FromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(contextKey{}).(*Span)
+	return span
+}
+
+// SetAttributes adds alternating key/value attributes to the span
+func (s *Span) SetAttributes(attrs ...any) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.attrs == nil {
+		s.attrs = make(map[string]any, len(attrs)/2)
+	}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		if key, ok := attrs[i].(string); ok {
+			s.attrs[key] = attrs[i+1]
+		}
+	}
+}
+
+// SetName renames the span, for when a better name is only known once the work is done
+func (s *Span) SetName(name string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.name = name
+	s.mu.Unlock()
+}
+
+// RecordError marks the span as failed. A nil error is ignored.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	s.errMsg = err.Error()
+	s.mu.Unlock()
+}
+
+// End finishes the span and queues it for export. Calling End more than once has no effect.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	s.mu.Unlock()
+
+	if e := exporter.Load(); e != nil {
+		e.enqueue(s)
+	}
+}
+
+// TraceID returns the span's trace ID in hex, for correlating logs with traces
+func (s *Span) TraceID() string {
+	if s == nil {
+		return ""
+	}
+	return hex.EncodeToString(s.traceID[:])
+}
+
+// Traceparent returns the W3C traceparent header value identifying this span
+func (s *Span) Traceparent() string {
+	if s == nil {
+		return ""
+	}
+	return fmt.Sprintf("00-%x-%x-01", s.traceID, s.spanID)
+}
+
+type remoteParentKey struct{}
+
+type remoteParent struct {
+	traceID [16]byte
+	spanID  [8]byte
+}
+
+// Extract returns ctx with the caller's span from a traceparent header as the parent for
+// spans started from it, so traces continue across services
+func Extract(ctx context.Context, header http.Header) context.Context {
+	m := traceparentRegex.FindStringSubmatch(header.Get("traceparent"))
+	if m == nil {
+		return ctx
+	}
+	var parent remoteParent
+	hex.Decode(parent.traceID[:], []byte(m[1]))
+	hex.Decode(parent.spanID[:], []byte(m[2]))
+	if parent.traceID == ([16]byte{}) || parent.spanID == ([8]byte{}) {
+		return ctx
+	}
+	return context.WithValue(ctx, remoteParentKey{}, parent)
+}
+
+// Inject sets the traceparent header for an outgoing request made within ctx's span
+func Inject(ctx context.Context, header http.Header) {
+	if span := FromContext(ctx); span != nil {
+		header.Set("traceparent", span.Traceparent())
+	}
+}
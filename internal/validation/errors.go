@@ -0,0 +1,71 @@
+package validation
+
+import "strings"
+
+// FieldError is a validation failure caused by one request field. Its message names the
+// field, so it reads well on its own.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+func fieldError(field, message string) error {
+	return &FieldError{Field: field, Message: message}
+}
+
+// Errors is every problem found with a request, so clients can fix them in one go
+type Errors []*FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Collector gathers field errors while a request is checked
+type Collector struct {
+	errs Errors
+}
+
+// Check records message against field unless ok
+func (c *Collector) Check(ok bool, field, message string) {
+	if !ok {
+		c.errs = append(c.errs, &FieldError{Field: field, Message: message})
+	}
+}
+
+// Required records "<field> is required" if value is blank, and reports whether it was present
+func (c *Collector) Required(field, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		c.errs = append(c.errs, &FieldError{Field: field, Message: field + " is required"})
+		return false
+	}
+	return true
+}
+
+// Add records the error from one of the Validate functions. Errors that don't name a field
+// are attributed to field.
+func (c *Collector) Add(field string, err error) {
+	if err == nil {
+		return
+	}
+	if fieldErr, ok := err.(*FieldError); ok {
+		c.errs = append(c.errs, fieldErr)
+		return
+	}
+	c.errs = append(c.errs, &FieldError{Field: field, Message: err.Error()})
+}
+
+// Err returns the collected errors, or nil if there were none
+func (c *Collector) Err() error {
+	if len(c.errs) == 0 {
+		return nil
+	}
+	return c.errs
+}
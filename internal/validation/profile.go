@@ -0,0 +1,102 @@
+package validation
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+const profileDateLayout = "2006-01-02"
+
+// WorkHistoryEntry mirrors the fields of models.WorkHistory that need checking.
+// Kept as plain strings so the validation package stays free of model imports.
+type WorkHistoryEntry struct {
+	Company   string
+	Title     string
+	StartDate string
+	EndDate   string
+}
+
+// ValidateWorkHistoryEntry checks required fields and that dates are well-formed and ordered
+func ValidateWorkHistoryEntry(e WorkHistoryEntry) error {
+	if e.Company == "" || e.Title == "" {
+		return fieldError("company", "company and title are required")
+	}
+
+	if e.StartDate == "" {
+		return fieldError("start_date", "start_date is required")
+	}
+	start, err := time.Parse(profileDateLayout, e.StartDate)
+	if err != nil {
+		return fieldError("start_date", "start_date must be in YYYY-MM-DD format")
+	}
+	if start.After(time.Now()) {
+		return fieldError("start_date", "start_date cannot be in the future")
+	}
+
+	if e.EndDate != "" {
+		end, err := time.Parse(profileDateLayout, e.EndDate)
+		if err != nil {
+			return fieldError("end_date", "end_date must be in YYYY-MM-DD format")
+		}
+		if end.Before(start) {
+			return fieldError("end_date", "end_date must be on or after start_date")
+		}
+	}
+
+	return nil
+}
+
+// ValidateEducationEntry checks required fields and that the graduation year is plausible
+func ValidateEducationEntry(school string, gradYear int) error {
+	if school == "" {
+		return fieldError("school", "school is required")
+	}
+	// Allow expected graduation dates a few years out
+	if gradYear != 0 && (gradYear < 1940 || gradYear > time.Now().Year()+8) {
+		return fieldError("grad_year", "grad_year is out of range")
+	}
+	return nil
+}
+
+// DateRangesOverlap reports whether two work periods overlap. An empty end date means "present".
+// Unparseable dates never overlap; they are rejected by ValidateWorkHistoryEntry anyway.
+func DateRangesOverlap(startA, endA, startB, endB string) bool {
+	parse := func(s string, open bool) (time.Time, bool) {
+		if s == "" && open {
+			return time.Now(), true
+		}
+		t, err := time.Parse(profileDateLayout, s)
+		return t, err == nil
+	}
+
+	sa, ok1 := parse(startA, false)
+	ea, ok2 := parse(endA, true)
+	sb, ok3 := parse(startB, false)
+	eb, ok4 := parse(endB, true)
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		return false
+	}
+
+	return sa.Before(eb) && sb.Before(ea)
+}
+
+var (
+	usZipRegex  = regexp.MustCompile(`^\d{5}(-\d{4})?$`)
+	postalRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9\- ]{1,9}$`)
+)
+
+// ValidateAddress checks that a partial address is still usable for geocoding and forms.
+// All-numeric postal codes must be US ZIP or ZIP+4; others just need a plausible shape.
+func ValidateAddress(street, city, zipCode string) error {
+	if street != "" && city == "" && zipCode == "" {
+		return fieldError("address.city", "address needs a city or zip_code")
+	}
+	if zipCode != "" {
+		numeric := strings.Trim(zipCode, "0123456789-") == ""
+		if (numeric && !usZipRegex.MatchString(zipCode)) || (!numeric && !postalRegex.MatchString(zipCode)) {
+			return fieldError("address.zip_code", "zip_code is not a valid postal code")
+		}
+	}
+	return nil
+}